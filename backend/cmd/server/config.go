@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+// loadConfig 加载并校验配置，失败时打印错误和用法后退出
+func loadConfig(args []string, validate func(config.Config) error) config.Config {
+	cfg, err := parseConfig(args)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) && !isFlagError(err) {
+			printConfigErrors("加载配置失败:", err)
+		}
+		os.Exit(2)
+	}
+
+	if err := validate(cfg); err != nil {
+		printConfigErrors("配置校验失败:", err)
+		os.Exit(2)
+	}
+
+	return cfg
+}
+
+// parseConfig 按 配置文件 < 环境变量 < 命令行参数 的优先级生成最终配置
+// 配置文件路径来自 --config 或 KUBEOPS_CONFIG
+func parseConfig(args []string) (config.Config, error) {
+	// 第一遍只为取得 --config，其余参数在第二遍处理
+	configPath := config.GetEnv("KUBEOPS_CONFIG", "")
+	pre := newFlagSet(&config.Config{}, &configPath)
+	pre.SetOutput(io.Discard)
+	pre.Usage = func() {}
+	_ = pre.Parse(args)
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return cfg, err
+	}
+
+	// 第二遍绑定到已加载的配置上，只有显式传入的参数才会覆盖文件和环境变量中的值
+	fs := newFlagSet(&cfg, &configPath)
+	if err := fs.Parse(args); err != nil {
+		return cfg, &flagError{err: err}
+	}
+	return cfg, nil
+}
+
+func newFlagSet(cfg *config.Config, configPath *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.SetOutput(os.Stdout)
+
+	fs.StringVar(configPath, "config", *configPath, "YAML 配置文件路径（KUBEOPS_CONFIG）")
+	fs.StringVar(&cfg.Port, "port", cfg.Port, "服务端口")
+	fs.StringVar(&cfg.Env, "env", cfg.Env, "运行环境")
+	fs.StringVar(&cfg.Kubeconfig, "kubeconfig", cfg.Kubeconfig, "kubeconfig 文件路径（未配置 clusters 时使用）")
+	fs.StringVar(&cfg.Postgres.Host, "postgres-host", cfg.Postgres.Host, "PostgreSQL 地址")
+	fs.StringVar(&cfg.Postgres.Port, "postgres-port", cfg.Postgres.Port, "PostgreSQL 端口")
+	fs.StringVar(&cfg.Postgres.User, "postgres-user", cfg.Postgres.User, "PostgreSQL 用户")
+	fs.StringVar(&cfg.Postgres.Password, "postgres-password", cfg.Postgres.Password, "PostgreSQL 密码")
+	fs.StringVar(&cfg.Postgres.Database, "postgres-db", cfg.Postgres.Database, "PostgreSQL 数据库")
+	fs.StringVar(&cfg.Postgres.SSLMode, "postgres-sslmode", cfg.Postgres.SSLMode, "PostgreSQL SSL 模式")
+	fs.StringVar(&cfg.Redis.Addr, "redis-addr", cfg.Redis.Addr, "Redis 地址")
+	fs.StringVar(&cfg.Redis.Password, "redis-password", cfg.Redis.Password, "Redis 密码")
+	fs.IntVar(&cfg.Redis.DB, "redis-db", cfg.Redis.DB, "Redis DB 编号")
+	fs.BoolVar(&cfg.Auth.Enabled, "auth-enabled", cfg.Auth.Enabled, "是否要求请求携带用户身份")
+	fs.BoolVar(&cfg.Cache.Enabled, "cache-enabled", cfg.Cache.Enabled, "是否启用列表接口缓存")
+	fs.DurationVar(&cfg.Cache.DefaultTTL, "cache-default-ttl", cfg.Cache.DefaultTTL, "列表接口缓存默认 TTL")
+	fs.BoolVar(&cfg.Tracing.Enabled, "tracing-enabled", cfg.Tracing.Enabled, "是否启用 OpenTelemetry 链路追踪")
+	fs.StringVar(&cfg.Tracing.Endpoint, "tracing-endpoint", cfg.Tracing.Endpoint, "OTLP/HTTP 接收地址")
+	fs.BoolVar(&cfg.Tracing.Insecure, "tracing-insecure", cfg.Tracing.Insecure, "OTLP 导出使用 HTTP 而非 HTTPS")
+	fs.Float64Var(&cfg.Tracing.SampleRatio, "tracing-sample-ratio", cfg.Tracing.SampleRatio, "链路追踪采样比例（0~1）")
+	fs.BoolVar(&cfg.RateLimit.Enabled, "rate-limit-enabled", cfg.RateLimit.Enabled, "是否启用基于 Redis 的分布式限流")
+	fs.BoolVar(&cfg.LeaderElection.Enabled, "leader-election", cfg.LeaderElection.Enabled, "多副本部署时启用后台任务主节点选举")
+	fs.StringVar(&cfg.LeaderElection.Backend, "leader-election-backend", cfg.LeaderElection.Backend, "主节点选举后端：lease 或 redis")
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(os.Stdout, "KubeOps 后端服务")
+		_, _ = fmt.Fprintln(os.Stdout, "")
+		_, _ = fmt.Fprintln(os.Stdout, "用法:")
+		_, _ = fmt.Fprintf(os.Stdout, "  %s [options]\n", os.Args[0])
+		_, _ = fmt.Fprintf(os.Stdout, "  %s migrate up|down|status [options]\n", os.Args[0])
+		_, _ = fmt.Fprintf(os.Stdout, "  %s config print [options]\n", os.Args[0])
+		_, _ = fmt.Fprintln(os.Stdout, "")
+		_, _ = fmt.Fprintln(os.Stdout, "配置优先级: 配置文件 < 环境变量 < 命令行参数")
+		_, _ = fmt.Fprintln(os.Stdout, "")
+		_, _ = fmt.Fprintln(os.Stdout, "选项:")
+		fs.PrintDefaults()
+		_, _ = fmt.Fprintln(os.Stdout, "")
+		_, _ = fmt.Fprintln(os.Stdout, "示例:")
+		_, _ = fmt.Fprintf(os.Stdout, "  %s --config /etc/kubeops/config.yaml\n", os.Args[0])
+		_, _ = fmt.Fprintf(os.Stdout, "  %s --postgres-host 192.168.33.100 --postgres-port 5432 --postgres-user kubeops \\\n", os.Args[0])
+		_, _ = fmt.Fprintln(os.Stdout, "    --postgres-password kubeops --postgres-db kubeops --redis-addr 192.168.33.100:6379")
+	}
+
+	return fs
+}
+
+// runConfig 处理 kubeops config print 子命令：输出生效配置，敏感字段已脱敏
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "print" {
+		_, _ = fmt.Fprintln(os.Stdout, "用法: kubeops config print [options]")
+		return 2
+	}
+
+	cfg, err := parseConfig(args[1:])
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) && !isFlagError(err) {
+			printConfigErrors("加载配置失败:", err)
+		}
+		return 2
+	}
+
+	out, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "序列化配置失败:", err)
+		return 1
+	}
+	_, _ = os.Stdout.Write(out)
+
+	if err := cfg.Validate(); err != nil {
+		printConfigErrors("配置校验失败:", err)
+		return 1
+	}
+	return 0
+}
+
+// flagError 命令行参数解析错误，flag 包已输出错误和用法
+type flagError struct {
+	err error
+}
+
+func (e *flagError) Error() string { return e.err.Error() }
+func (e *flagError) Unwrap() error { return e.err }
+
+func isFlagError(err error) bool {
+	var fe *flagError
+	return errors.As(err, &fe)
+}
+
+func printConfigErrors(title string, err error) {
+	_, _ = fmt.Fprintln(os.Stderr, title)
+	for _, line := range strings.Split(err.Error(), "\n") {
+		_, _ = fmt.Fprintln(os.Stderr, "  "+line)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/health"
+	"github.com/yansongwel/kubeops/backend/internal/informer"
+	"github.com/yansongwel/kubeops/backend/internal/leader"
+	"github.com/yansongwel/kubeops/backend/internal/migrate"
+)
+
+// apiserverTimeout apiserver /readyz 检查的超时，跨地域集群比本地依赖慢
+const apiserverTimeout = 3 * time.Second
+
+// healthCheckers 各探针使用的检查器
+type healthCheckers struct {
+	liveness  *health.Checker
+	readiness *health.Checker
+	startup   *health.Checker
+}
+
+// newHealthCheckers 组装 liveness、readiness、startup 三组检查
+// Postgres、数据库迁移和默认集群（配置中的第一个集群）是必需项，不可用时 /readyz 返回 503 摘除流量；
+// Redis 和其他集群是可选项：故障时服务以降级模式运行，/readyz 返回 degraded 而不是摘除流量
+func newHealthCheckers(
+	postgres *client.Dependency,
+	redis *client.Dependency,
+	clusters *client.ClusterManager,
+	informers *informer.Manager,
+	migrator *migrate.Migrator,
+	elector *leader.Elector,
+) healthCheckers {
+	migrations := func(ctx context.Context) error {
+		if err := postgres.Check(ctx); err != nil {
+			return err
+		}
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d migrations pending", pending)
+		}
+		return nil
+	}
+
+	readiness := health.NewChecker()
+	readiness.Add("postgres", 0, postgres.Check)
+	readiness.Add("migrations", 0, migrations)
+	readiness.AddOptional("redis", 0, redis.Check)
+	readiness.AddSet(apiserverChecks(clusters))
+	readiness.AddSet(informerChecks(clusters, informers))
+	readiness.AddInfo("leaderElection", func() interface{} { return elector.Status() })
+
+	// 启动探针只等待首次连接尝试完成，依赖不可用时也不阻塞启动，避免 CrashLoop
+	startup := health.NewChecker()
+	startup.Add("postgres", 0, postgres.Attempted)
+	startup.Add("redis", 0, redis.Attempted)
+
+	return healthCheckers{
+		liveness:  health.NewChecker(),
+		readiness: readiness,
+		startup:   startup,
+	}
+}
+
+// apiserverChecks 为每个集群生成 apiserver 可达性检查，默认集群之外的集群为可选项
+// 类比Shell: kubectl get --raw /readyz
+func apiserverChecks(clusters *client.ClusterManager) func() []health.Check {
+	return func() []health.Check {
+		var checks []health.Check
+		for i, name := range clusters.Names() {
+			name := name
+			checks = append(checks, health.Check{
+				Name:     "kubernetes:" + name,
+				Timeout:  apiserverTimeout,
+				Optional: i > 0,
+				Fn: func(ctx context.Context) error {
+					cluster, err := clusters.Get(name)
+					if err != nil {
+						return err
+					}
+					_, err = cluster.Clientset.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+					return err
+				},
+			})
+		}
+		return checks
+	}
+}
+
+// informerChecks 为每个集群生成 informer 同步检查，默认集群之外的集群为可选项
+func informerChecks(clusters *client.ClusterManager, informers *informer.Manager) func() []health.Check {
+	return func() []health.Check {
+		var checks []health.Check
+		for i, name := range clusters.Names() {
+			name := name
+			checks = append(checks, health.Check{
+				Name:     "informers:" + name,
+				Optional: i > 0,
+				Fn: func(context.Context) error {
+					started := false
+					for _, st := range informers.Status() {
+						if st.Cluster != name {
+							continue
+						}
+						started = true
+						if !st.Synced {
+							return fmt.Errorf("%s informer not synced", st.Resource)
+						}
+					}
+					if !started {
+						return fmt.Errorf("informers not started")
+					}
+					return nil
+				},
+			})
+		}
+		return checks
+	}
+}
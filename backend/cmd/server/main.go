@@ -2,71 +2,220 @@ package main
 
 import (
 	"context"
-	"flag"
-	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/cache"
 	"github.com/yansongwel/kubeops/backend/internal/client"
 	"github.com/yansongwel/kubeops/backend/internal/config"
 	"github.com/yansongwel/kubeops/backend/internal/handler"
+	"github.com/yansongwel/kubeops/backend/internal/informer"
+	"github.com/yansongwel/kubeops/backend/internal/inspection"
+	"github.com/yansongwel/kubeops/backend/internal/leader"
+	"github.com/yansongwel/kubeops/backend/internal/llm"
+	"github.com/yansongwel/kubeops/backend/internal/logsearch"
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+	"github.com/yansongwel/kubeops/backend/internal/middleware"
+	"github.com/yansongwel/kubeops/backend/internal/migrate"
+	"github.com/yansongwel/kubeops/backend/internal/ratelimit"
 	"github.com/yansongwel/kubeops/backend/internal/repository"
 	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
 )
 
 func main() {
+	// 子命令：kubeops migrate up|down|status、kubeops config print
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "config":
+			os.Exit(runConfig(os.Args[2:]))
+		}
+	}
+
 	// 1. 初始化日志
 	logger, _ := zap.NewProduction()
 	defer func() {
 		_ = logger.Sync()
 	}()
+	zap.ReplaceGlobals(logger)
 	logger.Info("KubeOps starting...")
 
-	cfg := loadConfig()
+	cfg := loadConfig(os.Args[1:], config.Config.Validate)
 
-	// 2. 初始化 K8s 客户端
-	k8sClient, err := client.InitK8sClient(logger, cfg.Kubeconfig)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
-		logger.Fatal("Failed to initialize Kubernetes client", zap.Error(err))
+		logger.Fatal("Failed to initialize tracing", zap.Error(err))
 	}
-	logger.Info("Successfully connected to Kubernetes cluster")
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Warn("Failed to flush traces", zap.Error(err))
+		}
+	}()
 
-	postgresPool, err := client.NewPostgresPool(cfg.Postgres)
+	// 2. 初始化 K8s 客户端（每个配置的集群一个）
+	// 依赖不可用时不退出：服务以降级模式启动，后台重连，需要该依赖的接口返回 503
+	metrics.RegisterKubernetesClientMetrics()
+	clusters := client.NewClusterManager(logger, cfg.ClusterList())
+	logger.Info("Kubernetes clients initialized",
+		zap.Strings("clusters", clusters.Names()),
+		zap.Strings("unavailable", clusters.Unavailable()),
+	)
+
+	postgresPool, err := client.OpenPostgresPool(cfg.Postgres)
 	if err != nil {
-		logger.Fatal("Failed to initialize Postgres", zap.Error(err))
+		logger.Fatal("Invalid Postgres configuration", zap.Error(err))
 	}
 	defer postgresPool.Close()
 
-	redisClient, err := client.NewRedisClient(cfg.Redis)
+	migrator, err := migrate.New(postgresPool, logger)
 	if err != nil {
-		logger.Fatal("Failed to initialize Redis", zap.Error(err))
+		logger.Fatal("Failed to load database migrations", zap.Error(err))
 	}
+
+	// 首次连上 Postgres 时自动执行数据库迁移（advisory lock 保证多副本安全）
+	var migrated atomic.Bool
+	postgresDep := client.NewDependency(logger, "postgres", response.CodePostgresUnavailable, func(ctx context.Context) error {
+		if err := postgresPool.Ping(ctx); err != nil {
+			return err
+		}
+		if !migrated.Load() {
+			if err := applyMigrations(ctx, migrator, logger); err != nil {
+				return err
+			}
+			migrated.Store(true)
+		}
+		return nil
+	})
+
+	redisClient := client.OpenRedisClient(cfg.Redis)
 	defer func() {
 		_ = redisClient.Close()
 	}()
+	redisDep := client.NewDependency(logger, "redis", response.CodeRedisUnavailable, func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	})
+
+	metrics.Registry.MustRegister(
+		metrics.NewPostgresCollector(postgresPool),
+		metrics.NewRedisCollector(redisClient),
+	)
+
+	depCtx, stopDeps := context.WithCancel(context.Background())
+	defer stopDeps()
+	go postgresDep.Run(depCtx)
+	go redisDep.Run(depCtx)
+
+	// 为每个集群启动 namespace、pod informer，就绪检查依赖其同步状态
+	informerCtx, stopInformers := context.WithCancel(context.Background())
+	defer stopInformers()
+	informers := informer.NewManager(logger, clusters)
+
+	// 列表接口缓存，informer 收到 watch 事件时失效
+	listCache := cache.New(logger, redisClient, redisDep, clusters, cfg.Cache)
+	informers.OnChange(listCache.OnChange)
+	go listCache.Run(depCtx)
+
+	// 按路由组和用户的分布式限流，配额由所有副本共享
+	limiter := ratelimit.NewLimiter(logger, redisClient, redisDep, cfg.RateLimit)
+
+	// 后台任务只在主节点上运行，任务通过 elector.Register 注册
+	elector := leader.NewElector(logger, cfg.LeaderElection, clusters, redisClient)
+
+	informers.Start(informerCtx)
+	go clusters.Run(depCtx, informers.Sync)
+
+	// 配置文件或 kubeconfig 变化时热加载，无需重启
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go newConfigReloader(logger, os.Args[1:], cfg, clusters, informers, limiter).Run(reloadCtx)
 
 	// 3. 初始化 Repository 层
-	namespaceRepo := repository.NewNamespaceRepository(k8sClient)
-	podRepo := repository.NewPodRepository(k8sClient)
+	namespaceRepo := repository.NewNamespaceRepository(clusters)
+	podRepo := repository.NewPodRepository(clusters)
+	serviceRepo := repository.NewServiceRepository(clusters)
+	nodeRepo := repository.NewNodeRepository(clusters)
+	metricsRepo := repository.NewMetricsRepository(clusters)
+	deploymentRepo := repository.NewDeploymentRepository(clusters)
+	eventRepo := repository.NewEventRepository(clusters)
+	datasourceRepo := repository.NewDatasourceRepository(postgresPool)
+	inspectionRepo := repository.NewInspectionRepository(postgresPool)
+	auditRepo := repository.NewAuditRepository(postgresPool)
+	jenkinsRepo := repository.NewJenkinsRepository(postgresPool)
+	argocdRepo := repository.NewArgoCDRepository(postgresPool)
+	notificationRepo := repository.NewNotificationRepository(postgresPool)
+	authzRepo := repository.NewAuthorizationRepository(clusters)
 
 	// 4. 初始化 Service 层
-	namespaceService := service.NewNamespaceService(namespaceRepo)
-	podService := service.NewPodService(podRepo)
+	namespaceService := service.NewNamespaceService(namespaceRepo, listCache)
+	podService := service.NewPodService(podRepo, metricsRepo, listCache)
+	nodeService := service.NewNodeService(nodeRepo, metricsRepo)
+	logService := service.NewLogService(logsearch.New(cfg.Logs), cfg.Logs)
+	notificationService := service.NewNotificationService(notificationRepo, redisClient, redisDep, clusters, nodeRepo, deploymentRepo, cfg.Notification)
+	inspectionService := service.NewInspectionService(clusters, podRepo, deploymentRepo, nodeRepo, inspectionRepo, inspection.NewEngine(),
+		notificationService, cfg.Inspection)
+	monitoringService := service.NewMonitoringService(datasourceRepo, clusters)
+	llmClient := llm.New(cfg.LLM)
+	explainService := service.NewExplainService(clusters, podRepo, eventRepo, llmClient, redisClient, redisDep, cfg.LLM)
+	assistantService := service.NewAssistantService(clusters, namespaceRepo, podRepo, deploymentRepo, eventRepo, metricsRepo,
+		monitoringService, authzRepo, auditRepo, llmClient, redisClient, redisDep, cfg.LLM)
+	auditService := service.NewAuditService(auditRepo)
+	jenkinsService := service.NewJenkinsService(jenkinsRepo, auditRepo, cfg.Jenkins)
+	argocdService := service.NewArgoCDService(argocdRepo, auditRepo, clusters)
+	portForwardService := service.NewPortForwardService(clusters, podRepo, serviceRepo, auditRepo, cfg.PortForward)
+	fileService := service.NewFileService(clusters, podRepo, auditRepo, cfg.FileTransfer)
+	terminalService := service.NewTerminalService(clusters, podRepo, auditRepo)
+	debugService := service.NewDebugService(clusters, podRepo, auditRepo, cfg.Debug)
+
+	// 注册后台任务后再参选，成为主节点后运行
+	elector.Register("inspection-scheduler", inspectionService.RunSchedules)
+	elector.Register("notification-watcher", notificationService.Watch)
+	// 通知发送 worker 在所有副本上运行，发送本副本产生的通知
+	go notificationService.Run(depCtx)
+	electorCtx, stopElector := context.WithCancel(context.Background())
+	defer stopElector()
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		elector.Run(electorCtx)
+	}()
 
 	// 5. 初始化 Handler 层
-	namespaceHandler := handler.NewNamespaceHandler(namespaceService)
-	podHandler := handler.NewPodHandler(podService)
-	healthHandler := handler.NewHealthHandler(postgresPool, redisClient)
+	checkers := newHealthCheckers(postgresDep, redisDep, clusters, informers, migrator, elector)
+	handlers := routeHandlers{
+		namespace:  handler.NewNamespaceHandler(namespaceService),
+		pod:        handler.NewPodHandler(podService),
+		forward:    handler.NewPortForwardHandler(portForwardService),
+		file:       handler.NewFileHandler(fileService),
+		terminal:   handler.NewTerminalHandler(terminalService),
+		debug:      handler.NewDebugHandler(debugService),
+		node:       handler.NewNodeHandler(nodeService),
+		monitoring: handler.NewMonitoringHandler(monitoringService),
+		log:        handler.NewLogHandler(logService),
+		inspection: handler.NewInspectionHandler(inspectionService),
+		explain:    handler.NewExplainHandler(explainService),
+		assistant:  handler.NewAssistantHandler(assistantService),
+		audit:      handler.NewAuditHandler(auditService),
+		jenkins:    handler.NewJenkinsHandler(jenkinsService),
+		argocd:     handler.NewArgoCDHandler(argocdService),
+		notify:     handler.NewNotificationHandler(notificationService),
+		health:     handler.NewHealthHandler(checkers.liveness, checkers.readiness, checkers.startup),
+	}
 
 	// 6. 配置路由
-	router := setupRouter(namespaceHandler, podHandler, healthHandler, cfg.Env, logger)
+	router := setupRouter(handlers, postgresDep, auth.NewAuthenticator(cfg.Auth), limiter, cfg.Env, logger)
 
 	// 7. 启动 HTTP 服务器
 	srv := &http.Server{
@@ -97,13 +246,42 @@ func main() {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
+	// 停止后台任务并释放主节点身份，其他副本无需等待 Lease 过期
+	stopElector()
+	select {
+	case <-electorDone:
+	case <-ctx.Done():
+	}
+
 	logger.Info("Server exited")
 }
 
+// routeHandlers 各模块的 Handler，由 main 组装后传给 setupRouter
+type routeHandlers struct {
+	namespace  *handler.NamespaceHandler
+	pod        *handler.PodHandler
+	forward    *handler.PortForwardHandler
+	file       *handler.FileHandler
+	terminal   *handler.TerminalHandler
+	debug      *handler.DebugHandler
+	node       *handler.NodeHandler
+	monitoring *handler.MonitoringHandler
+	log        *handler.LogHandler
+	inspection *handler.InspectionHandler
+	explain    *handler.ExplainHandler
+	assistant  *handler.AssistantHandler
+	audit      *handler.AuditHandler
+	jenkins    *handler.JenkinsHandler
+	argocd     *handler.ArgoCDHandler
+	notify     *handler.NotificationHandler
+	health     *handler.HealthHandler
+}
+
 func setupRouter(
-	namespaceHandler *handler.NamespaceHandler,
-	podHandler *handler.PodHandler,
-	healthHandler *handler.HealthHandler,
+	h routeHandlers,
+	postgresDep *client.Dependency,
+	authenticator *auth.Authenticator,
+	limiter *ratelimit.Limiter,
 	env string,
 	logger *zap.Logger,
 ) *gin.Engine {
@@ -111,98 +289,159 @@ func setupRouter(
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router := gin.Default()
-
-	router.GET("/health", healthHandler.Health)
+	// 使用 zap 输出 JSON 访问日志并记录 panic 堆栈，替代 gin.Default() 的纯文本日志
+	router := gin.New()
+	router.Use(
+		middleware.RequestID(),
+		tracing.HTTPMiddleware(),
+		metrics.HTTPMiddleware(),
+		middleware.Logger(logger),
+		middleware.Recovery(),
+	)
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(func(c *gin.Context) {
+		response.NotFound(c, "接口不存在")
+	})
+	router.NoMethod(func(c *gin.Context) {
+		response.Error(c, response.NewError(http.StatusMethodNotAllowed, response.CodeMethodNotAllowed, "不支持的请求方法", nil))
+	})
+
+	// 探针：/livez 存活，/readyz 就绪，/startupz 启动完成；均支持 ?verbose 输出检查明细
+	router.GET("/livez", h.health.Livez)
+	router.GET("/readyz", h.health.Readyz)
+	router.GET("/startupz", h.health.Startupz)
+	router.GET("/health", h.health.Health)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	// API v1 路由组
 	v1 := router.Group("/api/v1")
+	v1.Use(middleware.Auth(authenticator), middleware.RateLimit(limiter), middleware.CacheControl())
 	{
 		// 测试端点
 		v1.GET("/ping", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "pong"})
+			response.Success(c, "pong")
 		})
 
+		// Kubernetes 资源接口通过 ?cluster=<name> 指定集群，缺省使用第一个配置的集群
+
 		// 命名空间相关路由
-		v1.GET("/namespaces", namespaceHandler.ListNamespaces)
-		v1.GET("/namespaces/:name", namespaceHandler.GetNamespace)
+		v1.GET("/namespaces", h.namespace.ListNamespaces)
+		v1.GET("/namespaces/:namespace", h.namespace.GetNamespace)
 
 		// Pod 相关路由
-		v1.GET("/namespaces/:namespace/pods", podHandler.ListPods)
-		v1.GET("/namespaces/:namespace/pods/:name", podHandler.GetPod)
-		v1.GET("/pods", podHandler.ListAllPods)
+		v1.GET("/namespaces/:namespace/pods", h.pod.ListPods)
+		v1.GET("/namespaces/:namespace/pods/:name", h.pod.GetPod)
+		v1.GET("/pods", h.pod.ListAllPods)
+		// 根因分析：收集上下文脱敏后交给大模型（llm.provider）
+		v1.POST("/namespaces/:namespace/pods/:name/explain", h.explain.ExplainPod)
+		// 端口转发：WebSocket 隧道到 Pod 端口，Service 转发到一个就绪的后端 Pod
+		v1.GET("/namespaces/:namespace/pods/:name/portforward", h.forward.PodPortForward)
+		v1.GET("/namespaces/:namespace/services/:name/portforward", h.forward.ServicePortForward)
+		// 文件上传下载：通过 exec 在容器中运行 tar，进度按传输 ID 查询
+		v1.GET("/namespaces/:namespace/pods/:name/cp", h.file.Download)
+		v1.POST("/namespaces/:namespace/pods/:name/cp", h.file.Upload)
+		v1.GET("/file-transfers/:id", h.file.GetTransfer)
+		// 终端：WebSocket 连接 exec/attach，二进制消息为终端输入输出
+		v1.GET("/namespaces/:namespace/pods/:name/exec", h.terminal.Exec)
+		v1.GET("/namespaces/:namespace/pods/:name/attach", h.terminal.Attach)
+		// 调试容器：临时容器共享目标容器的进程命名空间，或创建修改了命令的 Pod 副本，运行后通过 attach 连接
+		v1.POST("/namespaces/:namespace/pods/:name/debug", h.debug.CreateEphemeral)
+		v1.POST("/namespaces/:namespace/pods/:name/debug-copy", h.debug.CreateCopy)
+
+		// 节点相关路由
+		v1.GET("/nodes", h.node.ListNodes)
+		v1.GET("/nodes/:name", h.node.GetNode)
+
+		// 监控：数据源存储在 Postgres，查询代理到集群的 Prometheus 兼容数据源
+		monitoring := v1.Group("", middleware.RequireDependency(postgresDep))
+		monitoring.GET("/monitoring/datasources", h.monitoring.ListDatasources)
+		monitoring.POST("/monitoring/datasources", h.monitoring.CreateDatasource)
+		monitoring.GET("/monitoring/datasources/:id", h.monitoring.GetDatasource)
+		monitoring.PUT("/monitoring/datasources/:id", h.monitoring.UpdateDatasource)
+		monitoring.DELETE("/monitoring/datasources/:id", h.monitoring.DeleteDatasource)
+		monitoring.GET("/monitoring/query", h.monitoring.Query)
+		monitoring.POST("/monitoring/query", h.monitoring.Query)
+		monitoring.GET("/monitoring/query_range", h.monitoring.QueryRange)
+		monitoring.POST("/monitoring/query_range", h.monitoring.QueryRange)
+		monitoring.GET("/monitoring/series", h.monitoring.Series)
+		monitoring.POST("/monitoring/series", h.monitoring.Series)
+		monitoring.GET("/monitoring/labels", h.monitoring.Labels)
+		monitoring.POST("/monitoring/labels", h.monitoring.Labels)
+		monitoring.GET("/namespaces/:namespace/pods/:name/metrics", h.monitoring.PodMetrics)
+
+		// 日志检索：后端由 logs.backend 选择 Loki 或 Elasticsearch
+		v1.GET("/logs", h.log.Search)
+		v1.GET("/logs/tail", h.log.Tail)
+
+		// 集群巡检：基于规则的确定性检查，巡检记录、定时巡检和确认规则存储在 Postgres
+		v1.GET("/inspections/checks", h.inspection.ListChecks)
+		inspections := v1.Group("/inspections", middleware.RequireDependency(postgresDep))
+		inspections.POST("", h.inspection.Inspect)
+		inspections.GET("/runs", h.inspection.ListRuns)
+		inspections.GET("/runs/:id", h.inspection.GetRun)
+		inspections.GET("/runs/:id/diff", h.inspection.GetRunDiff)
+		inspections.GET("/schedules", h.inspection.ListSchedules)
+		inspections.POST("/schedules", h.inspection.CreateSchedule)
+		inspections.GET("/schedules/:id", h.inspection.GetSchedule)
+		inspections.PUT("/schedules/:id", h.inspection.UpdateSchedule)
+		inspections.DELETE("/schedules/:id", h.inspection.DeleteSchedule)
+		inspections.GET("/suppressions", h.inspection.ListSuppressions)
+		inspections.POST("/suppressions", h.inspection.CreateSuppression)
+		inspections.DELETE("/suppressions/:id", h.inspection.DeleteSuppression)
+
+		// 集群问答助手：模型只能调用只读工具，每次调用校验用户权限并写入审计日志，过程以 SSE 推送
+		v1.GET("/assistant/tools", h.assistant.ListTools)
+		v1.POST("/assistant/chat", middleware.RequireDependency(postgresDep), h.assistant.Chat)
+
+		// 审计日志
+		v1.GET("/audit-logs", middleware.RequireDependency(postgresDep), h.audit.ListAuditLogs)
+
+		// Jenkins：服务器地址和凭据存储在 Postgres，其余接口代理到 Jenkins REST API
+		jenkins := v1.Group("/jenkins/servers", middleware.RequireDependency(postgresDep))
+		jenkins.GET("", h.jenkins.ListServers)
+		jenkins.POST("", h.jenkins.CreateServer)
+		jenkins.GET("/:id", h.jenkins.GetServer)
+		jenkins.PUT("/:id", h.jenkins.UpdateServer)
+		jenkins.DELETE("/:id", h.jenkins.DeleteServer)
+		jenkins.POST("/:id/test", h.jenkins.TestServer)
+		jenkins.GET("/:id/jobs", h.jenkins.ListJobs)
+		jenkins.GET("/:id/job", h.jenkins.GetJob)
+		jenkins.POST("/:id/job/build", h.jenkins.TriggerBuild)
+		jenkins.GET("/:id/queue/:queueId", h.jenkins.GetQueueItem)
+		jenkins.GET("/:id/builds/:number", h.jenkins.GetBuild)
+		jenkins.GET("/:id/builds/:number/log", h.jenkins.GetLog)
+		jenkins.GET("/:id/builds/:number/log/stream", h.jenkins.StreamLog)
+
+		// ArgoCD：实例地址和 Token 存储在 Postgres，其余接口代理到 ArgoCD REST API
+		argo := v1.Group("/argocd/servers", middleware.RequireDependency(postgresDep))
+		argo.GET("", h.argocd.ListServers)
+		argo.POST("", h.argocd.CreateServer)
+		argo.GET("/:id", h.argocd.GetServer)
+		argo.PUT("/:id", h.argocd.UpdateServer)
+		argo.DELETE("/:id", h.argocd.DeleteServer)
+		argo.POST("/:id/test", h.argocd.TestServer)
+		argo.GET("/:id/applications", h.argocd.ListApplications)
+		argo.GET("/:id/applications/:name", h.argocd.GetApplication)
+		argo.GET("/:id/applications/:name/diff", h.argocd.Diff)
+		argo.GET("/:id/applications/:name/workloads", h.argocd.Workloads)
+		argo.POST("/:id/applications/:name/sync", h.argocd.Sync)
+		argo.POST("/:id/applications/:name/rollback", h.argocd.Rollback)
+
+		// 通知：渠道和路由规则存储在 Postgres，去重和限流依赖 Redis（不可用时不去重）
+		notifications := v1.Group("/notifications", middleware.RequireDependency(postgresDep))
+		notifications.GET("/channels", h.notify.ListChannels)
+		notifications.POST("/channels", h.notify.CreateChannel)
+		notifications.GET("/channels/:id", h.notify.GetChannel)
+		notifications.PUT("/channels/:id", h.notify.UpdateChannel)
+		notifications.DELETE("/channels/:id", h.notify.DeleteChannel)
+		notifications.POST("/channels/:id/test", h.notify.TestChannel)
+		notifications.GET("/rules", h.notify.ListRules)
+		notifications.POST("/rules", h.notify.CreateRule)
+		notifications.GET("/rules/:id", h.notify.GetRule)
+		notifications.PUT("/rules/:id", h.notify.UpdateRule)
+		notifications.DELETE("/rules/:id", h.notify.DeleteRule)
 	}
 
 	logger.Info("Routes registered successfully")
 	return router
 }
-
-func loadConfig() config.Config {
-	cfg := config.Load()
-
-	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
-	fs.SetOutput(os.Stdout)
-
-	fs.StringVar(&cfg.Port, "port", cfg.Port, "服务端口")
-	fs.StringVar(&cfg.Env, "env", cfg.Env, "运行环境")
-	fs.StringVar(&cfg.Kubeconfig, "kubeconfig", cfg.Kubeconfig, "kubeconfig 文件路径")
-	fs.StringVar(&cfg.Postgres.Host, "postgres-host", cfg.Postgres.Host, "PostgreSQL 地址")
-	fs.StringVar(&cfg.Postgres.Port, "postgres-port", cfg.Postgres.Port, "PostgreSQL 端口")
-	fs.StringVar(&cfg.Postgres.User, "postgres-user", cfg.Postgres.User, "PostgreSQL 用户")
-	fs.StringVar(&cfg.Postgres.Password, "postgres-password", cfg.Postgres.Password, "PostgreSQL 密码")
-	fs.StringVar(&cfg.Postgres.Database, "postgres-db", cfg.Postgres.Database, "PostgreSQL 数据库")
-	fs.StringVar(&cfg.Postgres.SSLMode, "postgres-sslmode", cfg.Postgres.SSLMode, "PostgreSQL SSL 模式")
-	fs.StringVar(&cfg.Redis.Addr, "redis-addr", cfg.Redis.Addr, "Redis 地址")
-	fs.StringVar(&cfg.Redis.Password, "redis-password", cfg.Redis.Password, "Redis 密码")
-	fs.IntVar(&cfg.Redis.DB, "redis-db", cfg.Redis.DB, "Redis DB 编号")
-
-	fs.Usage = func() {
-		_, _ = fmt.Fprintln(os.Stdout, "KubeOps 后端服务")
-		_, _ = fmt.Fprintln(os.Stdout, "")
-		_, _ = fmt.Fprintln(os.Stdout, "用法:")
-		_, _ = fmt.Fprintf(os.Stdout, "  %s [options]\n", os.Args[0])
-		_, _ = fmt.Fprintln(os.Stdout, "")
-		_, _ = fmt.Fprintln(os.Stdout, "选项:")
-		fs.PrintDefaults()
-		_, _ = fmt.Fprintln(os.Stdout, "")
-		_, _ = fmt.Fprintln(os.Stdout, "示例:")
-		_, _ = fmt.Fprintf(os.Stdout, "  %s --postgres-host 192.168.33.100 --postgres-port 5432 --postgres-user kubeops \\\n", os.Args[0])
-		_, _ = fmt.Fprintln(os.Stdout, "    --postgres-password kubeops --postgres-db kubeops --redis-addr 192.168.33.100:6379")
-	}
-
-	if err := fs.Parse(os.Args[1:]); err != nil {
-		os.Exit(2)
-	}
-
-	missing := requiredConfigMissing(cfg)
-	if len(missing) > 0 {
-		_, _ = fmt.Fprintln(os.Stdout, "缺少必要参数:", strings.Join(missing, ", "))
-		fs.Usage()
-		os.Exit(2)
-	}
-
-	return cfg
-}
-
-func requiredConfigMissing(cfg config.Config) []string {
-	var missing []string
-	if cfg.Postgres.Host == "" {
-		missing = append(missing, "--postgres-host/POSTGRES_HOST")
-	}
-	if cfg.Postgres.Port == "" {
-		missing = append(missing, "--postgres-port/POSTGRES_PORT")
-	}
-	if cfg.Postgres.User == "" {
-		missing = append(missing, "--postgres-user/POSTGRES_USER")
-	}
-	if cfg.Postgres.Password == "" {
-		missing = append(missing, "--postgres-password/POSTGRES_PASSWORD")
-	}
-	if cfg.Postgres.Database == "" {
-		missing = append(missing, "--postgres-db/POSTGRES_DB")
-	}
-	if cfg.Redis.Addr == "" {
-		missing = append(missing, "--redis-addr/REDIS_ADDR")
-	}
-	return missing
-}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/migrate"
+)
+
+// applyMigrations 服务启动时应用所有未执行的迁移
+// ctx 为 Postgres 连接探测的 context：服务退出时随之取消，超时（如其他副本持有迁移锁）则在下次重连时重试
+func applyMigrations(ctx context.Context, migrator *migrate.Migrator, logger *zap.Logger) error {
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Info("Database migrations applied", zap.Int("count", applied))
+	return nil
+}
+
+// runMigrate 处理 kubeops migrate up|down [N]|status 子命令
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "用法: kubeops migrate up|down [N]|status [options]")
+		return 2
+	}
+	action, rest := args[0], args[1:]
+
+	steps := 1
+	if action == "down" && len(rest) > 0 {
+		if n, err := strconv.Atoi(rest[0]); err == nil {
+			if n < 1 {
+				_, _ = fmt.Fprintln(os.Stdout, "回滚步数必须大于 0")
+				return 2
+			}
+			steps = n
+			rest = rest[1:]
+		}
+	}
+
+	logger, _ := zap.NewProduction()
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	cfg := loadConfig(rest, func(cfg config.Config) error { return cfg.Postgres.Validate() })
+
+	pool, err := client.NewPostgresPool(cfg.Postgres)
+	if err != nil {
+		logger.Error("Failed to initialize Postgres", zap.Error(err))
+		return 1
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool, logger)
+	if err != nil {
+		logger.Error("Failed to load migrations", zap.Error(err))
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			logger.Error("Migration failed", zap.Error(err))
+			return 1
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "已应用 %d 个迁移\n", applied)
+	case "down":
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			logger.Error("Rollback failed", zap.Error(err))
+			return 1
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "已回滚 %d 个迁移\n", rolledBack)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Error("Failed to read migration status", zap.Error(err))
+			return 1
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(os.Stdout, "%04d  %-40s %s\n", st.Version, st.Name, state)
+		}
+	default:
+		_, _ = fmt.Fprintf(os.Stdout, "未知的 migrate 操作: %s（可选 up、down、status）\n", action)
+		return 2
+	}
+	return 0
+}
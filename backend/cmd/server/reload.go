@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/informer"
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+	"github.com/yansongwel/kubeops/backend/internal/ratelimit"
+	"github.com/yansongwel/kubeops/backend/internal/reload"
+)
+
+// configReloader 监听配置文件和 kubeconfig 变化，重新加载配置并原子替换受影响的集群客户端
+// 正在处理的请求继续使用替换前的客户端，不受影响
+type configReloader struct {
+	logger    *zap.Logger
+	args      []string
+	current   config.Config
+	clusters  *client.ClusterManager
+	informers *informer.Manager
+	limiter   *ratelimit.Limiter
+}
+
+func newConfigReloader(
+	logger *zap.Logger,
+	args []string,
+	cfg config.Config,
+	clusters *client.ClusterManager,
+	informers *informer.Manager,
+	limiter *ratelimit.Limiter,
+) *configReloader {
+	return &configReloader{
+		logger:    logger,
+		args:      args,
+		current:   cfg,
+		clusters:  clusters,
+		informers: informers,
+		limiter:   limiter,
+	}
+}
+
+// Run 阻塞运行直到 ctx 取消
+func (r *configReloader) Run(ctx context.Context) {
+	watcher := reload.NewWatcher(r.logger, time.Second, r.watchedPaths, r.reload)
+	if err := watcher.Run(ctx); err != nil {
+		r.logger.Error("Config watcher stopped", zap.Error(err))
+	}
+}
+
+func (r *configReloader) watchedPaths() []string {
+	return append([]string{r.current.File}, r.clusters.KubeconfigPaths()...)
+}
+
+func (r *configReloader) reload() {
+	// 与启动时相同的 文件 < 环境变量 < 命令行参数 优先级
+	cfg, err := parseConfig(r.args)
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		r.fail("Invalid configuration, keeping previous", err)
+		return
+	}
+
+	changed, err := r.clusters.Reload(cfg.ClusterList())
+	if err != nil {
+		r.fail("Failed to rebuild cluster clients, keeping previous", err)
+		return
+	}
+	if len(changed) > 0 {
+		r.informers.Sync()
+	}
+	r.limiter.SetConfig(cfg.RateLimit)
+
+	// 只有集群和限流配置热加载，其余配置段的修改在重启前不生效
+	// 重启前 r.current 保留这些配置段的旧值，每次热加载都会再次提示，直到重启
+	pending := cfg.RestartRequired(r.current)
+	r.current.Kubeconfig = cfg.Kubeconfig
+	r.current.Clusters = cfg.Clusters
+	r.current.RateLimit = cfg.RateLimit
+
+	metrics.ConfigLastReloadSuccess.SetToCurrentTime()
+	if len(pending) > 0 {
+		metrics.ConfigReloads.WithLabelValues("partial").Inc()
+		r.logger.Warn("Configuration partially reloaded, some sections take effect after restart",
+			zap.String("config", cfg.File),
+			zap.Strings("changedClusters", changed),
+			zap.Strings("restartRequired", pending),
+		)
+		return
+	}
+	metrics.ConfigReloads.WithLabelValues("success").Inc()
+	r.logger.Info("Configuration reloaded",
+		zap.String("config", cfg.File),
+		zap.Strings("changedClusters", changed),
+	)
+}
+
+func (r *configReloader) fail(msg string, err error) {
+	metrics.ConfigReloads.WithLabelValues("failure").Inc()
+	r.logger.Error(msg, zap.Error(err))
+}
@@ -0,0 +1,210 @@
+package archive
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrTooLarge 文件内容的总大小超过限制
+var ErrTooLarge = errors.New("archive exceeds size limit")
+
+// EntryError 压缩包中的条目不安全，如绝对路径、包含 .. 或指向根目录之外的链接
+type EntryError struct {
+	Name   string
+	Reason string
+}
+
+func (e *EntryError) Error() string {
+	return fmt.Sprintf("unsafe tar entry %q: %s", e.Name, e.Reason)
+}
+
+// Progress 已复制的条目数和文件内容字节数
+type Progress struct {
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+	Current string `json:"current,omitempty"` // 正在复制的条目
+}
+
+// Options 复制选项
+type Options struct {
+	MaxBytes   int64          // 文件内容的总字节数上限，0 表示不限制
+	Root       string         // 非空时所有条目必须是 Root 本身或位于 Root 之下
+	OnProgress func(Progress) // 每写入一块数据或一个条目后调用
+}
+
+// Copy 逐条读取 src 中的条目，校验后写入 dst，返回复制的统计；不写入 dst 的结束标记，由调用方在成功后 Close
+// 条目名清理为相对路径；拒绝绝对路径、.. 、指向根目录之外的符号链接或硬链接，以及经过包内符号链接的路径；
+// 设备文件、FIFO 等特殊文件被跳过；权限只保留 rwx 位，不保留属主和 setuid/setgid
+// 与 kubectl cp 解包时的检查一致，读写两端都不需要落盘
+func Copy(dst *tar.Writer, src *tar.Reader, opts Options) (Progress, error) {
+	var p Progress
+	buf := make([]byte, 32*1024)
+	symlinks := make(map[string]bool)
+	for {
+		hdr, err := src.Next()
+		if errors.Is(err, io.EOF) {
+			return p, nil
+		}
+		if err != nil {
+			return p, fmt.Errorf("failed to read tar entry: %w", err)
+		}
+
+		out, err := sanitize(hdr, opts.Root)
+		if err != nil {
+			return p, err
+		}
+		if out == nil {
+			continue
+		}
+		// 链接目标只按字面校验，经过包内符号链接的条目实际位置可能在根目录之外
+		for dir := path.Dir(out.Name); dir != "."; dir = path.Dir(dir) {
+			if symlinks[dir] {
+				return p, &EntryError{Name: hdr.Name, Reason: "path traverses symlink " + dir}
+			}
+		}
+		switch {
+		case out.Typeflag == tar.TypeSymlink:
+			symlinks[out.Name] = true
+		case out.Typeflag == tar.TypeLink && symlinks[out.Linkname]:
+			// 硬链接到符号链接会在另一个位置复制出相同的链接目标
+			return p, &EntryError{Name: hdr.Name, Reason: "hard link to symlink " + out.Linkname}
+		}
+		if opts.MaxBytes > 0 && p.Bytes+out.Size > opts.MaxBytes {
+			return p, ErrTooLarge
+		}
+
+		p.Current = out.Name
+		if err := dst.WriteHeader(out); err != nil {
+			return p, fmt.Errorf("failed to write tar header %q: %w", out.Name, err)
+		}
+		if out.Typeflag == tar.TypeReg {
+			if err := copyData(dst, src, out.Size, buf, &p, opts.OnProgress); err != nil {
+				return p, fmt.Errorf("failed to copy %q: %w", out.Name, err)
+			}
+		}
+		p.Entries++
+		if opts.OnProgress != nil {
+			opts.OnProgress(p)
+		}
+	}
+}
+
+// WriteFile 把 r 中 size 字节的内容作为名为 name 的普通文件写入 dst，用于上传单个文件
+func WriteFile(dst *tar.Writer, name string, size int64, r io.Reader, onProgress func(Progress)) (Progress, error) {
+	p := Progress{Current: name}
+	hdr := &tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: size, ModTime: time.Now()}
+	if err := dst.WriteHeader(hdr); err != nil {
+		return p, fmt.Errorf("failed to write tar header %q: %w", name, err)
+	}
+	if err := copyData(dst, r, size, make([]byte, 32*1024), &p, onProgress); err != nil {
+		return p, fmt.Errorf("failed to copy %q: %w", name, err)
+	}
+	p.Entries++
+	if onProgress != nil {
+		onProgress(p)
+	}
+	return p, nil
+}
+
+// copyData 复制一个文件的内容，每块数据后报告进度；内容比头部声明的短时返回 io.ErrUnexpectedEOF
+func copyData(dst io.Writer, src io.Reader, size int64, buf []byte, p *Progress, onProgress func(Progress)) error {
+	for size > 0 {
+		n, err := src.Read(buf[:min(int64(len(buf)), size)])
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			size -= int64(n)
+			p.Bytes += int64(n)
+			if onProgress != nil {
+				onProgress(*p)
+			}
+		}
+		if errors.Is(err, io.EOF) && size > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+	return nil
+}
+
+// sanitize 校验条目并生成要写出的头部，返回 nil 表示跳过该条目
+func sanitize(hdr *tar.Header, root string) (*tar.Header, error) {
+	// tar cf - . 生成的 ./ 条目即解包目录本身
+	if hdr.Typeflag == tar.TypeDir && path.Clean(hdr.Name) == "." && root == "" {
+		return nil, nil
+	}
+	name, err := cleanName(hdr.Name, root)
+	if err != nil {
+		return nil, err
+	}
+	out := &tar.Header{
+		Name:    name,
+		Mode:    hdr.Mode & 0o777,
+		ModTime: hdr.ModTime,
+	}
+
+	switch hdr.Typeflag {
+	case tar.TypeReg:
+		out.Typeflag = tar.TypeReg
+		out.Size = hdr.Size
+	case tar.TypeDir:
+		out.Typeflag = tar.TypeDir
+		out.Name += "/"
+	case tar.TypeSymlink:
+		// 相对链接按所在目录解析，不能指向根目录之外；绝对链接在容器中的含义与本地不同，一律拒绝
+		if path.IsAbs(hdr.Linkname) || !inside(path.Join(path.Dir(name), hdr.Linkname), root) {
+			return nil, &EntryError{Name: hdr.Name, Reason: "symlink target escapes the archive root: " + hdr.Linkname}
+		}
+		out.Typeflag = tar.TypeSymlink
+		out.Linkname = hdr.Linkname
+	case tar.TypeLink:
+		target, err := cleanName(hdr.Linkname, root)
+		if err != nil {
+			return nil, &EntryError{Name: hdr.Name, Reason: "hard link target escapes the archive root: " + hdr.Linkname}
+		}
+		out.Typeflag = tar.TypeLink
+		out.Linkname = target
+	default:
+		return nil, nil
+	}
+	return out, nil
+}
+
+// cleanName 把条目名清理为不含 .. 的相对路径，root 非空时要求位于 root 之下
+func cleanName(name, root string) (string, error) {
+	if path.IsAbs(name) {
+		return "", &EntryError{Name: name, Reason: "absolute path"}
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == ".." {
+			return "", &EntryError{Name: name, Reason: "path contains .."}
+		}
+	}
+	cleaned := path.Clean(name)
+	if cleaned == "." {
+		return "", &EntryError{Name: name, Reason: "empty path"}
+	}
+	if !inside(cleaned, root) {
+		return "", &EntryError{Name: name, Reason: "outside of " + root}
+	}
+	return cleaned, nil
+}
+
+// inside 判断已清理的相对路径是否位于 root 之下，root 为空时表示解包目录
+func inside(name, root string) bool {
+	if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+		return false
+	}
+	if root == "" {
+		return true
+	}
+	return name == root || strings.HasPrefix(name, root+"/")
+}
@@ -0,0 +1,203 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// entry 测试用的 tar 条目，Body 为普通文件的内容
+type entry struct {
+	Name     string
+	Type     byte
+	Linkname string
+	Mode     int64
+	Body     string
+}
+
+// buildTar 在内存中生成 tar 包
+func buildTar(t *testing.T, entries ...entry) *tar.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		mode := e.Mode
+		if mode == 0 {
+			mode = 0o644
+		}
+		hdr := &tar.Header{Name: e.Name, Typeflag: e.Type, Linkname: e.Linkname, Mode: mode, Size: int64(len(e.Body))}
+		if e.Type != tar.TypeReg {
+			hdr.Size = 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header %q: %v", e.Name, err)
+		}
+		if e.Type == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.Body)); err != nil {
+				t.Fatalf("write %q: %v", e.Name, err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	return tar.NewReader(&buf)
+}
+
+// copyAll 复制 src 并读回写出的条目
+func copyAll(t *testing.T, src *tar.Reader, opts Options) ([]*tar.Header, Progress, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	p, err := Copy(tw, src, opts)
+	if err != nil {
+		return nil, p, err
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	var headers []*tar.Header
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return headers, p, nil
+		}
+		if err != nil {
+			t.Fatalf("read copied tar: %v", err)
+		}
+		headers = append(headers, hdr)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	src := buildTar(t,
+		entry{Name: "./", Type: tar.TypeDir, Mode: 0o755},
+		entry{Name: "./conf/", Type: tar.TypeDir, Mode: 0o755},
+		entry{Name: "./conf/app.yaml", Type: tar.TypeReg, Mode: 0o4755, Body: "port: 8080\n"},
+		entry{Name: "conf/current", Type: tar.TypeSymlink, Linkname: "app.yaml"},
+		entry{Name: "conf/copy.yaml", Type: tar.TypeLink, Linkname: "./conf/app.yaml"},
+		entry{Name: "dev/null", Type: tar.TypeChar},
+		entry{Name: "fifo", Type: tar.TypeFifo},
+	)
+	var progress []Progress
+	headers, p, err := copyAll(t, src, Options{OnProgress: func(p Progress) { progress = append(progress, p) }})
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+
+	// ./ 被跳过，名称清理为相对路径，setuid 位去掉，设备文件和 FIFO 被跳过
+	want := []struct {
+		name     string
+		typ      byte
+		linkname string
+		mode     int64
+	}{
+		{"conf/", tar.TypeDir, "", 0o755},
+		{"conf/app.yaml", tar.TypeReg, "", 0o755},
+		{"conf/current", tar.TypeSymlink, "app.yaml", 0o644},
+		{"conf/copy.yaml", tar.TypeLink, "conf/app.yaml", 0o644},
+	}
+	if len(headers) != len(want) {
+		t.Fatalf("copied %d entries, want %d", len(headers), len(want))
+	}
+	for i, w := range want {
+		h := headers[i]
+		if h.Name != w.name || h.Typeflag != w.typ || h.Linkname != w.linkname || h.Mode != w.mode {
+			t.Errorf("entry %d = %q type %c link %q mode %o; want %q type %c link %q mode %o",
+				i, h.Name, h.Typeflag, h.Linkname, h.Mode, w.name, w.typ, w.linkname, w.mode)
+		}
+	}
+	if p.Entries != 4 || p.Bytes != int64(len("port: 8080\n")) {
+		t.Errorf("progress = %+v", p)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != p {
+		t.Errorf("last reported progress = %v, want %+v", progress, p)
+	}
+}
+
+func TestCopyRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		root    string
+		entries []entry
+	}{
+		{name: "absolute path", entries: []entry{{Name: "/etc/passwd", Type: tar.TypeReg}}},
+		{name: "parent directory", entries: []entry{{Name: "../escape", Type: tar.TypeReg}}},
+		{name: "parent directory in the middle", entries: []entry{{Name: "a/../../escape", Type: tar.TypeReg}}},
+		{name: "absolute symlink", entries: []entry{{Name: "link", Type: tar.TypeSymlink, Linkname: "/etc"}}},
+		{name: "symlink outside the root", entries: []entry{{Name: "a/link", Type: tar.TypeSymlink, Linkname: "../../etc"}}},
+		{name: "path through a symlink", entries: []entry{
+			{Name: "link", Type: tar.TypeSymlink, Linkname: "sub"},
+			{Name: "link/file", Type: tar.TypeReg, Body: "x"},
+		}},
+		{name: "path through a nested symlink", entries: []entry{
+			{Name: "a/link", Type: tar.TypeSymlink, Linkname: "."},
+			{Name: "a/link/b/file", Type: tar.TypeReg, Body: "x"},
+		}},
+		{name: "hard link to a symlink", entries: []entry{
+			{Name: "link", Type: tar.TypeSymlink, Linkname: "file"},
+			{Name: "other/hard", Type: tar.TypeLink, Linkname: "link"},
+		}},
+		{name: "hard link outside the root", entries: []entry{{Name: "hard", Type: tar.TypeLink, Linkname: "../etc/shadow"}}},
+		{name: "entry outside the root", root: "logs", entries: []entry{{Name: "etc/passwd", Type: tar.TypeReg}}},
+		{name: "sibling with the root as prefix", root: "logs", entries: []entry{{Name: "logs2/a", Type: tar.TypeReg}}},
+		{name: "symlink leaving the root", root: "logs", entries: []entry{{Name: "logs/link", Type: tar.TypeSymlink, Linkname: "../etc"}}},
+	}
+	for _, tt := range tests {
+		_, _, err := copyAll(t, buildTar(t, tt.entries...), Options{Root: tt.root})
+		var entryErr *EntryError
+		if !errors.As(err, &entryErr) {
+			t.Errorf("%s: Copy() error = %v, want *EntryError", tt.name, err)
+		}
+	}
+}
+
+func TestCopyRoot(t *testing.T) {
+	src := buildTar(t,
+		entry{Name: "logs/", Type: tar.TypeDir, Mode: 0o755},
+		entry{Name: "logs/app.log", Type: tar.TypeReg, Body: "ok"},
+		entry{Name: "logs/current", Type: tar.TypeSymlink, Linkname: "app.log"},
+	)
+	headers, _, err := copyAll(t, src, Options{Root: "logs"})
+	if err != nil || len(headers) != 3 {
+		t.Errorf("Copy() = %d entries, %v; want 3 entries", len(headers), err)
+	}
+}
+
+func TestCopyMaxBytes(t *testing.T) {
+	entries := []entry{
+		{Name: "a", Type: tar.TypeReg, Body: "12345"},
+		{Name: "b", Type: tar.TypeReg, Body: "67890"},
+	}
+	if _, _, err := copyAll(t, buildTar(t, entries...), Options{MaxBytes: 10}); err != nil {
+		t.Errorf("Copy() at the limit error = %v", err)
+	}
+	// 超过限制时在写入超限条目之前停止
+	_, p, err := copyAll(t, buildTar(t, entries...), Options{MaxBytes: 9})
+	if !errors.Is(err, ErrTooLarge) || p.Entries != 1 || p.Bytes != 5 {
+		t.Errorf("Copy() = %+v, %v; want ErrTooLarge after the first entry", p, err)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if _, err := WriteFile(tw, "app.yaml", 4, bytes.NewReader([]byte("port")), nil); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	_ = tw.Close()
+	tr := tar.NewReader(&buf)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != "app.yaml" || hdr.Size != 4 {
+		t.Fatalf("header = %+v, %v", hdr, err)
+	}
+
+	// 请求体比声明的短
+	tw = tar.NewWriter(io.Discard)
+	if _, err := WriteFile(tw, "short", 10, bytes.NewReader([]byte("port")), nil); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("WriteFile() short body error = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,228 @@
+package argocd
+
+import (
+	"time"
+)
+
+// Source Application 的清单来源
+type Source struct {
+	RepoURL        string `json:"repoURL"`
+	Path           string `json:"path,omitempty"`
+	Chart          string `json:"chart,omitempty"`
+	TargetRevision string `json:"targetRevision,omitempty"`
+}
+
+// Destination Application 部署的目标集群和命名空间
+type Destination struct {
+	Server    string `json:"server,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Status 同步或健康状态
+type Status struct {
+	Status   string `json:"status"`             // 同步：Synced、OutOfSync、Unknown；健康：Healthy、Progressing、Degraded、Suspended、Missing、Unknown
+	Revision string `json:"revision,omitempty"` // 同步状态对应的 Git 提交或 Chart 版本
+	Message  string `json:"message,omitempty"`
+}
+
+// Operation 最近一次同步或回滚操作
+type Operation struct {
+	Phase      string     `json:"phase"` // Running、Succeeded、Failed、Error、Terminating
+	Message    string     `json:"message,omitempty"`
+	Revision   string     `json:"revision,omitempty"`
+	DryRun     bool       `json:"dryRun,omitempty"`
+	Prune      bool       `json:"prune,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Application ArgoCD Application 的摘要
+type Application struct {
+	Name         string      `json:"name"`
+	Namespace    string      `json:"namespace"` // Application 资源所在的命名空间
+	Project      string      `json:"project"`
+	Source       Source      `json:"source"` // 多来源 Application 为第一个来源
+	Destination  Destination `json:"destination"`
+	Cluster      string      `json:"cluster,omitempty"` // 目标集群对应的 KubeOps 集群，由调用方填充，无法对应时为空
+	AutoSync     bool        `json:"autoSync"`
+	Sync         Status      `json:"sync"`
+	Health       Status      `json:"health"`
+	Operation    *Operation  `json:"operation,omitempty"`
+	ReconciledAt *time.Time  `json:"reconciledAt,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+// ResourceStatus Application 管理的一个资源的状态
+type ResourceStatus struct {
+	ResourceRef
+	Version         string  `json:"version"`
+	Status          string  `json:"status"` // Synced 或 OutOfSync
+	Health          *Status `json:"health,omitempty"`
+	RequiresPruning bool    `json:"requiresPruning,omitempty"`
+}
+
+// HistoryEntry 一次部署记录，ID 用于回滚
+type HistoryEntry struct {
+	ID         int64     `json:"id"`
+	Revision   string    `json:"revision"`
+	Source     Source    `json:"source"`
+	DeployedAt time.Time `json:"deployedAt"`
+}
+
+// Condition Application 的告警或错误
+type Condition struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// ApplicationDetail Application 详情
+type ApplicationDetail struct {
+	Application
+	Resources  []ResourceStatus `json:"resources"`
+	History    []HistoryEntry   `json:"history"` // 从新到旧
+	Conditions []Condition      `json:"conditions,omitempty"`
+	Images     []string         `json:"images,omitempty"`
+}
+
+type applicationJSON struct {
+	Metadata struct {
+		Name              string    `json:"name"`
+		Namespace         string    `json:"namespace"`
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		Project     string      `json:"project"`
+		Source      *Source     `json:"source"`
+		Sources     []Source    `json:"sources"`
+		Destination Destination `json:"destination"`
+		SyncPolicy  *struct {
+			Automated *struct{} `json:"automated"`
+		} `json:"syncPolicy"`
+	} `json:"spec"`
+	Status struct {
+		Sync struct {
+			Status    string   `json:"status"`
+			Revision  string   `json:"revision"`
+			Revisions []string `json:"revisions"`
+		} `json:"sync"`
+		Health         Status `json:"health"`
+		OperationState *struct {
+			Phase     string `json:"phase"`
+			Message   string `json:"message"`
+			Operation struct {
+				Sync *struct {
+					Revision string `json:"revision"`
+					DryRun   bool   `json:"dryRun"`
+					Prune    bool   `json:"prune"`
+				} `json:"sync"`
+			} `json:"operation"`
+			StartedAt  time.Time  `json:"startedAt"`
+			FinishedAt *time.Time `json:"finishedAt"`
+		} `json:"operationState"`
+		History []struct {
+			ID         int64     `json:"id"`
+			Revision   string    `json:"revision"`
+			Revisions  []string  `json:"revisions"`
+			Source     Source    `json:"source"`
+			Sources    []Source  `json:"sources"`
+			DeployedAt time.Time `json:"deployedAt"`
+		} `json:"history"`
+		Resources []struct {
+			Group           string  `json:"group"`
+			Version         string  `json:"version"`
+			Kind            string  `json:"kind"`
+			Namespace       string  `json:"namespace"`
+			Name            string  `json:"name"`
+			Status          string  `json:"status"`
+			Health          *Status `json:"health"`
+			RequiresPruning bool    `json:"requiresPruning"`
+		} `json:"resources"`
+		Conditions   []Condition `json:"conditions"`
+		ReconciledAt *time.Time  `json:"reconciledAt"`
+		Summary      struct {
+			Images []string `json:"images"`
+		} `json:"summary"`
+	} `json:"status"`
+}
+
+func (a applicationJSON) toApplication() Application {
+	app := Application{
+		Name:         a.Metadata.Name,
+		Namespace:    a.Metadata.Namespace,
+		Project:      a.Spec.Project,
+		Destination:  a.Spec.Destination,
+		AutoSync:     a.Spec.SyncPolicy != nil && a.Spec.SyncPolicy.Automated != nil,
+		Sync:         Status{Status: a.Status.Sync.Status, Revision: firstNonEmpty(a.Status.Sync.Revision, a.Status.Sync.Revisions...)},
+		Health:       a.Status.Health,
+		ReconciledAt: a.Status.ReconciledAt,
+		CreatedAt:    a.Metadata.CreationTimestamp,
+	}
+	app.Source = firstSource(a.Spec.Source, a.Spec.Sources)
+	if op := a.Status.OperationState; op != nil {
+		app.Operation = &Operation{
+			Phase:      op.Phase,
+			Message:    op.Message,
+			StartedAt:  op.StartedAt,
+			FinishedAt: op.FinishedAt,
+		}
+		if s := op.Operation.Sync; s != nil {
+			app.Operation.Revision = s.Revision
+			app.Operation.DryRun = s.DryRun
+			app.Operation.Prune = s.Prune
+		}
+	}
+	return app
+}
+
+func (a applicationJSON) toDetail() ApplicationDetail {
+	detail := ApplicationDetail{
+		Application: a.toApplication(),
+		Resources:   make([]ResourceStatus, 0, len(a.Status.Resources)),
+		History:     make([]HistoryEntry, 0, len(a.Status.History)),
+		Conditions:  a.Status.Conditions,
+		Images:      a.Status.Summary.Images,
+	}
+	for _, r := range a.Status.Resources {
+		detail.Resources = append(detail.Resources, ResourceStatus{
+			ResourceRef:     ResourceRef{Group: r.Group, Kind: r.Kind, Namespace: r.Namespace, Name: r.Name},
+			Version:         r.Version,
+			Status:          r.Status,
+			Health:          r.Health,
+			RequiresPruning: r.RequiresPruning,
+		})
+	}
+	// ArgoCD 按时间从旧到新保存历史
+	for i := len(a.Status.History) - 1; i >= 0; i-- {
+		h := a.Status.History[i]
+		detail.History = append(detail.History, HistoryEntry{
+			ID:         h.ID,
+			Revision:   firstNonEmpty(h.Revision, h.Revisions...),
+			Source:     firstSource(&h.Source, h.Sources),
+			DeployedAt: h.DeployedAt,
+		})
+	}
+	return detail
+}
+
+func firstSource(source *Source, sources []Source) Source {
+	if source != nil && source.RepoURL != "" {
+		return *source
+	}
+	if len(sources) > 0 {
+		return sources[0]
+	}
+	return Source{}
+}
+
+func firstNonEmpty(s string, rest ...string) string {
+	if s != "" {
+		return s
+	}
+	for _, v := range rest {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,283 @@
+// Package argocd ArgoCD REST API 客户端：Application 状态、同步、差异、回滚和资源树
+package argocd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// maxResponseBytes 响应的大小上限，managed-resources 包含所有资源的完整清单
+const maxResponseBytes = 32 << 20
+
+// defaultTimeout 未配置超时时单次请求的超时
+const defaultTimeout = 30 * time.Second
+
+// ClientConfig ArgoCD 实例的连接配置
+type ClientConfig struct {
+	URL           string
+	APIToken      string
+	TLSSkipVerify bool
+	Timeout       time.Duration
+}
+
+// Client ArgoCD REST API 客户端
+// 类比Shell: curl -s -H "Authorization: Bearer $ARGOCD_TOKEN" "$ARGOCD_URL/api/v1/applications"
+type Client struct {
+	cfg  ClientConfig
+	http *http.Client
+}
+
+// NewClient 创建 ArgoCD 客户端
+func NewClient(cfg ClientConfig) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // 由实例配置显式开启
+	}
+	return &Client{
+		cfg:  cfg,
+		http: &http.Client{Transport: tracing.WrapClientTransport("argocd", transport)},
+	}
+}
+
+// CloseIdleConnections 关闭连接池中的空闲连接，客户端被替换时调用
+func (c *Client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
+
+// ListOptions Application 列表过滤条件
+type ListOptions struct {
+	Project      string // 只返回该项目的 Application
+	Selector     string // 标签选择器
+	AppNamespace string // Application 所在的命名空间（apps-in-any-namespace），为空时为 ArgoCD 的命名空间
+}
+
+// SyncOptions 同步参数
+type SyncOptions struct {
+	Revision  string        `json:"revision,omitempty"` // 为空时同步到 targetRevision
+	Prune     bool          `json:"prune"`              // 删除 Git 中已不存在的资源
+	DryRun    bool          `json:"dryRun"`
+	Resources []ResourceRef `json:"resources,omitempty"` // 只同步这些资源，为空时同步全部
+}
+
+// ResourceRef 资源的标识
+type ResourceRef struct {
+	Group     string `json:"group"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ListApplications 列出 Application
+// 对应Shell: argocd app list -p $PROJECT -l $SELECTOR
+func (c *Client) ListApplications(ctx context.Context, opts ListOptions) ([]Application, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "projects", opts.Project)
+	setIfNotEmpty(query, "selector", opts.Selector)
+	setIfNotEmpty(query, "appNamespace", opts.AppNamespace)
+	var resp struct {
+		Items []applicationJSON `json:"items"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/applications", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	apps := make([]Application, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		apps = append(apps, item.toApplication())
+	}
+	return apps, nil
+}
+
+// GetApplication 获取 Application 详情，包括资源状态和部署历史
+// 对应Shell: argocd app get $NAME
+func (c *Client) GetApplication(ctx context.Context, name, appNamespace string) (ApplicationDetail, error) {
+	var resp applicationJSON
+	if err := c.do(ctx, http.MethodGet, appPath(name, ""), appQuery(appNamespace), nil, &resp); err != nil {
+		return ApplicationDetail{}, err
+	}
+	return resp.toDetail(), nil
+}
+
+// ManagedResources 获取 Application 管理的资源的期望状态和实际状态，用于计算差异
+func (c *Client) ManagedResources(ctx context.Context, name, appNamespace string) ([]ManagedResource, error) {
+	var resp struct {
+		Items []ManagedResource `json:"items"`
+	}
+	if err := c.do(ctx, http.MethodGet, appPath(name, "/managed-resources"), appQuery(appNamespace), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// ResourceTree 获取 Application 的资源树，包括 ReplicaSet、Pod 等由控制器创建的资源
+// 对应Shell: argocd app resources $NAME --output tree
+func (c *Client) ResourceTree(ctx context.Context, name, appNamespace string) ([]TreeNode, error) {
+	var resp struct {
+		Nodes []TreeNode `json:"nodes"`
+	}
+	if err := c.do(ctx, http.MethodGet, appPath(name, "/resource-tree"), appQuery(appNamespace), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Nodes, nil
+}
+
+// Sync 触发同步，返回更新后的 Application
+// 对应Shell: argocd app sync $NAME --prune --dry-run --revision $REVISION
+func (c *Client) Sync(ctx context.Context, name, appNamespace string, opts SyncOptions) (Application, error) {
+	body := struct {
+		SyncOptions
+		Name         string `json:"name"`
+		AppNamespace string `json:"appNamespace,omitempty"`
+	}{SyncOptions: opts, Name: name, AppNamespace: appNamespace}
+	var resp applicationJSON
+	if err := c.do(ctx, http.MethodPost, appPath(name, "/sync"), nil, body, &resp); err != nil {
+		return Application{}, err
+	}
+	return resp.toApplication(), nil
+}
+
+// Rollback 回滚到部署历史中的 id，返回更新后的 Application；开启自动同步的 Application 会被 ArgoCD 拒绝
+// 对应Shell: argocd app rollback $NAME $HISTORY_ID --prune
+func (c *Client) Rollback(ctx context.Context, name, appNamespace string, id int64, prune, dryRun bool) (Application, error) {
+	body := map[string]interface{}{"name": name, "id": id, "prune": prune, "dryRun": dryRun}
+	if appNamespace != "" {
+		body["appNamespace"] = appNamespace
+	}
+	var resp applicationJSON
+	if err := c.do(ctx, http.MethodPost, appPath(name, "/rollback"), nil, body, &resp); err != nil {
+		return Application{}, err
+	}
+	return resp.toApplication(), nil
+}
+
+// UserInfo 校验 Token，返回 Token 对应的账号
+// 对应Shell: argocd account get-user-info
+func (c *Client) UserInfo(ctx context.Context) (string, error) {
+	var resp struct {
+		LoggedIn bool   `json:"loggedIn"`
+		Username string `json:"username"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/session/userinfo", nil, nil, &resp); err != nil {
+		return "", err
+	}
+	if !resp.LoggedIn {
+		return "", response.NewError(http.StatusBadGateway, response.CodeBadGateway, "ArgoCD 认证失败，请检查 API Token", nil)
+	}
+	return resp.Username, nil
+}
+
+// do 发送 JSON 请求并把 ArgoCD 的错误映射为类型化错误
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	endpoint := strings.TrimRight(c.cfg.URL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "ArgoCD 请求超时", err)
+		}
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "ArgoCD 不可达", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "读取 ArgoCD 响应失败", err)
+	}
+	if len(data) > maxResponseBytes {
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "ArgoCD 响应过大", nil)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return statusError(resp.StatusCode, data)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "ArgoCD 返回了无法解析的响应", err)
+	}
+	return nil
+}
+
+// statusError 按 HTTP 状态码映射错误，ArgoCD 的错误响应形如 {"error": "...", "code": 3, "message": "..."}
+// Token 无效是实例配置问题，返回 502 而不是 401
+func statusError(status int, body []byte) error {
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(body, &apiErr)
+	detail := apiErr.Message
+	if detail == "" {
+		detail = strings.TrimSpace(string(body))
+		if len(detail) > 1024 {
+			detail = detail[:1024]
+		}
+	}
+	err := fmt.Errorf("argocd returned HTTP %d: %s", status, detail)
+	switch status {
+	case http.StatusUnauthorized:
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "ArgoCD 认证失败，请检查 API Token", err)
+	case http.StatusForbidden:
+		// ArgoCD 对没有权限和不存在的 Application 都返回 403
+		return response.NewError(http.StatusForbidden, response.CodeForbidden, "ArgoCD 账号没有权限或 Application 不存在", err)
+	case http.StatusNotFound:
+		return response.ErrNotFound("ArgoCD 中不存在该 Application", err)
+	case http.StatusBadRequest, http.StatusConflict, http.StatusPreconditionFailed:
+		return response.ErrBadRequest(fmt.Sprintf("ArgoCD 拒绝了请求: %s", detail), err)
+	default:
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, fmt.Sprintf("ArgoCD 请求失败（HTTP %d）", status), err)
+	}
+}
+
+func appPath(name, suffix string) string {
+	return "/api/v1/applications/" + url.PathEscape(name) + suffix
+}
+
+func appQuery(appNamespace string) url.Values {
+	query := url.Values{}
+	setIfNotEmpty(query, "appNamespace", appNamespace)
+	return query
+}
+
+func setIfNotEmpty(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,123 @@
+package argocd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/yaml"
+)
+
+// ManagedResource Application 管理的一个资源的期望状态和实际状态，均为 JSON 字符串，资源不存在时为空或 "null"
+// NormalizedLiveState 和 PredictedLiveState 已经应用了 ignoreDifferences 等规则，与 argocd app diff 的比较口径一致
+type ManagedResource struct {
+	Group               string `json:"group"`
+	Kind                string `json:"kind"`
+	Namespace           string `json:"namespace"`
+	Name                string `json:"name"`
+	TargetState         string `json:"targetState"`
+	LiveState           string `json:"liveState"`
+	NormalizedLiveState string `json:"normalizedLiveState"`
+	PredictedLiveState  string `json:"predictedLiveState"`
+	Hook                bool   `json:"hook"`
+	Modified            bool   `json:"modified"`
+}
+
+// 差异类型
+const (
+	DiffModified = "modified" // 集群中的资源与 Git 不一致
+	DiffAdded    = "added"    // Git 中有、集群中没有，同步时创建
+	DiffRemoved  = "removed"  // 集群中有、Git 中已删除，prune 同步时删除
+)
+
+// ResourceDiff 一个未同步资源的差异，Diff 为 live 到 desired 的 unified diff（YAML）
+type ResourceDiff struct {
+	ResourceRef
+	Action string `json:"action"`
+	Diff   string `json:"diff"`
+}
+
+// Diff 计算未同步资源的差异，跳过 hook 和已同步的资源
+// 对应Shell: argocd app diff $NAME
+func Diff(resources []ManagedResource) ([]ResourceDiff, error) {
+	result := []ResourceDiff{}
+	for _, r := range resources {
+		if r.Hook {
+			continue
+		}
+		live := firstState(r.NormalizedLiveState, r.LiveState)
+		desired := firstState(r.PredictedLiveState, r.TargetState)
+
+		var action string
+		switch {
+		case live == "" && desired == "":
+			continue
+		case live == "":
+			action = DiffAdded
+		case desired == "":
+			action = DiffRemoved
+		case r.Modified:
+			action = DiffModified
+		default:
+			continue
+		}
+
+		liveYAML, err := toYAML(live)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert live state of %s/%s: %w", r.Kind, r.Name, err)
+		}
+		desiredYAML, err := toYAML(desired)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert desired state of %s/%s: %w", r.Kind, r.Name, err)
+		}
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(liveYAML),
+			B:        splitLines(desiredYAML),
+			FromFile: "live",
+			ToFile:   "desired",
+			Context:  3,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s/%s: %w", r.Kind, r.Name, err)
+		}
+		if text == "" {
+			// 规范化后没有差异
+			continue
+		}
+		result = append(result, ResourceDiff{
+			ResourceRef: ResourceRef{Group: r.Group, Kind: r.Kind, Namespace: r.Namespace, Name: r.Name},
+			Action:      action,
+			Diff:        text,
+		})
+	}
+	return result, nil
+}
+
+func firstState(states ...string) string {
+	for _, s := range states {
+		if s = strings.TrimSpace(s); s != "" && s != "null" {
+			return s
+		}
+	}
+	return ""
+}
+
+// toYAML 把 JSON 清单转换为键有序的 YAML，便于按行比较
+func toYAML(state string) (string, error) {
+	if state == "" {
+		return "", nil
+	}
+	data, err := yaml.JSONToYAML([]byte(state))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// splitLines 按行切分并保留换行符，空文本返回空切片，避免 diff 中出现多余的空行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return difflib.SplitLines(strings.TrimSuffix(text, "\n"))
+}
@@ -0,0 +1,153 @@
+package argocd
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TreeNode 资源树中的一个节点，ParentRefs 指向创建它的资源（如 Pod -> ReplicaSet -> Deployment）
+type TreeNode struct {
+	ResourceRef
+	Version    string `json:"version"`
+	UID        string `json:"uid"`
+	ParentRefs []struct {
+		ResourceRef
+		UID string `json:"uid"`
+	} `json:"parentRefs"`
+	Health *Status `json:"health"`
+	Info   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"info"`
+	Images    []string   `json:"images"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+func (n TreeNode) info(name string) string {
+	for _, i := range n.Info {
+		if i.Name == name {
+			return i.Value
+		}
+	}
+	return ""
+}
+
+// workloadKinds 作为工作负载展示的资源类型
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"ReplicaSet":  true,
+	"Job":         true,
+	"CronJob":     true,
+	"Rollout":     true, // argoproj.io Argo Rollouts
+}
+
+// Workload Application 管理的工作负载及其 Pod
+type Workload struct {
+	ResourceRef
+	Health *Status       `json:"health,omitempty"`
+	Images []string      `json:"images,omitempty"`
+	Pods   []WorkloadPod `json:"pods"`
+}
+
+// WorkloadPod 工作负载的一个 Pod
+type WorkloadPod struct {
+	Namespace string  `json:"namespace"`
+	Name      string  `json:"name"`
+	Health    *Status `json:"health,omitempty"`
+	Status    string  `json:"status,omitempty"` // ArgoCD 给出的状态原因，如 Running、CrashLoopBackOff
+	Ready     string  `json:"ready,omitempty"`  // 如 1/1
+	Restarts  int     `json:"restarts"`
+	Node      string  `json:"node,omitempty"`
+	Link      string  `json:"link,omitempty"` // KubeOps Pod 接口的地址，由调用方填充
+}
+
+// Workloads 把资源树中的 Pod 归到最顶层的工作负载下（Pod -> ReplicaSet -> Deployment 归到 Deployment）
+// 没有 Pod 的工作负载（如副本数为 0）也会返回；不属于任何工作负载的 Pod 作为 Kind 为 Pod 的条目返回
+func Workloads(nodes []TreeNode) []Workload {
+	byUID := make(map[string]TreeNode, len(nodes))
+	for _, n := range nodes {
+		byUID[n.UID] = n
+	}
+	parent := func(n TreeNode) (TreeNode, bool) {
+		for _, ref := range n.ParentRefs {
+			if p, ok := byUID[ref.UID]; ok {
+				return p, true
+			}
+		}
+		return TreeNode{}, false
+	}
+	// root 沿 parentRefs 向上找到最顶层的工作负载，层数有上限以防 parentRefs 成环
+	root := func(n TreeNode) (TreeNode, bool) {
+		var top TreeNode
+		found := false
+		for depth := 0; depth < 10; depth++ {
+			if workloadKinds[n.Kind] {
+				top, found = n, true
+			}
+			p, ok := parent(n)
+			if !ok {
+				break
+			}
+			n = p
+		}
+		return top, found
+	}
+
+	workloads := make(map[string]*Workload)
+	var order []string
+	get := func(n TreeNode) *Workload {
+		if w, ok := workloads[n.UID]; ok {
+			return w
+		}
+		w := &Workload{ResourceRef: n.ResourceRef, Health: n.Health, Images: n.Images, Pods: []WorkloadPod{}}
+		workloads[n.UID] = w
+		order = append(order, n.UID)
+		return w
+	}
+
+	for _, n := range nodes {
+		switch {
+		case n.Kind == "Pod" && n.Group == "":
+			pod := WorkloadPod{
+				Namespace: n.Namespace,
+				Name:      n.Name,
+				Health:    n.Health,
+				Status:    n.info("Status Reason"),
+				Ready:     n.info("Containers"),
+				Node:      n.info("Node"),
+			}
+			pod.Restarts, _ = strconv.Atoi(n.info("Restart Count"))
+			owner, ok := root(n)
+			if !ok {
+				owner = n
+			}
+			w := get(owner)
+			w.Pods = append(w.Pods, pod)
+		case workloadKinds[n.Kind]:
+			if top, _ := root(n); top.UID == n.UID {
+				get(n)
+			}
+		}
+	}
+
+	result := make([]Workload, 0, len(order))
+	for _, uid := range order {
+		w := workloads[uid]
+		sort.Slice(w.Pods, func(i, j int) bool { return w.Pods[i].Name < w.Pods[j].Name })
+		result = append(result, *w)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
@@ -0,0 +1,167 @@
+// Package assistant 集群问答助手的工具定义：模型只能调用注册的只读工具，每次调用前声明所需的 Kubernetes 权限
+package assistant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/yansongwel/kubeops/backend/internal/llm"
+)
+
+// Access 工具调用所需的 Kubernetes 权限，对应 SubjectAccessReview 的 ResourceAttributes
+type Access struct {
+	Verb      string `json:"verb"`
+	Group     string `json:"group,omitempty"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"` // 为空表示所有命名空间或集群级资源
+}
+
+// String 形如 list pods -n payments，用于提示和审计
+func (a Access) String() string {
+	resource := a.Resource
+	if a.Group != "" {
+		resource += "." + a.Group
+	}
+	if a.Namespace == "" {
+		return fmt.Sprintf("%s %s --all-namespaces", a.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s -n %s", a.Verb, resource, a.Namespace)
+}
+
+// Result 工具的返回结果，Items 必须是切片
+type Result struct {
+	Count     int         `json:"count"` // 截断前的条目数
+	Items     interface{} `json:"items"`
+	Truncated bool        `json:"truncated,omitempty"`
+}
+
+// Invocation 解析参数后的一次工具调用
+type Invocation struct {
+	Access []Access
+	Run    func(ctx context.Context, cluster string) (Result, error)
+}
+
+// Tool 提供给模型的只读工具
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{} // 参数的 JSON Schema
+	// Prepare 解析模型给出的参数，返回调用所需的权限和执行函数；参数无效时返回错误，错误信息会返回给模型
+	Prepare func(arguments string) (Invocation, error)
+}
+
+// NewTool 创建参数类型为 A 的工具：参数只解析一次，access 和 run 共用解析结果
+func NewTool[A any](name, description string, parameters map[string]interface{},
+	access func(args A) []Access,
+	run func(ctx context.Context, cluster string, args A) (Result, error),
+) Tool {
+	return Tool{
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+		Prepare: func(arguments string) (Invocation, error) {
+			var args A
+			if arguments != "" {
+				if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+					return Invocation{}, fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+			return Invocation{
+				Access: access(args),
+				Run: func(ctx context.Context, cluster string) (Result, error) {
+					return run(ctx, cluster, args)
+				},
+			}, nil
+		},
+	}
+}
+
+// Registry 工具白名单，模型请求的工具不在其中时拒绝调用
+type Registry struct {
+	tools  []Tool
+	byName map[string]Tool
+}
+
+// NewRegistry 创建工具白名单
+func NewRegistry(tools ...Tool) *Registry {
+	r := &Registry{byName: make(map[string]Tool, len(tools))}
+	for _, t := range tools {
+		if _, dup := r.byName[t.Name]; dup {
+			panic(fmt.Sprintf("duplicate assistant tool %q", t.Name))
+		}
+		r.tools = append(r.tools, t)
+		r.byName[t.Name] = t
+	}
+	return r
+}
+
+// Lookup 按名称查找工具
+func (r *Registry) Lookup(name string) (Tool, bool) {
+	t, ok := r.byName[name]
+	return t, ok
+}
+
+// Definitions 返回发送给模型的工具定义
+func (r *Registry) Definitions() []llm.Tool {
+	defs := make([]llm.Tool, 0, len(r.tools))
+	for _, t := range r.tools {
+		defs = append(defs, llm.Tool{Name: t.Name, Description: t.Description, Parameters: t.Parameters})
+	}
+	return defs
+}
+
+// Encode 把结果编码为 JSON，估算的 token 数超过 maxTokens 时只保留前面的条目，返回结果是否被截断
+func Encode(result Result, maxTokens int) (string, bool) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return ErrorJSON(err), false
+	}
+	if llm.EstimateTokens(string(data)) <= maxTokens {
+		return string(data), result.Truncated
+	}
+
+	items := reflect.ValueOf(result.Items)
+	if items.Kind() != reflect.Slice {
+		return ErrorJSON(fmt.Errorf("result too large")), true
+	}
+	encode := func(n int) string {
+		r := Result{Count: result.Count, Items: items.Slice(0, n).Interface(), Truncated: true}
+		data, _ := json.Marshal(r)
+		return string(data)
+	}
+	lo, hi := 0, items.Len()
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if llm.EstimateTokens(encode(mid)) <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return encode(lo), true
+}
+
+// ErrorJSON 工具调用失败时返回给模型的内容
+func ErrorJSON(err error) string {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(data)
+}
+
+// Schema 构造 object 类型的 JSON Schema
+func Schema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// Prop 构造一个属性的 JSON Schema
+func Prop(typ, description string) map[string]interface{} {
+	return map[string]interface{}{"type": typ, "description": description}
+}
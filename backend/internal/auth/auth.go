@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+// Anonymous 未启用认证时使用的用户名
+const Anonymous = "anonymous"
+
+// User 当前请求的用户身份
+// Verified 为 true 表示身份经过签名校验或来自受信任的网关；未校验的身份只用于展示
+type User struct {
+	Name     string   `json:"name"`
+	Groups   []string `json:"groups,omitempty"`
+	Verified bool     `json:"verified"`
+}
+
+// ID 用于权限、配额和归属判断的用户标识，未校验的身份一律视为匿名，避免伪造用户名绕过限制
+func (u User) ID() string {
+	if !u.Verified || u.Name == "" {
+		return Anonymous
+	}
+	return u.Name
+}
+
+// DisplayName 用于审计日志和访问日志的用户名，未校验的身份带 (unverified) 后缀
+func (u User) DisplayName() string {
+	if u.Verified || u.Name == "" || u.Name == Anonymous {
+		return u.Name
+	}
+	return u.Name + " (unverified)"
+}
+
+type userKey struct{}
+
+// WithUser 将用户身份放入 context
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext 取出当前用户，没有时返回匿名用户
+func UserFromContext(ctx context.Context) User {
+	if user, ok := ctx.Value(userKey{}).(User); ok {
+		return user
+	}
+	return User{Name: Anonymous}
+}
+
+// Authenticator 从网关转发的请求中提取用户身份
+// 配置了 JWTSecret 时使用 HS256 校验签名；否则只有 TrustGateway 时才认为网关已经校验过 Token
+type Authenticator struct {
+	cfg    config.AuthConfig
+	parser *jwt.Parser
+}
+
+// NewAuthenticator 创建身份解析器
+func NewAuthenticator(cfg config.AuthConfig) *Authenticator {
+	return &Authenticator{
+		cfg:    cfg,
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()})),
+	}
+}
+
+// Authenticate 解析请求中的用户身份
+// 优先使用 UserHeader 请求头，其次解析 Authorization: Bearer <token>；未启用认证时缺少身份视为匿名
+// 浏览器发起 WebSocket 时无法设置请求头，升级请求也接受 ?access_token=<token>
+func (a *Authenticator) Authenticate(r *http.Request) (User, error) {
+	if a.cfg.UserHeader != "" {
+		if name := r.Header.Get(a.cfg.UserHeader); name != "" {
+			return User{Name: name, Verified: a.cfg.TrustGateway}, nil
+		}
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok && isWebSocketUpgrade(r) {
+		token = r.URL.Query().Get("access_token")
+		ok = token != ""
+	}
+	if !ok || token == "" {
+		if !a.cfg.Enabled {
+			return User{Name: Anonymous}, nil
+		}
+		return User{}, errors.New("missing bearer token")
+	}
+
+	user, err := a.parseToken(token)
+	if err != nil && !a.cfg.Enabled {
+		return User{Name: Anonymous}, nil
+	}
+	return user, err
+}
+
+// isWebSocketUpgrade 判断是否为 WebSocket 升级请求
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+func (a *Authenticator) parseToken(token string) (User, error) {
+	claims := jwt.MapClaims{}
+	var err error
+	if a.cfg.JWTSecret != "" {
+		_, err = a.parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+			return []byte(a.cfg.JWTSecret), nil
+		})
+	} else {
+		_, _, err = jwt.NewParser().ParseUnverified(token, claims)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("invalid token: %w", err)
+	}
+
+	name, _ := claims[a.cfg.UserClaim].(string)
+	if name == "" {
+		return User{}, fmt.Errorf("token has no %q claim", a.cfg.UserClaim)
+	}
+
+	user := User{Name: name, Verified: a.cfg.JWTSecret != "" || a.cfg.TrustGateway}
+	if groups, ok := claims["groups"].([]interface{}); ok {
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				user.Groups = append(user.Groups, s)
+			}
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,202 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+)
+
+const (
+	keyPrefix = "kubeops:cache:"
+	// genTTL 失效计数器的过期时间，远大于任何数据 TTL，计数器过期重置时旧数据早已过期
+	genTTL = 24 * time.Hour
+	// flushInterval watch 事件合并后批量写入 Redis 的周期，pod 频繁变化时避免每个事件一次往返
+	flushInterval = 500 * time.Millisecond
+)
+
+// Key 一次列表查询的缓存键
+// Namespace 为空表示跨命名空间查询；Query 为影响结果的其他查询参数
+type Key struct {
+	Cluster   string
+	Resource  string
+	Namespace string
+	Query     string
+}
+
+// Cache 基于 Redis 的列表接口缓存
+// 缓存键包含集群、资源、命名空间、已校验的用户 ID 和查询参数；informer 收到 watch 事件时递增对应的失效计数器，
+// 计数器是缓存键的一部分，递增后旧缓存不再命中并随 TTL 过期，无需扫描删除
+type Cache struct {
+	logger   *zap.Logger
+	redis    *redis.Client
+	dep      *client.Dependency
+	clusters *client.ClusterManager
+	cfg      config.CacheConfig
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+// New 创建缓存
+func New(logger *zap.Logger, redisClient *redis.Client, dep *client.Dependency, clusters *client.ClusterManager, cfg config.CacheConfig) *Cache {
+	return &Cache{
+		logger:   logger,
+		redis:    redisClient,
+		dep:      dep,
+		clusters: clusters,
+		cfg:      cfg,
+		pending:  make(map[string]struct{}),
+	}
+}
+
+// Fetch 优先从缓存读取结果，未命中时调用 load 并写入缓存
+// 缓存未启用、资源 TTL 为 0、Redis 不可用或请求要求绕过缓存时直接调用 load；Redis 读写失败不影响请求
+// 缓存按用户隔离，匿名和未经校验的身份可以随意伪造用户名，这些请求不使用缓存
+func Fetch[T any](ctx context.Context, c *Cache, key Key, load func(ctx context.Context) (T, error)) (T, error) {
+	rec := recorderFrom(ctx)
+	userID := auth.UserFromContext(ctx).ID()
+	if c == nil || !c.cfg.Enabled || c.cfg.CacheTTL(key.Resource) <= 0 || userID == auth.Anonymous || c.dep.Err() != nil || rec.bypass {
+		rec.set(StatusBypass)
+		return load(ctx)
+	}
+
+	// 缺省集群解析为实际集群名，与 informer 事件使用的名称一致
+	if cluster, err := c.clusters.Get(key.Cluster); err == nil {
+		key.Cluster = cluster.Name
+	} else {
+		rec.set(StatusBypass)
+		return load(ctx)
+	}
+
+	logger := logging.FromContext(ctx)
+	dataKey, err := c.dataKey(ctx, key, userID)
+	if err == nil {
+		var cached T
+		data, err := c.redis.Get(ctx, dataKey).Bytes()
+		if err == nil && json.Unmarshal(data, &cached) == nil {
+			rec.set(StatusHit)
+			return cached, nil
+		}
+		if err != nil && !errors.Is(err, redis.Nil) {
+			logger.Warn("Cache read failed", zap.String("key", dataKey), zap.Error(err))
+		}
+	} else {
+		logger.Warn("Cache generation lookup failed", zap.Error(err))
+	}
+
+	rec.set(StatusMiss)
+	result, err := load(ctx)
+	if err != nil || dataKey == "" {
+		return result, err
+	}
+	if data, err := json.Marshal(result); err == nil {
+		if err := c.redis.Set(ctx, dataKey, data, c.cfg.CacheTTL(key.Resource)).Err(); err != nil {
+			logger.Warn("Cache write failed", zap.String("key", dataKey), zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+// dataKey 读取失效计数器并拼出数据键
+// 格式：kubeops:cache:<集群>:<资源>:<命名空间或 _all>:g<计数器>:<用户和查询参数的摘要>
+func (c *Cache) dataKey(ctx context.Context, key Key, userID string) (string, error) {
+	gen, err := c.redis.Get(ctx, genKey(key.Cluster, key.Resource, key.Namespace)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+
+	scope := key.Namespace
+	if scope == "" {
+		scope = "_all"
+	}
+	sum := sha256.Sum256([]byte(userID + "\x00" + key.Query))
+	return fmt.Sprintf("%s%s:%s:%s:g%s:%s", keyPrefix, key.Cluster, key.Resource, scope,
+		strconv.FormatInt(gen, 10), hex.EncodeToString(sum[:8])), nil
+}
+
+// genKey 失效计数器的键，namespace 为空时为集群级计数器
+func genKey(cluster, resource, namespace string) string {
+	key := keyPrefix + "gen:" + cluster + ":" + resource
+	if namespace != "" {
+		key += ":" + namespace
+	}
+	return key
+}
+
+// Invalidate 使某个集群资源的缓存失效
+// 集群级查询总是失效；namespace 非空时同时使该命名空间内的查询失效
+func (c *Cache) Invalidate(cluster, resource, namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending[genKey(cluster, resource, "")] = struct{}{}
+	if namespace != "" {
+		c.pending[genKey(cluster, resource, namespace)] = struct{}{}
+	}
+}
+
+// OnChange 作为 informer 的变化回调，签名与 informer.ChangeHandler 一致
+func (c *Cache) OnChange(cluster, resource string, obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	namespace := ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		namespace = accessor.GetNamespace()
+	}
+	c.Invalidate(cluster, resource, namespace)
+}
+
+// Run 阻塞运行直到 ctx 取消，定期将合并后的失效事件写入 Redis
+func (c *Cache) Run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.flush(ctx)
+		}
+	}
+}
+
+func (c *Cache) flush(ctx context.Context) {
+	c.mu.Lock()
+	if len(c.pending) == 0 {
+		c.mu.Unlock()
+		return
+	}
+	keys := c.pending
+	c.pending = make(map[string]struct{})
+	c.mu.Unlock()
+
+	if c.dep.Err() != nil {
+		// Redis 不可用时读写都会绕过缓存，恢复后旧数据最多存活一个 TTL
+		return
+	}
+
+	pipe := c.redis.Pipeline()
+	for key := range keys {
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, genTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Warn("Failed to invalidate cache", zap.Int("keys", len(keys)), zap.Error(err))
+	}
+}
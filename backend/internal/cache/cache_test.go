@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+func TestFetchBypassesUnverifiedUsers(t *testing.T) {
+	c := &Cache{cfg: config.CacheConfig{Enabled: true, DefaultTTL: time.Minute}}
+	tests := []struct {
+		name string
+		user *auth.User
+	}{
+		{name: "no user"},
+		{name: "anonymous", user: &auth.User{Name: auth.Anonymous, Verified: true}},
+		// 未校验的 Token 可以伪造任意用户名，不能据此共享或区分缓存
+		{name: "unverified", user: &auth.User{Name: "alice"}},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.user != nil {
+			ctx = auth.WithUser(ctx, *tt.user)
+		}
+		ctx, rec := WithRecorder(ctx, false)
+
+		calls := 0
+		got, err := Fetch(ctx, c, Key{Cluster: "prod", Resource: "pods"}, func(context.Context) ([]string, error) {
+			calls++
+			return []string{"web-1"}, nil
+		})
+		if err != nil || calls != 1 || len(got) != 1 {
+			t.Errorf("%s: Fetch() = %v, %v after %d loads", tt.name, got, err, calls)
+		}
+		if rec.Status() != StatusBypass {
+			t.Errorf("%s: X-Cache = %q, want %q", tt.name, rec.Status(), StatusBypass)
+		}
+	}
+}
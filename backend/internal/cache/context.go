@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// Header 标识缓存命中情况的响应头
+const Header = "X-Cache"
+
+// X-Cache 响应头的取值
+const (
+	StatusHit    = "HIT"
+	StatusMiss   = "MISS"
+	StatusBypass = "BYPASS"
+)
+
+// Recorder 记录一次请求中缓存的使用情况
+type Recorder struct {
+	bypass bool
+
+	mu     sync.Mutex
+	status string
+}
+
+type recorderKey struct{}
+
+// WithRecorder 为请求创建缓存记录器，bypass 为 true 时跳过缓存读取（Cache-Control: no-cache）
+func WithRecorder(ctx context.Context, bypass bool) (context.Context, *Recorder) {
+	rec := &Recorder{bypass: bypass}
+	return context.WithValue(ctx, recorderKey{}, rec), rec
+}
+
+// Status 返回最近一次缓存查询的结果，未使用缓存时为空
+func (r *Recorder) Status() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+func (r *Recorder) set(status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// recorderFrom 取出请求的记录器，后台任务等没有记录器时返回一次性的空记录器
+func recorderFrom(ctx context.Context) *Recorder {
+	if rec, ok := ctx.Value(recorderKey{}).(*Recorder); ok {
+		return rec
+	}
+	return &Recorder{}
+}
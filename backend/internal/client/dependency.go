@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	// dependencyCheckInterval 连接正常时的探测周期
+	dependencyCheckInterval = 10 * time.Second
+	// dependencyInitialBackoff、dependencyMaxBackoff 连接失败后的指数退避区间
+	dependencyInitialBackoff = time.Second
+	dependencyMaxBackoff     = 30 * time.Second
+	// dependencyConnectTimeout 单次连接尝试的超时
+	dependencyConnectTimeout = 5 * time.Second
+)
+
+// errNotAttempted 后台尚未完成第一次连接尝试
+var errNotAttempted = errors.New("connection not attempted yet")
+
+// Dependency 一个外部依赖（Postgres、Redis）的运行时连接状态
+// 依赖不可用时服务照常启动，后台按指数退避重连，需要该依赖的接口返回 503
+type Dependency struct {
+	name    string
+	code    int // 不可用时返回的业务错误码
+	logger  *zap.Logger
+	connect func(ctx context.Context) error
+
+	mu        sync.RWMutex
+	attempted bool
+	err       error
+	since     time.Time
+}
+
+// NewDependency 创建依赖，connect 负责建立或探测连接，返回 nil 表示可用
+func NewDependency(logger *zap.Logger, name string, code int, connect func(ctx context.Context) error) *Dependency {
+	metrics.DependencyUp.WithLabelValues(name).Set(0)
+	return &Dependency{
+		name:    name,
+		code:    code,
+		logger:  logger,
+		connect: connect,
+		err:     errNotAttempted,
+		since:   time.Now(),
+	}
+}
+
+// Name 依赖名称
+func (d *Dependency) Name() string {
+	return d.name
+}
+
+// Run 阻塞运行直到 ctx 取消：不可用时按退避重连，可用后定期探测
+func (d *Dependency) Run(ctx context.Context) {
+	backoff := dependencyInitialBackoff
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, dependencyConnectTimeout)
+		err := d.connect(attemptCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		d.setState(err)
+
+		wait := dependencyCheckInterval
+		if err != nil {
+			wait = backoff
+			backoff = min(backoff*2, dependencyMaxBackoff)
+		} else {
+			backoff = dependencyInitialBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (d *Dependency) setState(err error) {
+	d.mu.Lock()
+	wasUp := d.attempted && d.err == nil
+	d.attempted = true
+	d.err = err
+	if wasUp != (err == nil) {
+		d.since = time.Now()
+	}
+	d.mu.Unlock()
+
+	switch {
+	case err == nil && !wasUp:
+		metrics.DependencyUp.WithLabelValues(d.name).Set(1)
+		d.logger.Info("Dependency available", zap.String("dependency", d.name))
+	case err != nil && wasUp:
+		metrics.DependencyUp.WithLabelValues(d.name).Set(0)
+		d.logger.Error("Dependency became unavailable, reconnecting in background",
+			zap.String("dependency", d.name), zap.Error(err))
+	case err != nil:
+		d.logger.Warn("Dependency unavailable, retrying", zap.String("dependency", d.name), zap.Error(err))
+	}
+}
+
+// Check 依赖可用时返回 nil，用于健康检查；只读取后台维护的状态，不发起连接
+func (d *Dependency) Check(context.Context) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.err != nil {
+		return fmt.Errorf("unavailable since %s: %w", d.since.Format(time.RFC3339), d.err)
+	}
+	return nil
+}
+
+// Attempted 完成第一次连接尝试（无论成败）后返回 nil，用于启动探针
+func (d *Dependency) Attempted(context.Context) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if !d.attempted {
+		return errNotAttempted
+	}
+	return nil
+}
+
+// Err 依赖不可用时返回 503 类型化错误，供 handler、service 在使用依赖前判断
+func (d *Dependency) Err() error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.err == nil {
+		return nil
+	}
+	return response.NewError(http.StatusServiceUnavailable, d.code, fmt.Sprintf("%s 暂不可用，请稍后重试", d.name), d.err)
+}
@@ -1,44 +1,335 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+	"github.com/yansongwel/kubeops/backend/internal/reload"
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
 )
 
-// InitK8sClient 初始化 Kubernetes 客户端
-func InitK8sClient(logger *zap.Logger, kubeconfig string) (*kubernetes.Clientset, error) {
-	// 优先使用集群内配置
-	k8sConfig, err := rest.InClusterConfig()
-	if err != nil {
-		// 回退到 kubeconfig 文件
-		if kubeconfig == "" {
-			kubeconfig = os.Getenv("KUBECONFIG")
-		}
-		if kubeconfig == "" {
-			homeDir := os.Getenv("HOME")
-			if homeDir == "" {
-				homeDir = os.Getenv("USERPROFILE") // Windows
+// Cluster 一个受管集群的客户端
+// 创建后不再修改，热加载时整体替换，持有旧实例的请求不受影响
+type Cluster struct {
+	Name      string
+	Config    *rest.Config
+	Clientset *kubernetes.Clientset
+	Metrics   *metricsclient.Clientset // metrics.k8s.io，集群未安装 metrics-server 时请求返回 NotFound
+
+	spec           config.ClusterConfig // 创建时使用的集群配置
+	kubeconfigPath string               // 实际读取的 kubeconfig 文件，集群内配置时为空
+	fingerprint    string               // kubeconfig 内容摘要，用于判断凭证是否轮换
+}
+
+// failedCluster 创建客户端失败的集群，保留配置供后台重试
+type failedCluster struct {
+	spec config.ClusterConfig
+	err  error
+}
+
+type clusterSet struct {
+	clusters map[string]*Cluster
+	failed   map[string]*failedCluster
+	names    []string
+}
+
+func newClusterSet(size int) *clusterSet {
+	return &clusterSet{
+		clusters: make(map[string]*Cluster, size),
+		failed:   make(map[string]*failedCluster),
+	}
+}
+
+func (s *clusterSet) add(cluster *Cluster) {
+	s.clusters[cluster.Name] = cluster
+	s.names = append(s.names, cluster.Name)
+}
+
+func (s *clusterSet) addFailed(cc config.ClusterConfig, err error) {
+	s.failed[cc.Name] = &failedCluster{spec: cc, err: err}
+	s.names = append(s.names, cc.Name)
+}
+
+// ClusterManager 多集群客户端注册表
+// 第一个配置的集群为默认集群，请求未指定集群时使用
+// 客户端创建失败的集群仍然注册，访问时返回 503，并由 Run 在后台重试
+type ClusterManager struct {
+	logger *zap.Logger
+	mu     sync.Mutex // 串行化 Reload
+	set    atomic.Pointer[clusterSet]
+}
+
+// NewClusterManager 为配置中的每个集群创建客户端，创建失败的集群标记为不可用而不是中止启动
+func NewClusterManager(logger *zap.Logger, clusters []config.ClusterConfig) *ClusterManager {
+	set := newClusterSet(len(clusters))
+	for _, cc := range clusters {
+		cluster, err := NewCluster(logger, cc)
+		if err != nil {
+			logger.Error("Cluster unavailable, retrying in background", zap.String("cluster", cc.Name), zap.Error(err))
+			metrics.DependencyUp.WithLabelValues("cluster:" + cc.Name).Set(0)
+			set.addFailed(cc, err)
+			continue
+		}
+		metrics.DependencyUp.WithLabelValues("cluster:" + cc.Name).Set(1)
+		set.add(cluster)
+	}
+
+	m := &ClusterManager{logger: logger}
+	m.set.Store(set)
+	return m
+}
+
+// Get 按名称获取集群客户端，name 为空时返回默认集群
+func (m *ClusterManager) Get(name string) (*Cluster, error) {
+	set := m.set.Load()
+	if name == "" && len(set.names) > 0 {
+		name = set.names[0]
+	}
+	if failed, ok := set.failed[name]; ok {
+		return nil, response.NewError(http.StatusServiceUnavailable, response.CodeClusterUnavailable, fmt.Sprintf("集群 %s 暂不可用", name), failed.err)
+	}
+	cluster, ok := set.clusters[name]
+	if !ok {
+		return nil, response.NewError(http.StatusNotFound, response.CodeNotFound, fmt.Sprintf("集群 %s 不存在", name), nil)
+	}
+	return cluster, nil
+}
+
+// Names 按配置顺序返回所有集群名
+func (m *ClusterManager) Names() []string {
+	return append([]string(nil), m.set.Load().names...)
+}
+
+// Unavailable 返回客户端创建失败的集群名
+func (m *ClusterManager) Unavailable() []string {
+	set := m.set.Load()
+	var names []string
+	for _, name := range set.names {
+		if _, ok := set.failed[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// KubeconfigPaths 返回所有集群使用的 kubeconfig 文件，供文件监听使用
+// 不可用的集群也监听其配置的 kubeconfig，文件修复后立即触发重建
+func (m *ClusterManager) KubeconfigPaths() []string {
+	set := m.set.Load()
+	var paths []string
+	for _, name := range set.names {
+		path := ""
+		if cluster, ok := set.clusters[name]; ok {
+			path = cluster.kubeconfigPath
+		} else if !set.failed[name].spec.InCluster {
+			path = set.failed[name].spec.Kubeconfig
+			if path == "" {
+				path = DefaultKubeconfigPath()
 			}
-			kubeconfig = homeDir + "/.kube/config"
 		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Reload 按新的集群配置重建客户端，返回新增、删除或重建的集群名
+// 配置和 kubeconfig 内容都未变化的集群复用原客户端；任一集群创建失败时保留全部旧客户端，
+// 但原本就不可用、配置也未变化的集群重建失败时继续标记为不可用，不阻塞其他集群的变更
+func (m *ClusterManager) Reload(clusters []config.ClusterConfig) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-		logger.Info("Using kubeconfig file", zap.String("kubeconfig", kubeconfig))
-		k8sConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	old := m.set.Load()
+	next := newClusterSet(len(clusters))
+	var changed []string
+	for _, cc := range clusters {
+		if prev, ok := old.clusters[cc.Name]; ok && prev.spec == cc && prev.fingerprint == reload.Fingerprint(prev.kubeconfigPath) {
+			next.add(prev)
+			continue
+		}
+		cluster, err := NewCluster(m.logger, cc)
 		if err != nil {
-			return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
+			if prev, ok := old.failed[cc.Name]; ok && prev.spec == cc {
+				next.addFailed(cc, err)
+				continue
+			}
+			return nil, fmt.Errorf("cluster %s: %w", cc.Name, err)
+		}
+		next.add(cluster)
+		changed = append(changed, cc.Name)
+	}
+	for _, name := range old.names {
+		_, ok := next.clusters[name]
+		if _, failed := next.failed[name]; !ok && !failed {
+			changed = append(changed, name)
+			metrics.DependencyUp.DeleteLabelValues("cluster:" + name)
 		}
 	}
 
+	m.set.Store(next)
+	m.reportAvailability(next)
+	return changed, nil
+}
+
+// Run 阻塞运行直到 ctx 取消，按指数退避重试不可用的集群，恢复后调用 onRecover
+func (m *ClusterManager) Run(ctx context.Context, onRecover func()) {
+	backoff := dependencyInitialBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if len(m.Unavailable()) == 0 {
+			backoff = dependencyInitialBackoff
+			continue
+		}
+		if m.retryFailed() {
+			onRecover()
+			backoff = dependencyInitialBackoff
+			continue
+		}
+		backoff = min(backoff*2, dependencyMaxBackoff)
+	}
+}
+
+// retryFailed 重新创建不可用集群的客户端，有集群恢复时返回 true
+func (m *ClusterManager) retryFailed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := m.set.Load()
+	next := newClusterSet(len(old.names))
+	recovered := false
+	for _, name := range old.names {
+		if cluster, ok := old.clusters[name]; ok {
+			next.add(cluster)
+			continue
+		}
+		failed := old.failed[name]
+		cluster, err := NewCluster(m.logger, failed.spec)
+		if err != nil {
+			next.addFailed(failed.spec, err)
+			continue
+		}
+		m.logger.Info("Cluster recovered", zap.String("cluster", name))
+		next.add(cluster)
+		recovered = true
+	}
+	if recovered {
+		m.set.Store(next)
+		m.reportAvailability(next)
+	}
+	return recovered
+}
+
+func (m *ClusterManager) reportAvailability(set *clusterSet) {
+	for _, name := range set.names {
+		_, ok := set.clusters[name]
+		value := 0.0
+		if ok {
+			value = 1
+		}
+		metrics.DependencyUp.WithLabelValues("cluster:" + name).Set(value)
+	}
+}
+
+// NewCluster 根据集群配置创建客户端
+func NewCluster(logger *zap.Logger, cc config.ClusterConfig) (*Cluster, error) {
+	k8sConfig, kubeconfigPath, err := NewRestConfig(logger, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每次 apiserver 往返都生成链路 span，并按资源统计请求结果
+	k8sConfig.Wrap(tracing.WrapTransport)
+	k8sConfig.Wrap(metrics.WrapKubernetesTransport)
+
 	// 创建 K8s 客户端
-	client, err := kubernetes.NewForConfig(k8sConfig)
+	clientset, err := kubernetes.NewForConfig(k8sConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
+	metricsClientset, err := metricsclient.NewForConfig(k8sConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics client: %w", err)
+	}
+
+	return &Cluster{
+		Name:           cc.Name,
+		Config:         k8sConfig,
+		Clientset:      clientset,
+		Metrics:        metricsClientset,
+		spec:           cc,
+		kubeconfigPath: kubeconfigPath,
+		fingerprint:    reload.Fingerprint(kubeconfigPath),
+	}, nil
+}
 
-	return client, nil
+// NewRestConfig 构建集群的 rest.Config，同时返回实际读取的 kubeconfig 路径（集群内配置时为空）
+// 显式指定 kubeconfig/context 时直接使用；否则优先使用集群内配置，再回退到默认 kubeconfig 文件
+func NewRestConfig(logger *zap.Logger, cc config.ClusterConfig) (*rest.Config, string, error) {
+	if cc.InCluster {
+		k8sConfig, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load in-cluster config: %w", err)
+		}
+		return k8sConfig, "", nil
+	}
+
+	kubeconfig := cc.Kubeconfig
+	if kubeconfig == "" && cc.Context == "" {
+		// 优先使用集群内配置
+		if k8sConfig, err := rest.InClusterConfig(); err == nil {
+			return k8sConfig, "", nil
+		}
+	}
+
+	// 回退到 kubeconfig 文件
+	if kubeconfig == "" {
+		kubeconfig = DefaultKubeconfigPath()
+	}
+
+	logger.Info("Using kubeconfig file",
+		zap.String("cluster", cc.Name),
+		zap.String("kubeconfig", kubeconfig),
+		zap.String("context", cc.Context),
+	)
+	k8sConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: cc.Context},
+	).ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+	return k8sConfig, kubeconfig, nil
+}
+
+// DefaultKubeconfigPath 返回 $KUBECONFIG 或用户目录下的默认 kubeconfig 路径
+func DefaultKubeconfigPath() string {
+	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
+		return kubeconfig
+	}
+	homeDir := os.Getenv("HOME")
+	if homeDir == "" {
+		homeDir = os.Getenv("USERPROFILE") // Windows
+	}
+	return homeDir + "/.kube/config"
 }
@@ -8,9 +8,29 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
 )
 
+// NewPostgresPool 创建连接池并立即 Ping，连接失败时返回错误，用于 migrate 等命令行场景
 func NewPostgresPool(cfg config.PostgresConfig) (*pgxpool.Pool, error) {
+	pool, err := OpenPostgresPool(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// OpenPostgresPool 创建连接池但不建立连接，连接在首次使用时建立
+// 服务启动使用该函数，Postgres 暂不可用时不影响启动
+func OpenPostgresPool(cfg config.PostgresConfig) (*pgxpool.Pool, error) {
 	sslMode := cfg.SSLMode
 	if sslMode == "" {
 		sslMode = "disable"
@@ -25,17 +45,11 @@ func NewPostgresPool(cfg config.PostgresConfig) (*pgxpool.Pool, error) {
 		sslMode,
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	pool, err := pgxpool.New(ctx, dsn)
+	poolConfig, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, err
 	}
-	if err := pool.Ping(ctx); err != nil {
-		pool.Close()
-		return nil, err
-	}
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
 
-	return pool, nil
+	return pgxpool.NewWithConfig(context.Background(), poolConfig)
 }
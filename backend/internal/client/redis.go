@@ -1,28 +1,19 @@
 package client
 
 import (
-	"context"
-	"time"
-
 	"github.com/redis/go-redis/v9"
 
 	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
 )
 
-func NewRedisClient(cfg config.RedisConfig) (*redis.Client, error) {
+// OpenRedisClient 创建客户端但不建立连接，Redis 暂不可用时不影响启动
+func OpenRedisClient(cfg config.RedisConfig) *redis.Client {
 	client := redis.NewClient(&redis.Options{
 		Addr:     cfg.Addr,
 		Password: cfg.Password,
 		DB:       cfg.DB,
 	})
-
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	if err := client.Ping(ctx).Err(); err != nil {
-		_ = client.Close()
-		return nil, err
-	}
-
-	return client, nil
+	client.AddHook(tracing.NewRedisHook())
+	return client
 }
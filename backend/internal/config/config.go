@@ -1,49 +1,560 @@
 package config
 
-import "os"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
 
 type PostgresConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Database string
-	SSLMode  string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+	SSLMode  string `yaml:"sslmode"`
 }
 
 type RedisConfig struct {
-	Addr     string
-	Password string
-	DB       int
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+// ClusterConfig 一个受管集群的连接方式
+// Kubeconfig 为空且 InCluster 为 false 时，按 InitK8sClient 的默认规则查找凭证
+type ClusterConfig struct {
+	Name       string `yaml:"name"`
+	Kubeconfig string `yaml:"kubeconfig"`
+	Context    string `yaml:"context"`
+	InCluster  bool   `yaml:"inCluster"`
+}
+
+// AuthConfig 身份认证配置
+// 网关（APISIX/Higress/Istio）负责校验 JWT 并转发原始 Token，后端从中提取用户身份
+type AuthConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	JWTSecret  string `yaml:"jwtSecret"`  // 非空时后端使用 HS256 再次校验签名
+	UserClaim  string `yaml:"userClaim"`  // 作为用户名的 JWT claim
+	UserHeader string `yaml:"userHeader"` // 网关注入的用户名请求头，优先于 JWT
+	// TrustGateway 网关已校验 Token（或注入 userHeader），且后端只能经网关访问；
+	// 未配置 jwtSecret 又不信任网关时，Token 中的身份未经校验，不用于权限、配额和归属判断
+	TrustGateway bool `yaml:"trustGateway"`
+}
+
+// CacheConfig 列表接口缓存配置
+type CacheConfig struct {
+	Enabled    bool                     `yaml:"enabled"`
+	DefaultTTL time.Duration            `yaml:"defaultTTL"`
+	TTLs       map[string]time.Duration `yaml:"ttls"` // 按资源类型覆盖 TTL，如 pods: 10s
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置，使用 OTLP/HTTP 导出
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`    // OTLP/HTTP 接收地址，如 otel-collector:4318
+	Insecure    bool    `yaml:"insecure"`    // 使用 HTTP 而非 HTTPS
+	SampleRatio float64 `yaml:"sampleRatio"` // 根 span 采样比例，0~1
+	ServiceName string  `yaml:"serviceName"`
+}
+
+// RateLimitConfig 基于 Redis 的分布式限流配置，多副本共享配额
+type RateLimitConfig struct {
+	Enabled     bool                     `yaml:"enabled"`
+	Groups      map[string]RateLimitRule `yaml:"groups"`      // 按路由组配置：reads、mutations、exec，未填写的字段沿用默认值
+	ExemptUsers []string                 `yaml:"exemptUsers"` // 不限流的用户名，如巡检、CI 账号
+	ExemptCIDRs []string                 `yaml:"exemptCIDRs"` // 不限流的客户端网段
+}
+
+// RateLimitRule 每个用户在 Period 内最多 Requests 个请求，允许一次性突发 Burst 个（默认等于 Requests）
+type RateLimitRule struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+}
+
+// 限流路由组
+const (
+	RateLimitGroupReads     = "reads"
+	RateLimitGroupMutations = "mutations"
+	RateLimitGroupExec      = "exec"
+)
+
+// LeaderElectionConfig 后台任务的主节点选举配置，保证多副本时只有一个副本运行巡检、归档等任务
+type LeaderElectionConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	Backend       string        `yaml:"backend"`   // lease（Kubernetes Lease）或 redis（集群外运行时使用）
+	Cluster       string        `yaml:"cluster"`   // Lease 所在集群，默认第一个集群
+	Namespace     string        `yaml:"namespace"` // Lease 所在命名空间，默认为 Pod 所在命名空间
+	Name          string        `yaml:"name"`      // Lease 名称或 Redis 键名
+	LeaseDuration time.Duration `yaml:"leaseDuration"`
+	RenewDeadline time.Duration `yaml:"renewDeadline"`
+	RetryPeriod   time.Duration `yaml:"retryPeriod"`
+}
+
+// 主节点选举后端
+const (
+	LeaderElectionBackendLease = "lease"
+	LeaderElectionBackendRedis = "redis"
+)
+
+// LogsConfig 日志检索后端配置，Backend 为空时日志接口返回 503
+type LogsConfig struct {
+	Backend       string              `yaml:"backend"`  // loki 或 elasticsearch
+	Timeout       time.Duration       `yaml:"timeout"`  // 单次查询超时
+	MaxLimit      int                 `yaml:"maxLimit"` // 单次查询最多返回的行数
+	TailInterval  time.Duration       `yaml:"tailInterval"`
+	Loki          LokiConfig          `yaml:"loki"`
+	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
+}
+
+// LokiConfig Loki 连接配置，Labels 为日志流中 Pod 元数据对应的标签名（Promtail/Alloy 默认值）
+type LokiConfig struct {
+	URL           string        `yaml:"url"`
+	TenantID      string        `yaml:"tenantID"` // 多租户时作为 X-Scope-OrgID
+	Username      string        `yaml:"username"`
+	Password      string        `yaml:"password"`
+	BearerToken   string        `yaml:"bearerToken"`
+	TLSSkipVerify bool          `yaml:"tlsSkipVerify"`
+	Labels        LogFieldNames `yaml:"labels"`
+}
+
+// ElasticsearchConfig Elasticsearch/OpenSearch 连接配置，Fields 为文档中各字段的路径（Fluent Bit 默认值）
+type ElasticsearchConfig struct {
+	URL           string        `yaml:"url"`
+	Index         string        `yaml:"index"` // 索引或索引模式，如 logstash-*
+	Username      string        `yaml:"username"`
+	Password      string        `yaml:"password"`
+	APIKey        string        `yaml:"apiKey"`
+	TLSSkipVerify bool          `yaml:"tlsSkipVerify"`
+	Fields        LogFieldNames `yaml:"fields"`
+}
+
+// LogFieldNames 日志后端中时间戳、日志内容和 Pod 元数据的标签名或字段路径
+type LogFieldNames struct {
+	Timestamp string `yaml:"timestamp"`
+	Message   string `yaml:"message"`
+	Namespace string `yaml:"namespace"`
+	Pod       string `yaml:"pod"`
+	Container string `yaml:"container"`
+}
+
+// 日志检索后端
+const (
+	LogsBackendLoki          = "loki"
+	LogsBackendElasticsearch = "elasticsearch"
+)
+
+// LLMConfig 大模型配置，Provider 为空时 AI 相关接口返回 503
+// openai 兼容 OpenAI Chat Completions 接口的服务（OpenAI、vLLM、Ollama、LocalAI 等）；stub 返回固定格式的本地结果，用于开发和演示
+type LLMConfig struct {
+	Provider         string          `yaml:"provider"` // openai 或 stub
+	BaseURL          string          `yaml:"baseURL"`  // 如 https://api.openai.com/v1、http://ollama:11434/v1
+	APIKey           string          `yaml:"apiKey"`
+	Model            string          `yaml:"model"`
+	Timeout          time.Duration   `yaml:"timeout"`
+	Temperature      float64         `yaml:"temperature"`
+	JSONMode         bool            `yaml:"jsonMode"` // 请求 response_format=json_object，本地模型服务不支持时关闭
+	TLSSkipVerify    bool            `yaml:"tlsSkipVerify"`
+	MaxInputTokens   int             `yaml:"maxInputTokens"`   // 单次请求输入的 token 上限（估算），超出时截断日志和事件
+	MaxOutputTokens  int             `yaml:"maxOutputTokens"`  // 单次请求输出的 token 上限
+	DailyTokenBudget int             `yaml:"dailyTokenBudget"` // 每个用户每天可消耗的 token 数，0 表示不限
+	Explain          ExplainConfig   `yaml:"explain"`
+	Assistant        AssistantConfig `yaml:"assistant"`
+}
+
+// ExplainConfig Pod 根因分析配置
+type ExplainConfig struct {
+	LogLines int           `yaml:"logLines"` // 每个容器读取的最近日志行数（当前和上一次各读取这么多）
+	Events   int           `yaml:"events"`   // 最多发送的事件数
+	CacheTTL time.Duration `yaml:"cacheTTL"` // 分析结果在 Redis 中的缓存时长，Pod 状态变化后自动失效
+}
+
+// AssistantConfig 集群问答助手配置
+type AssistantConfig struct {
+	MaxSteps            int  `yaml:"maxSteps"`            // 一次提问中模型最多请求工具的轮数
+	MaxToolResultTokens int  `yaml:"maxToolResultTokens"` // 单个工具结果发送给模型的 token 上限（估算），超出时截断条目
+	EnforceRBAC         bool `yaml:"enforceRBAC"`         // 工具调用前用 SubjectAccessReview 校验当前用户的 Kubernetes 权限
+}
+
+// 大模型 Provider
+const (
+	LLMProviderOpenAI = "openai"
+	LLMProviderStub   = "stub"
+)
+
+// InspectionConfig 巡检记录和定时巡检配置
+type InspectionConfig struct {
+	Retention        time.Duration `yaml:"retention"`        // 巡检记录保留时长，超过后由主节点清理
+	ScheduleInterval time.Duration `yaml:"scheduleInterval"` // 主节点检查定时巡检是否到期的间隔
+}
+
+// JenkinsConfig Jenkins 集成配置，服务器地址和凭据存储在 Postgres 中
+type JenkinsConfig struct {
+	LogPollInterval time.Duration `yaml:"logPollInterval"` // 跟踪构建控制台输出时轮询 Jenkins 的间隔
+}
+
+// NotificationConfig 通知配置，渠道和路由规则存储在 Postgres 中
+type NotificationConfig struct {
+	WatchInterval time.Duration `yaml:"watchInterval"` // 主节点检查节点 NotReady 和发布失败的间隔
+	DedupWindow   time.Duration `yaml:"dedupWindow"`   // 同一事件在该时长内只向同一渠道发送一次
+	RateLimit     int           `yaml:"rateLimit"`     // 每个渠道每分钟最多发送的通知数，0 表示不限制
+	MaxAttempts   int           `yaml:"maxAttempts"`   // 发送失败时的最大尝试次数（含第一次）
+	RetryBackoff  time.Duration `yaml:"retryBackoff"`  // 第一次重试前的等待时间，之后每次翻倍
+	Timeout       time.Duration `yaml:"timeout"`       // 单次发送的超时
+}
+
+// PortForwardConfig 端口转发配置
+type PortForwardConfig struct {
+	IdleTimeout time.Duration `yaml:"idleTimeout"` // 两个方向都没有数据的时长超过该值时断开
+	MaxPerUser  int           `yaml:"maxPerUser"`  // 每个用户在单个副本上同时打开的端口转发数，0 表示不限制
+}
+
+// FileTransferConfig 容器文件上传下载配置
+type FileTransferConfig struct {
+	MaxUploadMB   int64         `yaml:"maxUploadMB"`   // 单次上传的文件内容总大小上限（MiB）
+	MaxDownloadMB int64         `yaml:"maxDownloadMB"` // 单次下载的文件内容总大小上限（MiB）
+	Timeout       time.Duration `yaml:"timeout"`       // 单次传输的最长时间
+}
+
+// DebugConfig 调试容器配置
+type DebugConfig struct {
+	Image         string        `yaml:"image"`         // 未指定镜像时使用的调试镜像
+	AllowedImages []string      `yaml:"allowedImages"` // 允许使用的调试镜像，支持 * 通配符；为空表示不限制
+	StartTimeout  time.Duration `yaml:"startTimeout"`  // 等待调试容器运行的最长时间，包含拉取镜像
 }
 
 type Config struct {
-	Port       string
-	Env        string
-	Kubeconfig string
-	Postgres   PostgresConfig
-	Redis      RedisConfig
+	Port       string          `yaml:"port"`
+	Env        string          `yaml:"env"`
+	Kubeconfig string          `yaml:"kubeconfig"`
+	Clusters   []ClusterConfig `yaml:"clusters"`
+	Postgres   PostgresConfig  `yaml:"postgres"`
+	Redis      RedisConfig     `yaml:"redis"`
+	Auth       AuthConfig      `yaml:"auth"`
+	Cache      CacheConfig     `yaml:"cache"`
+	Tracing    TracingConfig   `yaml:"tracing"`
+	RateLimit  RateLimitConfig `yaml:"rateLimit"`
+	Logs       LogsConfig      `yaml:"logs"`
+
+	Inspection InspectionConfig `yaml:"inspection"`
+	LLM        LLMConfig        `yaml:"llm"`
+	Jenkins    JenkinsConfig    `yaml:"jenkins"`
+
+	Notification NotificationConfig `yaml:"notification"`
+	PortForward  PortForwardConfig  `yaml:"portForward"`
+	FileTransfer FileTransferConfig `yaml:"fileTransfer"`
+	Debug        DebugConfig        `yaml:"debug"`
+
+	LeaderElection LeaderElectionConfig `yaml:"leaderElection"`
+
+	// File 加载的配置文件路径，不出现在配置文件中
+	File string `yaml:"-"`
 }
 
-func Load() Config {
+// Default 返回内置默认配置
+func Default() Config {
 	return Config{
-		Port:       GetEnv("PORT", "8080"),
-		Env:        GetEnv("ENV", ""),
-		Kubeconfig: GetEnv("KUBECONFIG", ""),
-		Postgres: PostgresConfig{
-			Host:     GetEnv("POSTGRES_HOST", ""),
-			Port:     GetEnv("POSTGRES_PORT", ""),
-			User:     GetEnv("POSTGRES_USER", ""),
-			Password: GetEnv("POSTGRES_PASSWORD", ""),
-			Database: GetEnv("POSTGRES_DB", ""),
-			SSLMode:  GetEnv("POSTGRES_SSLMODE", ""),
+		Port: "8080",
+		Auth: AuthConfig{
+			UserClaim: "preferred_username",
+		},
+		Cache: CacheConfig{
+			Enabled:    true,
+			DefaultTTL: 30 * time.Second,
 		},
-		Redis: RedisConfig{
-			Addr:     GetEnv("REDIS_ADDR", ""),
-			Password: GetEnv("REDIS_PASSWORD", ""),
-			DB:       GetEnvInt("REDIS_DB", 0),
+		Tracing: TracingConfig{
+			Endpoint:    "localhost:4318",
+			SampleRatio: 1,
+			ServiceName: "kubeops",
 		},
+		RateLimit: RateLimitConfig{
+			Groups: map[string]RateLimitRule{
+				RateLimitGroupReads:     {Requests: 600, Period: time.Minute},
+				RateLimitGroupMutations: {Requests: 60, Period: time.Minute},
+				RateLimitGroupExec:      {Requests: 20, Period: time.Minute},
+			},
+		},
+		LeaderElection: LeaderElectionConfig{
+			Backend:       LeaderElectionBackendLease,
+			Name:          "kubeops-leader",
+			LeaseDuration: 15 * time.Second,
+			RenewDeadline: 10 * time.Second,
+			RetryPeriod:   2 * time.Second,
+		},
+		Inspection: InspectionConfig{
+			Retention:        30 * 24 * time.Hour,
+			ScheduleInterval: 30 * time.Second,
+		},
+		Jenkins: JenkinsConfig{
+			LogPollInterval: 2 * time.Second,
+		},
+		Notification: NotificationConfig{
+			WatchInterval: time.Minute,
+			DedupWindow:   30 * time.Minute,
+			RateLimit:     30,
+			MaxAttempts:   4,
+			RetryBackoff:  2 * time.Second,
+			Timeout:       10 * time.Second,
+		},
+		PortForward: PortForwardConfig{
+			IdleTimeout: 10 * time.Minute,
+			MaxPerUser:  5,
+		},
+		FileTransfer: FileTransferConfig{
+			MaxUploadMB:   100,
+			MaxDownloadMB: 500,
+			Timeout:       30 * time.Minute,
+		},
+		Debug: DebugConfig{
+			Image:        "busybox:1.36",
+			StartTimeout: 2 * time.Minute,
+		},
+		LLM: LLMConfig{
+			BaseURL:         "https://api.openai.com/v1",
+			Model:           "gpt-4o-mini",
+			Timeout:         60 * time.Second,
+			Temperature:     0.2,
+			JSONMode:        true,
+			MaxInputTokens:  12000,
+			MaxOutputTokens: 1500,
+			Explain: ExplainConfig{
+				LogLines: 100,
+				Events:   20,
+				CacheTTL: time.Hour,
+			},
+			Assistant: AssistantConfig{
+				MaxSteps:            8,
+				MaxToolResultTokens: 4000,
+				EnforceRBAC:         true,
+			},
+		},
+		Logs: LogsConfig{
+			Timeout:      30 * time.Second,
+			MaxLimit:     5000,
+			TailInterval: 2 * time.Second,
+			Loki: LokiConfig{
+				Labels: LogFieldNames{Namespace: "namespace", Pod: "pod", Container: "container"},
+			},
+			Elasticsearch: ElasticsearchConfig{
+				Index: "logstash-*",
+				Fields: LogFieldNames{
+					Timestamp: "@timestamp",
+					Message:   "log",
+					Namespace: "kubernetes.namespace_name",
+					Pod:       "kubernetes.pod_name",
+					Container: "kubernetes.container_name",
+				},
+			},
+		},
+	}
+}
+
+// Load 按 默认值 < 配置文件 < 环境变量 的优先级加载配置，命令行参数由调用方最后覆盖
+// path 为空时跳过配置文件
+func Load(path string) (Config, error) {
+	cfg := Default()
+	cfg.File = path
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+	if err := applyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// loadFile 读取 YAML 配置文件，未知字段视为错误，避免拼写错误被静默忽略
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	// YAML 解码 map 时每个条目从零值开始，先保存默认的限流规则，解码后逐字段合并
+	defaultGroups := maps.Clone(cfg.RateLimit.Groups)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	mergeRateLimitGroups(cfg.RateLimit.Groups, defaultGroups)
+	return nil
+}
+
+// mergeRateLimitGroups 用默认规则补全配置文件中未填写的字段，
+// 如只配置 requests 时沿用默认的 period 和 burst，而不是得到 period 为 0 的规则
+func mergeRateLimitGroups(groups, defaults map[string]RateLimitRule) {
+	for group, rule := range groups {
+		def, ok := defaults[group]
+		if !ok {
+			continue
+		}
+		if rule.Requests == 0 {
+			rule.Requests = def.Requests
+		}
+		if rule.Period == 0 {
+			rule.Period = def.Period
+		}
+		if rule.Burst == 0 {
+			rule.Burst = def.Burst
+		}
+		groups[group] = rule
+	}
+}
+
+// applyEnv 使用环境变量覆盖配置，未设置的变量保留原值
+func applyEnv(cfg *Config) error {
+	var errs []error
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	var err error
+
+	cfg.Port = GetEnv("PORT", cfg.Port)
+	cfg.Env = GetEnv("ENV", cfg.Env)
+	cfg.Kubeconfig = GetEnv("KUBECONFIG", cfg.Kubeconfig)
+
+	cfg.Postgres.Host = GetEnv("POSTGRES_HOST", cfg.Postgres.Host)
+	cfg.Postgres.Port = GetEnv("POSTGRES_PORT", cfg.Postgres.Port)
+	cfg.Postgres.User = GetEnv("POSTGRES_USER", cfg.Postgres.User)
+	cfg.Postgres.Password = GetEnv("POSTGRES_PASSWORD", cfg.Postgres.Password)
+	cfg.Postgres.Database = GetEnv("POSTGRES_DB", cfg.Postgres.Database)
+	cfg.Postgres.SSLMode = GetEnv("POSTGRES_SSLMODE", cfg.Postgres.SSLMode)
+
+	cfg.Redis.Addr = GetEnv("REDIS_ADDR", cfg.Redis.Addr)
+	cfg.Redis.Password = GetEnv("REDIS_PASSWORD", cfg.Redis.Password)
+	cfg.Redis.DB, err = GetEnvInt("REDIS_DB", cfg.Redis.DB)
+	check(err)
+
+	cfg.Auth.Enabled, err = GetEnvBool("AUTH_ENABLED", cfg.Auth.Enabled)
+	check(err)
+	cfg.Auth.JWTSecret = GetEnv("AUTH_JWT_SECRET", cfg.Auth.JWTSecret)
+	cfg.Auth.UserClaim = GetEnv("AUTH_USER_CLAIM", cfg.Auth.UserClaim)
+	cfg.Auth.UserHeader = GetEnv("AUTH_USER_HEADER", cfg.Auth.UserHeader)
+	cfg.Auth.TrustGateway, err = GetEnvBool("AUTH_TRUST_GATEWAY", cfg.Auth.TrustGateway)
+	check(err)
+
+	cfg.Cache.Enabled, err = GetEnvBool("CACHE_ENABLED", cfg.Cache.Enabled)
+	check(err)
+	cfg.Cache.DefaultTTL, err = GetEnvDuration("CACHE_DEFAULT_TTL", cfg.Cache.DefaultTTL)
+	check(err)
+
+	cfg.Tracing.Enabled, err = GetEnvBool("TRACING_ENABLED", cfg.Tracing.Enabled)
+	check(err)
+	cfg.Tracing.Endpoint = GetEnv("TRACING_ENDPOINT", cfg.Tracing.Endpoint)
+	cfg.Tracing.Insecure, err = GetEnvBool("TRACING_INSECURE", cfg.Tracing.Insecure)
+	check(err)
+	cfg.Tracing.SampleRatio, err = GetEnvFloat("TRACING_SAMPLE_RATIO", cfg.Tracing.SampleRatio)
+	check(err)
+	cfg.Tracing.ServiceName = GetEnv("TRACING_SERVICE_NAME", cfg.Tracing.ServiceName)
+
+	cfg.RateLimit.Enabled, err = GetEnvBool("RATE_LIMIT_ENABLED", cfg.RateLimit.Enabled)
+	check(err)
+
+	cfg.LeaderElection.Enabled, err = GetEnvBool("LEADER_ELECTION_ENABLED", cfg.LeaderElection.Enabled)
+	check(err)
+	cfg.LeaderElection.Backend = GetEnv("LEADER_ELECTION_BACKEND", cfg.LeaderElection.Backend)
+	cfg.LeaderElection.Namespace = GetEnv("LEADER_ELECTION_NAMESPACE", cfg.LeaderElection.Namespace)
+
+	cfg.Logs.Backend = GetEnv("LOGS_BACKEND", cfg.Logs.Backend)
+	cfg.Logs.Loki.URL = GetEnv("LOKI_URL", cfg.Logs.Loki.URL)
+	cfg.Logs.Loki.TenantID = GetEnv("LOKI_TENANT_ID", cfg.Logs.Loki.TenantID)
+	cfg.Logs.Loki.Password = GetEnv("LOKI_PASSWORD", cfg.Logs.Loki.Password)
+	cfg.Logs.Loki.BearerToken = GetEnv("LOKI_BEARER_TOKEN", cfg.Logs.Loki.BearerToken)
+	cfg.Logs.Elasticsearch.URL = GetEnv("ELASTICSEARCH_URL", cfg.Logs.Elasticsearch.URL)
+	cfg.Logs.Elasticsearch.Index = GetEnv("ELASTICSEARCH_INDEX", cfg.Logs.Elasticsearch.Index)
+	cfg.Logs.Elasticsearch.Password = GetEnv("ELASTICSEARCH_PASSWORD", cfg.Logs.Elasticsearch.Password)
+	cfg.Logs.Elasticsearch.APIKey = GetEnv("ELASTICSEARCH_API_KEY", cfg.Logs.Elasticsearch.APIKey)
+
+	cfg.LLM.Provider = GetEnv("LLM_PROVIDER", cfg.LLM.Provider)
+	cfg.LLM.BaseURL = GetEnv("LLM_BASE_URL", cfg.LLM.BaseURL)
+	cfg.LLM.APIKey = GetEnv("LLM_API_KEY", cfg.LLM.APIKey)
+	cfg.LLM.Model = GetEnv("LLM_MODEL", cfg.LLM.Model)
+	cfg.LLM.Assistant.EnforceRBAC, err = GetEnvBool("ASSISTANT_ENFORCE_RBAC", cfg.LLM.Assistant.EnforceRBAC)
+	check(err)
+
+	return errors.Join(errs...)
+}
+
+// ClusterList 返回生效的集群列表
+// 未配置 clusters 时，使用 kubeconfig 字段构造名为 default 的单集群，兼容旧的部署方式
+func (c Config) ClusterList() []ClusterConfig {
+	if len(c.Clusters) > 0 {
+		return c.Clusters
+	}
+	return []ClusterConfig{{Name: DefaultClusterName, Kubeconfig: c.Kubeconfig}}
+}
+
+// hotReloadSections 运行中修改后立即生效的配置段，其余配置段修改后需要重启
+var hotReloadSections = map[string]bool{"kubeconfig": true, "clusters": true, "rateLimit": true}
+
+// RestartRequired 返回相对 old 发生变化、但需要重启才能生效的配置段（yaml 名），按字段定义顺序排列
+func (c Config) RestartRequired(old Config) []string {
+	var sections []string
+	cur, prev := reflect.ValueOf(c), reflect.ValueOf(old)
+	for i := 0; i < cur.NumField(); i++ {
+		name, _, _ := strings.Cut(cur.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" || hotReloadSections[name] {
+			continue
+		}
+		if !reflect.DeepEqual(cur.Field(i).Interface(), prev.Field(i).Interface()) {
+			sections = append(sections, name)
+		}
 	}
+	return sections
+}
+
+// DefaultClusterName 未配置集群列表时的集群名
+const DefaultClusterName = "default"
+
+// CacheTTL 返回指定资源类型的缓存 TTL
+func (c CacheConfig) CacheTTL(resource string) time.Duration {
+	if ttl, ok := c.TTLs[resource]; ok {
+		return ttl
+	}
+	return c.DefaultTTL
+}
+
+const redacted = "******"
+
+// Redacted 返回隐藏了密码、密钥等敏感字段的配置副本，用于打印和日志
+func (c Config) Redacted() Config {
+	out := c
+	if out.Postgres.Password != "" {
+		out.Postgres.Password = redacted
+	}
+	if out.Redis.Password != "" {
+		out.Redis.Password = redacted
+	}
+	if out.Auth.JWTSecret != "" {
+		out.Auth.JWTSecret = redacted
+	}
+	for _, secret := range []*string{
+		&out.Logs.Loki.Password,
+		&out.Logs.Loki.BearerToken,
+		&out.Logs.Elasticsearch.Password,
+		&out.Logs.Elasticsearch.APIKey,
+		&out.LLM.APIKey,
+	} {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	return out
 }
 
 func GetEnv(key, fallback string) string {
@@ -53,17 +564,54 @@ func GetEnv(key, fallback string) string {
 	return fallback
 }
 
-func GetEnvInt(key string, fallback int) int {
+// GetEnvInt 读取整数环境变量，未设置时返回 fallback，格式错误时返回错误
+func GetEnvInt(key string, fallback int) (int, error) {
 	value := os.Getenv(key)
 	if value == "" {
-		return fallback
+		return fallback, nil
 	}
-	n := 0
-	for _, c := range value {
-		if c < '0' || c > '9' {
-			return fallback
-		}
-		n = n*10 + int(c-'0')
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback, fmt.Errorf("environment variable %s: invalid integer %q", key, value)
+	}
+	return n, nil
+}
+
+// GetEnvBool 读取布尔环境变量（true/false/1/0 等）
+func GetEnvBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback, fmt.Errorf("environment variable %s: invalid boolean %q", key, value)
+	}
+	return b, nil
+}
+
+// GetEnvFloat 读取浮点数环境变量
+func GetEnvFloat(key string, fallback float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fallback, fmt.Errorf("environment variable %s: invalid number %q", key, value)
+	}
+	return f, nil
+}
+
+// GetEnvDuration 读取时长环境变量（如 30s、5m）
+func GetEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback, fmt.Errorf("environment variable %s: invalid duration %q", key, value)
 	}
-	return n
+	return d, nil
 }
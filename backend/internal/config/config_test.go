@@ -0,0 +1,60 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRestartRequired(t *testing.T) {
+	old := Default()
+	cfg := Default()
+	if got := cfg.RestartRequired(old); len(got) != 0 {
+		t.Fatalf("RestartRequired() on equal configs = %v", got)
+	}
+
+	// 集群和限流热加载，不需要重启
+	cfg.Clusters = []ClusterConfig{{Name: "prod", Kubeconfig: "/etc/kubeops/prod"}}
+	cfg.RateLimit.Enabled = true
+	cfg.Auth.JWTSecret = "secret"
+	cfg.Cache.TTLs = map[string]time.Duration{"pods": time.Minute}
+	cfg.LeaderElection.Name = "other"
+	cfg.Port = "9090"
+
+	want := []string{"port", "auth", "cache", "leaderElection"}
+	if got := cfg.RestartRequired(old); !slices.Equal(got, want) {
+		t.Errorf("RestartRequired() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadRateLimitGroups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+rateLimit:
+  groups:
+    reads:
+      requests: 1200
+    exec:
+      burst: 5
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// 部分配置的路由组逐字段合并到默认值上，未出现的路由组保持默认
+	want := map[string]RateLimitRule{
+		RateLimitGroupReads:     {Requests: 1200, Period: time.Minute},
+		RateLimitGroupMutations: {Requests: 60, Period: time.Minute},
+		RateLimitGroupExec:      {Requests: 20, Period: time.Minute, Burst: 5},
+	}
+	if !maps.Equal(cfg.RateLimit.Groups, want) {
+		t.Errorf("groups = %+v, want %+v", cfg.RateLimit.Groups, want)
+	}
+}
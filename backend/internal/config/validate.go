@@ -0,0 +1,352 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// FieldError 指明具体字段的配置校验错误
+// Field 使用配置文件中的路径，如 postgres.host、clusters[1].name
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func fieldErr(field, format string, args ...interface{}) error {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+var validSSLModes = map[string]bool{
+	"":            true,
+	"disable":     true,
+	"allow":       true,
+	"prefer":      true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// Validate 校验完整的服务配置，返回所有字段错误
+func (c Config) Validate() error {
+	var errs []error
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fieldErr("port", "must be a port number between 1 and 65535, got %q (--port/PORT)", c.Port))
+	}
+
+	seen := make(map[string]int)
+	for i, cluster := range c.Clusters {
+		field := fmt.Sprintf("clusters[%d]", i)
+		if cluster.Name == "" {
+			errs = append(errs, fieldErr(field+".name", "is required"))
+		} else if j, dup := seen[cluster.Name]; dup {
+			errs = append(errs, fieldErr(field+".name", "duplicates clusters[%d].name %q", j, cluster.Name))
+		} else {
+			seen[cluster.Name] = i
+		}
+		if cluster.InCluster && cluster.Kubeconfig != "" {
+			errs = append(errs, fieldErr(field+".kubeconfig", "must be empty when inCluster is true"))
+		}
+	}
+
+	errs = append(errs, c.Postgres.Validate())
+
+	if c.Redis.Addr == "" {
+		errs = append(errs, fieldErr("redis.addr", "is required (--redis-addr/REDIS_ADDR)"))
+	}
+	if c.Redis.DB < 0 {
+		errs = append(errs, fieldErr("redis.db", "must not be negative, got %d", c.Redis.DB))
+	}
+
+	if c.Auth.Enabled && c.Auth.UserClaim == "" && c.Auth.UserHeader == "" {
+		errs = append(errs, fieldErr("auth.userClaim", "is required when auth.enabled is true and auth.userHeader is empty"))
+	}
+	if c.Auth.Enabled && !c.Auth.TrustGateway {
+		// 不校验签名时任何人都可以伪造 Token 中的用户名和用户组
+		if c.Auth.JWTSecret == "" {
+			errs = append(errs, fieldErr("auth.jwtSecret", "is required when auth.enabled is true, unless auth.trustGateway is true (AUTH_JWT_SECRET/AUTH_TRUST_GATEWAY)"))
+		}
+		if c.Auth.UserHeader != "" {
+			errs = append(errs, fieldErr("auth.userHeader", "requires auth.trustGateway to be true, the header can only be trusted behind a gateway"))
+		}
+	}
+
+	if c.Cache.DefaultTTL < 0 {
+		errs = append(errs, fieldErr("cache.defaultTTL", "must not be negative, got %s", c.Cache.DefaultTTL))
+	}
+	resources := make([]string, 0, len(c.Cache.TTLs))
+	for resource := range c.Cache.TTLs {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	for _, resource := range resources {
+		if ttl := c.Cache.TTLs[resource]; ttl < 0 {
+			errs = append(errs, fieldErr("cache.ttls."+resource, "must not be negative, got %s", ttl))
+		}
+	}
+
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		errs = append(errs, fieldErr("tracing.endpoint", "is required when tracing.enabled is true (--tracing-endpoint/TRACING_ENDPOINT)"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fieldErr("tracing.sampleRatio", "must be between 0 and 1, got %g", c.Tracing.SampleRatio))
+	}
+
+	groups := make([]string, 0, len(c.RateLimit.Groups))
+	for group := range c.RateLimit.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		field := "rateLimit.groups." + group
+		rule := c.RateLimit.Groups[group]
+		if !validRateLimitGroups[group] {
+			errs = append(errs, fieldErr(field, "unknown route group, must be one of reads, mutations, exec"))
+		}
+		if rule.Requests <= 0 {
+			errs = append(errs, fieldErr(field+".requests", "must be positive, got %d", rule.Requests))
+		}
+		if rule.Period <= 0 {
+			errs = append(errs, fieldErr(field+".period", "must be positive, got %s", rule.Period))
+		}
+		if rule.Burst < 0 {
+			errs = append(errs, fieldErr(field+".burst", "must not be negative, got %d", rule.Burst))
+		}
+	}
+	for i, cidr := range c.RateLimit.ExemptCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fieldErr(fmt.Sprintf("rateLimit.exemptCIDRs[%d]", i), "invalid CIDR %q", cidr))
+		}
+	}
+
+	if le := c.LeaderElection; le.Enabled {
+		if le.Backend != LeaderElectionBackendLease && le.Backend != LeaderElectionBackendRedis {
+			errs = append(errs, fieldErr("leaderElection.backend", "must be lease or redis, got %q", le.Backend))
+		}
+		if le.Name == "" {
+			errs = append(errs, fieldErr("leaderElection.name", "is required"))
+		}
+		if le.Cluster != "" && !clusterExists(c.ClusterList(), le.Cluster) {
+			errs = append(errs, fieldErr("leaderElection.cluster", "unknown cluster %q", le.Cluster))
+		}
+		// 与 client-go leaderelection 的约束一致
+		if le.RetryPeriod <= 0 {
+			errs = append(errs, fieldErr("leaderElection.retryPeriod", "must be positive, got %s", le.RetryPeriod))
+		}
+		if le.RenewDeadline <= time.Duration(1.2*float64(le.RetryPeriod)) {
+			errs = append(errs, fieldErr("leaderElection.renewDeadline", "must be greater than 1.2 * retryPeriod"))
+		}
+		if le.LeaseDuration <= le.RenewDeadline {
+			errs = append(errs, fieldErr("leaderElection.leaseDuration", "must be greater than renewDeadline"))
+		}
+	}
+
+	errs = append(errs, c.Logs.Validate())
+
+	errs = append(errs, c.LLM.Validate())
+
+	if c.Inspection.Retention <= 0 {
+		errs = append(errs, fieldErr("inspection.retention", "must be positive, got %s", c.Inspection.Retention))
+	}
+	if c.Inspection.ScheduleInterval <= 0 {
+		errs = append(errs, fieldErr("inspection.scheduleInterval", "must be positive, got %s", c.Inspection.ScheduleInterval))
+	}
+	if c.Jenkins.LogPollInterval <= 0 {
+		errs = append(errs, fieldErr("jenkins.logPollInterval", "must be positive, got %s", c.Jenkins.LogPollInterval))
+	}
+
+	errs = append(errs, c.Notification.Validate())
+
+	if c.PortForward.IdleTimeout <= 0 {
+		errs = append(errs, fieldErr("portForward.idleTimeout", "must be positive, got %s", c.PortForward.IdleTimeout))
+	}
+	if c.PortForward.MaxPerUser < 0 {
+		errs = append(errs, fieldErr("portForward.maxPerUser", "must not be negative, got %d", c.PortForward.MaxPerUser))
+	}
+	if c.FileTransfer.MaxUploadMB <= 0 {
+		errs = append(errs, fieldErr("fileTransfer.maxUploadMB", "must be positive, got %d", c.FileTransfer.MaxUploadMB))
+	}
+	if c.FileTransfer.MaxDownloadMB <= 0 {
+		errs = append(errs, fieldErr("fileTransfer.maxDownloadMB", "must be positive, got %d", c.FileTransfer.MaxDownloadMB))
+	}
+	if c.FileTransfer.Timeout <= 0 {
+		errs = append(errs, fieldErr("fileTransfer.timeout", "must be positive, got %s", c.FileTransfer.Timeout))
+	}
+	if c.Debug.Image == "" {
+		errs = append(errs, fieldErr("debug.image", "is required"))
+	}
+	for i, pattern := range c.Debug.AllowedImages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fieldErr(fmt.Sprintf("debug.allowedImages[%d]", i), "invalid pattern %q: %v", pattern, err))
+		}
+	}
+	if c.Debug.StartTimeout <= 0 {
+		errs = append(errs, fieldErr("debug.startTimeout", "must be positive, got %s", c.Debug.StartTimeout))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate 校验通知配置
+func (c NotificationConfig) Validate() error {
+	var errs []error
+	if c.WatchInterval <= 0 {
+		errs = append(errs, fieldErr("notification.watchInterval", "must be positive, got %s", c.WatchInterval))
+	}
+	if c.DedupWindow < 0 {
+		errs = append(errs, fieldErr("notification.dedupWindow", "must not be negative, got %s", c.DedupWindow))
+	}
+	if c.RateLimit < 0 {
+		errs = append(errs, fieldErr("notification.rateLimit", "must not be negative, got %d", c.RateLimit))
+	}
+	if c.MaxAttempts <= 0 {
+		errs = append(errs, fieldErr("notification.maxAttempts", "must be positive, got %d", c.MaxAttempts))
+	}
+	if c.RetryBackoff < 0 {
+		errs = append(errs, fieldErr("notification.retryBackoff", "must not be negative, got %s", c.RetryBackoff))
+	}
+	if c.Timeout <= 0 {
+		errs = append(errs, fieldErr("notification.timeout", "must be positive, got %s", c.Timeout))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate 校验日志检索后端配置
+func (c LogsConfig) Validate() error {
+	var errs []error
+	switch c.Backend {
+	case "":
+		return nil
+	case LogsBackendLoki:
+		errs = append(errs, validateURL("logs.loki.url", c.Loki.URL, "LOKI_URL"))
+	case LogsBackendElasticsearch:
+		errs = append(errs, validateURL("logs.elasticsearch.url", c.Elasticsearch.URL, "ELASTICSEARCH_URL"))
+		if c.Elasticsearch.Index == "" {
+			errs = append(errs, fieldErr("logs.elasticsearch.index", "is required"))
+		}
+		if c.Elasticsearch.Fields.Timestamp == "" || c.Elasticsearch.Fields.Message == "" {
+			errs = append(errs, fieldErr("logs.elasticsearch.fields", "timestamp and message are required"))
+		}
+	default:
+		errs = append(errs, fieldErr("logs.backend", "must be loki or elasticsearch, got %q (LOGS_BACKEND)", c.Backend))
+	}
+	if c.Timeout <= 0 {
+		errs = append(errs, fieldErr("logs.timeout", "must be positive, got %s", c.Timeout))
+	}
+	if c.MaxLimit <= 0 {
+		errs = append(errs, fieldErr("logs.maxLimit", "must be positive, got %d", c.MaxLimit))
+	}
+	if c.TailInterval <= 0 {
+		errs = append(errs, fieldErr("logs.tailInterval", "must be positive, got %s", c.TailInterval))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate 校验大模型配置
+func (c LLMConfig) Validate() error {
+	var errs []error
+	switch c.Provider {
+	case "":
+		return nil
+	case LLMProviderOpenAI:
+		errs = append(errs, validateURL("llm.baseURL", c.BaseURL, "LLM_BASE_URL"))
+		if c.Model == "" {
+			errs = append(errs, fieldErr("llm.model", "is required (LLM_MODEL)"))
+		}
+	case LLMProviderStub:
+	default:
+		errs = append(errs, fieldErr("llm.provider", "must be openai or stub, got %q (LLM_PROVIDER)", c.Provider))
+	}
+	if c.Timeout <= 0 {
+		errs = append(errs, fieldErr("llm.timeout", "must be positive, got %s", c.Timeout))
+	}
+	if c.Temperature < 0 || c.Temperature > 2 {
+		errs = append(errs, fieldErr("llm.temperature", "must be between 0 and 2, got %g", c.Temperature))
+	}
+	if c.MaxInputTokens <= 0 {
+		errs = append(errs, fieldErr("llm.maxInputTokens", "must be positive, got %d", c.MaxInputTokens))
+	}
+	if c.MaxOutputTokens <= 0 {
+		errs = append(errs, fieldErr("llm.maxOutputTokens", "must be positive, got %d", c.MaxOutputTokens))
+	}
+	if c.DailyTokenBudget < 0 {
+		errs = append(errs, fieldErr("llm.dailyTokenBudget", "must not be negative, got %d", c.DailyTokenBudget))
+	}
+	if c.Explain.LogLines <= 0 {
+		errs = append(errs, fieldErr("llm.explain.logLines", "must be positive, got %d", c.Explain.LogLines))
+	}
+	if c.Explain.Events < 0 {
+		errs = append(errs, fieldErr("llm.explain.events", "must not be negative, got %d", c.Explain.Events))
+	}
+	if c.Explain.CacheTTL < 0 {
+		errs = append(errs, fieldErr("llm.explain.cacheTTL", "must not be negative, got %s", c.Explain.CacheTTL))
+	}
+	if c.Assistant.MaxSteps <= 0 {
+		errs = append(errs, fieldErr("llm.assistant.maxSteps", "must be positive, got %d", c.Assistant.MaxSteps))
+	}
+	if c.Assistant.MaxToolResultTokens <= 0 {
+		errs = append(errs, fieldErr("llm.assistant.maxToolResultTokens", "must be positive, got %d", c.Assistant.MaxToolResultTokens))
+	}
+	return errors.Join(errs...)
+}
+
+func validateURL(field, value, env string) error {
+	if value == "" {
+		return fieldErr(field, "is required (%s)", env)
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fieldErr(field, "must be an http(s) URL, got %q", value)
+	}
+	return nil
+}
+
+func clusterExists(clusters []ClusterConfig, name string) bool {
+	for _, cluster := range clusters {
+		if cluster.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+var validRateLimitGroups = map[string]bool{
+	RateLimitGroupReads:     true,
+	RateLimitGroupMutations: true,
+	RateLimitGroupExec:      true,
+}
+
+// Validate 校验 PostgreSQL 连接配置
+func (c PostgresConfig) Validate() error {
+	var errs []error
+	required := []struct {
+		field string
+		value string
+		hint  string
+	}{
+		{"postgres.host", c.Host, "--postgres-host/POSTGRES_HOST"},
+		{"postgres.port", c.Port, "--postgres-port/POSTGRES_PORT"},
+		{"postgres.user", c.User, "--postgres-user/POSTGRES_USER"},
+		{"postgres.password", c.Password, "--postgres-password/POSTGRES_PASSWORD"},
+		{"postgres.database", c.Database, "--postgres-db/POSTGRES_DB"},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			errs = append(errs, fieldErr(r.field, "is required (%s)", r.hint))
+		}
+	}
+	if !validSSLModes[c.SSLMode] {
+		errs = append(errs, fieldErr("postgres.sslmode", "unsupported value %q", c.SSLMode))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,281 @@
+// Package diagnosis 基于大模型的 Pod 根因分析：收集上下文、脱敏、按 token 预算组装提示词并解析结构化结论
+package diagnosis
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/yansongwel/kubeops/backend/internal/llm"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// ContainerLogs 一个容器的最近日志
+type ContainerLogs struct {
+	Container string
+	Previous  bool // 上一次重启前的容器日志
+	Lines     []string
+}
+
+// PodContext 分析一个 Pod 所需的上下文，Pod 必须已经过 RedactPod 处理
+type PodContext struct {
+	Cluster string
+	Pod     *corev1.Pod
+	Events  []corev1.Event
+	Logs    []ContainerLogs
+}
+
+// Fix 一条修复建议
+type Fix struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Commands    []string `json:"commands,omitempty"`
+}
+
+// Diagnosis 模型给出的结构化结论
+type Diagnosis struct {
+	Summary    string   `json:"summary"`
+	RootCause  string   `json:"rootCause"`
+	Severity   string   `json:"severity"`   // critical、warning、info
+	Confidence string   `json:"confidence"` // high、medium、low
+	Evidence   []string `json:"evidence"`
+	Fixes      []Fix    `json:"fixes"`
+}
+
+// Truncation 为满足 token 预算对上下文的截断
+type Truncation struct {
+	Truncated     bool `json:"truncated"`
+	LogLinesKept  int  `json:"logLinesKept"`
+	LogLinesTotal int  `json:"logLinesTotal"`
+	EventsKept    int  `json:"eventsKept"`
+	EventsTotal   int  `json:"eventsTotal"`
+}
+
+const systemPrompt = `你是资深的 Kubernetes SRE，根据用户提供的 Pod 定义、状态、事件和容器日志分析 Pod 异常的根本原因。
+要求：
+- 只依据提供的上下文下结论，证据不足时降低 confidence，不要编造不存在的资源或日志
+- evidence 引用上下文中的具体事件或日志原文
+- 修复建议要可执行，命令使用 kubectl 且带上正确的命名空间
+- 上下文中的 [REDACTED] 是被隐藏的敏感值，不要猜测其内容
+- 使用中文回答，只输出一个 JSON 对象，格式如下：
+{"summary": "一句话结论", "rootCause": "根本原因的详细说明", "severity": "critical|warning|info", "confidence": "high|medium|low", "evidence": ["..."], "fixes": [{"title": "...", "description": "...", "commands": ["kubectl ..."]}]}`
+
+// BuildMessages 组装提示词，总 token 数（估算）超过 maxTokens 时依次截断较早的日志行和事件
+func BuildMessages(pc PodContext, maxTokens int) ([]llm.Message, Truncation) {
+	podYAML := renderPod(pc.Pod)
+	events := sortedEvents(pc.Events)
+	tr := Truncation{EventsTotal: len(events), EventsKept: len(events)}
+	for _, l := range pc.Logs {
+		tr.LogLinesTotal += len(l.Lines)
+	}
+
+	budget := maxTokens - llm.EstimateTokens(systemPrompt) - 16
+	build := func(keepLines, keepEvents int) string {
+		return renderUser(pc, podYAML, events[len(events)-keepEvents:], keepLines)
+	}
+
+	// 每个日志块保留最后 keep 行，二分查找满足预算的最大值
+	maxLines := 0
+	for _, l := range pc.Logs {
+		if len(l.Lines) > maxLines {
+			maxLines = len(l.Lines)
+		}
+	}
+	keepLines, keepEvents := maxLines, len(events)
+	user := build(keepLines, keepEvents)
+	if llm.EstimateTokens(user) > budget {
+		lo, hi := 0, maxLines
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if llm.EstimateTokens(build(mid, keepEvents)) <= budget {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		keepLines = lo
+		// 不带日志仍超出预算时逐条去掉较早的事件
+		for keepLines == 0 && keepEvents > 0 && llm.EstimateTokens(build(0, keepEvents)) > budget {
+			keepEvents--
+		}
+		user = build(keepLines, keepEvents)
+		tr.Truncated = true
+		// Pod 定义本身超出预算时按字符截断
+		if tokens := llm.EstimateTokens(user); tokens > budget && budget > 0 {
+			runes := []rune(user)
+			user = string(runes[:len(runes)*budget/tokens]) + "\n...(已截断)"
+		}
+	}
+	tr.EventsKept = keepEvents
+	for _, l := range pc.Logs {
+		tr.LogLinesKept += min(len(l.Lines), keepLines)
+	}
+
+	return []llm.Message{
+		{Role: llm.RoleSystem, Content: systemPrompt},
+		{Role: llm.RoleUser, Content: user},
+	}, tr
+}
+
+func renderUser(pc PodContext, podYAML string, events []corev1.Event, keepLines int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "集群: %s\nPod: %s/%s\n\n## Pod 定义和状态\n```yaml\n%s```\n\n## 事件（按时间从早到晚）\n",
+		pc.Cluster, pc.Pod.Namespace, pc.Pod.Name, podYAML)
+	if len(events) == 0 {
+		b.WriteString("(无事件)\n")
+	}
+	for _, e := range events {
+		fmt.Fprintf(&b, "- %s %s %s", eventTime(e), e.Type, e.Reason)
+		if e.Count > 1 {
+			fmt.Fprintf(&b, " (x%d)", e.Count)
+		}
+		fmt.Fprintf(&b, ": %s\n", strings.TrimSpace(e.Message))
+	}
+	b.WriteString("\n## 容器日志\n")
+	if len(pc.Logs) == 0 {
+		b.WriteString("(无日志)\n")
+	}
+	for _, l := range pc.Logs {
+		b.WriteString(logHeader(l))
+		lines := l.Lines
+		if len(lines) > keepLines {
+			lines = lines[len(lines)-keepLines:]
+		}
+		if len(lines) == 0 {
+			b.WriteString("(无日志)\n")
+		}
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+func logHeader(l ContainerLogs) string {
+	if l.Previous {
+		return fmt.Sprintf("\n### 容器 %s（上一次重启前）\n", l.Container)
+	}
+	return fmt.Sprintf("\n### 容器 %s\n", l.Container)
+}
+
+// renderPod 以 YAML 输出 Pod，省略对诊断无用的字段
+func renderPod(pod *corev1.Pod) string {
+	view := struct {
+		Metadata map[string]interface{} `json:"metadata"`
+		Spec     corev1.PodSpec         `json:"spec"`
+		Status   corev1.PodStatus       `json:"status"`
+	}{
+		Metadata: map[string]interface{}{
+			"name":      pod.Name,
+			"namespace": pod.Namespace,
+		},
+		Spec:   pod.Spec,
+		Status: pod.Status,
+	}
+	if len(pod.Labels) > 0 {
+		view.Metadata["labels"] = pod.Labels
+	}
+	if len(pod.Annotations) > 0 {
+		view.Metadata["annotations"] = pod.Annotations
+	}
+	if len(pod.OwnerReferences) > 0 {
+		owners := make([]string, 0, len(pod.OwnerReferences))
+		for _, o := range pod.OwnerReferences {
+			owners = append(owners, o.Kind+"/"+o.Name)
+		}
+		view.Metadata["owners"] = owners
+	}
+	data, err := yaml.Marshal(view)
+	if err != nil {
+		return fmt.Sprintf("# 无法序列化 Pod: %v\n", err)
+	}
+	return string(data)
+}
+
+// sortedEvents 按最后发生时间从早到晚排序
+func sortedEvents(events []corev1.Event) []corev1.Event {
+	out := append([]corev1.Event(nil), events...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return EventTimestamp(out[i]).Before(EventTimestamp(out[j]))
+	})
+	return out
+}
+
+// LatestEvents 返回最近的 n 条事件
+func LatestEvents(events []corev1.Event, n int) []corev1.Event {
+	sorted := sortedEvents(events)
+	if len(sorted) > n {
+		sorted = sorted[len(sorted)-n:]
+	}
+	return sorted
+}
+
+// EventTimestamp 事件最后一次发生的时间，兼容 events.k8s.io 写入的 series 字段
+func EventTimestamp(e corev1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
+
+func eventTime(e corev1.Event) string {
+	t := EventTimestamp(e)
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// Parse 解析模型回复中的 JSON 对象，兼容包裹在 Markdown 代码块中或前后带有说明文字的回复
+func Parse(content string) (Diagnosis, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return Diagnosis{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "大模型未返回有效的分析结果", fmt.Errorf("no JSON object in reply: %s", truncate(content, 256)))
+	}
+	var d Diagnosis
+	if err := json.Unmarshal([]byte(content[start:end+1]), &d); err != nil {
+		return Diagnosis{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "大模型未返回有效的分析结果", err)
+	}
+
+	d.Severity = normalize(d.Severity, "info", "critical", "warning", "info")
+	d.Confidence = normalize(d.Confidence, "low", "high", "medium", "low")
+	if d.Evidence == nil {
+		d.Evidence = []string{}
+	}
+	if d.Fixes == nil {
+		d.Fixes = []Fix{}
+	}
+	return d, nil
+}
+
+// normalize 把取值统一为小写，不在 allowed 中时使用 fallback
+func normalize(value, fallback string, allowed ...string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, a := range allowed {
+		if value == a {
+			return value
+		}
+	}
+	return fallback
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
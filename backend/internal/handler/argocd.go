@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/argocd"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// ArgoCDHandler ArgoCD 集成 HTTP处理层
+type ArgoCDHandler struct {
+	argocdService *service.ArgoCDService
+}
+
+// NewArgoCDHandler 创建 ArgoCD Handler
+func NewArgoCDHandler(svc *service.ArgoCDService) *ArgoCDHandler {
+	return &ArgoCDHandler{
+		argocdService: svc,
+	}
+}
+
+// argocdServerRequest 创建或更新 ArgoCD 实例的请求体，apiToken 只写不读
+type argocdServerRequest struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	APIToken       string `json:"apiToken"`
+	Cluster        string `json:"cluster"`
+	TLSSkipVerify  bool   `json:"tlsSkipVerify"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+}
+
+func (r argocdServerRequest) server() repository.ArgoCDServer {
+	return repository.ArgoCDServer{
+		Name:           r.Name,
+		URL:            r.URL,
+		APIToken:       r.APIToken,
+		Cluster:        r.Cluster,
+		TLSSkipVerify:  r.TLSSkipVerify,
+		TimeoutSeconds: r.TimeoutSeconds,
+	}
+}
+
+// ListServers 处理 GET /api/v1/argocd/servers 请求
+func (h *ArgoCDHandler) ListServers(c *gin.Context) {
+	servers, err := h.argocdService.ListServers(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, servers)
+}
+
+// GetServer 处理 GET /api/v1/argocd/servers/:id 请求
+func (h *ArgoCDHandler) GetServer(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	server, err := h.argocdService.GetServer(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, server)
+}
+
+// CreateServer 处理 POST /api/v1/argocd/servers 请求
+func (h *ArgoCDHandler) CreateServer(c *gin.Context) {
+	var req argocdServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	server, err := h.argocdService.CreateServer(c.Request.Context(), req.server())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, server)
+}
+
+// UpdateServer 处理 PUT /api/v1/argocd/servers/:id 请求
+func (h *ArgoCDHandler) UpdateServer(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	var req argocdServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	server := req.server()
+	server.ID = id
+	server, err := h.argocdService.UpdateServer(c.Request.Context(), server)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, server)
+}
+
+// DeleteServer 处理 DELETE /api/v1/argocd/servers/:id 请求
+func (h *ArgoCDHandler) DeleteServer(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	if err := h.argocdService.DeleteServer(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// TestServer 处理 POST /api/v1/argocd/servers/:id/test 请求，返回 Token 对应的账号
+func (h *ArgoCDHandler) TestServer(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	username, err := h.argocdService.TestServer(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, gin.H{"username": username})
+}
+
+// ListApplications 处理 GET /api/v1/argocd/servers/:id/applications?project=default&selector=team=web 请求
+func (h *ArgoCDHandler) ListApplications(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	apps, err := h.argocdService.ListApplications(c.Request.Context(), id, argocd.ListOptions{
+		Project:      c.Query("project"),
+		Selector:     c.Query("selector"),
+		AppNamespace: c.Query("appNamespace"),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, apps)
+}
+
+// GetApplication 处理 GET /api/v1/argocd/servers/:id/applications/:name 请求
+func (h *ArgoCDHandler) GetApplication(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	app, err := h.argocdService.GetApplication(c.Request.Context(), id, c.Param("name"), c.Query("appNamespace"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, app)
+}
+
+// Diff 处理 GET /api/v1/argocd/servers/:id/applications/:name/diff 请求，返回未同步资源的差异
+func (h *ArgoCDHandler) Diff(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	diffs, err := h.argocdService.Diff(c.Request.Context(), id, c.Param("name"), c.Query("appNamespace"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, diffs)
+}
+
+// Sync 处理 POST /api/v1/argocd/servers/:id/applications/:name/sync 请求
+// 请求体可以为空（同步到 targetRevision，不清理多余资源）
+func (h *ArgoCDHandler) Sync(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	var opts argocd.SyncOptions
+	if err := c.ShouldBindJSON(&opts); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	app, err := h.argocdService.Sync(c.Request.Context(), id, c.Param("name"), c.Query("appNamespace"), opts)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, app)
+}
+
+// Rollback 处理 POST /api/v1/argocd/servers/:id/applications/:name/rollback 请求
+func (h *ArgoCDHandler) Rollback(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	var req service.RollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	app, err := h.argocdService.Rollback(c.Request.Context(), id, c.Param("name"), c.Query("appNamespace"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, app)
+}
+
+// Workloads 处理 GET /api/v1/argocd/servers/:id/applications/:name/workloads 请求
+func (h *ArgoCDHandler) Workloads(c *gin.Context) {
+	id, ok := argocdServerID(c)
+	if !ok {
+		return
+	}
+	workloads, err := h.argocdService.Workloads(c.Request.Context(), id, c.Param("name"), c.Query("appNamespace"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, workloads)
+}
+
+func argocdServerID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.Error(c, response.ErrBadRequest("ArgoCD 实例 ID 无效", err))
+		return 0, false
+	}
+	return id, true
+}
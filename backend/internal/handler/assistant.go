@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// AssistantHandler 集群问答助手 HTTP处理层
+type AssistantHandler struct {
+	assistantService *service.AssistantService
+}
+
+// NewAssistantHandler 创建问答助手 Handler
+func NewAssistantHandler(svc *service.AssistantService) *AssistantHandler {
+	return &AssistantHandler{
+		assistantService: svc,
+	}
+}
+
+// ListTools 处理 GET /api/v1/assistant/tools 请求，返回模型可调用的只读工具
+func (h *AssistantHandler) ListTools(c *gin.Context) {
+	response.Success(c, h.assistantService.Tools())
+}
+
+// Chat 处理 POST /api/v1/assistant/chat 请求，以 SSE 推送工具调用、工具结果和回答
+// 请求无效时直接返回 JSON 错误；开始推送后出错时推送 error 事件
+func (h *AssistantHandler) Chat(c *gin.Context) {
+	var req service.AssistantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	if req.Cluster == "" {
+		req.Cluster = c.Query("cluster")
+	}
+
+	ctx := c.Request.Context()
+	streaming := false
+	err := h.assistantService.Chat(ctx, req, func(event string, data interface{}) error {
+		if !streaming {
+			streaming = true
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("X-Accel-Buffering", "no") // 关闭 Nginx/网关的响应缓冲
+			c.Status(http.StatusOK)
+		}
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+		return ctx.Err()
+	})
+	switch {
+	case err == nil:
+	case !streaming:
+		response.Error(c, err)
+	case ctx.Err() == nil:
+		logging.FromContext(ctx).Warn("Assistant chat stopped", zap.Error(err))
+		c.SSEvent(service.AssistantEventError, gin.H{"message": response.FromError(err).Message})
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// AuditHandler 审计日志 HTTP处理层
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler 创建审计日志 Handler
+func NewAuditHandler(svc *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: svc,
+	}
+}
+
+// ListAuditLogs 处理 GET /api/v1/audit-logs 请求
+// 支持 username、action、cluster 过滤，before、limit 翻页
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	f := repository.AuditFilter{
+		Username: c.Query("username"),
+		Action:   c.Query("action"),
+		Cluster:  c.Query("cluster"),
+	}
+	var err error
+	if v := c.Query("before"); v != "" {
+		if f.Before, err = strconv.ParseInt(v, 10, 64); err != nil {
+			response.Error(c, response.ErrBadRequest("before 参数无效", err))
+			return
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil {
+			response.Error(c, response.ErrBadRequest("limit 参数无效", err))
+			return
+		}
+	}
+
+	logs, err := h.auditService.List(c.Request.Context(), f)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, logs)
+}
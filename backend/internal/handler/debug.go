@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// DebugHandler 调试容器 HTTP处理层
+type DebugHandler struct {
+	debugService *service.DebugService
+}
+
+// NewDebugHandler 创建调试容器 Handler
+func NewDebugHandler(svc *service.DebugService) *DebugHandler {
+	return &DebugHandler{
+		debugService: svc,
+	}
+}
+
+// CreateEphemeral 处理 POST /api/v1/namespaces/:namespace/pods/:name/debug 请求，请求体可以为空
+// 容器运行后返回，之后通过 terminal 给出的 attach 地址连接
+// 对应Shell: kubectl debug -it $NAME -n $NAMESPACE --image=busybox:1.36 --target=$CONTAINER
+func (h *DebugHandler) CreateEphemeral(c *gin.Context) {
+	var req service.EphemeralDebugRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	req.Cluster = c.Query("cluster")
+	req.Namespace = c.Param("namespace")
+	req.Pod = c.Param("name")
+
+	container, err := h.debugService.CreateEphemeral(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, container)
+}
+
+// CreateCopy 处理 POST /api/v1/namespaces/:namespace/pods/:name/debug-copy 请求，请求体可以为空
+// 副本不会自动删除，调试结束后需要手动删除
+// 对应Shell: kubectl debug $NAME -n $NAMESPACE -it --copy-to=$NAME-debug --container=$CONTAINER -- sh
+func (h *DebugHandler) CreateCopy(c *gin.Context) {
+	var req service.CopyDebugRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	req.Cluster = c.Query("cluster")
+	req.Namespace = c.Param("namespace")
+	req.Pod = c.Param("name")
+
+	container, err := h.debugService.CreateCopy(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, container)
+}
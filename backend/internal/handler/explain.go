@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// ExplainHandler 根因分析 HTTP处理层
+type ExplainHandler struct {
+	explainService *service.ExplainService
+}
+
+// NewExplainHandler 创建根因分析 Handler
+func NewExplainHandler(svc *service.ExplainService) *ExplainHandler {
+	return &ExplainHandler{
+		explainService: svc,
+	}
+}
+
+// ExplainPod 处理 POST /api/v1/namespaces/:namespace/pods/:name/explain 请求
+// 支持 container、tailLines、refresh 参数
+func (h *ExplainHandler) ExplainPod(c *gin.Context) {
+	opts := service.ExplainOptions{Container: c.Query("container")}
+	if v := c.Query("tailLines"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			response.Error(c, response.ErrBadRequest("tailLines 参数无效", err))
+			return
+		}
+		opts.TailLines = n
+	}
+	opts.Refresh, _ = strconv.ParseBool(c.Query("refresh"))
+
+	result, err := h.explainService.ExplainPod(c.Request.Context(), c.Query("cluster"), c.Param("namespace"), c.Param("name"), opts)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// 传输 ID 响应头；下载开始后发生的错误通过 trailer 返回
+const (
+	transferIDHeader    = "X-Transfer-ID"
+	transferErrorHeader = "X-Transfer-Error"
+)
+
+// tarContentType 上传 tar 包和下载时使用的 Content-Type
+const tarContentType = "application/x-tar"
+
+// FileHandler 容器文件上传下载 HTTP处理层
+type FileHandler struct {
+	fileService *service.FileService
+}
+
+// NewFileHandler 创建容器文件 Handler
+func NewFileHandler(svc *service.FileService) *FileHandler {
+	return &FileHandler{
+		fileService: svc,
+	}
+}
+
+// Download 处理 GET /api/v1/namespaces/:namespace/pods/:name/cp?path=/etc/nginx&container=nginx 请求，返回 tar 包
+// 对应Shell: kubectl cp $NAMESPACE/$NAME:$PATH ./local -c $CONTAINER
+func (h *FileHandler) Download(c *gin.Context) {
+	started := false
+	t, err := h.fileService.Download(c.Request.Context(), fileRequest(c), func(t service.FileTransfer) io.Writer {
+		started = true
+		c.Header("Content-Type", tarContentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(t.Path) + ".tar"}))
+		c.Header(transferIDHeader, t.ID)
+		c.Header("Trailer", transferErrorHeader)
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		return c.Writer
+	})
+	if err == nil {
+		return
+	}
+	if !started {
+		if t.ID != "" {
+			c.Header(transferIDHeader, t.ID)
+		}
+		response.Error(c, err)
+		return
+	}
+	// 响应头已发送，只能在 trailer 中说明错误；tar 包缺少结束标记，客户端解包时也会报错
+	message := response.FromError(err).Message
+	logging.FromContext(c.Request.Context()).Warn("File download failed after streaming started",
+		zap.String("transfer", t.ID), zap.Error(err))
+	c.Writer.Header().Set(transferErrorHeader, message)
+}
+
+// Upload 处理 POST /api/v1/namespaces/:namespace/pods/:name/cp?path=/tmp/data 请求
+// Content-Type 为 application/x-tar 时请求体为 tar 包，解包到目录 path；否则请求体为单个文件的内容，写入文件 path
+// 对应Shell: kubectl cp ./local $NAMESPACE/$NAME:$PATH -c $CONTAINER
+func (h *FileHandler) Upload(c *gin.Context) {
+	archived := false
+	if mediaType, _, err := mime.ParseMediaType(c.ContentType()); err == nil && mediaType == tarContentType {
+		archived = true
+	}
+	t, err := h.fileService.Upload(c.Request.Context(), fileRequest(c), c.Request.Body, archived, c.Request.ContentLength)
+	if t.ID != "" {
+		c.Header(transferIDHeader, t.ID)
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, t)
+}
+
+// GetTransfer 处理 GET /api/v1/file-transfers/:id 请求，查询上传或下载的进度
+func (h *FileHandler) GetTransfer(c *gin.Context) {
+	t, err := h.fileService.GetTransfer(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, t)
+}
+
+func fileRequest(c *gin.Context) service.FileRequest {
+	return service.FileRequest{
+		Cluster:    c.Query("cluster"),
+		Namespace:  c.Param("namespace"),
+		Pod:        c.Param("name"),
+		Container:  c.Query("container"),
+		Path:       c.Query("path"),
+		TransferID: c.Query("transferId"),
+	}
+}
@@ -1,60 +1,87 @@
 package handler
 
 import (
-	"context"
 	"net/http"
-	"time"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/redis/go-redis/v9"
+
+	"github.com/yansongwel/kubeops/backend/internal/health"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
 )
 
 // HealthHandler 健康检查处理器
+// /livez 只反映进程本身是否存活，依赖故障不应触发重启；
+// /readyz 检查 Postgres、Redis、各集群 apiserver、informer 同步和数据库迁移，依赖故障时返回 degraded 而不摘除流量；
+// /startupz 等待各依赖完成首次连接尝试，通过后不再重复检查
 type HealthHandler struct {
-	postgres *pgxpool.Pool
-	redis    *redis.Client
+	liveness  *health.Checker
+	readiness *health.Checker
+	startup   *health.Checker
+	started   atomic.Bool
 }
 
 // NewHealthHandler 创建健康检查处理器
-func NewHealthHandler(postgres *pgxpool.Pool, redisClient *redis.Client) *HealthHandler {
-	return &HealthHandler{postgres: postgres, redis: redisClient}
+func NewHealthHandler(liveness, readiness, startup *health.Checker) *HealthHandler {
+	return &HealthHandler{liveness: liveness, readiness: readiness, startup: startup}
 }
 
-// Health 处理 GET /health 请求
-func (h *HealthHandler) Health(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
-	defer cancel()
-
-	status := "healthy"
-	details := gin.H{}
-
-	if h.postgres != nil {
-		if err := h.postgres.Ping(ctx); err != nil {
-			status = "unhealthy"
-			details["postgres"] = "unreachable"
-		} else {
-			details["postgres"] = "ok"
-		}
+// Livez 处理 GET /livez 请求
+func (h *HealthHandler) Livez(c *gin.Context) {
+	h.respond(c, h.liveness)
+}
+
+// Readyz 处理 GET /readyz 请求
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	h.respond(c, h.readiness)
+}
+
+// Startupz 处理 GET /startupz 请求
+func (h *HealthHandler) Startupz(c *gin.Context) {
+	if h.started.Load() {
+		response.Success(c, gin.H{"status": health.StatusOK})
+		return
+	}
+	if h.respond(c, h.startup) {
+		h.started.Store(true)
 	}
+}
+
+// Health 处理 GET /health 请求，保留给旧的网关和监控配置，等同于 /readyz
+func (h *HealthHandler) Health(c *gin.Context) {
+	h.Readyz(c)
+}
 
-	if h.redis != nil {
-		if err := h.redis.Ping(ctx).Err(); err != nil {
-			status = "unhealthy"
-			details["redis"] = "unreachable"
-		} else {
-			details["redis"] = "ok"
+// respond 执行检查并输出结果
+// 带 ?verbose 时返回每个检查项的明细；失败或降级时总是返回失败的检查项
+func (h *HealthHandler) respond(c *gin.Context, checker *health.Checker) bool {
+	results, status := checker.Run(c.Request.Context())
+	_, verbose := c.GetQuery("verbose")
+
+	data := gin.H{"status": status}
+	if verbose {
+		data["checks"] = results
+		if info := checker.Info(); info != nil {
+			data["info"] = info
+		}
+	} else if status != health.StatusOK {
+		var failed []health.Result
+		for _, r := range results {
+			if r.Status != health.StatusOK {
+				failed = append(failed, r)
+			}
 		}
+		data["checks"] = failed
 	}
 
-	code := http.StatusOK
-	if status != "healthy" {
-		code = http.StatusServiceUnavailable
+	if status == health.StatusFailed {
+		c.JSON(http.StatusServiceUnavailable, response.Response{
+			Code:    response.CodeServiceUnavailable,
+			Message: status,
+			Data:    data,
+		})
+		return false
 	}
-
-	c.JSON(code, gin.H{
-		"status":  status,
-		"service": "kubeops",
-		"details": details,
-	})
+	response.Success(c, data)
+	return true
 }
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// InspectionHandler 集群巡检 HTTP处理层
+type InspectionHandler struct {
+	inspectionService *service.InspectionService
+}
+
+// NewInspectionHandler 创建巡检 Handler
+func NewInspectionHandler(svc *service.InspectionService) *InspectionHandler {
+	return &InspectionHandler{
+		inspectionService: svc,
+	}
+}
+
+// ListChecks 处理 GET /api/v1/inspections/checks 请求
+func (h *InspectionHandler) ListChecks(c *gin.Context) {
+	response.Success(c, h.inspectionService.Checks())
+}
+
+// Inspect 处理 POST /api/v1/inspections 请求，执行巡检并保存记录
+// 请求体可以为空（巡检默认集群的所有检查项）
+func (h *InspectionHandler) Inspect(c *gin.Context) {
+	var req service.InspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	if req.Cluster == "" {
+		req.Cluster = c.Query("cluster")
+	}
+
+	report, err := h.inspectionService.Inspect(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, report)
+}
+
+// ListRuns 处理 GET /api/v1/inspections/runs 请求，支持 schedule、limit 参数
+func (h *InspectionHandler) ListRuns(c *gin.Context) {
+	var scheduleID int64
+	if v := c.Query("schedule"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			response.Error(c, response.ErrBadRequest("schedule 参数无效", err))
+			return
+		}
+		scheduleID = id
+	}
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			response.Error(c, response.ErrBadRequest("limit 参数无效", err))
+			return
+		}
+		limit = n
+	}
+
+	runs, err := h.inspectionService.ListRuns(c.Request.Context(), c.Query("cluster"), scheduleID, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, runs)
+}
+
+// GetRun 处理 GET /api/v1/inspections/runs/:id 请求，?all=true 时包括已确认或屏蔽的问题
+func (h *InspectionHandler) GetRun(c *gin.Context) {
+	id, ok := inspectionID(c, "巡检记录")
+	if !ok {
+		return
+	}
+	all, _ := strconv.ParseBool(c.Query("all"))
+	detail, err := h.inspectionService.GetRun(c.Request.Context(), c.Query("cluster"), id, all)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, detail)
+}
+
+// GetRunDiff 处理 GET /api/v1/inspections/runs/:id/diff 请求
+func (h *InspectionHandler) GetRunDiff(c *gin.Context) {
+	id, ok := inspectionID(c, "巡检记录")
+	if !ok {
+		return
+	}
+	diff, err := h.inspectionService.Diff(c.Request.Context(), c.Query("cluster"), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, diff)
+}
+
+// ListSchedules 处理 GET /api/v1/inspections/schedules 请求
+func (h *InspectionHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.inspectionService.ListSchedules(c.Request.Context(), c.Query("cluster"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, schedules)
+}
+
+// GetSchedule 处理 GET /api/v1/inspections/schedules/:id 请求
+func (h *InspectionHandler) GetSchedule(c *gin.Context) {
+	id, ok := inspectionID(c, "定时巡检")
+	if !ok {
+		return
+	}
+	schedule, err := h.inspectionService.GetSchedule(c.Request.Context(), c.Query("cluster"), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, schedule)
+}
+
+// CreateSchedule 处理 POST /api/v1/inspections/schedules 请求
+func (h *InspectionHandler) CreateSchedule(c *gin.Context) {
+	var req service.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	schedule, err := h.inspectionService.CreateSchedule(c.Request.Context(), c.Query("cluster"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, schedule)
+}
+
+// UpdateSchedule 处理 PUT /api/v1/inspections/schedules/:id 请求
+func (h *InspectionHandler) UpdateSchedule(c *gin.Context) {
+	id, ok := inspectionID(c, "定时巡检")
+	if !ok {
+		return
+	}
+	var req service.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	schedule, err := h.inspectionService.UpdateSchedule(c.Request.Context(), c.Query("cluster"), id, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, schedule)
+}
+
+// DeleteSchedule 处理 DELETE /api/v1/inspections/schedules/:id 请求
+func (h *InspectionHandler) DeleteSchedule(c *gin.Context) {
+	id, ok := inspectionID(c, "定时巡检")
+	if !ok {
+		return
+	}
+	if err := h.inspectionService.DeleteSchedule(c.Request.Context(), c.Query("cluster"), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ListSuppressions 处理 GET /api/v1/inspections/suppressions 请求，?all=true 时包括已过期的
+func (h *InspectionHandler) ListSuppressions(c *gin.Context) {
+	all, _ := strconv.ParseBool(c.Query("all"))
+	suppressions, err := h.inspectionService.ListSuppressions(c.Request.Context(), c.Query("cluster"), all)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, suppressions)
+}
+
+// CreateSuppression 处理 POST /api/v1/inspections/suppressions 请求
+func (h *InspectionHandler) CreateSuppression(c *gin.Context) {
+	var req service.SuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	sup, err := h.inspectionService.CreateSuppression(c.Request.Context(), c.Query("cluster"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, sup)
+}
+
+// DeleteSuppression 处理 DELETE /api/v1/inspections/suppressions/:id 请求
+func (h *InspectionHandler) DeleteSuppression(c *gin.Context) {
+	id, ok := inspectionID(c, "确认或屏蔽规则")
+	if !ok {
+		return
+	}
+	if err := h.inspectionService.DeleteSuppression(c.Request.Context(), c.Query("cluster"), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// inspectionID 解析路径中的 ID，失败时直接返回 400
+func inspectionID(c *gin.Context, resource string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.Error(c, response.ErrBadRequest(resource+" ID 无效", err))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/jenkins"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// JenkinsHandler Jenkins 集成 HTTP处理层
+type JenkinsHandler struct {
+	jenkinsService *service.JenkinsService
+}
+
+// NewJenkinsHandler 创建 Jenkins Handler
+func NewJenkinsHandler(svc *service.JenkinsService) *JenkinsHandler {
+	return &JenkinsHandler{
+		jenkinsService: svc,
+	}
+}
+
+// jenkinsServerRequest 创建或更新 Jenkins 服务器的请求体，apiToken 只写不读
+type jenkinsServerRequest struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	Username       string `json:"username"`
+	APIToken       string `json:"apiToken"`
+	TLSSkipVerify  bool   `json:"tlsSkipVerify"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+}
+
+func (r jenkinsServerRequest) server() repository.JenkinsServer {
+	return repository.JenkinsServer{
+		Name:           r.Name,
+		URL:            r.URL,
+		Username:       r.Username,
+		APIToken:       r.APIToken,
+		TLSSkipVerify:  r.TLSSkipVerify,
+		TimeoutSeconds: r.TimeoutSeconds,
+	}
+}
+
+// ListServers 处理 GET /api/v1/jenkins/servers 请求
+func (h *JenkinsHandler) ListServers(c *gin.Context) {
+	servers, err := h.jenkinsService.ListServers(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, servers)
+}
+
+// GetServer 处理 GET /api/v1/jenkins/servers/:id 请求
+func (h *JenkinsHandler) GetServer(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	server, err := h.jenkinsService.GetServer(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, server)
+}
+
+// CreateServer 处理 POST /api/v1/jenkins/servers 请求
+func (h *JenkinsHandler) CreateServer(c *gin.Context) {
+	var req jenkinsServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	server, err := h.jenkinsService.CreateServer(c.Request.Context(), req.server())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, server)
+}
+
+// UpdateServer 处理 PUT /api/v1/jenkins/servers/:id 请求
+func (h *JenkinsHandler) UpdateServer(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	var req jenkinsServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	server := req.server()
+	server.ID = id
+	server, err := h.jenkinsService.UpdateServer(c.Request.Context(), server)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, server)
+}
+
+// DeleteServer 处理 DELETE /api/v1/jenkins/servers/:id 请求
+func (h *JenkinsHandler) DeleteServer(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	if err := h.jenkinsService.DeleteServer(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// TestServer 处理 POST /api/v1/jenkins/servers/:id/test 请求，校验地址和凭据
+func (h *JenkinsHandler) TestServer(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	if err := h.jenkinsService.TestServer(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// ListJobs 处理 GET /api/v1/jenkins/servers/:id/jobs?folder=team/backend 请求
+func (h *JenkinsHandler) ListJobs(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	jobs, err := h.jenkinsService.ListJobs(c.Request.Context(), id, c.Query("folder"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, jobs)
+}
+
+// GetJob 处理 GET /api/v1/jenkins/servers/:id/job?job=team/backend/api&builds=20 请求
+func (h *JenkinsHandler) GetJob(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	builds := 0
+	if v := c.Query("builds"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(c, response.ErrBadRequest("builds 参数无效", err))
+			return
+		}
+		builds = n
+	}
+	job, err := h.jenkinsService.GetJob(c.Request.Context(), id, c.Query("job"), builds)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, job)
+}
+
+// TriggerBuild 处理 POST /api/v1/jenkins/servers/:id/job/build?job=team/backend/api 请求
+// 请求体 {"parameters": {"BRANCH": "main"}} 可以为空
+func (h *JenkinsHandler) TriggerBuild(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Parameters map[string]string `json:"parameters"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	build, err := h.jenkinsService.TriggerBuild(c.Request.Context(), id, c.Query("job"), req.Parameters)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, build)
+}
+
+// GetQueueItem 处理 GET /api/v1/jenkins/servers/:id/queue/:queueId 请求
+func (h *JenkinsHandler) GetQueueItem(c *gin.Context) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return
+	}
+	queueID, err := strconv.ParseInt(c.Param("queueId"), 10, 64)
+	if err != nil || queueID <= 0 {
+		response.Error(c, response.ErrBadRequest("队列条目 ID 无效", err))
+		return
+	}
+	item, err := h.jenkinsService.GetQueueItem(c.Request.Context(), id, queueID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// GetBuild 处理 GET /api/v1/jenkins/servers/:id/builds/:number?job=team/backend/api 请求
+func (h *JenkinsHandler) GetBuild(c *gin.Context) {
+	id, number, ok := jenkinsBuild(c)
+	if !ok {
+		return
+	}
+	build, err := h.jenkinsService.GetBuild(c.Request.Context(), id, c.Query("job"), number)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, build)
+}
+
+// GetLog 处理 GET /api/v1/jenkins/servers/:id/builds/:number/log?job=...&start=0 请求，返回一段控制台输出
+func (h *JenkinsHandler) GetLog(c *gin.Context) {
+	id, number, ok := jenkinsBuild(c)
+	if !ok {
+		return
+	}
+	start, ok := logStart(c, c.Query("start"))
+	if !ok {
+		return
+	}
+	chunk, err := h.jenkinsService.GetLog(c.Request.Context(), id, c.Query("job"), number, start)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, chunk)
+}
+
+// StreamLog 处理 GET /api/v1/jenkins/servers/:id/builds/:number/log/stream?job=... 请求
+// 以 SSE 推送控制台输出，事件 ID 为下次读取的偏移，断线重连时从 Last-Event-ID 继续
+func (h *JenkinsHandler) StreamLog(c *gin.Context) {
+	id, number, ok := jenkinsBuild(c)
+	if !ok {
+		return
+	}
+	from := c.Query("start")
+	if v := c.GetHeader("Last-Event-ID"); v != "" {
+		from = v
+	}
+	start, ok := logStart(c, from)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // 关闭 Nginx/网关的响应缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	err := h.jenkinsService.StreamLog(ctx, id, c.Query("job"), number, start, func(event string, data interface{}) error {
+		e := sse.Event{Event: event, Data: data}
+		switch d := data.(type) {
+		case jenkins.LogChunk:
+			e.Id = strconv.FormatInt(d.NextStart, 10)
+		case service.JenkinsLogEnd:
+			e.Id = strconv.FormatInt(d.NextStart, 10)
+		}
+		c.Render(-1, e)
+		c.Writer.Flush()
+		return ctx.Err()
+	})
+	if err != nil && ctx.Err() == nil {
+		logging.FromContext(ctx).Warn("Jenkins log stream stopped", zap.Error(err))
+		c.SSEvent(service.JenkinsEventError, gin.H{"message": response.FromError(err).Message})
+		c.Writer.Flush()
+	}
+}
+
+func jenkinsServerID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.Error(c, response.ErrBadRequest("Jenkins 服务器 ID 无效", err))
+		return 0, false
+	}
+	return id, true
+}
+
+func jenkinsBuild(c *gin.Context) (int64, int, bool) {
+	id, ok := jenkinsServerID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number <= 0 {
+		response.Error(c, response.ErrBadRequest("构建号无效", err))
+		return 0, 0, false
+	}
+	return id, number, true
+}
+
+func logStart(c *gin.Context, v string) (int64, bool) {
+	if v == "" {
+		return 0, true
+	}
+	start, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || start < 0 {
+		response.Error(c, response.ErrBadRequest("start 参数无效", err))
+		return 0, false
+	}
+	return start, true
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/logsearch"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// LogHandler 日志检索 HTTP处理层
+type LogHandler struct {
+	logService *service.LogService
+}
+
+// NewLogHandler 创建日志 Handler
+func NewLogHandler(svc *service.LogService) *LogHandler {
+	return &LogHandler{
+		logService: svc,
+	}
+}
+
+// Search 处理 GET /api/v1/logs 请求
+// 对应Shell: logcli query --from=1h --limit=100 '{namespace="default"}'
+func (h *LogHandler) Search(c *gin.Context) {
+	entries, err := h.logService.Search(c.Request.Context(), logQueryParams(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, entries)
+}
+
+// Tail 处理 GET /api/v1/logs/tail 请求，以 Server-Sent Events 持续推送新日志
+// 每条日志为一个 log 事件，查询失败时发送 error 事件后结束
+// 对应Shell: kubectl logs -f
+func (h *LogHandler) Tail(c *gin.Context) {
+	q, err := h.logService.TailQuery(logQueryParams(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // 关闭 Nginx/网关的响应缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	err = h.logService.Tail(ctx, q, func(e logsearch.Entry) error {
+		c.SSEvent("log", e)
+		c.Writer.Flush()
+		return ctx.Err()
+	})
+	if err != nil && ctx.Err() == nil {
+		logging.FromContext(ctx).Warn("Log tail stopped", zap.Error(err))
+		c.SSEvent("error", gin.H{"message": response.FromError(err).Message})
+		c.Writer.Flush()
+	}
+}
+
+func logQueryParams(c *gin.Context) service.LogQueryParams {
+	return service.LogQueryParams{
+		Namespace: c.Query("namespace"),
+		Pod:       c.Query("pod"),
+		Container: c.Query("container"),
+		Contains:  c.Query("contains"),
+		Query:     c.Query("query"),
+		Start:     c.Query("start"),
+		End:       c.Query("end"),
+		Limit:     c.Query("limit"),
+		Direction: c.Query("direction"),
+	}
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// MonitoringHandler 监控数据源和查询代理 HTTP处理层
+type MonitoringHandler struct {
+	monitoringService *service.MonitoringService
+}
+
+// NewMonitoringHandler 创建监控 Handler
+func NewMonitoringHandler(svc *service.MonitoringService) *MonitoringHandler {
+	return &MonitoringHandler{
+		monitoringService: svc,
+	}
+}
+
+// datasourceRequest 创建/更新数据源的请求体
+// secret 只写不读：响应中只返回 hasSecret，更新时留空表示保留原值
+type datasourceRequest struct {
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	URL            string `json:"url"`
+	AuthType       string `json:"authType"`
+	Username       string `json:"username"`
+	Secret         string `json:"secret"`
+	TLSSkipVerify  bool   `json:"tlsSkipVerify"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+	NamespaceLabel string `json:"namespaceLabel"`
+	// AllowClusterQueries 允许不指定 namespace 的集群范围查询
+	AllowClusterQueries bool `json:"allowClusterQueries"`
+	IsDefault           bool `json:"isDefault"`
+}
+
+func (r datasourceRequest) datasource() repository.Datasource {
+	return repository.Datasource{
+		Name:                r.Name,
+		Type:                r.Type,
+		URL:                 r.URL,
+		AuthType:            r.AuthType,
+		Username:            r.Username,
+		Secret:              r.Secret,
+		TLSSkipVerify:       r.TLSSkipVerify,
+		TimeoutSeconds:      r.TimeoutSeconds,
+		NamespaceLabel:      r.NamespaceLabel,
+		AllowClusterQueries: r.AllowClusterQueries,
+		IsDefault:           r.IsDefault,
+	}
+}
+
+// ListDatasources 处理 GET /api/v1/monitoring/datasources 请求
+func (h *MonitoringHandler) ListDatasources(c *gin.Context) {
+	datasources, err := h.monitoringService.ListDatasources(c.Request.Context(), c.Query("cluster"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, datasources)
+}
+
+// GetDatasource 处理 GET /api/v1/monitoring/datasources/:id 请求
+func (h *MonitoringHandler) GetDatasource(c *gin.Context) {
+	id, ok := datasourceID(c)
+	if !ok {
+		return
+	}
+	ds, err := h.monitoringService.GetDatasource(c.Request.Context(), c.Query("cluster"), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, ds)
+}
+
+// CreateDatasource 处理 POST /api/v1/monitoring/datasources 请求
+func (h *MonitoringHandler) CreateDatasource(c *gin.Context) {
+	var req datasourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	ds, err := h.monitoringService.CreateDatasource(c.Request.Context(), c.Query("cluster"), req.datasource())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, ds)
+}
+
+// UpdateDatasource 处理 PUT /api/v1/monitoring/datasources/:id 请求
+func (h *MonitoringHandler) UpdateDatasource(c *gin.Context) {
+	id, ok := datasourceID(c)
+	if !ok {
+		return
+	}
+	var req datasourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	ds := req.datasource()
+	ds.ID = id
+	ds, err := h.monitoringService.UpdateDatasource(c.Request.Context(), c.Query("cluster"), ds)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, ds)
+}
+
+// DeleteDatasource 处理 DELETE /api/v1/monitoring/datasources/:id 请求
+func (h *MonitoringHandler) DeleteDatasource(c *gin.Context) {
+	id, ok := datasourceID(c)
+	if !ok {
+		return
+	}
+	if err := h.monitoringService.DeleteDatasource(c.Request.Context(), c.Query("cluster"), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// Query 处理 GET|POST /api/v1/monitoring/query 请求
+// 对应Shell: curl "$PROM/api/v1/query?query=up"
+func (h *MonitoringHandler) Query(c *gin.Context) {
+	params, ok := queryParams(c)
+	if !ok {
+		return
+	}
+	result, err := h.monitoringService.Query(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// QueryRange 处理 GET|POST /api/v1/monitoring/query_range 请求
+func (h *MonitoringHandler) QueryRange(c *gin.Context) {
+	params, ok := queryParams(c)
+	if !ok {
+		return
+	}
+	result, err := h.monitoringService.QueryRange(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// Series 处理 GET|POST /api/v1/monitoring/series 请求
+func (h *MonitoringHandler) Series(c *gin.Context) {
+	params, ok := queryParams(c)
+	if !ok {
+		return
+	}
+	result, err := h.monitoringService.Series(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// Labels 处理 GET|POST /api/v1/monitoring/labels 请求，?label=<name> 时返回该标签的取值
+func (h *MonitoringHandler) Labels(c *gin.Context) {
+	params, ok := queryParams(c)
+	if !ok {
+		return
+	}
+	result, err := h.monitoringService.Labels(c.Request.Context(), params)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// PodMetrics 处理 GET /api/v1/namespaces/:namespace/pods/:name/metrics 请求
+// 返回 Pod 的 CPU、内存、网络曲线，默认最近 1 小时
+func (h *MonitoringHandler) PodMetrics(c *gin.Context) {
+	params, ok := queryParams(c)
+	if !ok {
+		return
+	}
+	params.Namespace = c.Param("namespace")
+	result, err := h.monitoringService.PodMetrics(c.Request.Context(), params, c.Param("name"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// datasourceID 解析路径中的数据源 ID，失败时直接返回 400
+func datasourceID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.Error(c, response.ErrBadRequest("数据源 ID 无效", err))
+		return 0, false
+	}
+	return id, true
+}
+
+// queryParams 从查询串或表单中读取参数，兼容 Prometheus HTTP API 的参数名
+func queryParams(c *gin.Context) (service.QueryParams, bool) {
+	params := service.QueryParams{
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Query("namespace"),
+		Query:     c.Request.FormValue("query"),
+		Time:      c.Request.FormValue("time"),
+		Start:     c.Request.FormValue("start"),
+		End:       c.Request.FormValue("end"),
+		Step:      c.Request.FormValue("step"),
+		Label:     c.Query("label"),
+	}
+	// FormValue 已解析查询串和表单，match[] 可重复
+	params.Match = c.Request.Form["match[]"]
+
+	if v := c.Query("datasource"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			response.Error(c, response.ErrBadRequest("datasource 参数无效", err))
+			return params, false
+		}
+		params.Datasource = id
+	}
+	if v := c.Request.FormValue("timeout"); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil || timeout <= 0 {
+			response.Error(c, response.ErrBadRequest("timeout 参数无效，示例: 30s", err))
+			return params, false
+		}
+		params.Timeout = timeout
+	}
+	return params, true
+}
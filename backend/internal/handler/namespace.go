@@ -1,10 +1,10 @@
 package handler
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
+
 	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
 )
 
 // NamespaceHandler 命名空间HTTP处理层
@@ -24,37 +24,28 @@ func NewNamespaceHandler(svc *service.NamespaceService) *NamespaceHandler {
 // 对应Shell: case "namespaces" list_namespaces_handler ;;
 func (h *NamespaceHandler) ListNamespaces(c *gin.Context) {
 	// 调用Service层获取数据
-	namespaces, err := h.namespaceService.ListNamespaces()
+	namespaces, err := h.namespaceService.ListNamespaces(c.Request.Context(), c.Query("cluster"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to list namespaces",
-			"details": err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
 	// 返回JSON响应
-	c.JSON(http.StatusOK, gin.H{
-		"data": namespaces,
-	})
+	response.Success(c, namespaces)
 }
 
-// GetNamespace 处理 GET /api/v1/namespaces/:name 请求
+// GetNamespace 处理 GET /api/v1/namespaces/:namespace 请求
+// 路径参数与 /namespaces/:namespace/pods 保持同名，gin 不允许同一位置使用不同的通配符名
 func (h *NamespaceHandler) GetNamespace(c *gin.Context) {
-	name := c.Param("name")
+	name := c.Param("namespace")
 
 	// 调用Service层获取数据
-	namespace, err := h.namespaceService.GetNamespace(name)
+	namespace, err := h.namespaceService.GetNamespace(c.Request.Context(), c.Query("cluster"), name)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Namespace not found",
-			"details": err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
 	// 返回JSON响应
-	c.JSON(http.StatusOK, gin.H{
-		"data": namespace,
-	})
+	response.Success(c, namespace)
 }
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// NodeHandler 节点 HTTP处理层
+type NodeHandler struct {
+	nodeService *service.NodeService
+}
+
+// NewNodeHandler 创建节点 Handler
+func NewNodeHandler(svc *service.NodeService) *NodeHandler {
+	return &NodeHandler{
+		nodeService: svc,
+	}
+}
+
+// ListNodes 处理 GET /api/v1/nodes 请求，?sortBy=cpu|memory 按实时用量降序
+// 对应Shell: kubectl top nodes --sort-by=cpu
+func (h *NodeHandler) ListNodes(c *gin.Context) {
+	nodes, err := h.nodeService.ListNodes(c.Request.Context(), c.Query("cluster"), c.Query("sortBy"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nodes)
+}
+
+// GetNode 处理 GET /api/v1/nodes/:name 请求
+func (h *NodeHandler) GetNode(c *gin.Context) {
+	node, err := h.nodeService.GetNode(c.Request.Context(), c.Query("cluster"), c.Param("name"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, node)
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/notify"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// NotificationHandler 通知渠道和路由规则 HTTP处理层
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewNotificationHandler 创建通知 Handler
+func NewNotificationHandler(svc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: svc,
+	}
+}
+
+// notificationChannelRequest 创建或更新通知渠道的请求体，secret 只写不读
+type notificationChannelRequest struct {
+	Name     string               `json:"name"`
+	Type     string               `json:"type"`
+	Config   notify.ChannelConfig `json:"config"`
+	Secret   string               `json:"secret"`
+	Template string               `json:"template"`
+	Enabled  *bool                `json:"enabled"` // 默认启用
+}
+
+func (r notificationChannelRequest) channel() repository.NotificationChannel {
+	return repository.NotificationChannel{
+		Name:     r.Name,
+		Type:     r.Type,
+		Config:   r.Config,
+		Secret:   r.Secret,
+		Template: r.Template,
+		Enabled:  r.Enabled == nil || *r.Enabled,
+	}
+}
+
+// notificationRuleRequest 创建或更新路由规则的请求体
+type notificationRuleRequest struct {
+	Name       string   `json:"name"`
+	ChannelID  int64    `json:"channelId"`
+	Clusters   []string `json:"clusters"`
+	Namespaces []string `json:"namespaces"`
+	Severities []string `json:"severities"`
+	EventTypes []string `json:"eventTypes"`
+	Enabled    *bool    `json:"enabled"` // 默认启用
+}
+
+func (r notificationRuleRequest) rule() repository.NotificationRule {
+	return repository.NotificationRule{
+		Name:       r.Name,
+		ChannelID:  r.ChannelID,
+		Clusters:   r.Clusters,
+		Namespaces: r.Namespaces,
+		Severities: r.Severities,
+		EventTypes: r.EventTypes,
+		Enabled:    r.Enabled == nil || *r.Enabled,
+	}
+}
+
+// ListChannels 处理 GET /api/v1/notifications/channels 请求
+func (h *NotificationHandler) ListChannels(c *gin.Context) {
+	channels, err := h.notificationService.ListChannels(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, channels)
+}
+
+// GetChannel 处理 GET /api/v1/notifications/channels/:id 请求
+func (h *NotificationHandler) GetChannel(c *gin.Context) {
+	id, ok := notificationID(c, "通知渠道")
+	if !ok {
+		return
+	}
+	ch, err := h.notificationService.GetChannel(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, ch)
+}
+
+// CreateChannel 处理 POST /api/v1/notifications/channels 请求
+func (h *NotificationHandler) CreateChannel(c *gin.Context) {
+	var req notificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	ch, err := h.notificationService.CreateChannel(c.Request.Context(), req.channel())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, ch)
+}
+
+// UpdateChannel 处理 PUT /api/v1/notifications/channels/:id 请求
+func (h *NotificationHandler) UpdateChannel(c *gin.Context) {
+	id, ok := notificationID(c, "通知渠道")
+	if !ok {
+		return
+	}
+	var req notificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	ch := req.channel()
+	ch.ID = id
+	ch, err := h.notificationService.UpdateChannel(c.Request.Context(), ch)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, ch)
+}
+
+// DeleteChannel 处理 DELETE /api/v1/notifications/channels/:id 请求
+func (h *NotificationHandler) DeleteChannel(c *gin.Context) {
+	id, ok := notificationID(c, "通知渠道")
+	if !ok {
+		return
+	}
+	if err := h.notificationService.DeleteChannel(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// TestChannel 处理 POST /api/v1/notifications/channels/:id/test 请求，发送一条测试消息
+func (h *NotificationHandler) TestChannel(c *gin.Context) {
+	id, ok := notificationID(c, "通知渠道")
+	if !ok {
+		return
+	}
+	result, err := h.notificationService.TestChannel(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// ListRules 处理 GET /api/v1/notifications/rules?channel=1 请求
+func (h *NotificationHandler) ListRules(c *gin.Context) {
+	var channelID int64
+	if v := c.Query("channel"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			response.Error(c, response.ErrBadRequest("channel 参数无效", err))
+			return
+		}
+		channelID = id
+	}
+	rules, err := h.notificationService.ListRules(c.Request.Context(), channelID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, rules)
+}
+
+// GetRule 处理 GET /api/v1/notifications/rules/:id 请求
+func (h *NotificationHandler) GetRule(c *gin.Context) {
+	id, ok := notificationID(c, "路由规则")
+	if !ok {
+		return
+	}
+	rule, err := h.notificationService.GetRule(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, rule)
+}
+
+// CreateRule 处理 POST /api/v1/notifications/rules 请求
+func (h *NotificationHandler) CreateRule(c *gin.Context) {
+	var req notificationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	rule, err := h.notificationService.CreateRule(c.Request.Context(), req.rule())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, rule)
+}
+
+// UpdateRule 处理 PUT /api/v1/notifications/rules/:id 请求
+func (h *NotificationHandler) UpdateRule(c *gin.Context) {
+	id, ok := notificationID(c, "路由规则")
+	if !ok {
+		return
+	}
+	var req notificationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, response.ErrBadRequest("请求体格式错误", err))
+		return
+	}
+	rule := req.rule()
+	rule.ID = id
+	rule, err := h.notificationService.UpdateRule(c.Request.Context(), rule)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, rule)
+}
+
+// DeleteRule 处理 DELETE /api/v1/notifications/rules/:id 请求
+func (h *NotificationHandler) DeleteRule(c *gin.Context) {
+	id, ok := notificationID(c, "路由规则")
+	if !ok {
+		return
+	}
+	if err := h.notificationService.DeleteRule(c.Request.Context(), id); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// notificationID 解析路径中的 ID，失败时直接返回 400
+func notificationID(c *gin.Context, resource string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.Error(c, response.ErrBadRequest(resource+" ID 无效", err))
+		return 0, false
+	}
+	return id, true
+}
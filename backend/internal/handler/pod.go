@@ -1,10 +1,10 @@
 package handler
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
+
 	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
 )
 
 // PodHandler Pod HTTP处理层
@@ -20,27 +20,21 @@ func NewPodHandler(svc *service.PodService) *PodHandler {
 	}
 }
 
-// ListPods 处理 GET /api/v1/namespaces/:namespace/pods 请求
+// ListPods 处理 GET /api/v1/namespaces/:namespace/pods 请求，?sortBy=cpu|memory 按实时用量降序
 // 对应Shell: case "pods" list_pods_handler ;;
 func (h *PodHandler) ListPods(c *gin.Context) {
 	// 从URL参数中提取namespace
 	namespace := c.Param("namespace")
 
 	// 调用Service层获取数据
-	pods, err := h.podService.ListPodsInNamespace(namespace)
+	pods, err := h.podService.ListPodsInNamespace(c.Request.Context(), c.Query("cluster"), namespace, c.Query("sortBy"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to list pods",
-			"details": err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
 	// 返回JSON响应
-	c.JSON(http.StatusOK, gin.H{
-		"data": pods,
-		"namespace": namespace,
-	})
+	response.Success(c, pods)
 }
 
 // GetPod 处理 GET /api/v1/namespaces/:namespace/pods/:name 请求
@@ -49,36 +43,25 @@ func (h *PodHandler) GetPod(c *gin.Context) {
 	name := c.Param("name")
 
 	// 调用Service层获取数据
-	pod, err := h.podService.GetPod(namespace, name)
+	pod, err := h.podService.GetPod(c.Request.Context(), c.Query("cluster"), namespace, name)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Pod not found",
-			"details": err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
 	// 返回JSON响应
-	c.JSON(http.StatusOK, gin.H{
-		"data": pod,
-		"namespace": namespace,
-	})
+	response.Success(c, pod)
 }
 
 // ListAllPods 处理 GET /api/v1/pods 请求（获取所有命名空间的Pod）
 func (h *PodHandler) ListAllPods(c *gin.Context) {
 	// 调用Service层获取数据
-	pods, err := h.podService.ListAllPods()
+	pods, err := h.podService.ListAllPods(c.Request.Context(), c.Query("cluster"), c.Query("sortBy"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to list all pods",
-			"details": err.Error(),
-		})
+		response.Error(c, err)
 		return
 	}
 
 	// 返回JSON响应
-	c.JSON(http.StatusOK, gin.H{
-		"data": pods,
-	})
+	response.Success(c, pods)
 }
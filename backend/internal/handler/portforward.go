@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// closeIdleTimeout 空闲超时断开时的 WebSocket 关闭码（4000-4999 由应用自定义）
+const closeIdleTimeout = 4008
+
+// PortForwardHandler 端口转发 HTTP处理层
+type PortForwardHandler struct {
+	portForwardService *service.PortForwardService
+	upgrader           websocket.Upgrader
+}
+
+// NewPortForwardHandler 创建端口转发 Handler
+// 使用默认的 Origin 校验：浏览器只能从同源页面发起，命令行客户端不带 Origin 头不受影响
+func NewPortForwardHandler(svc *service.PortForwardService) *PortForwardHandler {
+	return &PortForwardHandler{
+		portForwardService: svc,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  32 * 1024,
+			WriteBufferSize: 32 * 1024,
+		},
+	}
+}
+
+// PodPortForward 处理 GET /api/v1/namespaces/:namespace/pods/:name/portforward?port=8080 请求
+// 对应Shell: kubectl port-forward pod/$NAME -n $NAMESPACE $PORT
+func (h *PortForwardHandler) PodPortForward(c *gin.Context) {
+	h.serve(c, service.PortForwardRequest{
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Param("namespace"),
+		Pod:       c.Param("name"),
+		Port:      c.Query("port"),
+	})
+}
+
+// ServicePortForward 处理 GET /api/v1/namespaces/:namespace/services/:name/portforward?port=http 请求
+// 对应Shell: kubectl port-forward svc/$NAME -n $NAMESPACE $PORT
+func (h *PortForwardHandler) ServicePortForward(c *gin.Context) {
+	h.serve(c, service.PortForwardRequest{
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Param("namespace"),
+		Service:   c.Param("name"),
+		Port:      c.Query("port"),
+	})
+}
+
+// serve 先连接 Pod 端口再升级为 WebSocket，之后每个二进制消息原样转发到 Pod，Pod 返回的数据同样以二进制消息发回
+// 一个 WebSocket 对应一个 TCP 连接，结束时用关闭帧说明原因
+func (h *PortForwardHandler) serve(c *gin.Context, req service.PortForwardRequest) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		response.Error(c, response.ErrBadRequest("端口转发需要使用 WebSocket 连接", nil))
+		return
+	}
+	ctx := c.Request.Context()
+	session, err := h.portForwardService.Open(ctx, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	ws, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade 失败时已写入错误响应
+		session.Close()
+		return
+	}
+	defer ws.Close()
+
+	result := session.Serve(ctx, &wsConn{ws: ws})
+
+	var closeMessage []byte
+	switch result.Reason {
+	case service.PortForwardClosedByClient:
+		return
+	case service.PortForwardClosedIdle:
+		closeMessage = websocket.FormatCloseMessage(closeIdleTimeout, "idle timeout")
+	case service.PortForwardClosedError:
+		closeMessage = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, truncateCloseReason(result.Error))
+	case service.PortForwardClosedShutdown:
+		closeMessage = websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	default:
+		closeMessage = websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	}
+	_ = ws.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+}
+
+// truncateCloseReason 关闭帧的控制负载不能超过 125 字节，去掉 2 字节关闭码后原因最多 123 字节
+func truncateCloseReason(reason string) string {
+	const limit = 123
+	if len(reason) <= limit {
+		return reason
+	}
+	// 按 rune 截断，避免产生非法的 UTF-8
+	cut := 0
+	for i := range reason {
+		if i > limit {
+			break
+		}
+		cut = i
+	}
+	return reason[:cut]
+}
+
+// wsConn 把 WebSocket 适配为字节流：读取时拼接连续的消息，写入时每次发送一个二进制消息
+type wsConn struct {
+	ws     *websocket.Conn
+	reader io.Reader
+}
+
+func (w *wsConn) Read(p []byte) (int, error) {
+	for {
+		if w.reader == nil {
+			_, r, err := w.ws.NextReader()
+			if err != nil {
+				var closeErr *websocket.CloseError
+				if errors.As(err, &closeErr) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+			w.reader = r
+		}
+		n, err := w.reader.Read(p)
+		if errors.Is(err, io.EOF) {
+			w.reader = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (w *wsConn) Write(p []byte) (int, error) {
+	if err := w.ws.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
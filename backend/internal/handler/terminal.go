@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/yansongwel/kubeops/backend/internal/service"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// terminalMessage 终端 WebSocket 上的文本消息
+// 客户端发送 resize（cols、rows）调整终端大小、stdin（data）输入文本；服务端在进程结束时发送 exit（exitCode、error）
+type terminalMessage struct {
+	Type     string `json:"type"`
+	Cols     uint16 `json:"cols,omitempty"`
+	Rows     uint16 `json:"rows,omitempty"`
+	Data     string `json:"data,omitempty"`
+	ExitCode *int   `json:"exitCode,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// TerminalHandler 容器终端 HTTP处理层
+type TerminalHandler struct {
+	terminalService *service.TerminalService
+	upgrader        websocket.Upgrader
+}
+
+// NewTerminalHandler 创建终端 Handler
+func NewTerminalHandler(svc *service.TerminalService) *TerminalHandler {
+	return &TerminalHandler{
+		terminalService: svc,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  8 * 1024,
+			WriteBufferSize: 32 * 1024,
+		},
+	}
+}
+
+// Exec 处理 GET /api/v1/namespaces/:namespace/pods/:name/exec?container=app&command=sh 请求，command 可以重复传入多个参数
+// 对应Shell: kubectl exec -it $NAME -n $NAMESPACE -c $CONTAINER -- $COMMAND
+func (h *TerminalHandler) Exec(c *gin.Context) {
+	command := c.QueryArray("command")
+	if len(command) == 0 {
+		response.Error(c, response.ErrBadRequest("command 参数不能为空", nil))
+		return
+	}
+	h.serve(c, command)
+}
+
+// Attach 处理 GET /api/v1/namespaces/:namespace/pods/:name/attach?container=debugger-x1y2z 请求，连接到容器主进程
+// 对应Shell: kubectl attach -it $NAME -n $NAMESPACE -c $CONTAINER
+func (h *TerminalHandler) Attach(c *gin.Context) {
+	h.serve(c, nil)
+}
+
+// serve 校验容器后升级为 WebSocket：二进制消息为终端的输入输出，文本消息为 terminalMessage
+func (h *TerminalHandler) serve(c *gin.Context, command []string) {
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		response.Error(c, response.ErrBadRequest("终端需要使用 WebSocket 连接", nil))
+		return
+	}
+	req, err := h.terminalService.Prepare(c.Request.Context(), service.TerminalRequest{
+		Cluster:   c.Query("cluster"),
+		Namespace: c.Param("namespace"),
+		Pod:       c.Param("name"),
+		Container: c.Query("container"),
+		Command:   command,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	ws, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	// 客户端断开时取消，结束 exec/attach
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	conn := &terminalConn{ws: ws, sizes: make(chan remotecommand.TerminalSize, 1), ctx: ctx}
+	stdin, stdinWriter := io.Pipe()
+	defer stdin.Close()
+	go conn.readLoop(stdinWriter, cancel)
+
+	result := h.terminalService.Run(ctx, req, service.TerminalStreams{Stdin: stdin, Stdout: conn, Sizes: conn})
+	if ctx.Err() != nil {
+		return
+	}
+	_ = conn.writeJSON(terminalMessage{Type: "exit", ExitCode: &result.ExitCode, Error: result.Error})
+	_ = ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+}
+
+// terminalConn 把 WebSocket 适配为终端的输出和终端大小队列
+type terminalConn struct {
+	ws    *websocket.Conn
+	sizes chan remotecommand.TerminalSize
+	ctx   context.Context
+	mu    sync.Mutex // WebSocket 不支持并发写
+}
+
+// readLoop 读取客户端消息直到连接关闭：二进制消息和 stdin 消息写入终端输入，resize 消息更新终端大小
+func (t *terminalConn) readLoop(stdin *io.PipeWriter, cancel context.CancelFunc) {
+	defer cancel()
+	defer stdin.Close()
+	for {
+		messageType, data, err := t.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if messageType == websocket.BinaryMessage {
+			if _, err := stdin.Write(data); err != nil {
+				return
+			}
+			continue
+		}
+
+		var msg terminalMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		switch msg.Type {
+		case "stdin":
+			if _, err := stdin.Write([]byte(msg.Data)); err != nil {
+				return
+			}
+		case "resize":
+			if msg.Cols == 0 || msg.Rows == 0 {
+				continue
+			}
+			// 只保留最新的大小
+			select {
+			case <-t.sizes:
+			default:
+			}
+			t.sizes <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
+		}
+	}
+}
+
+// Next 实现 remotecommand.TerminalSizeQueue，连接结束时返回 nil
+func (t *terminalConn) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-t.sizes:
+		return &size
+	case <-t.ctx.Done():
+		return nil
+	}
+}
+
+// Write 把终端输出作为二进制消息发送
+func (t *terminalConn) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.ws.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (t *terminalConn) writeJSON(msg terminalMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.ws.WriteJSON(msg)
+}
@@ -0,0 +1,170 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout 未指定超时的检查项使用的超时时间
+const DefaultTimeout = 2 * time.Second
+
+// CheckFunc 单个检查项，返回 nil 表示通过
+type CheckFunc func(ctx context.Context) error
+
+// Check 一个命名的检查项
+// Optional 的检查项失败时只将整体状态标记为 degraded，不影响探针结果
+type Check struct {
+	Name     string
+	Timeout  time.Duration
+	Fn       CheckFunc
+	Optional bool
+}
+
+// Result 检查项的执行结果
+type Result struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"` // ok 或 failed
+	Optional bool   `json:"optional,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// 整体状态
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFailed   = "failed"
+)
+
+// Checker 一组检查项，如 readiness、startup
+// 检查项并发执行，每项使用自己的超时，单项卡住不会拖慢整个探针
+type Checker struct {
+	mu      sync.RWMutex
+	sources []func() []Check
+	info    map[string]func() interface{}
+}
+
+// NewChecker 创建空的检查器，没有检查项时始终通过
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Add 注册固定的检查项，timeout 为 0 时使用 DefaultTimeout
+func (c *Checker) Add(name string, timeout time.Duration, fn CheckFunc) {
+	check := Check{Name: name, Timeout: timeout, Fn: fn}
+	c.AddSet(func() []Check { return []Check{check} })
+}
+
+// AddOptional 注册可选检查项，失败时服务以降级模式继续提供服务
+func (c *Checker) AddOptional(name string, timeout time.Duration, fn CheckFunc) {
+	check := Check{Name: name, Timeout: timeout, Fn: fn, Optional: true}
+	c.AddSet(func() []Check { return []Check{check} })
+}
+
+// AddSet 注册动态生成的检查项，如按集群生成，每次执行时重新计算，配置热加载后自动生效
+func (c *Checker) AddSet(source func() []Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources = append(c.sources, source)
+}
+
+// AddInfo 注册只用于展示的状态信息（如主节点选举），在 ?verbose 输出中返回，不影响检查结果
+func (c *Checker) AddInfo(name string, fn func() interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.info == nil {
+		c.info = make(map[string]func() interface{})
+	}
+	c.info[name] = fn
+}
+
+// Info 返回所有状态信息，没有注册时返回 nil
+func (c *Checker) Info() map[string]interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.info) == 0 {
+		return nil
+	}
+	info := make(map[string]interface{}, len(c.info))
+	for name, fn := range c.info {
+		info[name] = fn()
+	}
+	return info
+}
+
+// Run 执行所有检查项，按注册顺序返回结果和整体状态
+// 必需检查项失败为 failed，仅可选检查项失败为 degraded
+func (c *Checker) Run(ctx context.Context) (results []Result, status string) {
+	c.mu.RLock()
+	var checks []Check
+	for _, source := range c.sources {
+		checks = append(checks, source()...)
+	}
+	c.mu.RUnlock()
+
+	results = make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	status = StatusOK
+	for _, r := range results {
+		switch {
+		case r.Status == StatusOK:
+		case r.Optional:
+			if status == StatusOK {
+				status = StatusDegraded
+			}
+		default:
+			status = StatusFailed
+		}
+	}
+	return results, status
+}
+
+func run(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		errCh <- check.Fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	result := Result{
+		Name:     check.Name,
+		Status:   StatusOK,
+		Optional: check.Optional,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package informer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+)
+
+// 由 informer 监听的资源类型
+const (
+	ResourceNamespaces = "namespaces"
+	ResourcePods       = "pods"
+)
+
+// resyncPeriod 全量重新同步周期，0 表示只依赖 watch 事件
+const resyncPeriod = 0
+
+// ChangeHandler 资源发生增删改时的回调，只携带集群和资源类型
+// 类比Shell: kubectl get pods -A --watch
+type ChangeHandler func(cluster, resource string, obj interface{})
+
+// Status 某个集群资源 informer 的同步状态
+type Status struct {
+	Cluster  string `json:"cluster"`
+	Resource string `json:"resource"`
+	Synced   bool   `json:"synced"`
+}
+
+// clusterInformers 一个集群的 informer 集合，集群客户端被替换时整体重建
+type clusterInformers struct {
+	cluster   *client.Cluster
+	informers map[string]cache.SharedIndexInformer
+	cancel    context.CancelFunc
+}
+
+// Manager 为每个受管集群维护 namespace 和 pod 的 informer
+// 集群热加载后调用 Sync，只重建客户端发生变化的集群
+type Manager struct {
+	logger   *zap.Logger
+	clusters *client.ClusterManager
+
+	mu       sync.Mutex
+	ctx      context.Context
+	running  map[string]*clusterInformers
+	handlers []ChangeHandler
+}
+
+// NewManager 创建 informer 管理器
+func NewManager(logger *zap.Logger, clusters *client.ClusterManager) *Manager {
+	return &Manager{
+		logger:   logger,
+		clusters: clusters,
+		running:  make(map[string]*clusterInformers),
+	}
+}
+
+// OnChange 注册资源变化回调，需在 Start 之前调用
+func (m *Manager) OnChange(handler ChangeHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Start 为所有集群启动 informer，ctx 取消时全部停止
+func (m *Manager) Start(ctx context.Context) {
+	m.mu.Lock()
+	m.ctx = ctx
+	m.mu.Unlock()
+	m.Sync()
+}
+
+// Sync 使运行中的 informer 与当前集群列表一致：删除的集群停止，新增或客户端被替换的集群重建
+func (m *Manager) Sync() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ctx == nil {
+		return
+	}
+
+	current := make(map[string]*client.Cluster)
+	for _, name := range m.clusters.Names() {
+		if cluster, err := m.clusters.Get(name); err == nil {
+			current[name] = cluster
+		}
+	}
+
+	for name, ci := range m.running {
+		if cluster, ok := current[name]; !ok || cluster != ci.cluster {
+			ci.cancel()
+			delete(m.running, name)
+			for resource := range ci.informers {
+				metrics.SetInformerSynced(name, resource, false)
+			}
+		}
+	}
+	for name, cluster := range current {
+		if _, ok := m.running[name]; !ok {
+			m.running[name] = m.start(cluster)
+		}
+	}
+}
+
+func (m *Manager) start(cluster *client.Cluster) *clusterInformers {
+	ctx, cancel := context.WithCancel(m.ctx)
+	factory := informers.NewSharedInformerFactoryWithOptions(cluster.Clientset, resyncPeriod,
+		informers.WithTransform(stripManagedFields))
+
+	ci := &clusterInformers{
+		cluster: cluster,
+		informers: map[string]cache.SharedIndexInformer{
+			ResourceNamespaces: factory.Core().V1().Namespaces().Informer(),
+			ResourcePods:       factory.Core().V1().Pods().Informer(),
+		},
+		cancel: cancel,
+	}
+
+	for resource, inf := range ci.informers {
+		resource, inf := resource, inf
+		metrics.SetInformerSynced(cluster.Name, resource, false)
+		_, _ = inf.AddEventHandler(m.eventHandler(cluster.Name, resource, inf))
+	}
+
+	factory.Start(ctx.Done())
+	for resource, inf := range ci.informers {
+		go m.waitForSync(ctx, cluster.Name, resource, inf)
+	}
+
+	m.logger.Info("Informers started", zap.String("cluster", cluster.Name))
+	return ci
+}
+
+func (m *Manager) waitForSync(ctx context.Context, cluster, resource string, inf cache.SharedIndexInformer) {
+	start := time.Now()
+	if !cache.WaitForCacheSync(ctx.Done(), inf.HasSynced) {
+		return
+	}
+	metrics.SetInformerSynced(cluster, resource, true)
+	m.logger.Info("Informer synced",
+		zap.String("cluster", cluster),
+		zap.String("resource", resource),
+		zap.Duration("elapsed", time.Since(start)),
+	)
+}
+
+// eventHandler 首次同步完成前的 Add 事件来自初始 List，不触发回调
+func (m *Manager) eventHandler(cluster, resource string, inf cache.SharedIndexInformer) cache.ResourceEventHandler {
+	notify := func(obj interface{}) {
+		m.mu.Lock()
+		handlers := m.handlers
+		m.mu.Unlock()
+		for _, h := range handlers {
+			h(cluster, resource, obj)
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if inf.HasSynced() {
+				notify(obj)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) { notify(obj) },
+		DeleteFunc: notify,
+	}
+}
+
+// Status 返回所有 informer 的同步状态，按集群配置顺序排列
+func (m *Manager) Status() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []Status
+	for _, name := range m.clusters.Names() {
+		ci, ok := m.running[name]
+		if !ok {
+			continue
+		}
+		for _, resource := range []string{ResourceNamespaces, ResourcePods} {
+			result = append(result, Status{
+				Cluster:  name,
+				Resource: resource,
+				Synced:   ci.informers[resource].HasSynced(),
+			})
+		}
+	}
+	return result
+}
+
+// stripManagedFields 丢弃 managedFields，减少 pod 缓存占用的内存
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, ok := obj.(metav1.ObjectMetaAccessor); ok {
+		accessor.GetObjectMeta().SetManagedFields(nil)
+	}
+	return obj, nil
+}
@@ -0,0 +1,375 @@
+package inspection
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// restartThreshold 重启次数达到该值时视为异常
+const restartThreshold = 5
+
+// DefaultChecks 内置检查项
+func DefaultChecks() []Check {
+	return []Check{
+		{
+			ID:          "pod-crashloop",
+			Category:    "pod",
+			Severity:    SeverityCritical,
+			Description: "容器处于 CrashLoopBackOff",
+			Run:         checkCrashLoop,
+		},
+		{
+			ID:          "pod-image-pull-error",
+			Category:    "pod",
+			Severity:    SeverityCritical,
+			Description: "容器镜像拉取失败",
+			Run:         checkImagePull,
+		},
+		{
+			ID:          "pod-high-restarts",
+			Category:    "pod",
+			Severity:    SeverityWarning,
+			Description: fmt.Sprintf("容器重启次数不少于 %d 次", restartThreshold),
+			Run:         checkHighRestarts,
+		},
+		{
+			ID:          "container-missing-requests",
+			Category:    "workload",
+			Severity:    SeverityWarning,
+			Description: "容器未设置 CPU 或内存 requests",
+			Run:         checkMissingRequests,
+		},
+		{
+			ID:          "container-missing-limits",
+			Category:    "workload",
+			Severity:    SeverityWarning,
+			Description: "容器未设置内存 limits",
+			Run:         checkMissingLimits,
+		},
+		{
+			ID:          "container-latest-image",
+			Category:    "workload",
+			Severity:    SeverityWarning,
+			Description: "容器镜像使用 latest 或未指定标签",
+			Run:         checkLatestImage,
+		},
+		{
+			ID:          "container-no-readiness-probe",
+			Category:    "workload",
+			Severity:    SeverityWarning,
+			Description: "容器未配置就绪探针（Job 除外）",
+			Run:         checkReadinessProbe,
+		},
+		{
+			ID:          "container-privileged",
+			Category:    "workload",
+			Severity:    SeverityCritical,
+			Description: "容器以特权模式运行",
+			Run:         checkPrivileged,
+		},
+		{
+			ID:          "deployment-single-replica",
+			Category:    "workload",
+			Severity:    SeverityWarning,
+			Description: "Deployment 只有一个副本",
+			Run:         checkSingleReplica,
+		},
+		{
+			ID:          "node-not-ready",
+			Category:    "node",
+			Severity:    SeverityCritical,
+			Description: "节点 NotReady",
+			Run:         checkNodeNotReady,
+		},
+		{
+			ID:          "node-pressure",
+			Category:    "node",
+			Severity:    SeverityWarning,
+			Description: "节点存在内存、磁盘或 PID 压力",
+			Run:         checkNodePressure,
+		},
+	}
+}
+
+func checkCrashLoop(s *Snapshot) []Finding {
+	return containerStatusFindings(s, func(pod *corev1.Pod, st corev1.ContainerStatus) *Finding {
+		if st.State.Waiting == nil || st.State.Waiting.Reason != "CrashLoopBackOff" {
+			return nil
+		}
+		msg := fmt.Sprintf("容器处于 CrashLoopBackOff，已重启 %d 次", st.RestartCount)
+		if t := st.LastTerminationState.Terminated; t != nil {
+			msg += fmt.Sprintf("，上次退出原因 %s（退出码 %d）", t.Reason, t.ExitCode)
+		}
+		return &Finding{
+			Message: msg,
+			Remediation: fmt.Sprintf("执行 kubectl logs %s -n %s -c %s --previous 查看上次崩溃的日志；"+
+				"退出码 137 通常为 OOMKilled，需要调大内存 limits；检查启动命令、配置和依赖服务是否可用。",
+				pod.Name, pod.Namespace, st.Name),
+		}
+	})
+}
+
+func checkImagePull(s *Snapshot) []Finding {
+	return containerStatusFindings(s, func(pod *corev1.Pod, st corev1.ContainerStatus) *Finding {
+		if st.State.Waiting == nil {
+			return nil
+		}
+		switch st.State.Waiting.Reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+		default:
+			return nil
+		}
+		return &Finding{
+			Message: fmt.Sprintf("镜像 %s 拉取失败（%s）：%s", st.Image, st.State.Waiting.Reason, st.State.Waiting.Message),
+			Remediation: "确认镜像名和标签存在；私有仓库需要在 Pod 或 ServiceAccount 上配置 imagePullSecrets；" +
+				"检查节点到镜像仓库的网络。",
+		}
+	})
+}
+
+func checkHighRestarts(s *Snapshot) []Finding {
+	return containerStatusFindings(s, func(pod *corev1.Pod, st corev1.ContainerStatus) *Finding {
+		// CrashLoopBackOff 已由 pod-crashloop 报告
+		if st.RestartCount < restartThreshold || (st.State.Waiting != nil && st.State.Waiting.Reason == "CrashLoopBackOff") {
+			return nil
+		}
+		msg := fmt.Sprintf("容器已重启 %d 次", st.RestartCount)
+		if t := st.LastTerminationState.Terminated; t != nil {
+			msg += fmt.Sprintf("，上次退出原因 %s（退出码 %d）", t.Reason, t.ExitCode)
+		}
+		return &Finding{
+			Message: msg,
+			Remediation: fmt.Sprintf("执行 kubectl describe pod %s -n %s 查看重启原因；OOMKilled 需要调大内存 limits，"+
+				"存活探针失败需要检查探针配置和应用健康状况。", pod.Name, pod.Namespace),
+		}
+	})
+}
+
+func checkMissingRequests(s *Snapshot) []Finding {
+	return workloadContainerFindings(s, func(_ *corev1.Pod, c *corev1.Container) *Finding {
+		var missing []string
+		if _, ok := c.Resources.Requests[corev1.ResourceCPU]; !ok {
+			missing = append(missing, "cpu")
+		}
+		if _, ok := c.Resources.Requests[corev1.ResourceMemory]; !ok {
+			missing = append(missing, "memory")
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		return &Finding{
+			Message: fmt.Sprintf("容器未设置 %s requests", strings.Join(missing, "、")),
+			Remediation: "按实际用量（可参考 kubectl top 或监控中的 P95）设置 resources.requests，" +
+				"否则调度器无法正确分配节点，且 Pod 的 QoS 为 BestEffort，资源紧张时最先被驱逐。",
+		}
+	})
+}
+
+func checkMissingLimits(s *Snapshot) []Finding {
+	return workloadContainerFindings(s, func(_ *corev1.Pod, c *corev1.Container) *Finding {
+		if _, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			return nil
+		}
+		return &Finding{
+			Message:     "容器未设置内存 limits",
+			Remediation: "设置 resources.limits.memory，避免内存泄漏时耗尽节点内存并影响同节点的其他 Pod；也可以通过命名空间的 LimitRange 设置默认值。",
+		}
+	})
+}
+
+func checkLatestImage(s *Snapshot) []Finding {
+	return workloadContainerFindings(s, func(_ *corev1.Pod, c *corev1.Container) *Finding {
+		tag, pinned := imageTag(c.Image)
+		if pinned || (tag != "" && tag != "latest") {
+			return nil
+		}
+		return &Finding{
+			Message:     fmt.Sprintf("镜像 %s 使用 latest 或未指定标签", c.Image),
+			Remediation: "使用不可变的版本标签或 digest（image@sha256:...），保证各副本运行同一版本并且可以回滚。",
+		}
+	})
+}
+
+func checkReadinessProbe(s *Snapshot) []Finding {
+	return workloadContainerFindings(s, func(pod *corev1.Pod, c *corev1.Container) *Finding {
+		if c.ReadinessProbe != nil || isJobPod(pod) {
+			return nil
+		}
+		return &Finding{
+			Message:     "容器未配置就绪探针",
+			Remediation: "配置 readinessProbe（HTTP、TCP 或 exec），否则容器启动后立即接收流量，滚动更新和启动期间可能出现请求失败。",
+		}
+	})
+}
+
+func checkPrivileged(s *Snapshot) []Finding {
+	return workloadContainerFindings(s, func(_ *corev1.Pod, c *corev1.Container) *Finding {
+		if c.SecurityContext == nil || c.SecurityContext.Privileged == nil || !*c.SecurityContext.Privileged {
+			return nil
+		}
+		return &Finding{
+			Message:     "容器以特权模式运行（securityContext.privileged: true）",
+			Remediation: "去掉 privileged，改为只授予需要的 capabilities（securityContext.capabilities.add）；特权容器可以访问宿主机的所有设备。",
+		}
+	})
+}
+
+func checkSingleReplica(s *Snapshot) []Finding {
+	var findings []Finding
+	for i := range s.Deployments {
+		d := &s.Deployments[i]
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		if replicas != 1 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Object:      Object{Kind: "Deployment", Namespace: d.Namespace, Name: d.Name},
+			Message:     "Deployment 只有一个副本，节点故障或滚动更新时服务会中断",
+			Remediation: "将 replicas 调整为 2 个或以上，并配置 PodDisruptionBudget 和 Pod 反亲和性，使副本分布在不同节点。",
+		})
+	}
+	return findings
+}
+
+func checkNodeNotReady(s *Snapshot) []Finding {
+	var findings []Finding
+	for i := range s.Nodes {
+		node := &s.Nodes[i]
+		cond := nodeCondition(node, corev1.NodeReady)
+		if cond != nil && cond.Status == corev1.ConditionTrue {
+			continue
+		}
+		msg := "节点 NotReady"
+		if cond != nil && cond.Message != "" {
+			msg += "：" + cond.Message
+		}
+		findings = append(findings, Finding{
+			Object:  Object{Kind: "Node", Name: node.Name},
+			Message: msg,
+			Remediation: fmt.Sprintf("执行 kubectl describe node %s 查看状态；登录节点检查 kubelet（systemctl status kubelet、journalctl -u kubelet）"+
+				"和容器运行时是否正常，以及节点到 apiserver 的网络。", node.Name),
+		})
+	}
+	return findings
+}
+
+func checkNodePressure(s *Snapshot) []Finding {
+	var findings []Finding
+	for i := range s.Nodes {
+		node := &s.Nodes[i]
+		for _, t := range []corev1.NodeConditionType{corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure} {
+			cond := nodeCondition(node, t)
+			if cond == nil || cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			msg := fmt.Sprintf("节点存在 %s", t)
+			if cond.Message != "" {
+				msg += "：" + cond.Message
+			}
+			findings = append(findings, Finding{
+				Object:      Object{Kind: "Node", Name: node.Name},
+				Message:     msg,
+				Remediation: "kubelet 会驱逐该节点上的 Pod；清理磁盘（镜像、日志）、迁移高负载 Pod 或扩容节点，并检查 Pod 是否设置了合理的 requests。",
+			})
+		}
+	}
+	return findings
+}
+
+// containerStatusFindings 对运行中 Pod 的每个容器状态执行判断，结果指向具体的 Pod 和容器
+func containerStatusFindings(s *Snapshot, fn func(pod *corev1.Pod, st corev1.ContainerStatus) *Finding) []Finding {
+	var findings []Finding
+	for i := range s.Pods {
+		pod := &s.Pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, st := range pod.Status.ContainerStatuses {
+			if f := fn(pod, st); f != nil {
+				f.Object = Object{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, Container: st.Name}
+				findings = append(findings, *f)
+			}
+		}
+	}
+	return findings
+}
+
+// workloadContainerFindings 对容器配置执行判断
+// 配置问题属于工作负载而不是某个 Pod：结果指向 Pod 的控制器（如 Deployment），同一控制器的多个副本只报告一次
+func workloadContainerFindings(s *Snapshot, fn func(pod *corev1.Pod, c *corev1.Container) *Finding) []Finding {
+	var findings []Finding
+	seen := make(map[Object]bool)
+	for i := range s.Pods {
+		pod := &s.Pods[i]
+		owner := podOwner(pod)
+		for j := range pod.Spec.Containers {
+			c := &pod.Spec.Containers[j]
+			obj := owner
+			obj.Container = c.Name
+			if seen[obj] {
+				continue
+			}
+			seen[obj] = true
+			if f := fn(pod, c); f != nil {
+				f.Object = obj
+				findings = append(findings, *f)
+			}
+		}
+	}
+	return findings
+}
+
+// podOwner 返回 Pod 的顶层控制器；ReplicaSet 按 pod-template-hash 还原为 Deployment，没有控制器时返回 Pod 本身
+func podOwner(pod *corev1.Pod) Object {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(ref.Name, "-"+hash) {
+				return Object{Kind: "Deployment", Namespace: pod.Namespace, Name: strings.TrimSuffix(ref.Name, "-"+hash)}
+			}
+		}
+		return Object{Kind: ref.Kind, Namespace: pod.Namespace, Name: ref.Name}
+	}
+	return Object{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+}
+
+func isJobPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "Job" {
+			return true
+		}
+	}
+	return false
+}
+
+// imageTag 解析镜像标签；使用 digest 时 pinned 为 true
+// 注意仓库地址可能带端口（registry:5000/app），标签只在最后一个 / 之后查找
+func imageTag(image string) (tag string, pinned bool) {
+	if strings.Contains(image, "@") {
+		return "", true
+	}
+	name := image
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:], false
+	}
+	return "", false
+}
+
+func nodeCondition(node *corev1.Node, t corev1.NodeConditionType) *corev1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == t {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package inspection
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// healthyContainer 满足所有容器配置检查的容器
+func healthyContainer(name string) corev1.Container {
+	return corev1.Container{
+		Name:  name,
+		Image: "registry:5000/team/app:1.2.3",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+		ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/readyz"}}},
+	}
+}
+
+// deploymentPod Deployment web 的一个副本，mutate 修改容器配置或状态
+func deploymentPod(name string, mutate func(pod *corev1.Pod)) corev1.Pod {
+	controller := true
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "shop",
+			Labels:          map[string]string{"pod-template-hash": "7d9f"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-7d9f", Controller: &controller}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{healthyContainer("app")}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Image: "registry:5000/team/app:1.2.3", Ready: true}},
+		},
+	}
+	if mutate != nil {
+		mutate(&pod)
+	}
+	return pod
+}
+
+func waiting(reason string) corev1.ContainerState {
+	return corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: "back-off"}}
+}
+
+func node(name string, conditions ...corev1.NodeCondition) corev1.Node {
+	return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: corev1.NodeStatus{Conditions: conditions}}
+}
+
+func deployment(name string, replicas *int32) appsv1.Deployment {
+	return appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: replicas}}
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func TestDefaultChecks(t *testing.T) {
+	ready := corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue}
+	pod := Object{Kind: "Pod", Namespace: "shop", Name: "web-1", Container: "app"}
+	workload := Object{Kind: "Deployment", Namespace: "shop", Name: "web", Container: "app"}
+
+	tests := []struct {
+		check    string
+		snapshot Snapshot
+		want     []Object // 为空表示检查通过
+		severity Severity
+	}{
+		// pod-crashloop
+		{check: "pod-crashloop", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", nil)}}},
+		{check: "pod-crashloop", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses[0].State = waiting("CrashLoopBackOff")
+		})}}, want: []Object{pod}, severity: SeverityCritical},
+		// 已结束的 Pod 不检查容器状态
+		{check: "pod-crashloop", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Status.Phase = corev1.PodFailed
+			p.Status.ContainerStatuses[0].State = waiting("CrashLoopBackOff")
+		})}}},
+
+		// pod-image-pull-error
+		{check: "pod-image-pull-error", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses[0].State = waiting("ContainerCreating")
+		})}}},
+		{check: "pod-image-pull-error", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses[0].State = waiting("ImagePullBackOff")
+		})}}, want: []Object{pod}, severity: SeverityCritical},
+
+		// pod-high-restarts
+		{check: "pod-high-restarts", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses[0].RestartCount = restartThreshold - 1
+		})}}},
+		{check: "pod-high-restarts", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses[0].RestartCount = restartThreshold
+		})}}, want: []Object{pod}, severity: SeverityWarning},
+		// CrashLoopBackOff 只由 pod-crashloop 报告
+		{check: "pod-high-restarts", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses[0].RestartCount = 20
+			p.Status.ContainerStatuses[0].State = waiting("CrashLoopBackOff")
+		})}}},
+
+		// container-missing-requests：同一 Deployment 的多个副本只报告一次
+		{check: "container-missing-requests", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", nil)}}},
+		{check: "container-missing-requests", snapshot: Snapshot{Pods: []corev1.Pod{
+			deploymentPod("web-1", func(p *corev1.Pod) { delete(p.Spec.Containers[0].Resources.Requests, corev1.ResourceMemory) }),
+			deploymentPod("web-2", func(p *corev1.Pod) { delete(p.Spec.Containers[0].Resources.Requests, corev1.ResourceMemory) }),
+		}}, want: []Object{workload}, severity: SeverityWarning},
+
+		// container-missing-limits
+		{check: "container-missing-limits", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", nil)}}},
+		{check: "container-missing-limits", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Spec.Containers[0].Resources.Limits = nil
+		})}}, want: []Object{workload}, severity: SeverityWarning},
+
+		// container-latest-image：仓库端口不是标签，digest 视为固定版本
+		{check: "container-latest-image", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Spec.Containers[0].Image = "registry:5000/team/app@sha256:abc"
+		})}}},
+		{check: "container-latest-image", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Spec.Containers[0].Image = "registry:5000/team/app"
+		})}}, want: []Object{workload}, severity: SeverityWarning},
+		{check: "container-latest-image", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Spec.Containers[0].Image = "nginx:latest"
+		})}}, want: []Object{workload}, severity: SeverityWarning},
+
+		// container-no-readiness-probe：Job 的 Pod 不需要就绪探针
+		{check: "container-no-readiness-probe", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Spec.Containers[0].ReadinessProbe = nil
+			p.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "migrate"}}
+		})}}},
+		{check: "container-no-readiness-probe", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			p.Spec.Containers[0].ReadinessProbe = nil
+		})}}, want: []Object{workload}, severity: SeverityWarning},
+
+		// container-privileged
+		{check: "container-privileged", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			privileged := false
+			p.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+		})}}},
+		{check: "container-privileged", snapshot: Snapshot{Pods: []corev1.Pod{deploymentPod("web-1", func(p *corev1.Pod) {
+			privileged := true
+			p.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+		})}}, want: []Object{workload}, severity: SeverityCritical},
+
+		// deployment-single-replica：未设置 replicas 时默认为 1
+		{check: "deployment-single-replica", snapshot: Snapshot{Deployments: []appsv1.Deployment{deployment("web", int32Ptr(3))}}},
+		{check: "deployment-single-replica", snapshot: Snapshot{Deployments: []appsv1.Deployment{deployment("web", nil)}},
+			want: []Object{{Kind: "Deployment", Namespace: "shop", Name: "web"}}, severity: SeverityWarning},
+
+		// node-not-ready：没有 Ready 条件也视为 NotReady
+		{check: "node-not-ready", snapshot: Snapshot{Nodes: []corev1.Node{node("n1", ready)}}},
+		{check: "node-not-ready", snapshot: Snapshot{Nodes: []corev1.Node{node("n1")}},
+			want: []Object{{Kind: "Node", Name: "n1"}}, severity: SeverityCritical},
+
+		// node-pressure：每种压力一条
+		{check: "node-pressure", snapshot: Snapshot{Nodes: []corev1.Node{node("n1", ready,
+			corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse})}}},
+		{check: "node-pressure", snapshot: Snapshot{Nodes: []corev1.Node{node("n1", ready,
+			corev1.NodeCondition{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+			corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue})}},
+			want: []Object{{Kind: "Node", Name: "n1"}, {Kind: "Node", Name: "n1"}}, severity: SeverityWarning},
+	}
+
+	engine := NewEngine()
+	for _, tt := range tests {
+		report := engine.Run(&tt.snapshot, []string{tt.check}, "")
+		if len(report.Errors) > 0 {
+			t.Errorf("%s: errors = %v", tt.check, report.Errors)
+			continue
+		}
+		if len(report.Findings) != len(tt.want) {
+			t.Errorf("%s: got %d findings %+v, want %d", tt.check, len(report.Findings), report.Findings, len(tt.want))
+			continue
+		}
+		for i, f := range report.Findings {
+			if f.Check != tt.check || f.Object != tt.want[i] || f.Severity != tt.severity {
+				t.Errorf("%s: finding = %s %s %s, want %s %s", tt.check, f.Check, f.Severity, f.Object, tt.severity, tt.want[i])
+			}
+			if f.Message == "" || f.Remediation == "" {
+				t.Errorf("%s: finding without message or remediation: %+v", tt.check, f)
+			}
+		}
+	}
+}
+
+func TestEngineRun(t *testing.T) {
+	checks := []Check{
+		{ID: "b-warning", Severity: SeverityWarning, Run: func(*Snapshot) []Finding {
+			return []Finding{
+				{Object: Object{Kind: "Pod", Name: "z"}},
+				// 检查项可以为单个问题指定不同于默认值的严重程度
+				{Object: Object{Kind: "Pod", Name: "y"}, Severity: SeverityCritical},
+			}
+		}},
+		{ID: "a-info", Severity: SeverityInfo, Run: func(*Snapshot) []Finding {
+			return []Finding{{Object: Object{Kind: "Pod", Name: "x"}}}
+		}},
+		{ID: "c-panics", Severity: SeverityCritical, Run: func(*Snapshot) []Finding { panic("boom") }},
+	}
+	engine := NewEngine(checks...)
+
+	report := engine.Run(&Snapshot{Cluster: "prod"}, nil, "")
+	// 按严重程度、检查项、资源排序
+	want := []struct {
+		check    string
+		name     string
+		severity Severity
+	}{
+		{"b-warning", "y", SeverityCritical},
+		{"b-warning", "z", SeverityWarning},
+		{"a-info", "x", SeverityInfo},
+	}
+	if len(report.Findings) != len(want) {
+		t.Fatalf("findings = %+v", report.Findings)
+	}
+	for i, w := range want {
+		f := report.Findings[i]
+		if f.Check != w.check || f.Object.Name != w.name || f.Severity != w.severity {
+			t.Errorf("findings[%d] = %s %s %s, want %s %s %s", i, f.Check, f.Object.Name, f.Severity, w.check, w.name, w.severity)
+		}
+	}
+	if report.Summary != (Summary{Total: 3, Critical: 1, Warning: 1, Info: 1}) {
+		t.Errorf("summary = %+v", report.Summary)
+	}
+	// panic 的检查项记为错误，不影响其他检查项
+	if len(report.Errors) != 1 || report.Errors[0].Check != "c-panics" {
+		t.Errorf("errors = %+v", report.Errors)
+	}
+
+	report = engine.Run(&Snapshot{}, []string{"b-warning", "a-info", "unknown"}, SeverityWarning)
+	if len(report.Checks) != 2 || report.Summary != (Summary{Total: 2, Critical: 1, Warning: 1}) {
+		t.Errorf("filtered run: checks = %v, summary = %+v", report.Checks, report.Summary)
+	}
+}
+
+func TestPodOwner(t *testing.T) {
+	controller := true
+	tests := []struct {
+		name   string
+		labels map[string]string
+		refs   []metav1.OwnerReference
+		want   Object
+	}{
+		{name: "deployment", labels: map[string]string{"pod-template-hash": "abc"},
+			refs: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc", Controller: &controller}},
+			want: Object{Kind: "Deployment", Namespace: "shop", Name: "web"}},
+		{name: "bare replicaset", refs: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc", Controller: &controller}},
+			want: Object{Kind: "ReplicaSet", Namespace: "shop", Name: "web-abc"}},
+		{name: "statefulset", refs: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: &controller}},
+			want: Object{Kind: "StatefulSet", Namespace: "shop", Name: "db"}},
+		{name: "non-controller owner", refs: []metav1.OwnerReference{{Kind: "ConfigMap", Name: "cm"}},
+			want: Object{Kind: "Pod", Namespace: "shop", Name: "p"}},
+	}
+	for _, tt := range tests {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "shop", Labels: tt.labels, OwnerReferences: tt.refs}}
+		if got := podOwner(pod); got != tt.want {
+			t.Errorf("%s: podOwner() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
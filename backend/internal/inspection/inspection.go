@@ -0,0 +1,266 @@
+// Package inspection 基于规则的集群巡检引擎，检查项对集群快照做确定性判断，不依赖 AI
+package inspection
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Severity 问题的严重程度
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical" // 正在影响业务，需要立即处理
+	SeverityWarning  Severity = "warning"  // 存在风险或违反最佳实践
+	SeverityInfo     Severity = "info"     // 建议优化
+)
+
+// rank 严重程度排序，数值越小越严重
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Valid 是否为已知的严重程度
+func (s Severity) Valid() bool {
+	return s == SeverityCritical || s == SeverityWarning || s == SeverityInfo
+}
+
+// AtLeast 是否不低于 min
+func (s Severity) AtLeast(min Severity) bool {
+	return s.rank() <= min.rank()
+}
+
+// Object 问题涉及的资源，Container 仅容器级别的问题填写
+type Object struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+}
+
+func (o Object) String() string {
+	s := o.Kind + "/" + o.Name
+	if o.Namespace != "" {
+		s = o.Namespace + "/" + s
+	}
+	if o.Container != "" {
+		s += "[" + o.Container + "]"
+	}
+	return s
+}
+
+// Finding 一条巡检结果
+type Finding struct {
+	Check       string   `json:"check"`
+	Severity    Severity `json:"severity"`
+	Object      Object   `json:"object"`
+	Message     string   `json:"message"`
+	Remediation string   `json:"remediation"`
+}
+
+// Fingerprint 问题的指纹：同一检查项在同一资源上发现的问题指纹相同，用于跨次对比和确认
+func (f Finding) Fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{f.Check, f.Object.Kind, f.Object.Namespace, f.Object.Name, f.Object.Container}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Scope 巡检范围
+type Scope struct {
+	Namespaces              []string `json:"namespaces"`              // 为空时巡检所有命名空间
+	IncludeSystemNamespaces bool     `json:"includeSystemNamespaces"` // 未指定命名空间时是否包含 kube-system 等系统命名空间
+	Checks                  []string `json:"checks"`                  // 为空时执行所有检查项
+	MinSeverity             Severity `json:"minSeverity"`             // 只返回不低于该级别的问题
+}
+
+// Key 范围的摘要，命名空间和检查项的顺序不影响结果；范围相同的巡检之间才能做差异对比
+func (s Scope) Key() string {
+	namespaces := append([]string(nil), s.Namespaces...)
+	checks := append([]string(nil), s.Checks...)
+	sort.Strings(namespaces)
+	sort.Strings(checks)
+	raw := fmt.Sprintf("ns=%s;system=%t;checks=%s;min=%s",
+		strings.Join(namespaces, ","), s.IncludeSystemNamespaces, strings.Join(checks, ","), s.MinSeverity)
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Snapshot 巡检使用的集群快照，由调用方通过 Repository 读取
+type Snapshot struct {
+	Cluster     string
+	Pods        []corev1.Pod
+	Deployments []appsv1.Deployment
+	Nodes       []corev1.Node
+}
+
+// Check 一个检查项
+// Run 只读取快照，返回发现的问题；Severity 为该检查项发现问题时的默认严重程度
+type Check struct {
+	ID          string                      `json:"id"`
+	Category    string                      `json:"category"` // pod、workload 或 node
+	Severity    Severity                    `json:"severity"`
+	Description string                      `json:"description"`
+	Run         func(s *Snapshot) []Finding `json:"-"`
+}
+
+// Summary 按严重程度统计的问题数，Suppressed 为已确认或屏蔽、不计入统计的问题数
+type Summary struct {
+	Total      int `json:"total"`
+	Critical   int `json:"critical"`
+	Warning    int `json:"warning"`
+	Info       int `json:"info"`
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// Add 把一个问题计入统计
+func (s *Summary) Add(severity Severity) {
+	s.Total++
+	switch severity {
+	case SeverityCritical:
+		s.Critical++
+	case SeverityWarning:
+		s.Warning++
+	default:
+		s.Info++
+	}
+}
+
+// CheckError 执行失败的检查项
+type CheckError struct {
+	Check string `json:"check"`
+	Error string `json:"error"`
+}
+
+// Report 一次巡检的结果
+type Report struct {
+	Cluster   string       `json:"cluster"`
+	StartedAt time.Time    `json:"startedAt"`
+	Duration  string       `json:"duration"`
+	Checks    []string     `json:"checks"`
+	Summary   Summary      `json:"summary"`
+	Findings  []Finding    `json:"findings"`
+	Errors    []CheckError `json:"errors,omitempty"`
+	Objects   ObjectCounts `json:"objects"`
+}
+
+// ObjectCounts 参与巡检的资源数量
+type ObjectCounts struct {
+	Pods        int `json:"pods"`
+	Deployments int `json:"deployments"`
+	Nodes       int `json:"nodes"`
+}
+
+// Engine 巡检引擎，持有一组检查项
+// 类比Shell: for check in checks/*.sh; do "$check" snapshot.json; done | sort -k severity
+type Engine struct {
+	checks []Check
+	byID   map[string]Check
+}
+
+// NewEngine 创建巡检引擎，checks 为空时使用 DefaultChecks
+func NewEngine(checks ...Check) *Engine {
+	if len(checks) == 0 {
+		checks = DefaultChecks()
+	}
+	byID := make(map[string]Check, len(checks))
+	for _, c := range checks {
+		byID[c.ID] = c
+	}
+	return &Engine{checks: checks, byID: byID}
+}
+
+// Checks 返回所有检查项
+func (e *Engine) Checks() []Check {
+	return e.checks
+}
+
+// Lookup 按 ID 查找检查项
+func (e *Engine) Lookup(id string) (Check, bool) {
+	c, ok := e.byID[id]
+	return c, ok
+}
+
+// Run 对快照执行检查项，ids 为空时执行全部检查项
+// 结果按严重程度、检查项、资源排序；单个检查项 panic 不影响其他检查项
+func (e *Engine) Run(s *Snapshot, ids []string, minSeverity Severity) Report {
+	started := time.Now()
+	checks := e.checks
+	if len(ids) > 0 {
+		checks = make([]Check, 0, len(ids))
+		for _, id := range ids {
+			if c, ok := e.byID[id]; ok {
+				checks = append(checks, c)
+			}
+		}
+	}
+
+	report := Report{
+		Cluster:   s.Cluster,
+		StartedAt: started,
+		Checks:    make([]string, 0, len(checks)),
+		Findings:  []Finding{},
+		Objects: ObjectCounts{
+			Pods:        len(s.Pods),
+			Deployments: len(s.Deployments),
+			Nodes:       len(s.Nodes),
+		},
+	}
+	for _, c := range checks {
+		report.Checks = append(report.Checks, c.ID)
+		findings, err := runCheck(c, s)
+		if err != nil {
+			report.Errors = append(report.Errors, CheckError{Check: c.ID, Error: err.Error()})
+			continue
+		}
+		for _, f := range findings {
+			if minSeverity == "" || f.Severity.AtLeast(minSeverity) {
+				report.Findings = append(report.Findings, f)
+			}
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity.rank() < b.Severity.rank()
+		}
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		return a.Object.String() < b.Object.String()
+	})
+	for _, f := range report.Findings {
+		report.Summary.Add(f.Severity)
+	}
+	report.Duration = time.Since(started).String()
+	return report
+}
+
+func runCheck(c Check, s *Snapshot) (findings []Finding, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("check panicked: %v", r)
+		}
+	}()
+	for _, f := range c.Run(s) {
+		f.Check = c.ID
+		if f.Severity == "" {
+			f.Severity = c.Severity
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
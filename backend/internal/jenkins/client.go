@@ -0,0 +1,638 @@
+// Package jenkins Jenkins REST API 客户端：浏览文件夹和 Job、触发参数化构建、增量读取控制台输出
+package jenkins
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	// maxResponseBytes JSON 响应的大小上限
+	maxResponseBytes = 8 << 20
+	// maxLogChunkBytes 单次读取控制台输出的上限，超出部分在下一次读取
+	maxLogChunkBytes = 1 << 20
+)
+
+// ClientConfig Jenkins 服务器的连接配置
+type ClientConfig struct {
+	URL           string
+	Username      string
+	APIToken      string
+	TLSSkipVerify bool
+	Timeout       time.Duration // 单次请求的最长时间（不含日志流的等待间隔）
+}
+
+// Client Jenkins REST API 客户端
+// 类比Shell: curl -s -u "$USER:$TOKEN" "$JENKINS_URL/job/$JOB/api/json"
+type Client struct {
+	cfg  ClientConfig
+	http *http.Client
+
+	// crumb 防 CSRF 令牌，与会话 Cookie 绑定，首次写操作时获取
+	mu    sync.Mutex
+	crumb *crumb
+}
+
+type crumb struct {
+	Field string `json:"crumbRequestField"`
+	Value string `json:"crumb"`
+}
+
+// NewClient 创建 Jenkins 客户端
+func NewClient(cfg ClientConfig) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // 由服务器配置显式开启
+	}
+	jar, _ := cookiejar.New(nil)
+	return &Client{
+		cfg: cfg,
+		http: &http.Client{
+			Transport: tracing.WrapClientTransport("jenkins", transport),
+			Jar:       jar,
+			// 触发构建返回 201 + Location，不需要跟随；其余重定向（如 http 跳转 https）正常跟随
+		},
+	}
+}
+
+// CloseIdleConnections 关闭连接池中的空闲连接，客户端被替换时调用
+func (c *Client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
+
+// Job 文件夹中的一个条目，可能是 Job 也可能是子文件夹
+type Job struct {
+	Name        string        `json:"name"`
+	FullName    string        `json:"fullName"` // 含文件夹的完整路径，如 team/backend/api
+	Type        string        `json:"type"`     // folder、multibranch、pipeline、freestyle 或 job
+	Folder      bool          `json:"folder"`   // 可以继续浏览其中的 Job
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url"`
+	Status      string        `json:"status,omitempty"` // 由 color 转换：success、failure、unstable、aborted、notbuilt、disabled
+	Building    bool          `json:"building"`
+	Buildable   bool          `json:"buildable"`
+	LastBuild   *BuildSummary `json:"lastBuild,omitempty"`
+}
+
+// BuildSummary 构建历史中的一次构建
+type BuildSummary struct {
+	Number     int       `json:"number"`
+	Result     string    `json:"result,omitempty"` // SUCCESS、FAILURE、UNSTABLE、ABORTED、NOT_BUILT，构建中为空
+	Building   bool      `json:"building"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+	URL        string    `json:"url"`
+}
+
+// ParameterDefinition Job 的构建参数
+type ParameterDefinition struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"` // string、text、boolean、choice、password 等
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Choices     []string    `json:"choices,omitempty"`
+}
+
+// JobDetail Job 详情，包括构建参数和最近的构建
+type JobDetail struct {
+	Job
+	Parameters      []ParameterDefinition `json:"parameters"`
+	Builds          []BuildSummary        `json:"builds"`
+	InQueue         bool                  `json:"inQueue"`
+	NextBuildNumber int                   `json:"nextBuildNumber"`
+}
+
+// Build 一次构建的详情
+type Build struct {
+	BuildSummary
+	DisplayName         string            `json:"displayName"`
+	Description         string            `json:"description,omitempty"`
+	EstimatedDurationMs int64             `json:"estimatedDurationMs"`
+	Parameters          map[string]string `json:"parameters,omitempty"`
+	Causes              []string          `json:"causes,omitempty"`
+}
+
+// QueueItem 构建队列中的条目，开始构建后 Build 不为空
+type QueueItem struct {
+	ID        int64         `json:"id"`
+	Why       string        `json:"why,omitempty"` // 等待的原因，如 Waiting for next available executor
+	Blocked   bool          `json:"blocked"`
+	Cancelled bool          `json:"cancelled"`
+	Build     *BuildSummary `json:"build,omitempty"`
+}
+
+// LogChunk 一段控制台输出，NextStart 为下次读取的字节偏移
+type LogChunk struct {
+	Text      string `json:"text"`
+	NextStart int64  `json:"nextStart"`
+	More      bool   `json:"more"` // 构建仍在进行，之后还有输出
+}
+
+const (
+	buildTree = `number,result,building,timestamp,duration,url`
+	jobTree   = `name,fullName,description,url,color,buildable,_class,lastBuild[` + buildTree + `]`
+)
+
+// ListJobs 列出文件夹中的 Job，folder 为空时列出根目录
+// 对应Shell: curl "$JENKINS_URL/job/$FOLDER/api/json?tree=jobs[name,color]"
+func (c *Client) ListJobs(ctx context.Context, folder string) ([]Job, error) {
+	path, err := jobPath(folder)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Jobs []jobJSON `json:"jobs"`
+	}
+	if err := c.getJSON(ctx, path+"/api/json", url.Values{"tree": {"jobs[" + jobTree + "]"}}, &resp); err != nil {
+		return nil, err
+	}
+	jobs := make([]Job, 0, len(resp.Jobs))
+	for _, j := range resp.Jobs {
+		job := j.toJob()
+		if job.FullName == "" {
+			job.FullName = strings.TrimPrefix(folder+"/"+job.Name, "/")
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetJob 获取 Job 详情和最近 builds 次构建
+func (c *Client) GetJob(ctx context.Context, job string, builds int) (JobDetail, error) {
+	path, err := requiredJobPath(job)
+	if err != nil {
+		return JobDetail{}, err
+	}
+	tree := fmt.Sprintf(`%s,inQueue,nextBuildNumber,builds[%s]{0,%d},`+
+		`property[parameterDefinitions[name,type,description,choices,defaultParameterValue[value]]]`, jobTree, buildTree, builds)
+	var resp struct {
+		jobJSON
+		InQueue         bool        `json:"inQueue"`
+		NextBuildNumber int         `json:"nextBuildNumber"`
+		Builds          []buildJSON `json:"builds"`
+		Property        []struct {
+			ParameterDefinitions []struct {
+				Name        string   `json:"name"`
+				Type        string   `json:"type"`
+				Description string   `json:"description"`
+				Choices     []string `json:"choices"`
+				Default     *struct {
+					Value interface{} `json:"value"`
+				} `json:"defaultParameterValue"`
+			} `json:"parameterDefinitions"`
+		} `json:"property"`
+	}
+	if err := c.getJSON(ctx, path+"/api/json", url.Values{"tree": {tree}}, &resp); err != nil {
+		return JobDetail{}, err
+	}
+
+	detail := JobDetail{
+		Job:             resp.toJob(),
+		Parameters:      []ParameterDefinition{},
+		Builds:          make([]BuildSummary, 0, len(resp.Builds)),
+		InQueue:         resp.InQueue,
+		NextBuildNumber: resp.NextBuildNumber,
+	}
+	for _, b := range resp.Builds {
+		detail.Builds = append(detail.Builds, b.toSummary())
+	}
+	for _, p := range resp.Property {
+		for _, d := range p.ParameterDefinitions {
+			def := ParameterDefinition{
+				Name:        d.Name,
+				Type:        parameterType(d.Type),
+				Description: d.Description,
+				Choices:     d.Choices,
+			}
+			if d.Default != nil && def.Type != "password" {
+				def.Default = d.Default.Value
+			}
+			detail.Parameters = append(detail.Parameters, def)
+		}
+	}
+	return detail, nil
+}
+
+// GetBuild 获取构建详情
+func (c *Client) GetBuild(ctx context.Context, job string, number int) (Build, error) {
+	path, err := requiredJobPath(job)
+	if err != nil {
+		return Build{}, err
+	}
+	tree := buildTree + `,displayName,description,estimatedDuration,actions[parameters[name,value],causes[shortDescription]]`
+	var resp struct {
+		buildJSON
+		DisplayName       string `json:"displayName"`
+		Description       string `json:"description"`
+		EstimatedDuration int64  `json:"estimatedDuration"`
+		Actions           []struct {
+			Parameters []struct {
+				Name  string      `json:"name"`
+				Value interface{} `json:"value"`
+			} `json:"parameters"`
+			Causes []struct {
+				ShortDescription string `json:"shortDescription"`
+			} `json:"causes"`
+		} `json:"actions"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/%d/api/json", path, number), url.Values{"tree": {tree}}, &resp); err != nil {
+		return Build{}, err
+	}
+	build := Build{
+		BuildSummary:        resp.toSummary(),
+		DisplayName:         resp.DisplayName,
+		Description:         resp.Description,
+		EstimatedDurationMs: resp.EstimatedDuration,
+	}
+	for _, a := range resp.Actions {
+		for _, p := range a.Parameters {
+			if build.Parameters == nil {
+				build.Parameters = make(map[string]string)
+			}
+			if p.Value == nil {
+				// 密码参数的值不会返回
+				build.Parameters[p.Name] = ""
+				continue
+			}
+			build.Parameters[p.Name] = fmt.Sprint(p.Value)
+		}
+		for _, cause := range a.Causes {
+			build.Causes = append(build.Causes, cause.ShortDescription)
+		}
+	}
+	return build, nil
+}
+
+// Build 触发构建，返回队列条目 ID；parameterized 为 true 时调用 buildWithParameters
+// 对应Shell: curl -X POST -H "$CRUMB" "$JENKINS_URL/job/$JOB/buildWithParameters" --data-urlencode "BRANCH=main"
+func (c *Client) Build(ctx context.Context, job string, parameterized bool, params map[string]string) (int64, error) {
+	path, err := requiredJobPath(job)
+	if err != nil {
+		return 0, err
+	}
+	form := url.Values{}
+	endpoint := path + "/build"
+	if parameterized {
+		endpoint = path + "/buildWithParameters"
+		for k, v := range params {
+			form.Set(k, v)
+		}
+	}
+
+	resp, err := c.post(ctx, endpoint, form)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	// Location 形如 $JENKINS_URL/queue/item/123/
+	location := strings.TrimRight(resp.Header.Get("Location"), "/")
+	id, err := strconv.ParseInt(location[strings.LastIndex(location, "/")+1:], 10, 64)
+	if err != nil {
+		return 0, response.NewError(http.StatusBadGateway, response.CodeBadGateway,
+			fmt.Sprintf("Jenkins 未返回队列地址（Location: %q）", resp.Header.Get("Location")), err)
+	}
+	return id, nil
+}
+
+// GetQueueItem 获取队列条目，构建开始后返回构建号；条目在构建开始几分钟后被 Jenkins 清除
+func (c *Client) GetQueueItem(ctx context.Context, id int64) (QueueItem, error) {
+	var resp struct {
+		ID         int64  `json:"id"`
+		Why        string `json:"why"`
+		Blocked    bool   `json:"blocked"`
+		Cancelled  bool   `json:"cancelled"`
+		Executable *struct {
+			Number int    `json:"number"`
+			URL    string `json:"url"`
+		} `json:"executable"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("/queue/item/%d/api/json", id), nil, &resp); err != nil {
+		return QueueItem{}, err
+	}
+	item := QueueItem{ID: resp.ID, Why: resp.Why, Blocked: resp.Blocked, Cancelled: resp.Cancelled}
+	if resp.Executable != nil {
+		item.Build = &BuildSummary{Number: resp.Executable.Number, Building: true, URL: resp.Executable.URL}
+	}
+	return item, nil
+}
+
+// ProgressiveLog 从字节偏移 start 开始读取控制台输出
+// 对应Shell: curl "$JENKINS_URL/job/$JOB/$NUMBER/logText/progressiveText?start=$START"
+func (c *Client) ProgressiveLog(ctx context.Context, job string, number int, start int64) (LogChunk, error) {
+	path, err := requiredJobPath(job)
+	if err != nil {
+		return LogChunk{}, err
+	}
+	query := url.Values{"start": {strconv.FormatInt(start, 10)}}
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d/logText/progressiveText", path, number), query, nil)
+	if err != nil {
+		return LogChunk{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogChunkBytes+1))
+	if err != nil {
+		return LogChunk{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "读取 Jenkins 控制台输出失败", err)
+	}
+	chunk := LogChunk{More: resp.Header.Get("X-More-Data") == "true"}
+	if len(data) > maxLogChunkBytes {
+		// 输出过多时在最后一个换行处截断，剩余部分下次读取，避免拆开多字节字符
+		data = data[:maxLogChunkBytes]
+		if i := strings.LastIndexByte(string(data), '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+		chunk.Text = string(data)
+		chunk.NextStart = start + int64(len(data))
+		chunk.More = true
+		return chunk, nil
+	}
+	chunk.Text = string(data)
+	chunk.NextStart = start + int64(len(data))
+	if size, err := strconv.ParseInt(resp.Header.Get("X-Text-Size"), 10, 64); err == nil {
+		chunk.NextStart = size
+	}
+	return chunk, nil
+}
+
+// Ping 校验地址和凭据，对应Shell: curl -u "$USER:$TOKEN" "$JENKINS_URL/api/json"
+func (c *Client) Ping(ctx context.Context) error {
+	var resp struct{}
+	return c.getJSON(ctx, "/api/json", url.Values{"tree": {"mode"}}, &resp)
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out interface{}) error {
+	resp, err := c.do(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "读取 Jenkins 响应失败", err)
+	}
+	if len(body) > maxResponseBytes {
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "Jenkins 响应过大", nil)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "Jenkins 返回了无法解析的响应", err)
+	}
+	return nil
+}
+
+// post 发送带 crumb 的写请求；crumb 过期（403）时重新获取并重试一次
+func (c *Client) post(ctx context.Context, path string, form url.Values) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		cr, err := c.getCrumb(ctx, attempt > 0)
+		if err != nil {
+			return nil, err
+		}
+		header := http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}
+		if cr != nil {
+			header.Set(cr.Field, cr.Value)
+		}
+		resp, err := c.do(ctx, http.MethodPost, path, nil, &request{body: form.Encode(), header: header})
+		var appErr *response.AppError
+		if attempt == 0 && cr != nil && errors.As(err, &appErr) && appErr.Status == http.StatusForbidden {
+			continue
+		}
+		return resp, err
+	}
+}
+
+// getCrumb 获取防 CSRF 令牌，Jenkins 未启用 CSRF 保护（404）时返回 nil
+func (c *Client) getCrumb(ctx context.Context, refresh bool) (*crumb, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.crumb != nil && !refresh {
+		return c.crumb, nil
+	}
+	var cr crumb
+	err := c.getJSON(ctx, "/crumbIssuer/api/json", nil, &cr)
+	var appErr *response.AppError
+	if errors.As(err, &appErr) && appErr.Status == http.StatusNotFound {
+		c.crumb = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.crumb = &cr
+	return c.crumb, nil
+}
+
+type request struct {
+	body   string
+	header http.Header
+}
+
+// do 发送请求并把 Jenkins 的错误映射为类型化错误，成功时由调用方关闭响应体
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, r *request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	endpoint := strings.TrimRight(c.cfg.URL, "/") + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var body io.Reader
+	if r != nil {
+		body = strings.NewReader(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	if r != nil {
+		for k, v := range r.header {
+			req.Header[k] = v
+		}
+	}
+	if c.cfg.Username != "" || c.cfg.APIToken != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.APIToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "Jenkins 请求超时", err)
+		}
+		return nil, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "Jenkins 不可达", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer cancel()
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, statusError(resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// statusError 按 HTTP 状态码映射错误；Jenkins 的认证失败是上游配置问题，返回 502 而不是 401
+func statusError(status int, body string) error {
+	err := fmt.Errorf("jenkins returned HTTP %d: %s", status, body)
+	switch status {
+	case http.StatusUnauthorized:
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "Jenkins 认证失败，请检查用户名和 API Token", err)
+	case http.StatusForbidden:
+		return response.NewError(http.StatusForbidden, response.CodeForbidden, "Jenkins 用户没有权限执行该操作", err)
+	case http.StatusNotFound:
+		return response.ErrNotFound("Jenkins 中不存在该 Job、构建或队列条目", err)
+	case http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusConflict:
+		return response.ErrBadRequest("Jenkins 拒绝了请求", err)
+	default:
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, fmt.Sprintf("Jenkins 请求失败（HTTP %d）", status), err)
+	}
+}
+
+// cancelBody 关闭响应体时释放请求的超时 context
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// jobPath 把 team/backend/api 转换为 /job/team/job/backend/job/api
+func jobPath(name string) (string, error) {
+	name = strings.Trim(name, "/")
+	if name == "" {
+		return "", nil
+	}
+	var b strings.Builder
+	for _, segment := range strings.Split(name, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", response.ErrBadRequest(fmt.Sprintf("Job 路径无效: %s", name), nil)
+		}
+		b.WriteString("/job/")
+		b.WriteString(url.PathEscape(segment))
+	}
+	return b.String(), nil
+}
+
+func requiredJobPath(name string) (string, error) {
+	path, err := jobPath(name)
+	if err == nil && path == "" {
+		err = response.ErrBadRequest("job 参数必填", nil)
+	}
+	return path, err
+}
+
+type jobJSON struct {
+	Class       string     `json:"_class"`
+	Name        string     `json:"name"`
+	FullName    string     `json:"fullName"`
+	Description string     `json:"description"`
+	URL         string     `json:"url"`
+	Color       string     `json:"color"`
+	Buildable   bool       `json:"buildable"`
+	LastBuild   *buildJSON `json:"lastBuild"`
+}
+
+func (j jobJSON) toJob() Job {
+	job := Job{
+		Name:        j.Name,
+		FullName:    j.FullName,
+		Type:        jobType(j.Class),
+		Description: j.Description,
+		URL:         j.URL,
+		Buildable:   j.Buildable,
+	}
+	job.Folder = job.Type == "folder" || job.Type == "multibranch"
+	job.Status, job.Building = colorStatus(j.Color)
+	if j.LastBuild != nil {
+		b := j.LastBuild.toSummary()
+		job.LastBuild = &b
+	}
+	return job
+}
+
+type buildJSON struct {
+	Number    int    `json:"number"`
+	Result    string `json:"result"`
+	Building  bool   `json:"building"`
+	Timestamp int64  `json:"timestamp"` // 毫秒
+	Duration  int64  `json:"duration"`
+	URL       string `json:"url"`
+}
+
+func (b buildJSON) toSummary() BuildSummary {
+	s := BuildSummary{
+		Number:     b.Number,
+		Result:     b.Result,
+		Building:   b.Building,
+		DurationMs: b.Duration,
+		URL:        b.URL,
+	}
+	if b.Timestamp > 0 {
+		s.StartedAt = time.UnixMilli(b.Timestamp).UTC()
+	}
+	return s
+}
+
+// jobType 按 Jenkins 的 _class 区分 Job 类型
+func jobType(class string) string {
+	switch class {
+	case "com.cloudbees.hudson.plugins.folder.Folder", "jenkins.branch.OrganizationFolder":
+		return "folder"
+	case "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject":
+		return "multibranch"
+	case "org.jenkinsci.plugins.workflow.job.WorkflowJob":
+		return "pipeline"
+	case "hudson.model.FreeStyleProject":
+		return "freestyle"
+	default:
+		return "job"
+	}
+}
+
+// colorStatus 把 Jenkins 的状态颜色转换为状态，_anime 后缀表示正在构建
+func colorStatus(color string) (string, bool) {
+	base, building := strings.CutSuffix(color, "_anime")
+	switch base {
+	case "blue", "green":
+		return "success", building
+	case "red":
+		return "failure", building
+	case "yellow":
+		return "unstable", building
+	case "aborted":
+		return "aborted", building
+	case "notbuilt", "nobuilt", "grey":
+		return "notbuilt", building
+	case "disabled":
+		return "disabled", building
+	default:
+		return "", building
+	}
+}
+
+// parameterType StringParameterDefinition -> string
+func parameterType(t string) string {
+	return strings.ToLower(strings.TrimSuffix(t, "ParameterDefinition"))
+}
@@ -0,0 +1,310 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// stubJenkins Jenkins HTTP 接口的替身
+// crumb 与会话 Cookie 绑定，staleCrumbs 次写请求返回 403 模拟会话过期后 crumb 失效
+type stubJenkins struct {
+	t *testing.T
+
+	mu          sync.Mutex
+	csrf        bool
+	crumbs      int
+	staleCrumbs int
+	builds      []string // 收到的构建请求体
+	log         string
+	building    bool
+}
+
+func (s *stubJenkins) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, token, ok := r.BasicAuth(); !ok || user != "admin" || token != "api-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/crumbIssuer/api/json":
+		if !s.csrf {
+			http.NotFound(w, r)
+			return
+		}
+		s.crumbs++
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: strconv.Itoa(s.crumbs), Path: "/"})
+		fmt.Fprintf(w, `{"crumbRequestField":"Jenkins-Crumb","crumb":"crumb-%d"}`, s.crumbs)
+
+	case r.URL.Path == "/job/team/api/json":
+		if !strings.HasPrefix(r.URL.Query().Get("tree"), "jobs[") {
+			s.t.Errorf("tree = %q, want jobs[...]", r.URL.Query().Get("tree"))
+		}
+		_, _ = w.Write([]byte(`{"jobs":[
+			{"_class":"com.cloudbees.hudson.plugins.folder.Folder","name":"backend","fullName":"team/backend"},
+			{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"api","color":"red_anime","buildable":true,
+			 "lastBuild":{"number":7,"building":true,"timestamp":1767322800000,"url":"http://jenkins/job/team/job/api/7/"}},
+			{"_class":"hudson.model.FreeStyleProject","name":"docs","color":"disabled"}
+		]}`))
+
+	case r.URL.Path == "/job/team/job/api/api/json":
+		_, _ = w.Write([]byte(`{"_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob","name":"api","fullName":"team/api",
+			"color":"blue","buildable":true,"nextBuildNumber":8,
+			"builds":[{"number":7,"result":"SUCCESS","timestamp":1767322800000,"duration":61000}],
+			"property":[{},{"parameterDefinitions":[
+				{"name":"BRANCH","type":"StringParameterDefinition","defaultParameterValue":{"value":"main"}},
+				{"name":"TOKEN","type":"PasswordParameterDefinition","defaultParameterValue":{"value":"s3cret"}},
+				{"name":"ENV","type":"ChoiceParameterDefinition","choices":["dev","prod"],"defaultParameterValue":{"value":"dev"}}
+			]}]}`))
+
+	case r.Method == http.MethodPost && r.URL.Path == "/job/team/job/api/buildWithParameters":
+		if s.csrf {
+			cookie, err := r.Cookie("JSESSIONID")
+			if err != nil || r.Header.Get("Jenkins-Crumb") != "crumb-"+cookie.Value {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if s.staleCrumbs > 0 {
+				s.staleCrumbs--
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte("No valid crumb was included in the request"))
+				return
+			}
+		}
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.builds = append(s.builds, r.PostForm.Encode())
+		w.Header().Set("Location", "http://jenkins/queue/item/42/")
+		w.WriteHeader(http.StatusCreated)
+
+	case r.URL.Path == "/job/team/job/api/7/logText/progressiveText":
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		start = min(start, len(s.log))
+		w.Header().Set("X-Text-Size", strconv.Itoa(len(s.log)))
+		if s.building {
+			w.Header().Set("X-More-Data", "true")
+		}
+		_, _ = w.Write([]byte(s.log[start:]))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newStubClient(t *testing.T, stub *stubJenkins) *Client {
+	t.Helper()
+	stub.t = t
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	return NewClient(ClientConfig{URL: srv.URL + "/", Username: "admin", APIToken: "api-token", Timeout: 5 * time.Second})
+}
+
+func TestListJobs(t *testing.T) {
+	client := newStubClient(t, &stubJenkins{})
+	jobs, err := client.ListJobs(context.Background(), "team")
+	if err != nil {
+		t.Fatalf("ListJobs() error = %v", err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("got %d jobs, want 3", len(jobs))
+	}
+
+	folder, api, docs := jobs[0], jobs[1], jobs[2]
+	if folder.Type != "folder" || !folder.Folder || folder.FullName != "team/backend" {
+		t.Errorf("folder = %+v", folder)
+	}
+	if api.Type != "pipeline" || api.Status != "failure" || !api.Building || api.FullName != "team/api" {
+		t.Errorf("api = %+v", api)
+	}
+	if api.LastBuild == nil || api.LastBuild.Number != 7 || !api.LastBuild.StartedAt.Equal(time.UnixMilli(1767322800000)) {
+		t.Errorf("api.LastBuild = %+v", api.LastBuild)
+	}
+	if docs.Type != "freestyle" || docs.Status != "disabled" {
+		t.Errorf("docs = %+v", docs)
+	}
+}
+
+func TestGetJobHidesPasswordDefaults(t *testing.T) {
+	client := newStubClient(t, &stubJenkins{})
+	job, err := client.GetJob(context.Background(), "team/api", 10)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if job.NextBuildNumber != 8 || len(job.Builds) != 1 || job.Builds[0].Result != "SUCCESS" {
+		t.Errorf("job = %+v", job)
+	}
+	want := map[string]interface{}{"BRANCH": "main", "TOKEN": nil, "ENV": "dev"}
+	if len(job.Parameters) != len(want) {
+		t.Fatalf("got %d parameters, want %d", len(job.Parameters), len(want))
+	}
+	for _, p := range job.Parameters {
+		if p.Default != want[p.Name] {
+			t.Errorf("parameter %s default = %v, want %v", p.Name, p.Default, want[p.Name])
+		}
+	}
+	if job.Parameters[1].Type != "password" || len(job.Parameters[2].Choices) != 2 {
+		t.Errorf("parameters = %+v", job.Parameters)
+	}
+}
+
+func TestBuildRetriesWithFreshCrumbOn403(t *testing.T) {
+	stub := &stubJenkins{csrf: true}
+	client := newStubClient(t, stub)
+
+	// 第一次构建获取 crumb-1
+	if _, err := client.Build(context.Background(), "team/api", true, map[string]string{"BRANCH": "main"}); err != nil {
+		t.Fatalf("first Build() error = %v", err)
+	}
+	// crumb 失效后重新获取一次并重试
+	stub.mu.Lock()
+	stub.staleCrumbs = 1
+	stub.mu.Unlock()
+	id, err := client.Build(context.Background(), "team/api", true, map[string]string{"BRANCH": "release"})
+	if err != nil {
+		t.Fatalf("second Build() error = %v", err)
+	}
+	if id != 42 {
+		t.Errorf("queue id = %d, want 42", id)
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if stub.crumbs != 2 {
+		t.Errorf("crumb issued %d times, want 2", stub.crumbs)
+	}
+	if want := []string{"BRANCH=main", "BRANCH=release"}; strings.Join(stub.builds, ",") != strings.Join(want, ",") {
+		t.Errorf("builds = %v, want %v", stub.builds, want)
+	}
+}
+
+func TestBuildGivesUpAfterSecond403(t *testing.T) {
+	stub := &stubJenkins{csrf: true, staleCrumbs: 2}
+	client := newStubClient(t, stub)
+
+	_, err := client.Build(context.Background(), "team/api", true, nil)
+	var appErr *response.AppError
+	if !errors.As(err, &appErr) || appErr.Status != http.StatusForbidden {
+		t.Fatalf("Build() error = %v, want 403", err)
+	}
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if stub.crumbs != 2 {
+		t.Errorf("crumb issued %d times, want 2", stub.crumbs)
+	}
+}
+
+func TestBuildWithoutCSRFProtection(t *testing.T) {
+	client := newStubClient(t, &stubJenkins{})
+	id, err := client.Build(context.Background(), "team/api", true, map[string]string{"ENV": "prod"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if id != 42 {
+		t.Errorf("queue id = %d, want 42", id)
+	}
+}
+
+func TestProgressiveLogOffsets(t *testing.T) {
+	stub := &stubJenkins{log: "Started\nStep 1\n", building: true}
+	client := newStubClient(t, stub)
+	ctx := context.Background()
+
+	chunk, err := client.ProgressiveLog(ctx, "team/api", 7, 0)
+	if err != nil {
+		t.Fatalf("ProgressiveLog() error = %v", err)
+	}
+	if chunk.Text != "Started\nStep 1\n" || chunk.NextStart != 15 || !chunk.More {
+		t.Errorf("first chunk = %+v", chunk)
+	}
+
+	stub.mu.Lock()
+	stub.log += "Finished: SUCCESS\n"
+	stub.building = false
+	stub.mu.Unlock()
+
+	chunk, err = client.ProgressiveLog(ctx, "team/api", 7, chunk.NextStart)
+	if err != nil {
+		t.Fatalf("ProgressiveLog() error = %v", err)
+	}
+	if chunk.Text != "Finished: SUCCESS\n" || chunk.NextStart != 33 || chunk.More {
+		t.Errorf("second chunk = %+v", chunk)
+	}
+}
+
+func TestProgressiveLogSplitsLargeOutputAtNewline(t *testing.T) {
+	line := strings.Repeat("x", 99) + "\n"
+	stub := &stubJenkins{log: strings.Repeat(line, maxLogChunkBytes/len(line)+10)}
+	client := newStubClient(t, stub)
+
+	chunk, err := client.ProgressiveLog(context.Background(), "team/api", 7, 0)
+	if err != nil {
+		t.Fatalf("ProgressiveLog() error = %v", err)
+	}
+	if len(chunk.Text) > maxLogChunkBytes || !strings.HasSuffix(chunk.Text, "\n") {
+		t.Errorf("chunk length = %d, ends with newline = %v", len(chunk.Text), strings.HasSuffix(chunk.Text, "\n"))
+	}
+	if !chunk.More || chunk.NextStart != int64(len(chunk.Text)) {
+		t.Errorf("More = %v, NextStart = %d, want true and %d", chunk.More, chunk.NextStart, len(chunk.Text))
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		job        string
+		client     ClientConfig
+		wantStatus int
+	}{
+		{name: "bad credentials", job: "team/api", client: ClientConfig{Username: "admin", APIToken: "wrong"}, wantStatus: http.StatusBadGateway},
+		{name: "missing job", job: "team/missing", client: ClientConfig{Username: "admin", APIToken: "api-token"}, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubJenkins{t: t}
+			srv := httptest.NewServer(stub)
+			defer srv.Close()
+			tt.client.URL = srv.URL
+			tt.client.Timeout = 5 * time.Second
+
+			_, err := NewClient(tt.client).GetJob(context.Background(), tt.job, 1)
+			var appErr *response.AppError
+			if !errors.As(err, &appErr) || appErr.Status != tt.wantStatus {
+				t.Errorf("GetJob() error = %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestJobPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "", want: ""},
+		{name: "api", want: "/job/api"},
+		{name: "/team/backend/api/", want: "/job/team/job/backend/job/api"},
+		{name: "team/feature%2Fx", want: "/job/team/job/feature%252Fx"},
+		{name: "team/../admin", wantErr: true},
+		{name: "team//api", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := jobPath(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("jobPath(%q) = %q, %v; want %q, wantErr %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package kuberequest 从 client-go 发往 apiserver 的请求中解析 Kubernetes verb 和资源，
+// 供链路追踪和指标使用低基数的名称，而不是完整的请求路径
+package kuberequest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Info apiserver 请求的 verb 和资源
+type Info struct {
+	// Verb Kubernetes verb：get、list、watch、create、update、patch、delete、deletecollection；
+	// 非资源请求为小写的 HTTP 方法
+	Verb string
+	// Resource 资源名（含子资源），如 pods、pods/log；API 发现请求为 discovery，非资源请求为 nonresource
+	Resource string
+}
+
+// Parse 按 apiserver 的规则从 HTTP 方法和 URL 解析 verb 和资源
+// GET /api/v1/namespaces/default/pods -> list pods，GET ...?watch=true -> watch pods，
+// DELETE /apis/apps/v1/namespaces/web/deployments -> deletecollection deployments
+func Parse(method string, u *url.URL) Info {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 3 && segments[0] == "apis":
+		segments = segments[3:]
+	default:
+		return Info{Verb: strings.ToLower(method), Resource: "nonresource"}
+	}
+	// 旧的 /watch/ 前缀形式
+	watch := false
+	if len(segments) > 0 && segments[0] == "watch" {
+		watch = true
+		segments = segments[1:]
+	}
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		segments = segments[2:]
+	}
+
+	var info Info
+	named := len(segments) >= 2
+	switch len(segments) {
+	case 0:
+		return Info{Verb: strings.ToLower(method), Resource: "discovery"}
+	case 1, 2:
+		info.Resource = segments[0]
+	default:
+		info.Resource = segments[0] + "/" + segments[2]
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case watch || isWatch(u):
+			info.Verb = "watch"
+		case named:
+			info.Verb = "get"
+		default:
+			info.Verb = "list"
+		}
+	case http.MethodPost:
+		info.Verb = "create"
+	case http.MethodPut:
+		info.Verb = "update"
+	case http.MethodPatch:
+		info.Verb = "patch"
+	case http.MethodDelete:
+		if named {
+			info.Verb = "delete"
+		} else {
+			info.Verb = "deletecollection"
+		}
+	default:
+		info.Verb = strings.ToLower(method)
+	}
+	return info
+}
+
+// isWatch 判断 watch 查询参数，client-go 发送 watch=true
+func isWatch(u *url.URL) bool {
+	watch, _ := strconv.ParseBool(u.Query().Get("watch"))
+	return watch
+}
@@ -0,0 +1,42 @@
+package kuberequest
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   Info
+	}{
+		{http.MethodGet, "/api/v1/namespaces/default/pods", Info{"list", "pods"}},
+		{http.MethodGet, "/api/v1/namespaces/default/pods?watch=true&resourceVersion=10", Info{"watch", "pods"}},
+		{http.MethodGet, "/api/v1/watch/namespaces/default/pods", Info{"watch", "pods"}},
+		{http.MethodGet, "/api/v1/namespaces/default/pods/nginx", Info{"get", "pods"}},
+		{http.MethodGet, "/api/v1/namespaces/default/pods/nginx/log", Info{"get", "pods/log"}},
+		{http.MethodGet, "/api/v1/namespaces/default", Info{"get", "namespaces"}},
+		{http.MethodGet, "/api/v1/namespaces", Info{"list", "namespaces"}},
+		{http.MethodGet, "/api/v1/nodes/node-1", Info{"get", "nodes"}},
+		{http.MethodGet, "/apis/apps/v1/deployments", Info{"list", "deployments"}},
+		{http.MethodPost, "/api/v1/namespaces/default/pods/nginx/exec?command=sh", Info{"create", "pods/exec"}},
+		{http.MethodPost, "/apis/apps/v1/namespaces/web/deployments", Info{"create", "deployments"}},
+		{http.MethodPut, "/apis/apps/v1/namespaces/web/deployments/api/scale", Info{"update", "deployments/scale"}},
+		{http.MethodPatch, "/apis/apps/v1/namespaces/web/deployments/api", Info{"patch", "deployments"}},
+		{http.MethodDelete, "/api/v1/namespaces/web/pods/api-1", Info{"delete", "pods"}},
+		{http.MethodDelete, "/api/v1/namespaces/web/pods", Info{"deletecollection", "pods"}},
+		{http.MethodGet, "/apis/apps/v1", Info{"get", "discovery"}},
+		{http.MethodGet, "/readyz", Info{"get", "nonresource"}},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Parse(tt.method, u); got != tt.want {
+			t.Errorf("Parse(%s %s) = %+v, want %+v", tt.method, tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+)
+
+// serviceAccountNamespace 集群内运行时 Pod 所在命名空间
+const serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// campaignRetry 选举失败（如集群或 Redis 不可用）后重新参选的间隔
+const campaignRetry = 5 * time.Second
+
+// Worker 只在主节点上运行的后台任务
+// Run 在成为主节点时启动，失去主节点身份时 ctx 被取消，应尽快返回
+type Worker struct {
+	Name string
+	Run  func(ctx context.Context)
+}
+
+// Status 当前选举状态
+type Status struct {
+	Enabled  bool     `json:"enabled"`
+	Backend  string   `json:"backend,omitempty"`
+	Identity string   `json:"identity"`
+	Leader   string   `json:"leader"`
+	IsLeader bool     `json:"isLeader"`
+	Workers  []string `json:"workers"`
+}
+
+// campaign 一次参选：阻塞直到失去主节点身份或 ctx 取消
+// 成为主节点时调用 onStarted，新的主节点出现时调用 onNewLeader
+type campaign func(ctx context.Context, onStarted func(ctx context.Context), onNewLeader func(identity string)) error
+
+// Elector 后台任务的主节点选举
+// 多副本中只有主节点运行已注册的 Worker；未启用选举时本副本始终视为主节点（单副本部署）
+type Elector struct {
+	logger   *zap.Logger
+	cfg      config.LeaderElectionConfig
+	identity string
+	campaign campaign
+
+	mu       sync.RWMutex
+	workers  []Worker
+	leader   string
+	isLeader bool
+}
+
+// NewElector 按配置创建选举器
+func NewElector(logger *zap.Logger, cfg config.LeaderElectionConfig, clusters *client.ClusterManager, redisClient *redis.Client) *Elector {
+	e := &Elector{
+		logger:   logger,
+		cfg:      cfg,
+		identity: identity(),
+	}
+	if cfg.Namespace == "" {
+		e.cfg.Namespace = podNamespace()
+	}
+
+	switch cfg.Backend {
+	case config.LeaderElectionBackendRedis:
+		e.campaign = newRedisCampaign(e.cfg, e.identity, redisClient)
+	default:
+		e.campaign = newLeaseCampaign(e.cfg, e.identity, clusters)
+	}
+	return e
+}
+
+// Register 注册只在主节点上运行的后台任务，需在 Run 之前调用
+func (e *Elector) Register(name string, run func(ctx context.Context)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.workers = append(e.workers, Worker{Name: name, Run: run})
+}
+
+// Run 阻塞运行直到 ctx 取消：参选，成为主节点后运行所有 Worker，失去身份后重新参选
+func (e *Elector) Run(ctx context.Context) {
+	if !e.cfg.Enabled {
+		e.setLeader(e.identity)
+		e.runWorkers(ctx)
+		return
+	}
+
+	e.logger.Info("Starting leader election",
+		zap.String("backend", e.cfg.Backend),
+		zap.String("identity", e.identity),
+		zap.String("namespace", e.cfg.Namespace),
+		zap.String("name", e.cfg.Name),
+	)
+	for {
+		err := e.campaign(ctx, e.runWorkers, e.setLeader)
+		e.setLeader("")
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			e.logger.Warn("Leader election failed, retrying", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(campaignRetry):
+		}
+	}
+}
+
+// runWorkers 启动所有 Worker 并等待它们在 ctx 取消后退出
+func (e *Elector) runWorkers(ctx context.Context) {
+	e.mu.RLock()
+	workers := append([]Worker(nil), e.workers...)
+	e.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w Worker) {
+			defer wg.Done()
+			e.logger.Info("Starting background worker", zap.String("worker", w.Name))
+			w.Run(ctx)
+			e.logger.Info("Background worker stopped", zap.String("worker", w.Name))
+		}(w)
+	}
+	wg.Wait()
+}
+
+func (e *Elector) setLeader(leader string) {
+	e.mu.Lock()
+	changed := e.leader != leader
+	e.leader = leader
+	wasLeader := e.isLeader
+	e.isLeader = leader == e.identity
+	isLeader := e.isLeader
+	e.mu.Unlock()
+
+	if isLeader {
+		metrics.LeaderStatus.Set(1)
+	} else {
+		metrics.LeaderStatus.Set(0)
+	}
+	if isLeader != wasLeader {
+		metrics.LeaderTransitions.Inc()
+	}
+	if changed && leader != "" {
+		e.logger.Info("Leader elected", zap.String("leader", leader), zap.Bool("isLeader", isLeader))
+	}
+}
+
+// IsLeader 本副本当前是否为主节点
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.isLeader
+}
+
+// Status 返回当前选举状态，用于健康检查
+func (e *Elector) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	st := Status{
+		Enabled:  e.cfg.Enabled,
+		Identity: e.identity,
+		Leader:   e.leader,
+		IsLeader: e.isLeader,
+		Workers:  make([]string, 0, len(e.workers)),
+	}
+	if e.cfg.Enabled {
+		st.Backend = e.cfg.Backend
+	}
+	for _, w := range e.workers {
+		st.Workers = append(st.Workers, w.Name)
+	}
+	return st
+}
+
+// identity 副本标识：主机名（集群内即 Pod 名）加随机后缀，避免同名进程冲突
+func identity() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "kubeops"
+	}
+	return fmt.Sprintf("%s_%s", hostname, uuid.NewString()[:8])
+}
+
+// podNamespace 优先使用 POD_NAMESPACE，其次读取 ServiceAccount 挂载的命名空间，最后回退到 default
+func podNamespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespace); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
@@ -0,0 +1,74 @@
+package leader
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+// newLeaseCampaign 使用 Kubernetes Lease 对象选举
+// 类比Shell: kubectl get lease kubeops-leader -o jsonpath='{.spec.holderIdentity}'
+// 每次参选时重新获取集群客户端，集群热加载或恢复后自动使用新客户端
+func newLeaseCampaign(cfg config.LeaderElectionConfig, identity string, clusters *client.ClusterManager) campaign {
+	return func(ctx context.Context, onStarted func(ctx context.Context), onNewLeader func(string)) error {
+		cluster, err := clusters.Get(cfg.Cluster)
+		if err != nil {
+			return err
+		}
+
+		// client-go 在 goroutine 中调用 OnStartedLeading，Run 返回前不等待它结束，ctx 取消时还会先释放 Lease
+		// 因此 Worker 由这里运行：选举使用独立的 context，Worker 全部返回后才结束选举、释放 Lease，
+		// 避免本副本的旧 Worker 与重新参选后或其他副本上的新 Worker 同时运行
+		electCtx, stopElection := context.WithCancel(context.WithoutCancel(ctx))
+		defer stopElection()
+		leading := make(chan context.Context, 1)
+
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock: &resourcelock.LeaseLock{
+				LeaseMeta:  metav1.ObjectMeta{Name: cfg.Name, Namespace: cfg.Namespace},
+				Client:     cluster.Clientset.CoordinationV1(),
+				LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+			},
+			LeaseDuration:   cfg.LeaseDuration,
+			RenewDeadline:   cfg.RenewDeadline,
+			RetryPeriod:     cfg.RetryPeriod,
+			ReleaseOnCancel: true,
+			Name:            cfg.Name,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(leaderCtx context.Context) { leading <- leaderCtx },
+				OnStoppedLeading: func() {},
+				OnNewLeader:      onNewLeader,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Run 在失去 Lease 或 electCtx 取消时返回
+		runDone := make(chan struct{})
+		go func() {
+			defer close(runDone)
+			elector.Run(electCtx)
+		}()
+
+		select {
+		case leaderCtx := <-leading:
+			// 失去 Lease 时 leaderCtx 被取消，ctx 取消时由 AfterFunc 通知 Worker 退出
+			workerCtx, cancel := context.WithCancel(leaderCtx)
+			stop := context.AfterFunc(ctx, cancel)
+			onStarted(workerCtx)
+			stop()
+			cancel()
+		case <-runDone:
+		case <-ctx.Done():
+		}
+		stopElection()
+		<-runDone
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+const redisKeyPrefix = "kubeops:leader:"
+
+// renewScript 只有锁仍属于自己时才续期
+var renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript 只有锁仍属于自己时才释放
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// newRedisCampaign 使用 Redis 锁选举，适用于集群外运行（无法创建 Lease）的场景
+// 语义与 Lease 一致：持有者每 RetryPeriod 续期，超过 RenewDeadline 未续期成功即主动让出
+func newRedisCampaign(cfg config.LeaderElectionConfig, identity string, redisClient *redis.Client) campaign {
+	key := redisKeyPrefix + cfg.Name
+	ttl := cfg.LeaseDuration.Milliseconds()
+
+	return func(ctx context.Context, onStarted func(ctx context.Context), onNewLeader func(string)) error {
+		// 等待获取锁，期间记录当前持有者
+		for {
+			acquired, err := redisClient.SetNX(ctx, key, identity, cfg.LeaseDuration).Result()
+			if err != nil {
+				return fmt.Errorf("failed to acquire leader lock: %w", err)
+			}
+			if acquired {
+				break
+			}
+			if holder, err := redisClient.Get(ctx, key).Result(); err == nil {
+				onNewLeader(holder)
+			} else if !errors.Is(err, redis.Nil) {
+				return fmt.Errorf("failed to read leader lock: %w", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(cfg.RetryPeriod):
+			}
+		}
+
+		onNewLeader(identity)
+		leaderCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			onStarted(leaderCtx)
+		}()
+
+		err := renewLoop(leaderCtx, redisClient, key, identity, ttl, cfg)
+		cancel()
+		<-done
+
+		// 使用独立 context 释放锁，其他副本无需等待锁过期
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), time.Second)
+		defer releaseCancel()
+		_ = releaseScript.Run(releaseCtx, redisClient, []string{key}, identity).Err()
+		return err
+	}
+}
+
+// renewLoop 定期续期，锁被他人持有或超过 RenewDeadline 未续期成功时返回
+func renewLoop(ctx context.Context, redisClient *redis.Client, key, identity string, ttl int64, cfg config.LeaderElectionConfig) error {
+	lastRenew := time.Now()
+	ticker := time.NewTicker(cfg.RetryPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		renewed, err := renewScript.Run(ctx, redisClient, []string{key}, identity, ttl).Int()
+		switch {
+		case err == nil && renewed == 1:
+			lastRenew = time.Now()
+		case err == nil:
+			return errors.New("leader lock taken over by another replica")
+		case time.Since(lastRenew) > cfg.RenewDeadline:
+			return fmt.Errorf("failed to renew leader lock within %s: %w", cfg.RenewDeadline, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package llm 大模型客户端：兼容 OpenAI Chat Completions 接口的服务，以及用于开发和演示的本地 stub
+package llm
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+// 消息角色
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Message 一条对话消息
+// 模型请求调用工具时 assistant 消息带有 ToolCalls；工具结果以 tool 消息返回，ToolCallID 对应调用的 ID
+type Message struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"toolCalls,omitempty"`
+	ToolCallID string     `json:"toolCallId,omitempty"`
+}
+
+// Tool 提供给模型调用的函数，Parameters 为参数的 JSON Schema
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolCall 模型请求的一次函数调用，Arguments 为 JSON 字符串
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// Request 一次对话请求
+type Request struct {
+	Messages    []Message
+	Tools       []Tool
+	MaxTokens   int
+	Temperature float64
+	JSON        bool // 要求模型只输出 JSON 对象
+}
+
+// Usage token 用量，服务端未返回时为估算值
+type Usage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// Response 模型回复
+type Response struct {
+	Content      string
+	Model        string
+	FinishReason string // length 表示输出达到 MaxTokens 被截断
+	ToolCalls    []ToolCall
+	Usage        Usage
+}
+
+// Client 大模型客户端
+// 类比Shell: curl "$BASE_URL/chat/completions" -d '{"model": "...", "messages": [...]}'
+type Client interface {
+	// Model 使用的模型名
+	Model() string
+	// Chat 发送对话并返回模型回复
+	Chat(ctx context.Context, req Request) (Response, error)
+}
+
+// New 按配置创建客户端，未配置时返回 nil
+func New(cfg config.LLMConfig) Client {
+	switch cfg.Provider {
+	case config.LLMProviderOpenAI:
+		return NewOpenAI(cfg)
+	case config.LLMProviderStub:
+		return NewStub()
+	default:
+		return nil
+	}
+}
+
+// EstimateTokens 粗略估算文本的 token 数：ASCII 约 4 个字符一个 token，中文等非 ASCII 字符约一个字符一个 token
+// 只用于预算控制和截断，不同模型的分词结果会有差异
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		i += size
+	}
+	return (ascii+3)/4 + other
+}
+
+// EstimateMessages 估算一组消息的 token 数，每条消息额外计入角色等格式开销
+func EstimateMessages(messages []Message) int {
+	total := 0
+	for _, m := range messages {
+		total += EstimateTokens(m.Content) + 4
+		for _, call := range m.ToolCalls {
+			total += EstimateTokens(call.Name) + EstimateTokens(call.Arguments) + 4
+		}
+	}
+	return total
+}
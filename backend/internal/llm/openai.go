@@ -0,0 +1,228 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// maxResponseBytes 单次回复的大小上限
+const maxResponseBytes = 8 << 20
+
+// OpenAI 兼容 OpenAI Chat Completions 接口的客户端
+type OpenAI struct {
+	cfg    config.LLMConfig
+	client *http.Client
+}
+
+// NewOpenAI 创建 OpenAI 兼容客户端
+func NewOpenAI(cfg config.LLMConfig) *OpenAI {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // 由配置显式开启
+	}
+	return &OpenAI{
+		cfg:    cfg,
+		client: &http.Client{Transport: tracing.WrapClientTransport("llm", transport)},
+	}
+}
+
+// Model 使用的模型名
+func (o *OpenAI) Model() string {
+	return o.cfg.Model
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []wireMessage   `json:"messages"`
+	Tools          []wireTool      `json:"tools,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	Temperature    float64         `json:"temperature"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+// wireMessage Chat Completions 接口的消息格式
+type wireMessage struct {
+	Role       string         `json:"role"`
+	Content    *string        `json:"content"` // 只有工具调用的 assistant 消息 content 为 null
+	ToolCalls  []wireToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type wireToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type wireTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description"`
+		Parameters  map[string]interface{} `json:"parameters"`
+	} `json:"function"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      wireMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Chat 调用 POST {baseURL}/chat/completions
+func (o *OpenAI) Chat(ctx context.Context, req Request) (Response, error) {
+	body := chatRequest{
+		Model:       o.cfg.Model,
+		Messages:    toWireMessages(req.Messages),
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}
+	for _, t := range req.Tools {
+		var wt wireTool
+		wt.Type = "function"
+		wt.Function.Name = t.Name
+		wt.Function.Description = t.Description
+		wt.Function.Parameters = t.Parameters
+		body.Tools = append(body.Tools, wt)
+	}
+	if req.JSON && o.cfg.JSONMode {
+		body.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.cfg.Timeout)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(o.cfg.BaseURL, "/")+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to build chat request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.cfg.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+o.cfg.APIKey)
+	}
+
+	resp, err := o.client.Do(httpReq)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return Response{}, response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "大模型响应超时", err)
+		}
+		return Response{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "大模型服务不可达", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return Response{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "读取大模型响应失败", err)
+	}
+
+	var out chatResponse
+	decodeErr := json.Unmarshal(data, &out)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail := fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(data), 512))
+		if decodeErr == nil && out.Error != nil && out.Error.Message != "" {
+			detail = fmt.Errorf("HTTP %d: %s", resp.StatusCode, out.Error.Message)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return Response{}, response.NewError(http.StatusTooManyRequests, response.CodeTooManyRequests, "大模型服务限流，请稍后重试", detail)
+		}
+		return Response{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "大模型调用失败", detail)
+	}
+	if decodeErr != nil {
+		return Response{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "大模型响应格式错误", decodeErr)
+	}
+	if len(out.Choices) == 0 {
+		return Response{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "大模型未返回结果", nil)
+	}
+
+	msg := out.Choices[0].Message
+	result := Response{
+		Model:        out.Model,
+		FinishReason: out.Choices[0].FinishReason,
+	}
+	if msg.Content != nil {
+		result.Content = *msg.Content
+	}
+	for _, call := range msg.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+	if result.Model == "" {
+		result.Model = o.cfg.Model
+	}
+	if out.Usage != nil {
+		result.Usage = Usage{
+			PromptTokens:     out.Usage.PromptTokens,
+			CompletionTokens: out.Usage.CompletionTokens,
+			TotalTokens:      out.Usage.TotalTokens,
+		}
+	} else {
+		result.Usage = estimateUsage(req.Messages, result.Content)
+	}
+	return result, nil
+}
+
+func toWireMessages(messages []Message) []wireMessage {
+	out := make([]wireMessage, 0, len(messages))
+	for _, m := range messages {
+		wm := wireMessage{Role: m.Role, ToolCallID: m.ToolCallID}
+		if m.Content != "" || len(m.ToolCalls) == 0 {
+			content := m.Content
+			wm.Content = &content
+		}
+		for _, call := range m.ToolCalls {
+			var wc wireToolCall
+			wc.ID = call.ID
+			wc.Type = "function"
+			wc.Function.Name = call.Name
+			wc.Function.Arguments = call.Arguments
+			wm.ToolCalls = append(wm.ToolCalls, wc)
+		}
+		out = append(out, wm)
+	}
+	return out
+}
+
+// estimateUsage 服务端未返回用量时（部分本地模型服务）按估算值计入预算
+func estimateUsage(messages []Message, content string) Usage {
+	prompt := EstimateMessages(messages)
+	completion := EstimateTokens(content)
+	return Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,185 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StubModel stub 客户端的模型名
+const StubModel = "stub"
+
+// Stub 不调用任何模型的本地客户端，按关键字返回固定格式的结果，用于开发、演示和无外网环境
+type Stub struct{}
+
+// NewStub 创建 stub 客户端
+func NewStub() *Stub {
+	return &Stub{}
+}
+
+// Model 使用的模型名
+func (s *Stub) Model() string {
+	return StubModel
+}
+
+// stubRule 根据上下文中出现的关键字给出的结论
+type stubRule struct {
+	keyword   string
+	rootCause string
+	severity  string
+	fix       string
+	command   string
+}
+
+var stubRules = []stubRule{
+	{"OOMKilled", "容器内存超过 limits 被 OOMKilled", "critical",
+		"提高内存 limits，或排查应用内存泄漏", "kubectl top pod <pod> -n <namespace> --containers"},
+	{"ImagePullBackOff", "镜像拉取失败，镜像名、标签或仓库凭据可能有误", "critical",
+		"确认镜像存在并检查 imagePullSecrets", "kubectl describe pod <pod> -n <namespace>"},
+	{"ErrImagePull", "镜像拉取失败，镜像名、标签或仓库凭据可能有误", "critical",
+		"确认镜像存在并检查 imagePullSecrets", "kubectl describe pod <pod> -n <namespace>"},
+	{"CreateContainerConfigError", "容器配置错误，通常是引用的 ConfigMap、Secret 或其中的键不存在", "critical",
+		"检查 env、envFrom 和 volumes 引用的 ConfigMap、Secret", "kubectl describe pod <pod> -n <namespace>"},
+	{"FailedScheduling", "Pod 无法调度，节点资源不足或亲和性、污点不匹配", "warning",
+		"降低 requests，或调整 nodeSelector、亲和性和容忍", "kubectl describe pod <pod> -n <namespace>"},
+	{"Unhealthy", "探针检查失败，容器被重启或从 Service 中摘除", "warning",
+		"检查探针路径、端口和 initialDelaySeconds 是否与应用启动时间匹配", "kubectl describe pod <pod> -n <namespace>"},
+	{"CrashLoopBackOff", "容器启动后反复退出", "critical",
+		"查看上一次容器退出前的日志定位启动失败原因", "kubectl logs <pod> -n <namespace> --previous"},
+}
+
+// Chat 在最后一条用户消息中查找已知的故障关键字；带工具的请求按关键字选择工具
+func (s *Stub) Chat(_ context.Context, req Request) (Response, error) {
+	if len(req.Tools) > 0 {
+		return s.chatWithTools(req), nil
+	}
+	var prompt string
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == RoleUser {
+			prompt = req.Messages[i].Content
+			break
+		}
+	}
+
+	type fix struct {
+		Title    string   `json:"title"`
+		Commands []string `json:"commands"`
+	}
+	result := struct {
+		Summary    string   `json:"summary"`
+		RootCause  string   `json:"rootCause"`
+		Severity   string   `json:"severity"`
+		Confidence string   `json:"confidence"`
+		Evidence   []string `json:"evidence"`
+		Fixes      []fix    `json:"fixes"`
+	}{
+		Summary:    "未发现已知的故障特征（stub 模型只做关键字匹配）",
+		RootCause:  "未知",
+		Severity:   "info",
+		Confidence: "low",
+		Evidence:   []string{},
+		Fixes:      []fix{},
+	}
+	for _, rule := range stubRules {
+		if !strings.Contains(prompt, rule.keyword) {
+			continue
+		}
+		if len(result.Evidence) == 0 {
+			result.Summary = rule.rootCause
+			result.RootCause = rule.rootCause
+			result.Severity = rule.severity
+			result.Confidence = "medium"
+		}
+		result.Evidence = append(result.Evidence, "上下文中出现 "+rule.keyword)
+		result.Fixes = append(result.Fixes, fix{Title: rule.fix, Commands: []string{rule.command}})
+	}
+
+	content, err := json.Marshal(result)
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{
+		Content:      string(content),
+		Model:        StubModel,
+		FinishReason: "stop",
+		Usage:        estimateUsage(req.Messages, string(content)),
+	}, nil
+}
+
+// stubToolKeywords 问题中的关键字对应的工具，按顺序匹配，都不匹配时使用 list_pods
+var stubToolKeywords = []struct {
+	tool     string
+	keywords []string
+}{
+	{"get_events", []string{"事件", "event"}},
+	{"top_pods", []string{"cpu", "内存", "memory", "用量", "usage"}},
+	{"list_pods", []string{"重启", "restart"}},
+	{"list_deployments", []string{"deployment", "部署"}},
+	{"list_namespaces", []string{"命名空间有哪些", "list namespaces", "namespaces"}},
+}
+
+func stubPickTool(question string, available map[string]bool) string {
+	for _, candidate := range stubToolKeywords {
+		if !available[candidate.tool] {
+			continue
+		}
+		for _, kw := range candidate.keywords {
+			if strings.Contains(question, kw) {
+				return candidate.tool
+			}
+		}
+	}
+	return "list_pods"
+}
+
+var stubNamespacePattern = regexp.MustCompile(`(?i)(?:\bin\s+|namespace\s*|命名空间\s*)([a-z0-9][a-z0-9-]*)`)
+
+// chatWithTools 第一轮按关键字调用一个工具，拿到工具结果后汇总条数
+func (s *Stub) chatWithTools(req Request) Response {
+	last := req.Messages[len(req.Messages)-1]
+	resp := Response{Model: StubModel, FinishReason: "stop"}
+
+	if last.Role == RoleTool {
+		var b strings.Builder
+		b.WriteString("stub 模型不做推理，工具调用结果如下：")
+		for i := len(req.Messages) - 1; i >= 0 && req.Messages[i].Role == RoleTool; i-- {
+			var result struct {
+				Count int    `json:"count"`
+				Error string `json:"error"`
+			}
+			_ = json.Unmarshal([]byte(req.Messages[i].Content), &result)
+			if result.Error != "" {
+				fmt.Fprintf(&b, "\n- %s：%s", req.Messages[i].ToolCallID, result.Error)
+			} else {
+				fmt.Fprintf(&b, "\n- %s：%d 条结果", req.Messages[i].ToolCallID, result.Count)
+			}
+		}
+		resp.Content = b.String()
+		resp.Usage = estimateUsage(req.Messages, resp.Content)
+		return resp
+	}
+
+	question := strings.ToLower(last.Content)
+	available := make(map[string]bool, len(req.Tools))
+	for _, t := range req.Tools {
+		available[t.Name] = true
+	}
+	tool := stubPickTool(question, available)
+	if !available[tool] {
+		resp.Content = "stub 模型没有可用的工具"
+		resp.Usage = estimateUsage(req.Messages, resp.Content)
+		return resp
+	}
+
+	args := map[string]string{}
+	if m := stubNamespacePattern.FindStringSubmatch(last.Content); m != nil && tool != "list_namespaces" {
+		args["namespace"] = m[1]
+	}
+	data, _ := json.Marshal(args)
+	resp.FinishReason = "tool_calls"
+	resp.ToolCalls = []ToolCall{{ID: "call_" + tool, Name: tool, Arguments: string(data)}}
+	resp.Usage = estimateUsage(req.Messages, string(data))
+	return resp
+}
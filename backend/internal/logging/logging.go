@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type loggerKey struct{}
+
+// WithLogger 将请求级 logger 放入 context
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 取出请求级 logger（已带 request_id、trace_id 等字段）
+// context 中没有时返回全局 logger，因此在后台任务中调用也是安全的
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return zap.L()
+}
@@ -0,0 +1,190 @@
+package logsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// Elasticsearch 基于 _search 接口的 Elasticsearch/OpenSearch 日志后端
+// 类比Shell: curl -s "$ES/logstash-*/_search" -H 'Content-Type: application/json' -d @query.json
+type Elasticsearch struct {
+	cfg     config.ElasticsearchConfig
+	timeout time.Duration
+	http    *http.Client
+}
+
+// NewElasticsearch 创建 Elasticsearch 后端
+func NewElasticsearch(cfg config.ElasticsearchConfig, timeout time.Duration) *Elasticsearch {
+	return &Elasticsearch{
+		cfg:     cfg,
+		timeout: timeout,
+		http:    newHTTPClient("elasticsearch", cfg.TLSSkipVerify),
+	}
+}
+
+// Name 后端名称
+func (e *Elasticsearch) Name() string {
+	return config.LogsBackendElasticsearch
+}
+
+// esResponse _search 的响应格式，只解析需要的字段
+type esResponse struct {
+	Hits struct {
+		Hits []struct {
+			Source map[string]interface{} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// Search 调用 <index>/_search，通用过滤条件作为 bool filter，原生查询作为 query_string
+func (e *Elasticsearch) Search(ctx context.Context, q Query) ([]Entry, error) {
+	body, err := json.Marshal(e.searchBody(q))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode search request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	endpoint := fmt.Sprintf("%s/%s/_search?ignore_unavailable=true&allow_no_indices=true",
+		strings.TrimRight(e.cfg.URL, "/"), url.PathEscape(e.cfg.Index))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case e.cfg.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+e.cfg.APIKey)
+	case e.cfg.Username != "":
+		req.SetBasicAuth(e.cfg.Username, e.cfg.Password)
+	}
+
+	data, err := do(e.http, req, "Elasticsearch", "Elasticsearch 查询语句错误")
+	if err != nil {
+		return nil, err
+	}
+	var resp esResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "Elasticsearch 返回了无法解析的响应", err)
+	}
+
+	fields := e.cfg.Fields
+	entries := make([]Entry, 0, len(resp.Hits.Hits))
+	for _, hit := range resp.Hits.Hits {
+		ts, ok := parseTimestamp(lookup(hit.Source, fields.Timestamp))
+		if !ok {
+			continue
+		}
+		entries = append(entries, Entry{
+			Timestamp: ts,
+			Namespace: stringField(hit.Source, fields.Namespace),
+			Pod:       stringField(hit.Source, fields.Pod),
+			Container: stringField(hit.Source, fields.Container),
+			Line:      strings.TrimRight(stringField(hit.Source, fields.Message), "\n"),
+		})
+	}
+	return entries, nil
+}
+
+// searchBody 构造查询 DSL
+// Pod 元数据使用 match_phrase，对 text 和 keyword 类型的字段都适用，不依赖索引映射
+func (e *Elasticsearch) searchBody(q Query) map[string]interface{} {
+	fields := e.cfg.Fields
+	filters := []interface{}{
+		map[string]interface{}{"range": map[string]interface{}{
+			fields.Timestamp: map[string]interface{}{
+				"gte":    q.Start.UTC().Format(time.RFC3339Nano),
+				"lte":    q.End.UTC().Format(time.RFC3339Nano),
+				"format": "strict_date_optional_time_nanos",
+			},
+		}},
+	}
+	for _, f := range []struct{ field, value string }{
+		{fields.Namespace, q.Namespace},
+		{fields.Pod, q.Pod},
+		{fields.Container, q.Container},
+		{fields.Message, q.Contains},
+	} {
+		if f.value != "" {
+			filters = append(filters, map[string]interface{}{"match_phrase": map[string]interface{}{f.field: f.value}})
+		}
+	}
+	boolQuery := map[string]interface{}{"filter": filters}
+	if q.Query != "" {
+		boolQuery["must"] = []interface{}{map[string]interface{}{"query_string": map[string]interface{}{
+			"query":         q.Query,
+			"default_field": fields.Message,
+		}}}
+	}
+
+	order := "desc"
+	if q.Direction == Forward {
+		order = "asc"
+	}
+	return map[string]interface{}{
+		"size":    q.Limit,
+		"query":   map[string]interface{}{"bool": boolQuery},
+		"sort":    []interface{}{map[string]interface{}{fields.Timestamp: map[string]interface{}{"order": order}}},
+		"_source": []string{fields.Timestamp, fields.Message, fields.Namespace, fields.Pod, fields.Container},
+	}
+}
+
+// lookup 按点分路径读取字段，同时兼容嵌套对象（kubernetes: {pod_name: ...}）和扁平键（"kubernetes.pod_name": ...）
+func lookup(source map[string]interface{}, path string) interface{} {
+	if path == "" {
+		return nil
+	}
+	if v, ok := source[path]; ok {
+		return v
+	}
+	head, rest, found := strings.Cut(path, ".")
+	for found {
+		if nested, ok := source[head].(map[string]interface{}); ok {
+			if v := lookup(nested, rest); v != nil {
+				return v
+			}
+		}
+		var next string
+		next, rest, found = strings.Cut(rest, ".")
+		head = head + "." + next
+	}
+	return nil
+}
+
+func stringField(source map[string]interface{}, path string) string {
+	switch v := lookup(source, path).(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseTimestamp 解析 RFC3339 字符串或毫秒时间戳
+func parseTimestamp(v interface{}) (time.Time, bool) {
+	switch ts := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return t.UTC(), true
+		}
+		if ms, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			return time.UnixMilli(ms).UTC(), true
+		}
+	case float64:
+		return time.UnixMilli(int64(ts)).UTC(), true
+	}
+	return time.Time{}, false
+}
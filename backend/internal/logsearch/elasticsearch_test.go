@@ -0,0 +1,128 @@
+package logsearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+func TestElasticsearchSearch(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/logstash-*/_search" {
+			t.Errorf("request = %s %s, want POST /logstash-*/_search", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "ApiKey key" {
+			t.Errorf("Authorization = %q, want ApiKey key", got)
+		}
+		var body struct {
+			Size  int `json:"size"`
+			Query struct {
+				Bool struct {
+					Filter []map[string]map[string]interface{} `json:"filter"`
+					Must   []map[string]map[string]interface{} `json:"must"`
+				} `json:"bool"`
+			} `json:"query"`
+			Sort []map[string]map[string]string `json:"sort"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+			return
+		}
+		if body.Size != 100 {
+			t.Errorf("size = %d, want 100", body.Size)
+		}
+		if got := body.Sort[0]["@timestamp"]["order"]; got != "asc" {
+			t.Errorf("sort order = %q, want asc", got)
+		}
+		phrases := map[string]interface{}{}
+		for _, f := range body.Query.Bool.Filter {
+			for k, v := range f["match_phrase"] {
+				phrases[k] = v
+			}
+		}
+		wantPhrases := map[string]interface{}{
+			"kubernetes.namespace_name": "prod",
+			"kubernetes.pod_name":       "api-1",
+			"log":                       "timeout",
+		}
+		for k, want := range wantPhrases {
+			if phrases[k] != want {
+				t.Errorf("match_phrase %s = %v, want %v", k, phrases[k], want)
+			}
+		}
+		if len(body.Query.Bool.Must) != 1 || body.Query.Bool.Must[0]["query_string"]["query"] != "level:error" {
+			t.Errorf("must = %v, want query_string level:error", body.Query.Bool.Must)
+		}
+
+		// 嵌套对象、扁平键和毫秒时间戳都要能解析；缺少时间戳的文档被跳过
+		_, _ = w.Write([]byte(`{"hits":{"hits":[
+			{"_source":{"@timestamp":"2026-01-02T03:00:01.5Z","log":"first\n",
+			  "kubernetes":{"namespace_name":"prod","pod_name":"api-1","container_name":"app"}}},
+			{"_source":{"@timestamp":1767322802000,"log":"second",
+			  "kubernetes.namespace_name":"prod","kubernetes.pod_name":"api-1","kubernetes.container_name":"app"}},
+			{"_source":{"log":"no timestamp"}}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	cfg := config.Default().Logs.Elasticsearch
+	cfg.URL = srv.URL
+	cfg.APIKey = "key"
+	es := NewElasticsearch(cfg, 5*time.Second)
+
+	entries, err := es.Search(context.Background(), Query{
+		Namespace: "prod",
+		Pod:       "api-1",
+		Contains:  "timeout",
+		Query:     "level:error",
+		Start:     start,
+		End:       start.Add(time.Hour),
+		Limit:     100,
+		Direction: Forward,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := []Entry{
+		{Timestamp: start.Add(1500 * time.Millisecond), Namespace: "prod", Pod: "api-1", Container: "app", Line: "first"},
+		{Timestamp: start.Add(2 * time.Second), Namespace: "prod", Pod: "api-1", Container: "app", Line: "second"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	source := map[string]interface{}{
+		"kubernetes": map[string]interface{}{
+			"labels": map[string]interface{}{"app.kubernetes.io/name": "web"},
+		},
+		"kubernetes.pod_name": "web-0",
+	}
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "kubernetes.pod_name", want: "web-0"},
+		{path: "kubernetes.labels.app.kubernetes.io/name", want: "web"},
+		{path: "kubernetes.missing", want: nil},
+		{path: "", want: nil},
+	}
+	for _, tt := range tests {
+		if got := lookup(source, tt.path); got != tt.want {
+			t.Errorf("lookup(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package logsearch 可插拔的日志检索后端（Loki、Elasticsearch），结果统一为 Pod 维度的日志行
+package logsearch
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// maxResponseBytes 单次查询响应的大小上限
+const maxResponseBytes = 32 << 20
+
+// Direction 日志排序方向
+type Direction string
+
+const (
+	Backward Direction = "backward" // 从新到旧，默认
+	Forward  Direction = "forward"  // 从旧到新
+)
+
+// Query 日志查询条件
+// Namespace、Pod、Container、Contains 为通用过滤条件；Query 为后端原生查询（LogQL 或 Lucene query_string）
+type Query struct {
+	Namespace string
+	Pod       string
+	Container string
+	Contains  string // 日志内容包含的子串
+	Query     string
+	Start     time.Time
+	End       time.Time
+	Limit     int
+	Direction Direction
+}
+
+// Entry 一条日志，各后端的结果都转换为这个结构
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Line      string    `json:"line"`
+}
+
+// Backend 日志检索后端
+// 类比Shell: logcli query '{namespace="default"}' 或 curl "$ES/logstash-*/_search"
+type Backend interface {
+	// Name 后端名称：loki 或 elasticsearch
+	Name() string
+	// Search 查询 [Start, End] 内的日志，按 Direction 排序，最多 Limit 条
+	Search(ctx context.Context, q Query) ([]Entry, error)
+}
+
+// New 按配置创建日志后端，未配置时返回 nil
+func New(cfg config.LogsConfig) Backend {
+	switch cfg.Backend {
+	case config.LogsBackendLoki:
+		return NewLoki(cfg.Loki, cfg.Timeout)
+	case config.LogsBackendElasticsearch:
+		return NewElasticsearch(cfg.Elasticsearch, cfg.Timeout)
+	default:
+		return nil
+	}
+}
+
+// Tail 持续跟踪新日志，每隔 interval 查询一次上次之后的日志并按时间顺序回调 fn
+// 两种后端共用轮询实现：Elasticsearch 没有推送接口，Loki 的 WebSocket tail 需要穿透网关
+// 类比Shell: kubectl logs -f，或 logcli query --tail
+func Tail(ctx context.Context, backend Backend, q Query, interval time.Duration, fn func(Entry) error) error {
+	q.Direction = Forward
+	if q.Start.IsZero() {
+		q.Start = time.Now()
+	}
+	// 同一时间戳可能有多条日志，记录最后一个时间戳上已发送的日志避免重复
+	seen := make(map[Entry]bool)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		q.End = time.Now()
+		entries, err := backend.Search(ctx, q)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for _, e := range entries {
+			if seen[e] {
+				continue
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		if n := len(entries); n > 0 {
+			last := entries[n-1].Timestamp
+			seen = make(map[Entry]bool)
+			for _, e := range entries {
+				if e.Timestamp.Equal(last) {
+					seen[e] = true
+				}
+			}
+			q.Start = last
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sortEntries 按时间排序，Backward 时新的在前
+func sortEntries(entries []Entry, direction Direction) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if direction == Forward {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		}
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+}
+
+// newHTTPClient 创建带链路追踪的 HTTP 客户端
+func newHTTPClient(system string, skipVerify bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if skipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // 由配置显式开启
+	}
+	return &http.Client{Transport: tracing.WrapClientTransport(system, transport)}
+}
+
+// do 发送请求并读取响应体，网络错误和非 2xx 状态映射为类型化错误
+// badRequest 为后端返回 400 时的提示，通常是查询语法错误
+func do(client *http.Client, req *http.Request, system, badRequest string) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "日志查询超时", err)
+		}
+		return nil, response.NewError(http.StatusBadGateway, response.CodeBadGateway, fmt.Sprintf("%s 不可达", system), err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return nil, response.NewError(http.StatusBadGateway, response.CodeBadGateway, fmt.Sprintf("读取 %s 响应失败", system), err)
+	}
+	if len(body) > maxResponseBytes {
+		return nil, response.NewError(http.StatusUnprocessableEntity, response.CodeInvalid, "查询结果过大，请缩小时间范围或减少条数", nil)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail := fmt.Errorf("HTTP %d: %s", resp.StatusCode, truncate(string(body), 512))
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return nil, response.NewError(http.StatusBadRequest, response.CodeBadRequest, badRequest, detail)
+		case http.StatusGatewayTimeout:
+			return nil, response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "日志查询超时", detail)
+		default:
+			return nil, response.NewError(http.StatusBadGateway, response.CodeBadGateway, fmt.Sprintf("%s 查询失败", system), detail)
+		}
+	}
+	return body, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
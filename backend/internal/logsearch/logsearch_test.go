@@ -0,0 +1,91 @@
+package logsearch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBackend 依次返回预设的查询结果，并记录每次查询的起始时间
+type fakeBackend struct {
+	mu      sync.Mutex
+	results [][]Entry
+	starts  []time.Time
+}
+
+func (f *fakeBackend) Name() string { return "fake" }
+
+func (f *fakeBackend) Search(_ context.Context, q Query) ([]Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.starts = append(f.starts, q.Start)
+	if q.Direction != Forward {
+		return nil, errors.New("tail must query forward")
+	}
+	if len(f.results) == 0 {
+		return nil, nil
+	}
+	result := f.results[0]
+	f.results = f.results[1:]
+	return result, nil
+}
+
+func TestTailSkipsDuplicatesOnLastTimestamp(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	a := Entry{Timestamp: t0, Pod: "web-0", Line: "a"}
+	b := Entry{Timestamp: t0.Add(time.Second), Pod: "web-0", Line: "b"}
+	c := Entry{Timestamp: t0.Add(time.Second), Pod: "web-0", Line: "c"}
+	d := Entry{Timestamp: t0.Add(2 * time.Second), Pod: "web-0", Line: "d"}
+	backend := &fakeBackend{results: [][]Entry{
+		{a, b},
+		// 从 b 的时间戳开始查询，b 会再次返回
+		{b, c, d},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []string
+	err := Tail(ctx, backend, Query{Start: t0}, time.Millisecond, func(e Entry) error {
+		got = append(got, e.Line)
+		if len(got) == 4 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(got, want) {
+		t.Errorf("lines = %v, want %v", got, want)
+	}
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if len(backend.starts) < 2 || !backend.starts[1].Equal(b.Timestamp) {
+		t.Errorf("second query start = %v, want %v", backend.starts, b.Timestamp)
+	}
+}
+
+func TestTailStopsOnCallbackError(t *testing.T) {
+	backend := &fakeBackend{results: [][]Entry{{{Timestamp: time.Now(), Line: "x"}}}}
+	stop := errors.New("client gone")
+	err := Tail(context.Background(), backend, Query{}, time.Millisecond, func(Entry) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("Tail() error = %v, want %v", err, stop)
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	t0 := time.Now()
+	entries := []Entry{{Timestamp: t0.Add(time.Second), Line: "2"}, {Timestamp: t0, Line: "1"}, {Timestamp: t0.Add(2 * time.Second), Line: "3"}}
+	sortEntries(entries, Forward)
+	if got := []string{entries[0].Line, entries[1].Line, entries[2].Line}; !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Errorf("forward = %v", got)
+	}
+	sortEntries(entries, Backward)
+	if got := []string{entries[0].Line, entries[1].Line, entries[2].Line}; !slices.Equal(got, []string{"3", "2", "1"}) {
+		t.Errorf("backward = %v", got)
+	}
+}
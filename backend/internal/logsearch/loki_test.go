@@ -0,0 +1,148 @@
+package logsearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+func newTestLoki(url string) *Loki {
+	cfg := config.Default().Logs.Loki
+	cfg.URL = url
+	return NewLoki(cfg, 5*time.Second)
+}
+
+func TestLokiSearch(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/query_range" {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		checks := map[string]string{
+			"query":     `{namespace="default",pod="web-0"} |= "error"`,
+			"start":     "1767322800000000000",
+			"end":       "1767326400000000000",
+			"limit":     "2",
+			"direction": "backward",
+		}
+		for k, want := range checks {
+			if got := q.Get(k); got != want {
+				t.Errorf("param %s = %q, want %q", k, got, want)
+			}
+		}
+		if got := r.Header.Get("X-Scope-OrgID"); got != "team-a" {
+			t.Errorf("X-Scope-OrgID = %q, want team-a", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want Bearer secret", got)
+		}
+		// 两个日志流各自有序，合并后需要整体排序
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"streams","result":[
+			{"stream":{"namespace":"default","pod":"web-0","container":"app"},
+			 "values":[["1767322860000000000","error a1"],["1767322800000000000","error a0"]]},
+			{"stream":{"namespace":"default","pod":"web-0","container":"sidecar"},
+			 "values":[["1767322830000000000","error b0"]]}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	loki := newTestLoki(srv.URL)
+	loki.cfg.TenantID = "team-a"
+	loki.cfg.BearerToken = "secret"
+
+	entries, err := loki.Search(context.Background(), Query{
+		Namespace: "default",
+		Pod:       "web-0",
+		Contains:  "error",
+		Start:     start,
+		End:       end,
+		Limit:     2,
+		Direction: Backward,
+	})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := []Entry{
+		{Timestamp: start.Add(time.Minute), Namespace: "default", Pod: "web-0", Container: "app", Line: "error a1"},
+		{Timestamp: start.Add(30 * time.Second), Namespace: "default", Pod: "web-0", Container: "sidecar", Line: "error b0"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestLokiLogQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   Query
+		want    string
+		wantErr bool
+	}{
+		{name: "no filters selects every namespace", query: Query{}, want: `{namespace=~".+"}`},
+		{name: "pod filters", query: Query{Namespace: "prod", Pod: "api-1", Container: "app"}, want: `{namespace="prod",pod="api-1",container="app"}`},
+		{name: "quotes are escaped", query: Query{Namespace: "prod", Contains: `say "hi"`}, want: `{namespace="prod"} |= "say \"hi\""`},
+		{name: "native query", query: Query{Query: `{app="web"} |= "x"`}, want: `{app="web"} |= "x"`},
+		{name: "native query with contains", query: Query{Query: `{app="web"}`, Contains: "timeout"}, want: `{app="web"} |= "timeout"`},
+		{name: "native query cannot be combined with pod filters", query: Query{Query: `{app="web"}`, Namespace: "prod"}, wantErr: true},
+	}
+	loki := newTestLoki("http://loki")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loki.logQL(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("logQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("logQL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLokiSearchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantStatus int
+	}{
+		{name: "bad LogQL", status: http.StatusBadRequest, body: "parse error", wantStatus: http.StatusBadRequest},
+		{name: "metric query", status: http.StatusOK, body: `{"status":"success","data":{"resultType":"matrix","result":[]}}`, wantStatus: http.StatusBadRequest},
+		{name: "server error", status: http.StatusInternalServerError, body: "boom", wantStatus: http.StatusBadGateway},
+		{name: "gateway timeout", status: http.StatusGatewayTimeout, body: "", wantStatus: http.StatusGatewayTimeout},
+		{name: "invalid json", status: http.StatusOK, body: "<html>", wantStatus: http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := newTestLoki(srv.URL).Search(context.Background(), Query{Namespace: "default", Limit: 10, Direction: Backward})
+			var appErr *response.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("Search() error = %v, want *response.AppError", err)
+			}
+			if appErr.Status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", appErr.Status, tt.wantStatus)
+			}
+		})
+	}
+}
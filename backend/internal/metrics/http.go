@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequests = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubeops",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kubeops",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// HTTPMiddleware 记录每个请求的次数和耗时
+// 路由使用 gin 注册的模板（如 /api/v1/namespaces/:namespace/pods），避免路径参数导致标签基数爆炸
+func HTTPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
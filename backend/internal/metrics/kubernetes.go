@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	clientmetrics "k8s.io/client-go/tools/metrics"
+
+	"github.com/yansongwel/kubeops/backend/internal/kuberequest"
+)
+
+var (
+	kubeRequestLatency = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kubeops",
+		Subsystem: "kube_client",
+		Name:      "request_duration_seconds",
+		Help:      "Kubernetes API request latency by Kubernetes verb (get, list, watch, create, ...), resource and host.",
+		Buckets:   []float64{0.005, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"verb", "resource", "host"})
+
+	kubeRequestResults = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubeops",
+		Subsystem: "kube_client",
+		Name:      "requests_total",
+		Help:      "Kubernetes API requests by status code, Kubernetes verb (get, list, watch, create, ...), resource and host; code is <error> when no response was received.",
+	}, []string{"code", "verb", "resource", "host"})
+
+	kubeRequestRetries = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubeops",
+		Subsystem: "kube_client",
+		Name:      "request_retries_total",
+		Help:      "Kubernetes API request retries by HTTP method, status code and host.",
+	}, []string{"code", "method", "host"})
+)
+
+// RegisterKubernetesClientMetrics 将 client-go 的请求指标接入 Registry
+// client-go 全局只接受一次注册，重复调用无副作用
+// 请求结果由 WrapKubernetesTransport 统计：client-go 的 RequestResult 回调只提供 method 和 host，无法按资源区分
+func RegisterKubernetesClientMetrics() {
+	clientmetrics.Register(clientmetrics.RegisterOpts{
+		RequestLatency: latencyAdapter{},
+		RequestRetry:   retryAdapter{},
+	})
+}
+
+// WrapKubernetesTransport 按状态码、Kubernetes verb、资源和 host 统计每次 apiserver 往返，用于 rest.Config.Wrap
+func WrapKubernetesTransport(rt http.RoundTripper) http.RoundTripper {
+	return &resultTransport{next: rt}
+}
+
+type resultTransport struct {
+	next http.RoundTripper
+}
+
+func (t *resultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	code := "<error>"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	info := kuberequest.Parse(req.Method, req.URL)
+	kubeRequestResults.WithLabelValues(code, info.Verb, info.Resource, req.URL.Host).Inc()
+	return resp, err
+}
+
+// WrappedRoundTripper 供 client-go 的 utilnet 解包，找到底层 Transport
+func (t *resultTransport) WrappedRoundTripper() http.RoundTripper {
+	return t.next
+}
+
+type latencyAdapter struct{}
+
+// Observe client-go 传入的 verb 实际是 HTTP 方法，这里按 URL 换算为 Kubernetes verb（区分 get/list/watch）
+func (latencyAdapter) Observe(_ context.Context, method string, u url.URL, latency time.Duration) {
+	info := kuberequest.Parse(method, &u)
+	kubeRequestLatency.WithLabelValues(info.Verb, info.Resource, u.Host).Observe(latency.Seconds())
+}
+
+type retryAdapter struct{}
+
+func (retryAdapter) IncrementRetry(_ context.Context, code, method, host string) {
+	kubeRequestRetries.WithLabelValues(code, method, host).Inc()
+}
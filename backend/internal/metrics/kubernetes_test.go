@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestWrapKubernetesTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/namespaces/web/pods/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	host := srv.Listener.Addr().String()
+
+	client := &http.Client{Transport: WrapKubernetesTransport(http.DefaultTransport)}
+	for _, path := range []string{"/api/v1/namespaces/web/pods", "/api/v1/namespaces/web/pods/missing", "/api/v1/namespaces/web/pods?watch=true"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	// verb 按 Kubernetes 语义区分 list、get 和 watch，而不是 HTTP 方法
+	for _, tt := range []struct{ code, verb string }{{"200", "list"}, {"404", "get"}, {"200", "watch"}} {
+		if got := testutil.ToFloat64(kubeRequestResults.WithLabelValues(tt.code, tt.verb, "pods", host)); got != 1 {
+			t.Errorf("%s %s pods = %v, want 1", tt.code, tt.verb, got)
+		}
+	}
+
+	// 连接失败时 code 为 <error>
+	u := url.URL{Scheme: "http", Host: host, Path: "/api/v1/nodes"}
+	srv.Close()
+	if resp, err := client.Get(u.String()); err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("request to a closed server succeeded")
+	}
+	if got := testutil.ToFloat64(kubeRequestResults.WithLabelValues("<error>", "list", "nodes", host)); got != 1 {
+		t.Errorf("<error> list nodes = %v, want 1", got)
+	}
+}
+
+func TestLatencyAdapter(t *testing.T) {
+	// client-go 传入的是 HTTP 方法，按 URL 换算为 watch
+	u := url.URL{Scheme: "https", Host: "latency.example:6443", Path: "/apis/apps/v1/deployments", RawQuery: "watch=true"}
+	latencyAdapter{}.Observe(context.Background(), http.MethodGet, u, 0)
+	if got := testutil.CollectAndCount(kubeRequestLatency.MustCurryWith(prometheus.Labels{"verb": "watch", "resource": "deployments", "host": u.Host})); got != 1 {
+		t.Errorf("watch deployments series = %d, want 1", got)
+	}
+}
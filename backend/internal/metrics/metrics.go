@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Registry KubeOps 自身指标的注册表，由 /metrics 暴露
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+var (
+	// ConfigReloads 配置热加载次数，result 为 success、partial（有需要重启才能生效的修改）或 failure
+	ConfigReloads = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubeops",
+		Name:      "config_reloads_total",
+		Help:      "Total number of configuration reload attempts by result.",
+	}, []string{"result"})
+
+	// ConfigLastReloadSuccess 最近一次成功热加载的时间戳
+	ConfigLastReloadSuccess = promauto.With(Registry).NewGauge(prometheus.GaugeOpts{
+		Namespace: "kubeops",
+		Name:      "config_last_reload_success_timestamp_seconds",
+		Help:      "Timestamp of the last successful configuration reload.",
+	})
+
+	// InformerSynced informer 缓存同步状态，1 表示已完成首次同步
+	// 由启动 informer 的模块通过 SetInformerSynced 上报
+	InformerSynced = promauto.With(Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kubeops",
+		Name:      "informer_synced",
+		Help:      "Whether the informer cache for a cluster resource has completed its initial sync (1) or not (0).",
+	}, []string{"cluster", "resource"})
+
+	// DependencyUp 外部依赖连接状态，1 表示可用
+	DependencyUp = promauto.With(Registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kubeops",
+		Name:      "dependency_up",
+		Help:      "Whether an external dependency (postgres, redis, cluster) is currently reachable (1) or not (0).",
+	}, []string{"dependency"})
+
+	// RateLimited 被限流拒绝的请求数
+	RateLimited = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubeops",
+		Name:      "ratelimit_rejected_total",
+		Help:      "Total number of requests rejected by the rate limiter by route group.",
+	}, []string{"group"})
+
+	// Notifications 通知发送结果，result 为 sent、failed、deduplicated、rate_limited 或 dropped
+	Notifications = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kubeops",
+		Name:      "notifications_total",
+		Help:      "Total number of notifications by channel type and result.",
+	}, []string{"channel_type", "result"})
+
+	// LeaderStatus 本副本是否为后台任务主节点
+	LeaderStatus = promauto.With(Registry).NewGauge(prometheus.GaugeOpts{
+		Namespace: "kubeops",
+		Name:      "leader_is_leader",
+		Help:      "Whether this replica currently holds background worker leadership (1) or not (0).",
+	})
+
+	// LeaderTransitions 本副本获得或失去主节点身份的次数
+	LeaderTransitions = promauto.With(Registry).NewCounter(prometheus.CounterOpts{
+		Namespace: "kubeops",
+		Name:      "leader_transitions_total",
+		Help:      "Total number of times this replica gained or lost leadership.",
+	})
+)
+
+// SetInformerSynced 上报某个集群资源 informer 的同步状态
+func SetInformerSynced(cluster, resource string, synced bool) {
+	value := 0.0
+	if synced {
+		value = 1
+	}
+	InformerSynced.WithLabelValues(cluster, resource).Set(value)
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// postgresCollector 在每次抓取时读取 pgxpool 连接池统计
+type postgresCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	totalConns       *prometheus.Desc
+	maxConns         *prometheus.Desc
+	acquireCount     *prometheus.Desc
+	acquireDuration  *prometheus.Desc
+	emptyAcquire     *prometheus.Desc
+	canceledAcquire  *prometheus.Desc
+	newConns         *prometheus.Desc
+	maxLifetimeClose *prometheus.Desc
+	maxIdleClose     *prometheus.Desc
+}
+
+// NewPostgresCollector 创建 pgxpool 连接池指标采集器
+func NewPostgresCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("kubeops_postgres_pool_"+name, help, nil, nil)
+	}
+	return &postgresCollector{
+		pool:             pool,
+		acquiredConns:    desc("acquired_conns", "Number of currently acquired connections."),
+		idleConns:        desc("idle_conns", "Number of currently idle connections."),
+		totalConns:       desc("total_conns", "Total number of connections in the pool."),
+		maxConns:         desc("max_conns", "Maximum size of the pool."),
+		acquireCount:     desc("acquires_total", "Cumulative count of successful acquires."),
+		acquireDuration:  desc("acquire_duration_seconds_total", "Total time spent waiting for successful acquires."),
+		emptyAcquire:     desc("empty_acquires_total", "Cumulative count of acquires that had to wait for a connection."),
+		canceledAcquire:  desc("canceled_acquires_total", "Cumulative count of acquires canceled by a context."),
+		newConns:         desc("new_conns_total", "Cumulative count of new connections opened."),
+		maxLifetimeClose: desc("max_lifetime_destroys_total", "Cumulative count of connections closed due to MaxConnLifetime."),
+		maxIdleClose:     desc("max_idle_destroys_total", "Cumulative count of connections closed due to MaxConnIdleTime."),
+	}
+}
+
+func (c *postgresCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *postgresCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+	gauge(c.acquiredConns, float64(stat.AcquiredConns()))
+	gauge(c.idleConns, float64(stat.IdleConns()))
+	gauge(c.totalConns, float64(stat.TotalConns()))
+	gauge(c.maxConns, float64(stat.MaxConns()))
+	counter(c.acquireCount, float64(stat.AcquireCount()))
+	counter(c.acquireDuration, stat.AcquireDuration().Seconds())
+	counter(c.emptyAcquire, float64(stat.EmptyAcquireCount()))
+	counter(c.canceledAcquire, float64(stat.CanceledAcquireCount()))
+	counter(c.newConns, float64(stat.NewConnsCount()))
+	counter(c.maxLifetimeClose, float64(stat.MaxLifetimeDestroyCount()))
+	counter(c.maxIdleClose, float64(stat.MaxIdleDestroyCount()))
+}
+
+// redisCollector 在每次抓取时读取 go-redis 连接池统计
+type redisCollector struct {
+	client *redis.Client
+
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+// NewRedisCollector 创建 Redis 连接池指标采集器
+func NewRedisCollector(client *redis.Client) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("kubeops_redis_pool_"+name, help, nil, nil)
+	}
+	return &redisCollector{
+		client:     client,
+		hits:       desc("hits_total", "Number of times a free connection was found in the pool."),
+		misses:     desc("misses_total", "Number of times a free connection was not found in the pool."),
+		timeouts:   desc("timeouts_total", "Number of times a wait for a connection timed out."),
+		totalConns: desc("total_conns", "Number of total connections in the pool."),
+		idleConns:  desc("idle_conns", "Number of idle connections in the pool."),
+		staleConns: desc("stale_conns_total", "Number of stale connections removed from the pool."),
+	}
+}
+
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
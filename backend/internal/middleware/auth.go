@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// Auth 解析用户身份放入 context，并在请求级 logger 上附加 user 字段
+// 启用认证且无法识别身份时返回 401
+func Auth(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := authenticator.Authenticate(c.Request)
+		if err != nil {
+			response.Error(c, response.NewError(http.StatusUnauthorized, response.CodeUnauthorized, "未认证或凭证已失效", err))
+			return
+		}
+
+		ctx := auth.WithUser(c.Request.Context(), user)
+		ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With(zap.String("user", user.DisplayName())))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/cache"
+)
+
+// CacheControl 为请求创建缓存记录器，并在响应头写出前附加 X-Cache: HIT/MISS/BYPASS
+// 请求头 Cache-Control: no-cache（或 Pragma: no-cache）时跳过缓存读取，结果仍会写回缓存
+func CacheControl() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bypass := strings.Contains(strings.ToLower(c.GetHeader("Cache-Control")), "no-cache") ||
+			strings.EqualFold(c.GetHeader("Pragma"), "no-cache")
+
+		ctx, rec := cache.WithRecorder(c.Request.Context(), bypass)
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &cacheHeaderWriter{ResponseWriter: c.Writer, recorder: rec}
+		c.Next()
+	}
+}
+
+// cacheHeaderWriter 在响应头真正写出前设置 X-Cache，handler 无需关心缓存
+type cacheHeaderWriter struct {
+	gin.ResponseWriter
+	recorder *cache.Recorder
+}
+
+func (w *cacheHeaderWriter) setHeader() {
+	if w.Written() {
+		return
+	}
+	if status := w.recorder.Status(); status != "" {
+		w.Header().Set(cache.Header, status)
+	}
+}
+
+func (w *cacheHeaderWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheHeaderWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheHeaderWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
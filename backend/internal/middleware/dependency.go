@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// RequireDependency 依赖不可用时直接返回 503 和对应的业务错误码，用于整组依赖 Postgres/Redis 的路由
+func RequireDependency(deps ...*client.Dependency) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, dep := range deps {
+			if err := dep.Err(); err != nil {
+				response.Error(c, err)
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+)
+
+// quietPaths 探针和指标抓取请求只在 debug 级别记录，避免淹没业务日志
+var quietPaths = map[string]bool{
+	"/livez":    true,
+	"/readyz":   true,
+	"/startupz": true,
+	"/health":   true,
+	"/metrics":  true,
+}
+
+// Logger 为每个请求创建带 request_id、trace_id 的 logger 放入 context，并在请求结束时输出 JSON 访问日志
+// 需要注册在 RequestID 和 tracing 中间件之后
+func Logger(base *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		fields := append([]zap.Field{zap.String("request_id", GetRequestID(c))}, tracing.ZapFields(c.Request.Context())...)
+		reqLogger := base.With(fields...)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), reqLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := zapcore.InfoLevel
+		switch {
+		case status >= 500:
+			level = zapcore.ErrorLevel
+		case status >= 400:
+			level = zapcore.WarnLevel
+		case quietPaths[c.Request.URL.Path]:
+			level = zapcore.DebugLevel
+		}
+
+		if ce := reqLogger.Check(level, "HTTP request"); ce != nil {
+			accessFields := []zap.Field{
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("route", c.FullPath()),
+				zap.String("query", redactQuery(c.Request.URL)),
+				zap.Int("status", status),
+				zap.Duration("latency", time.Since(start)),
+				zap.String("client_ip", c.ClientIP()),
+				zap.String("user_agent", c.Request.UserAgent()),
+				zap.Int("bytes", c.Writer.Size()),
+			}
+			if len(c.Errors) > 0 {
+				accessFields = append(accessFields, zap.String("errors", c.Errors.String()))
+			}
+			ce.Write(accessFields...)
+		}
+	}
+}
+
+// redactQuery 隐藏查询参数中的 access_token，避免令牌写入访问日志
+func redactQuery(u *url.URL) string {
+	q := u.Query()
+	if !q.Has("access_token") {
+		return u.RawQuery
+	}
+	q.Set("access_token", "REDACTED")
+	return q.Encode()
+}
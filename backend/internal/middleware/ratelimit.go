@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+	"github.com/yansongwel/kubeops/backend/internal/ratelimit"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// RateLimit 按路由组和用户限流，超出配额返回 429 和 Retry-After
+// 需要注册在 Auth 之后，以便按用户而不是按 IP 计算配额
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		user := auth.UserFromContext(ctx)
+		if limiter.Exempt(user, c.ClientIP()) {
+			c.Next()
+			return
+		}
+
+		group := ratelimit.Group(c.Request.Method, c.FullPath())
+		identity := ratelimit.Identity(user, c.ClientIP())
+		result, err := limiter.Allow(ctx, group, identity)
+		if err != nil {
+			logging.FromContext(ctx).Warn("Rate limiter unavailable, allowing request", zap.Error(err))
+		}
+		if result.Limit > 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		}
+
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(group).Inc()
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			response.Error(c, response.NewError(http.StatusTooManyRequests, response.CodeTooManyRequests,
+				"请求过于频繁，请 "+strconv.Itoa(max(retryAfter, 1))+" 秒后重试", nil))
+			return
+		}
+		c.Next()
+	}
+}
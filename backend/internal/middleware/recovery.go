@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"net/http/httputil"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// Recovery 捕获 handler 中的 panic，通过请求级 logger 记录堆栈并返回 500
+// 客户端已断开（broken pipe）时只记录日志，不再写响应
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			logger := logging.FromContext(c.Request.Context())
+			request, _ := httputil.DumpRequest(c.Request, false)
+			fields := []zap.Field{
+				zap.Any("panic", rec),
+				zap.String("request", redactAuthorization(string(request))),
+				zap.Stack("stack"),
+			}
+
+			if isBrokenPipe(rec) {
+				logger.Warn("Connection broken while handling request", fields...)
+				c.Abort()
+				return
+			}
+
+			logger.Error("Panic recovered", fields...)
+			response.InternalServerError(c, "服务内部错误")
+		}()
+		c.Next()
+	}
+}
+
+func isBrokenPipe(rec interface{}) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if errors.As(opErr, &syscallErr) {
+		return errors.Is(syscallErr.Err, syscall.EPIPE) || errors.Is(syscallErr.Err, syscall.ECONNRESET)
+	}
+	return false
+}
+
+// redactAuthorization 日志中隐藏 Authorization 请求头
+func redactAuthorization(dump string) string {
+	lines := strings.Split(dump, "\r\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.ToLower(line), "authorization:") {
+			lines[i] = "Authorization: ******"
+		}
+	}
+	return strings.Join(lines, "\r\n")
+}
@@ -0,0 +1,322 @@
+package migrate
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// advisoryLockKey 迁移使用的 Postgres advisory lock 键，保证多副本同时启动时只有一个实例执行迁移
+const advisoryLockKey int64 = 0x6b7562656f7073 // "kubeops"
+
+// undefinedTable Postgres 表不存在的错误码
+const undefinedTable = "42P01"
+
+const createVersionTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT      PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`
+
+// Migration 一个版本的迁移脚本
+// 文件命名约定：<版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status 某个迁移版本的应用状态
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Migrator 数据库迁移执行器
+// 类比Shell: for f in migrations/*.up.sql; do psql -f $f; done
+type Migrator struct {
+	pool       *pgxpool.Pool
+	logger     *zap.Logger
+	migrations []Migration
+}
+
+// New 创建迁移执行器，加载内嵌的 SQL 文件
+func New(pool *pgxpool.Pool, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		pool:       pool,
+		logger:     logger,
+		migrations: migrations,
+	}, nil
+}
+
+// Up 按版本顺序应用所有未执行的迁移，返回本次应用的数量
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			m.logger.Info("Applying migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+			if err := execInTx(ctx, conn, mig.Up, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.Version, mig.Name)
+				return err
+			}); err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 回滚最近应用的 steps 个迁移，返回实际回滚的数量
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		targets, err := rollbackTargets(m.migrations, done, steps)
+		if err != nil {
+			return err
+		}
+		for _, mig := range targets {
+			m.logger.Info("Rolling back migration", zap.Int64("version", mig.Version), zap.String("name", mig.Name))
+			if err := execInTx(ctx, conn, mig.Down, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("failed to roll back migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			rolledBack++
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// rollbackTargets 按版本从新到旧返回最近应用的 steps 个迁移
+// 任何一个没有 down 脚本时返回错误且不回滚任何迁移，避免只删除版本记录而表结构未回滚
+func rollbackTargets(migrations []Migration, done map[int64]time.Time, steps int) ([]Migration, error) {
+	var targets []Migration
+	for i := len(migrations) - 1; i >= 0 && len(targets) < steps; i-- {
+		mig := migrations[i]
+		if _, ok := done[mig.Version]; !ok {
+			continue
+		}
+		if strings.TrimSpace(mig.Down) == "" {
+			return nil, fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+		}
+		targets = append(targets, mig)
+	}
+	return targets, nil
+}
+
+// Status 返回所有内嵌迁移的应用状态；只读，不创建 schema_migrations，可用于就绪检查
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	done, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Version: mig.Version, Name: mig.Name}
+		if at, ok := done[mig.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = &at
+		}
+		result = append(result, st)
+	}
+	return result, nil
+}
+
+// Pending 返回尚未应用的迁移数量，用于就绪检查
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, st := range statuses {
+		if !st.Applied {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// withLock 在持有 advisory lock 的独占连接上执行 fn
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// 使用独立的 context，避免调用方 ctx 已取消导致锁未释放
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, advisoryLockKey); err != nil {
+			m.logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, createVersionTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// execInTx 在同一事务中执行迁移脚本和版本记录，script 为空时只记录版本（Down 已拒绝空的 down 脚本）
+func execInTx(ctx context.Context, conn *pgxpool.Conn, script string, record func(tx pgx.Tx) error) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if strings.TrimSpace(script) != "" {
+		if _, err := tx.Exec(ctx, script); err != nil {
+			return err
+		}
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// appliedVersions 读取已应用的迁移版本，schema_migrations 不存在时返回空集合
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	done := make(map[int64]time.Time)
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err == nil {
+		for rows.Next() {
+			var version int64
+			var at time.Time
+			if err := rows.Scan(&version, &at); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			done[version] = at
+		}
+		rows.Close()
+		err = rows.Err()
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == undefinedTable {
+		// 还没有执行过任何迁移
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	return done, nil
+}
+
+// loadMigrations 解析内嵌目录中的迁移文件并按版本排序
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", fileName)
+		}
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", fileName, err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join("migrations", fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: name}
+			byVersion[version] = mig
+		} else if mig.Name != name {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, mig.Name, name)
+		}
+		// 0001_x 和 1_x 解析为同一版本，不能互相覆盖
+		script := &mig.Up
+		if direction == "down" {
+			script = &mig.Down
+		}
+		if *script != "" {
+			return nil, fmt.Errorf("migration version %d has more than one %s script", version, direction)
+		}
+		*script = string(content)
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d has no up script", mig.Version)
+		}
+		result = append(result, *mig)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
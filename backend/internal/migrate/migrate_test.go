@@ -0,0 +1,107 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func migrationFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys["migrations/"+name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	return fsys
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFS(map[string]string{
+		"0010_add_index.up.sql":             "CREATE INDEX i ON t (a);",
+		"0002_create_table.up.sql":          "CREATE TABLE t (a INT);",
+		"0002_create_table.down.sql":        "DROP TABLE t;",
+		"0010_add_index.down.sql":           "DROP INDEX i;",
+		"0003_seed_with_underscores.up.sql": "INSERT INTO t VALUES (1);",
+		"README.md":                         "ignored",
+	}))
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	// 按版本数值排序，名称是第一个下划线之后的部分
+	want := []Migration{
+		{Version: 2, Name: "create_table", Up: "CREATE TABLE t (a INT);", Down: "DROP TABLE t;"},
+		{Version: 3, Name: "seed_with_underscores", Up: "INSERT INTO t VALUES (1);"},
+		{Version: 10, Name: "add_index", Up: "CREATE INDEX i ON t (a);", Down: "DROP INDEX i;"},
+	}
+	if len(migrations) != len(want) {
+		t.Fatalf("loadMigrations() = %d migrations, want %d", len(migrations), len(want))
+	}
+	for i := range want {
+		if migrations[i] != want[i] {
+			t.Errorf("migrations[%d] = %+v, want %+v", i, migrations[i], want[i])
+		}
+	}
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{name: "missing up script", files: map[string]string{"0001_a.down.sql": "DROP TABLE a;"}, wantErr: "no up script"},
+		{name: "duplicate version", files: map[string]string{"0001_a.up.sql": "SELECT 1;", "0001_b.up.sql": "SELECT 2;"}, wantErr: "conflicting names"},
+		{name: "same version with different padding", files: map[string]string{"0001_a.up.sql": "SELECT 1;", "1_a.up.sql": "SELECT 2;"}, wantErr: "more than one up script"},
+		{name: "missing name", files: map[string]string{"0001.up.sql": "SELECT 1;"}, wantErr: "invalid migration file name"},
+		{name: "invalid version", files: map[string]string{"v1_a.up.sql": "SELECT 1;"}, wantErr: "invalid migration version"},
+	}
+	for _, tt := range tests {
+		_, err := loadMigrations(migrationFS(tt.files))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: loadMigrations() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	for i, mig := range migrations {
+		if i > 0 && mig.Version <= migrations[i-1].Version {
+			t.Errorf("migration %d is not after %d", mig.Version, migrations[i-1].Version)
+		}
+		if strings.TrimSpace(mig.Down) == "" {
+			t.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+		}
+	}
+}
+
+func TestRollbackTargets(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "a", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"},
+		{Version: 2, Name: "b", Up: "CREATE TABLE b ();", Down: " \n"},
+		{Version: 3, Name: "c", Up: "CREATE TABLE c ();", Down: "DROP TABLE c;"},
+		{Version: 4, Name: "d", Up: "CREATE TABLE d ();", Down: "DROP TABLE d;"},
+	}
+	now := time.Now()
+
+	// 未应用的版本 4 跳过，从最近应用的版本开始回滚
+	targets, err := rollbackTargets(migrations, map[int64]time.Time{1: now, 2: now, 3: now}, 1)
+	if err != nil || len(targets) != 1 || targets[0].Version != 3 {
+		t.Errorf("rollbackTargets(1) = %+v, %v; want version 3", targets, err)
+	}
+
+	// 版本 2 没有 down 脚本：整批拒绝，版本 3 也不回滚
+	targets, err = rollbackTargets(migrations, map[int64]time.Time{1: now, 2: now, 3: now}, 2)
+	if err == nil || targets != nil {
+		t.Errorf("rollbackTargets(2) = %+v, %v; want an error for the blank down script", targets, err)
+	}
+
+	targets, err = rollbackTargets(migrations, map[int64]time.Time{1: now}, 5)
+	if err != nil || len(targets) != 1 || targets[0].Version != 1 {
+		t.Errorf("rollbackTargets(5) = %+v, %v; want version 1", targets, err)
+	}
+}
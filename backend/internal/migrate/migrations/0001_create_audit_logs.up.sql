@@ -0,0 +1,14 @@
+-- 审计日志：记录用户在平台上的操作
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          BIGSERIAL PRIMARY KEY,
+    username    TEXT        NOT NULL DEFAULT '',
+    action      TEXT        NOT NULL,
+    cluster     TEXT        NOT NULL DEFAULT '',
+    namespace   TEXT        NOT NULL DEFAULT '',
+    resource    TEXT        NOT NULL DEFAULT '',
+    detail      JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_username ON audit_logs (username);
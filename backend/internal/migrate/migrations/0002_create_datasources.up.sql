@@ -0,0 +1,21 @@
+-- 监控数据源：每个集群可配置多个 Prometheus 兼容数据源（Prometheus、VictoriaMetrics、Thanos 等）
+CREATE TABLE IF NOT EXISTS datasources (
+    id              BIGSERIAL PRIMARY KEY,
+    cluster         TEXT        NOT NULL,
+    name            TEXT        NOT NULL,
+    type            TEXT        NOT NULL DEFAULT 'prometheus',
+    url             TEXT        NOT NULL,
+    auth_type       TEXT        NOT NULL DEFAULT 'none',
+    username        TEXT        NOT NULL DEFAULT '',
+    secret          TEXT        NOT NULL DEFAULT '',
+    tls_skip_verify BOOLEAN     NOT NULL DEFAULT false,
+    timeout_seconds INTEGER     NOT NULL DEFAULT 30,
+    namespace_label TEXT        NOT NULL DEFAULT 'namespace',
+    is_default      BOOLEAN     NOT NULL DEFAULT false,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (cluster, name)
+);
+
+-- 每个集群最多一个默认数据源
+CREATE UNIQUE INDEX IF NOT EXISTS idx_datasources_cluster_default ON datasources (cluster) WHERE is_default;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS inspection_suppressions;
+DROP TABLE IF EXISTS inspection_schedules;
+DROP TABLE IF EXISTS inspection_findings;
+DROP TABLE IF EXISTS inspection_runs;
@@ -0,0 +1,80 @@
+-- 巡检记录：每次巡检（手动或定时）一条记录
+-- scope_key 为巡检范围（命名空间、检查项）的摘要，只有范围相同的相邻两次巡检才做差异对比
+CREATE TABLE IF NOT EXISTS inspection_runs (
+    id           BIGSERIAL PRIMARY KEY,
+    cluster      TEXT        NOT NULL,
+    trigger      TEXT        NOT NULL DEFAULT 'manual',
+    schedule_id  BIGINT,
+    scope_key    TEXT        NOT NULL,
+    request      JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    previous_id  BIGINT,
+    started_at   TIMESTAMPTZ NOT NULL,
+    duration_ms  BIGINT      NOT NULL DEFAULT 0,
+    summary      JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    objects      JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    errors       JSONB       NOT NULL DEFAULT '[]'::jsonb,
+    new_count    INTEGER     NOT NULL DEFAULT 0,
+    resolved     INTEGER     NOT NULL DEFAULT 0,
+    requested_by TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inspection_runs_cluster ON inspection_runs (cluster, id DESC);
+CREATE INDEX IF NOT EXISTS idx_inspection_runs_scope ON inspection_runs (cluster, scope_key, id DESC);
+CREATE INDEX IF NOT EXISTS idx_inspection_runs_created_at ON inspection_runs (created_at);
+
+-- 巡检发现的问题，fingerprint 为 检查项 + 资源 的摘要，用于跨次对比
+-- 已确认或屏蔽的问题同样保存，保证差异对比准确，查询时默认隐藏
+CREATE TABLE IF NOT EXISTS inspection_findings (
+    id            BIGSERIAL PRIMARY KEY,
+    run_id        BIGINT      NOT NULL REFERENCES inspection_runs (id) ON DELETE CASCADE,
+    fingerprint   TEXT        NOT NULL,
+    check_id      TEXT        NOT NULL,
+    severity      TEXT        NOT NULL,
+    kind          TEXT        NOT NULL,
+    namespace     TEXT        NOT NULL DEFAULT '',
+    name          TEXT        NOT NULL,
+    container     TEXT        NOT NULL DEFAULT '',
+    message       TEXT        NOT NULL,
+    remediation   TEXT        NOT NULL DEFAULT '',
+    is_new        BOOLEAN     NOT NULL DEFAULT false,
+    -- 保存时匹配到的确认或屏蔽规则，规则删除后保留原值
+    suppressed_by BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS idx_inspection_findings_run ON inspection_findings (run_id);
+
+-- 定时巡检：cron 表达式为标准 5 段格式或 @hourly 等描述符
+CREATE TABLE IF NOT EXISTS inspection_schedules (
+    id          BIGSERIAL PRIMARY KEY,
+    cluster     TEXT        NOT NULL,
+    name        TEXT        NOT NULL,
+    cron        TEXT        NOT NULL,
+    request     JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    enabled     BOOLEAN     NOT NULL DEFAULT true,
+    last_run_at TIMESTAMPTZ,
+    last_run_id BIGINT,
+    created_by  TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (cluster, name)
+);
+
+-- 确认（ack）和屏蔽（suppress）：
+-- ack 针对某个具体问题（fingerprint），suppress 按检查项和可选的命名空间、资源名匹配一类问题
+-- expires_at 为空表示永久有效
+CREATE TABLE IF NOT EXISTS inspection_suppressions (
+    id          BIGSERIAL PRIMARY KEY,
+    cluster     TEXT        NOT NULL,
+    type        TEXT        NOT NULL,
+    fingerprint TEXT        NOT NULL DEFAULT '',
+    check_id    TEXT        NOT NULL DEFAULT '',
+    namespace   TEXT        NOT NULL DEFAULT '',
+    name        TEXT        NOT NULL DEFAULT '',
+    reason      TEXT        NOT NULL DEFAULT '',
+    expires_at  TIMESTAMPTZ,
+    created_by  TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inspection_suppressions_cluster ON inspection_suppressions (cluster);
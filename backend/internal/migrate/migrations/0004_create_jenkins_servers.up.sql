@@ -0,0 +1,12 @@
+-- Jenkins 服务器：DevOps 页面通过 Jenkins REST API 浏览 Job、触发构建和查看控制台输出
+CREATE TABLE IF NOT EXISTS jenkins_servers (
+    id              BIGSERIAL PRIMARY KEY,
+    name            TEXT        NOT NULL UNIQUE,
+    url             TEXT        NOT NULL,
+    username        TEXT        NOT NULL DEFAULT '',
+    api_token       TEXT        NOT NULL DEFAULT '',
+    tls_skip_verify BOOLEAN     NOT NULL DEFAULT false,
+    timeout_seconds INTEGER     NOT NULL DEFAULT 30,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,13 @@
+-- ArgoCD 实例：通过 ArgoCD REST API 查看 Application 状态、同步、比较差异和回滚
+-- cluster 为 ArgoCD 所在的 KubeOps 集群，部署到 https://kubernetes.default.svc 的 Application 关联到该集群
+CREATE TABLE IF NOT EXISTS argocd_servers (
+    id              BIGSERIAL PRIMARY KEY,
+    name            TEXT        NOT NULL UNIQUE,
+    url             TEXT        NOT NULL,
+    api_token       TEXT        NOT NULL DEFAULT '',
+    cluster         TEXT        NOT NULL DEFAULT '',
+    tls_skip_verify BOOLEAN     NOT NULL DEFAULT false,
+    timeout_seconds INTEGER     NOT NULL DEFAULT 30,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,31 @@
+-- 通知渠道：webhook、slack、dingtalk、feishu 或 email
+-- config 为渠道类型对应的配置（地址、请求头、SMTP 服务器和收件人），secret 为加签密钥或 SMTP 密码，不通过 API 返回
+-- template 为空时使用内置模板
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL UNIQUE,
+    type       TEXT        NOT NULL,
+    config     JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    secret     TEXT        NOT NULL DEFAULT '',
+    template   TEXT        NOT NULL DEFAULT '',
+    enabled    BOOLEAN     NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- 路由规则：事件匹配规则的所有条件时发送到规则的渠道，条件为空表示不限制
+-- clusters、namespaces 支持 * 通配符
+CREATE TABLE IF NOT EXISTS notification_rules (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT        NOT NULL UNIQUE,
+    channel_id  BIGINT      NOT NULL REFERENCES notification_channels (id) ON DELETE CASCADE,
+    clusters    TEXT[]      NOT NULL DEFAULT '{}',
+    namespaces  TEXT[]      NOT NULL DEFAULT '{}',
+    severities  TEXT[]      NOT NULL DEFAULT '{}',
+    event_types TEXT[]      NOT NULL DEFAULT '{}',
+    enabled     BOOLEAN     NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_rules_channel ON notification_rules (channel_id);
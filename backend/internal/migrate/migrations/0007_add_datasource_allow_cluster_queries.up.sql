@@ -0,0 +1,2 @@
+-- 未指定命名空间的查询可以读取整个集群的指标，默认拒绝，由管理员按数据源显式开启
+ALTER TABLE datasources ADD COLUMN IF NOT EXISTS allow_cluster_queries BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,179 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// maxResponseBytes 单次查询响应的大小上限，避免大范围查询撑爆内存
+const maxResponseBytes = 32 << 20
+
+// ClientConfig Prometheus 兼容数据源的连接配置
+type ClientConfig struct {
+	URL           string
+	AuthType      string // none、basic 或 bearer
+	Username      string
+	Secret        string
+	TLSSkipVerify bool
+	Timeout       time.Duration // 单次查询的最长时间
+}
+
+// Client Prometheus HTTP API 客户端，兼容 VictoriaMetrics、Thanos 等实现
+// 类比Shell: curl -s "$PROM/api/v1/query" --data-urlencode "query=up"
+type Client struct {
+	cfg  ClientConfig
+	http *http.Client
+}
+
+// NewClient 创建数据源客户端
+func NewClient(cfg ClientConfig) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // 由数据源配置显式开启
+	}
+	return &Client{
+		cfg:  cfg,
+		http: &http.Client{Transport: tracing.WrapClientTransport("prometheus", transport)},
+	}
+}
+
+// CloseIdleConnections 关闭连接池中的空闲连接，客户端被替换时调用
+func (c *Client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
+
+// apiResponse Prometheus API 的响应格式
+type apiResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType"`
+	Error     string          `json:"error"`
+	Warnings  []string        `json:"warnings"`
+}
+
+// Result 查询结果，Data 为 Prometheus 返回的 data 字段原样透传
+type Result struct {
+	Data     json.RawMessage `json:"data"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+// Query 即时查询 /api/v1/query，ts 为空时使用当前时间
+func (c *Client) Query(ctx context.Context, query, ts string, timeout time.Duration) (Result, error) {
+	form := url.Values{"query": {query}}
+	setIfNotEmpty(form, "time", ts)
+	return c.do(ctx, "/api/v1/query", form, timeout)
+}
+
+// QueryRange 区间查询 /api/v1/query_range
+func (c *Client) QueryRange(ctx context.Context, query, start, end, step string, timeout time.Duration) (Result, error) {
+	form := url.Values{"query": {query}, "start": {start}, "end": {end}, "step": {step}}
+	return c.do(ctx, "/api/v1/query_range", form, timeout)
+}
+
+// Series 查询匹配的时间序列 /api/v1/series
+func (c *Client) Series(ctx context.Context, matches []string, start, end string, timeout time.Duration) (Result, error) {
+	form := url.Values{"match[]": matches}
+	setIfNotEmpty(form, "start", start)
+	setIfNotEmpty(form, "end", end)
+	return c.do(ctx, "/api/v1/series", form, timeout)
+}
+
+// Labels 查询标签名 /api/v1/labels；label 非空时查询该标签的取值 /api/v1/label/<label>/values
+func (c *Client) Labels(ctx context.Context, label string, matches []string, start, end string, timeout time.Duration) (Result, error) {
+	form := url.Values{}
+	if len(matches) > 0 {
+		form["match[]"] = matches
+	}
+	setIfNotEmpty(form, "start", start)
+	setIfNotEmpty(form, "end", end)
+	if label != "" {
+		return c.do(ctx, "/api/v1/label/"+url.PathEscape(label)+"/values", form, timeout)
+	}
+	return c.do(ctx, "/api/v1/labels", form, timeout)
+}
+
+// do 以 POST 表单发送请求（避免长查询超出 URL 长度限制），并把 Prometheus 的错误映射为类型化错误
+// timeout 为 0 或超过数据源上限时使用数据源上限；超时同时传给 Prometheus，让服务端及时放弃查询
+func (c *Client) do(ctx context.Context, path string, form url.Values, timeout time.Duration) (Result, error) {
+	if timeout <= 0 || timeout > c.cfg.Timeout {
+		timeout = c.cfg.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	form.Set("timeout", timeout.String())
+
+	endpoint := strings.TrimRight(c.cfg.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	switch c.cfg.AuthType {
+	case "basic":
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Secret)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Secret)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return Result{}, response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "监控查询超时", err)
+		}
+		return Result{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "监控数据源不可达", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return Result{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "读取监控数据源响应失败", err)
+	}
+	if len(body) > maxResponseBytes {
+		return Result{}, response.NewError(http.StatusUnprocessableEntity, response.CodeInvalid, "查询结果过大，请缩小时间范围或增大步长", nil)
+	}
+
+	var apiResp apiResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return Result{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway,
+			fmt.Sprintf("监控数据源返回了无法解析的响应（HTTP %d）", resp.StatusCode), err)
+	}
+	if apiResp.Status != "success" {
+		return Result{}, apiError(apiResp)
+	}
+	return Result{Data: apiResp.Data, Warnings: apiResp.Warnings}, nil
+}
+
+// apiError 按 Prometheus 的 errorType 映射 HTTP 状态码
+func apiError(resp apiResponse) error {
+	err := errors.New(resp.Error)
+	switch resp.ErrorType {
+	case "bad_data":
+		return response.NewError(http.StatusBadRequest, response.CodeBadRequest, "查询语句错误", err)
+	case "timeout", "canceled":
+		return response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "监控查询超时", err)
+	case "unavailable":
+		return response.NewError(http.StatusServiceUnavailable, response.CodeServiceUnavailable, "监控数据源不可用", err)
+	default:
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "监控查询失败", err)
+	}
+}
+
+func setIfNotEmpty(form url.Values, key, value string) {
+	if value != "" {
+		form.Set(key, value)
+	}
+}
@@ -0,0 +1,175 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// promRequest 数据源收到的一次请求
+type promRequest struct {
+	method string
+	path   string
+	auth   string
+	form   url.Values
+}
+
+// promStub 记录收到的请求，返回 reply 中按路径预设的响应
+type promStub struct {
+	mu       sync.Mutex
+	requests []promRequest
+}
+
+func (p *promStub) recorded() []promRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]promRequest(nil), p.requests...)
+}
+
+func stubPrometheus(t *testing.T, reply map[string]string) (*httptest.Server, *promStub) {
+	t.Helper()
+	stub := &promStub{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form: %v", err)
+			return
+		}
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, promRequest{
+			method: r.Method, path: r.URL.Path, auth: r.Header.Get("Authorization"), form: r.PostForm,
+		})
+		stub.mu.Unlock()
+		body, ok := reply[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"not_found","error":"unknown path"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, stub
+}
+
+func TestClientRequests(t *testing.T) {
+	srv, stub := stubPrometheus(t, map[string]string{
+		"/prom/api/v1/query":            `{"status":"success","data":{"resultType":"vector","result":[]},"warnings":["partial"]}`,
+		"/prom/api/v1/query_range":      `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+		"/prom/api/v1/series":           `{"status":"success","data":[]}`,
+		"/prom/api/v1/labels":           `{"status":"success","data":["job"]}`,
+		"/prom/api/v1/label/pod/values": `{"status":"success","data":["web-0"]}`,
+	})
+	c := NewClient(ClientConfig{URL: srv.URL + "/prom/", AuthType: "bearer", Secret: "token", Timeout: 30 * time.Second})
+	ctx := context.Background()
+
+	result, err := c.Query(ctx, `up{namespace="demo"}`, "1767322800", 10*time.Second)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if string(result.Data) != `{"resultType":"vector","result":[]}` || !slices.Equal(result.Warnings, []string{"partial"}) {
+		t.Errorf("Query() = %s %v", result.Data, result.Warnings)
+	}
+	if _, err := c.QueryRange(ctx, "up", "1", "2", "15", 0); err != nil {
+		t.Fatalf("QueryRange() error = %v", err)
+	}
+	if _, err := c.Series(ctx, []string{`up`, `{job="a"}`}, "", "", time.Hour); err != nil {
+		t.Fatalf("Series() error = %v", err)
+	}
+	if _, err := c.Labels(ctx, "", nil, "", "", 0); err != nil {
+		t.Fatalf("Labels() error = %v", err)
+	}
+	if _, err := c.Labels(ctx, "pod", []string{`up`}, "", "", 0); err != nil {
+		t.Fatalf("Labels(pod) error = %v", err)
+	}
+
+	// 请求的超时不超过数据源上限，并传给 Prometheus
+	want := []url.Values{
+		{"query": {`up{namespace="demo"}`}, "time": {"1767322800"}, "timeout": {"10s"}},
+		{"query": {"up"}, "start": {"1"}, "end": {"2"}, "step": {"15"}, "timeout": {"30s"}},
+		{"match[]": {`up`, `{job="a"}`}, "timeout": {"30s"}},
+		{"timeout": {"30s"}},
+		{"match[]": {`up`}, "timeout": {"30s"}},
+	}
+	requests := stub.recorded()
+	if len(requests) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(requests), len(want))
+	}
+	for i, r := range requests {
+		if r.method != http.MethodPost {
+			t.Errorf("%s method = %s, want POST", r.path, r.method)
+		}
+		if r.auth != "Bearer token" {
+			t.Errorf("%s Authorization = %q, want Bearer token", r.path, r.auth)
+		}
+		if r.form.Encode() != want[i].Encode() {
+			t.Errorf("%s form = %s, want %s", r.path, r.form.Encode(), want[i].Encode())
+		}
+	}
+}
+
+func TestClientBasicAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":[]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(ClientConfig{URL: srv.URL, AuthType: "basic", Username: "admin", Secret: "secret", Timeout: time.Second})
+	if _, err := c.Labels(context.Background(), "", nil, "", "", 0); err != nil {
+		t.Errorf("Labels() error = %v", err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		delay    time.Duration
+		wantCode int
+	}{
+		{name: "bad query", status: http.StatusBadRequest, body: `{"status":"error","errorType":"bad_data","error":"parse error"}`, wantCode: response.CodeBadRequest},
+		{name: "prometheus timeout", status: http.StatusServiceUnavailable, body: `{"status":"error","errorType":"timeout","error":"query timed out"}`, wantCode: response.CodeTimeout},
+		{name: "unavailable", status: http.StatusServiceUnavailable, body: `{"status":"error","errorType":"unavailable","error":"shutting down"}`, wantCode: response.CodeServiceUnavailable},
+		{name: "execution error", status: http.StatusUnprocessableEntity, body: `{"status":"error","errorType":"execution","error":"many-to-many matching"}`, wantCode: response.CodeBadGateway},
+		{name: "html from proxy", status: http.StatusBadGateway, body: `<html>bad gateway</html>`, wantCode: response.CodeBadGateway},
+		{name: "client timeout", status: http.StatusOK, body: `{"status":"success","data":{}}`, delay: 200 * time.Millisecond, wantCode: response.CodeTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.delay > 0 {
+					select {
+					case <-time.After(tt.delay):
+					case <-r.Context().Done():
+						return
+					}
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewClient(ClientConfig{URL: srv.URL, Timeout: time.Second})
+			_, err := c.Query(context.Background(), "up", "", 50*time.Millisecond)
+			var appErr *response.AppError
+			if !errors.As(err, &appErr) {
+				t.Fatalf("Query() error = %v, want *response.AppError", err)
+			}
+			if appErr.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d (%v)", appErr.Code, tt.wantCode, err)
+			}
+		})
+	}
+}
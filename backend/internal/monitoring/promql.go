@@ -0,0 +1,44 @@
+package monitoring
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// ScopeQuery 为 PromQL 中的每个向量选择器注入 label="value" 匹配条件，把查询限制在一个命名空间内
+// 类比 prom-label-proxy：up → up{namespace="demo"}，rate(x{job="a"}[5m]) → rate(x{namespace="demo",job="a"}[5m])
+// 查询先按 Prometheus 的语法解析为 AST 再重写，注释、字符串等不会被误认为选择器；
+// 查询自行指定了该标签时返回错误，避免通过 namespace=~".*" 越权
+func ScopeQuery(query, label, value string) (string, error) {
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query: %w", err)
+	}
+	matcher, err := labels.NewMatcher(labels.MatchEqual, label, value)
+	if err != nil {
+		return "", fmt.Errorf("failed to build matcher: %w", err)
+	}
+
+	var scopeErr error
+	// 区间选择器 x[5m] 和子查询中的向量选择器也会被遍历到
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		for _, m := range vs.LabelMatchers {
+			if m.Name == label {
+				scopeErr = fmt.Errorf("query must not set the %q label when scoped to a namespace", label)
+				return scopeErr
+			}
+		}
+		vs.LabelMatchers = append([]*labels.Matcher{matcher}, vs.LabelMatchers...)
+		return nil
+	})
+	if scopeErr != nil {
+		return "", scopeErr
+	}
+	return expr.String(), nil
+}
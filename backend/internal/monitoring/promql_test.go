@@ -0,0 +1,59 @@
+package monitoring
+
+import "testing"
+
+func TestScopeQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: `up`, want: `up{namespace="demo"}`},
+		{query: `up{job="api"}`, want: `up{job="api",namespace="demo"}`},
+		{query: `rate(http_requests_total{code=~"5.."}[5m])`, want: `rate(http_requests_total{code=~"5..",namespace="demo"}[5m])`},
+		{query: `sum by (pod) (rate(x[1h:5m]))`, want: `sum by (pod) (rate(x{namespace="demo"}[1h:5m]))`},
+		{query: `sum(rate(a[5m])) / on(pod) group_left(node) b`, want: `sum(rate(a{namespace="demo"}[5m])) / on (pod) group_left (node) b{namespace="demo"}`},
+		// 没有指标名的选择器和按 __name__ 正则匹配的选择器
+		{query: `{__name__=~"node_.*"}`, want: `{__name__=~"node_.*",namespace="demo"}`},
+		{query: `{job="api"}`, want: `{job="api",namespace="demo"}`},
+		{query: `count({__name__=~".+"})`, want: `count({__name__=~".+",namespace="demo"})`},
+		// # 注释由 Prometheus 丢弃，注释之后的选择器同样要限定
+		{query: "vector(0) # \"\nor {__name__=~'.+',namespace2='x'} # \"", want: `vector(0) or {__name__=~".+",namespace2="x",namespace="demo"}`},
+		{query: "up # {namespace=\"other\"}", want: `up{namespace="demo"}`},
+		{query: `up{job="a#b"}`, want: `up{job="a#b",namespace="demo"}`},
+		{query: `rate(x[5m] @ 100)`, want: `rate(x{namespace="demo"}[5m] @ 100.000)`},
+		{query: `a and b offset 5m`, want: `a{namespace="demo"} and b{namespace="demo"} offset 5m`},
+		{query: `label_replace(up, "x", "$1", "job", "(.*)")`, want: `label_replace(up{namespace="demo"}, "x", "$1", "job", "(.*)")`},
+		{query: `histogram_quantile(0.9, sum without (instance) (rate(h_bucket[5m])))`, want: `histogram_quantile(0.9, sum without (instance) (rate(h_bucket{namespace="demo"}[5m])))`},
+		{query: `vector(1)`, want: `vector(1)`},
+	}
+	for _, tt := range tests {
+		got, err := ScopeQuery(tt.query, "namespace", "demo")
+		if err != nil {
+			t.Errorf("ScopeQuery(%s) error = %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ScopeQuery(%s) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestScopeQueryRejectsEscapes(t *testing.T) {
+	for _, query := range []string{
+		`up{namespace=~".*"}`,
+		`up{namespace!="demo"}`,
+		`up{namespace!~"demo"}`,
+		`{ namespace="other"}`,
+		`{"namespace"="other"}`,
+		// 注释中的换行之后越权
+		"vector(0) # \"\nor {__name__=~'.+',namespace='other'} # \"",
+		"up # comment\n or sum(rate({__name__=~\".+\", namespace=~\".*\"}[5m]))",
+		`up{job="a"`,
+		`rate(up[5m)`,
+		`up{job="a}`,
+	} {
+		if got, err := ScopeQuery(query, "namespace", "demo"); err == nil {
+			t.Errorf("ScopeQuery(%s) = %s, want error", query, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package monitoring
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PodMetric 预置的 Pod 监控指标
+type PodMetric struct {
+	Name  string `json:"name"`
+	Unit  string `json:"unit"`
+	Query string `json:"query"`
+}
+
+// PodQueries 生成 Pod 的 CPU、内存、网络查询，基于 cAdvisor（kubelet）指标
+// namespaceLabel 为数据源中命名空间标签的名称，通常为 namespace
+// 类比Shell: kubectl top pod $POD -n $NAMESPACE --containers
+func PodQueries(namespaceLabel, namespace, pod string) []PodMetric {
+	selector := fmt.Sprintf(`%s=%s,pod=%s`, namespaceLabel, strconv.Quote(namespace), strconv.Quote(pod))
+	container := selector + `,container!="",container!="POD"`
+	return []PodMetric{
+		{
+			Name:  "cpu",
+			Unit:  "cores",
+			Query: fmt.Sprintf(`sum by (container) (rate(container_cpu_usage_seconds_total{%s}[5m]))`, container),
+		},
+		{
+			Name:  "memory",
+			Unit:  "bytes",
+			Query: fmt.Sprintf(`sum by (container) (container_memory_working_set_bytes{%s})`, container),
+		},
+		{
+			Name:  "network_receive",
+			Unit:  "bytes/s",
+			Query: fmt.Sprintf(`sum(rate(container_network_receive_bytes_total{%s}[5m]))`, selector),
+		},
+		{
+			Name:  "network_transmit",
+			Unit:  "bytes/s",
+			Query: fmt.Sprintf(`sum(rate(container_network_transmit_bytes_total{%s}[5m]))`, selector),
+		},
+	}
+}
@@ -0,0 +1,291 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/tracing"
+)
+
+// 渠道类型
+const (
+	ChannelWebhook  = "webhook"
+	ChannelSlack    = "slack"
+	ChannelDingTalk = "dingtalk"
+	ChannelFeishu   = "feishu"
+	ChannelEmail    = "email"
+)
+
+// ChannelTypes 支持的渠道类型
+var ChannelTypes = []string{ChannelWebhook, ChannelSlack, ChannelDingTalk, ChannelFeishu, ChannelEmail}
+
+// SMTP 连接的加密方式
+const (
+	SMTPTLSNone     = "none"     // 明文，只适合本地测试
+	SMTPTLSStartTLS = "starttls" // 明文连接后升级，通常为 587 端口
+	SMTPTLSImplicit = "tls"      // 直接建立 TLS 连接，通常为 465 端口
+)
+
+// ChannelConfig 渠道配置，按渠道类型使用其中的字段
+// 密钥（钉钉、飞书的加签密钥，Webhook 的签名密钥，SMTP 密码）单独存储，不在这里
+type ChannelConfig struct {
+	URL     string            `json:"url,omitempty"`     // webhook、slack、dingtalk、feishu 的地址
+	Headers map[string]string `json:"headers,omitempty"` // webhook 附加的请求头
+
+	SMTPHost      string   `json:"smtpHost,omitempty"`
+	SMTPPort      int      `json:"smtpPort,omitempty"`
+	SMTPTLS       string   `json:"smtpTLS,omitempty"` // none、starttls 或 tls，默认 starttls
+	Username      string   `json:"username,omitempty"`
+	From          string   `json:"from,omitempty"`
+	To            []string `json:"to,omitempty"`
+	TLSSkipVerify bool     `json:"tlsSkipVerify,omitempty"` // 跳过 HTTPS 或 SMTP 的证书校验
+}
+
+// Validate 按渠道类型校验配置并填充默认值
+func (c *ChannelConfig) Validate(channelType string) error {
+	if err := c.checkUnmasked(); err != nil {
+		return err
+	}
+	switch channelType {
+	case ChannelWebhook, ChannelSlack, ChannelDingTalk, ChannelFeishu:
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url 无效: %q", c.URL)
+		}
+	case ChannelEmail:
+		if c.SMTPHost == "" {
+			return errors.New("smtpHost 不能为空")
+		}
+		if c.SMTPTLS == "" {
+			c.SMTPTLS = SMTPTLSStartTLS
+		}
+		switch c.SMTPTLS {
+		case SMTPTLSNone, SMTPTLSStartTLS:
+			if c.SMTPPort == 0 {
+				c.SMTPPort = 587
+			}
+		case SMTPTLSImplicit:
+			if c.SMTPPort == 0 {
+				c.SMTPPort = 465
+			}
+		default:
+			return fmt.Errorf("smtpTLS 只能是 none、starttls 或 tls，当前为 %q", c.SMTPTLS)
+		}
+		if c.SMTPPort < 1 || c.SMTPPort > 65535 {
+			return fmt.Errorf("smtpPort 无效: %d", c.SMTPPort)
+		}
+		if _, err := mail.ParseAddress(c.From); err != nil {
+			return fmt.Errorf("from 无效: %q", c.From)
+		}
+		if len(c.To) == 0 {
+			return errors.New("to 至少需要一个收件人")
+		}
+		for _, to := range c.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				return fmt.Errorf("收件人无效: %q", to)
+			}
+		}
+	default:
+		return fmt.Errorf("不支持的渠道类型 %q", channelType)
+	}
+	return nil
+}
+
+// NewSender 按渠道类型创建发送端，timeout 为单次发送的超时
+func NewSender(channelType string, cfg ChannelConfig, secret string, timeout time.Duration) (Sender, error) {
+	if err := cfg.Validate(channelType); err != nil {
+		return nil, err
+	}
+	if channelType == ChannelEmail {
+		return &emailSender{cfg: cfg, password: secret, timeout: timeout}, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLSSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // 由渠道配置显式开启
+	}
+	return &httpSender{
+		channelType: channelType,
+		cfg:         cfg,
+		secret:      secret,
+		http: &http.Client{
+			Timeout:   timeout,
+			Transport: tracing.WrapClientTransport("notify", transport),
+		},
+	}, nil
+}
+
+// httpSender 通过 HTTP Webhook 发送的渠道：通用 Webhook、Slack、钉钉和飞书机器人
+type httpSender struct {
+	channelType string
+	cfg         ChannelConfig
+	secret      string
+	http        *http.Client
+}
+
+// CloseIdleConnections 关闭连接池中的空闲连接，发送端被替换时调用
+func (s *httpSender) CloseIdleConnections() {
+	s.http.CloseIdleConnections()
+}
+
+// Send 按渠道类型组装请求体并发送
+// 对应Shell: curl -X POST -H 'Content-Type: application/json' -d '{"text": "..."}' "$WEBHOOK_URL"
+func (s *httpSender) Send(ctx context.Context, msg Message) error {
+	endpoint := s.cfg.URL
+	headers := map[string]string{}
+	var payload interface{}
+	now := time.Now()
+
+	switch s.channelType {
+	case ChannelWebhook:
+		payload = map[string]interface{}{"event": msg.Event, "text": msg.Text}
+		for k, v := range s.cfg.Headers {
+			headers[k] = v
+		}
+	case ChannelSlack:
+		payload = map[string]string{"text": msg.Text}
+	case ChannelDingTalk:
+		// 加签：对 timestamp + "\n" + secret 做 HmacSHA256，结果放在 URL 参数中
+		if s.secret != "" {
+			timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+			mac := hmac.New(sha256.New, []byte(s.secret))
+			mac.Write([]byte(timestamp + "\n" + s.secret))
+			u, err := url.Parse(endpoint)
+			if err != nil {
+				return permanent(fmt.Errorf("invalid url: %w", err))
+			}
+			q := u.Query()
+			q.Set("timestamp", timestamp)
+			q.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+			u.RawQuery = q.Encode()
+			endpoint = u.String()
+		}
+		payload = map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]string{"content": msg.Text},
+		}
+	case ChannelFeishu:
+		body := map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": msg.Text},
+		}
+		// 加签：以 timestamp + "\n" + secret 为密钥对空字符串做 HmacSHA256，结果放在请求体中
+		if s.secret != "" {
+			timestamp := strconv.FormatInt(now.Unix(), 10)
+			mac := hmac.New(sha256.New, []byte(timestamp+"\n"+s.secret))
+			body["timestamp"] = timestamp
+			body["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		}
+		payload = body
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return permanent(fmt.Errorf("failed to encode payload: %w", err))
+	}
+	// 通用 Webhook 配置了密钥时对请求体签名，接收方用同一密钥校验来源
+	if s.channelType == ChannelWebhook && s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(data)
+		headers["X-KubeOps-Signature"] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return permanent(fmt.Errorf("failed to build request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		// url.Error 的消息包含完整地址，其中可能有 Token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to send %s notification: %w", s.channelType, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	// 429 和 5xx 可以重试，其余 4xx 说明地址或请求有误
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%s returned HTTP %d: %s", s.channelType, resp.StatusCode, truncate(body))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return permanent(fmt.Errorf("%s returned HTTP %d: %s", s.channelType, resp.StatusCode, truncate(body)))
+	}
+	return s.checkBody(body)
+}
+
+// checkBody 钉钉和飞书出错时也返回 HTTP 200，错误码在响应体中
+func (s *httpSender) checkBody(body []byte) error {
+	switch s.channelType {
+	case ChannelDingTalk:
+		var result struct {
+			ErrCode int    `json:"errcode"`
+			ErrMsg  string `json:"errmsg"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return permanent(fmt.Errorf("dingtalk returned unexpected response: %s", truncate(body)))
+		}
+		switch result.ErrCode {
+		case 0:
+			return nil
+		case 130101: // 发送太快，每个机器人每分钟最多 20 条
+			return fmt.Errorf("dingtalk error %d: %s", result.ErrCode, result.ErrMsg)
+		default:
+			return permanent(fmt.Errorf("dingtalk error %d: %s", result.ErrCode, result.ErrMsg))
+		}
+	case ChannelFeishu:
+		var result struct {
+			Code       *int   `json:"code"`
+			Msg        string `json:"msg"`
+			StatusCode *int   `json:"StatusCode"` // 旧版接口
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return permanent(fmt.Errorf("feishu returned unexpected response: %s", truncate(body)))
+		}
+		code := 0
+		if result.Code != nil {
+			code = *result.Code
+		} else if result.StatusCode != nil {
+			code = *result.StatusCode
+		}
+		switch code {
+		case 0:
+			return nil
+		case 9499, 11232: // 请求频率超过限制
+			return fmt.Errorf("feishu error %d: %s", code, result.Msg)
+		default:
+			return permanent(fmt.Errorf("feishu error %d: %s", code, result.Msg))
+		}
+	}
+	return nil
+}
+
+func truncate(body []byte) string {
+	const limit = 512
+	if len(body) > limit {
+		return string(body[:limit]) + "..."
+	}
+	return string(body)
+}
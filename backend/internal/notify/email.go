@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emailSender 通过 SMTP 发送邮件
+type emailSender struct {
+	cfg      ChannelConfig
+	password string
+	timeout  time.Duration
+}
+
+// Send 连接 SMTP 服务器发送一封纯文本邮件
+// 对应Shell: curl --ssl-reqd smtp://$SMTP_HOST:587 --mail-from $FROM --mail-rcpt $TO -u $USER:$PASS -T mail.txt
+func (s *emailSender) Send(ctx context.Context, msg Message) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	tlsConfig := &tls.Config{
+		ServerName:         s.cfg.SMTPHost,
+		InsecureSkipVerify: s.cfg.TLSSkipVerify, //nolint:gosec // 由渠道配置显式开启
+	}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{}
+	if s.cfg.SMTPTLS == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server %s: %w", addr, err)
+	}
+	// net/smtp 不支持 context，用连接的截止时间控制整个会话的超时
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.cfg.SMTPHost)
+	if err != nil {
+		_ = conn.Close()
+		return smtpError("failed to start smtp session", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+
+	if s.cfg.SMTPTLS == SMTPTLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return permanent(fmt.Errorf("smtp server %s does not support STARTTLS", addr))
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return smtpError("failed to start tls", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		// PlainAuth 只在 TLS 连接或 localhost 上发送密码；认证失败重试也不会成功
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.password, s.cfg.SMTPHost)); err != nil {
+			return permanent(fmt.Errorf("smtp authentication failed: %w", err))
+		}
+	}
+
+	from, _ := mail.ParseAddress(s.cfg.From)
+	if err := c.Mail(from.Address); err != nil {
+		return smtpError("smtp MAIL FROM rejected", err)
+	}
+	for _, to := range s.cfg.To {
+		addr, _ := mail.ParseAddress(to)
+		if err := c.Rcpt(addr.Address); err != nil {
+			return smtpError(fmt.Sprintf("smtp RCPT TO %s rejected", addr.Address), err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError("smtp DATA rejected", err)
+	}
+	body, err := s.message(msg)
+	if err != nil {
+		return permanent(err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return smtpError("failed to write mail body", err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError("smtp server rejected the mail", err)
+	}
+	return c.Quit()
+}
+
+// message 组装邮件头和 quoted-printable 编码的正文
+func (s *emailSender) message(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", s.cfg.From)
+	header("To", strings.Join(s.cfg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject()))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	header("X-KubeOps-Event", msg.Event.Type)
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(msg.Text, "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("failed to encode mail body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode mail body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// smtpError 5xx 响应为永久性错误（如收件人不存在、认证失败），其余可以重试
+func smtpError(msg string, err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return permanent(fmt.Errorf("%s: %w", msg, err))
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession 记录一次 SMTP 会话收到的命令和邮件
+type smtpSession struct {
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// stubSMTP 在 127.0.0.1 上接受一个明文 SMTP 会话；rcptReply 非空时用它拒绝 RCPT
+func stubSMTP(t *testing.T, rcptReply string) (host string, port int, done <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	ch := make(chan smtpSession, 1)
+	go func() {
+		var s smtpSession
+		defer func() { ch <- s }()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 stub ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250-stub\r\n250 AUTH PLAIN")
+			case "AUTH":
+				s.auth = arg
+				_ = tp.PrintfLine("235 2.7.0 Authentication successful")
+			case "MAIL":
+				s.from = arg
+				_ = tp.PrintfLine("250 OK")
+			case "RCPT":
+				if rcptReply != "" {
+					_ = tp.PrintfLine("%s", rcptReply)
+					continue
+				}
+				s.rcpt = append(s.rcpt, arg)
+				_ = tp.PrintfLine("250 OK")
+			case "DATA":
+				_ = tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				data, err := io.ReadAll(tp.DotReader())
+				if err != nil {
+					return
+				}
+				s.data = string(data)
+				_ = tp.PrintfLine("250 OK: queued")
+			case "QUIT":
+				_ = tp.PrintfLine("221 Bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 command not implemented")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+func emailConfig(host string, port int) ChannelConfig {
+	return ChannelConfig{
+		SMTPHost: host,
+		SMTPPort: port,
+		SMTPTLS:  SMTPTLSNone,
+		Username: "alert",
+		From:     "KubeOps <kubeops@example.com>",
+		To:       []string{"ops@example.com", "Oncall <oncall@example.com>"},
+	}
+}
+
+func waitSession(t *testing.T, done <-chan smtpSession) smtpSession {
+	t.Helper()
+	select {
+	case s := <-done:
+		return s
+	case <-time.After(5 * time.Second):
+		t.Fatal("smtp session did not finish")
+		return smtpSession{}
+	}
+}
+
+func TestEmailSend(t *testing.T) {
+	host, port, done := stubSMTP(t, "")
+	sender := newTestSender(t, ChannelEmail, emailConfig(host, port), "smtp-pass")
+
+	msg := testMessage()
+	msg.Text = "第一行\n第二行"
+	if err := sender.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	s := waitSession(t, done)
+
+	// PlainAuth 在 127.0.0.1 上允许明文发送凭据
+	if want := "PLAIN " + base64.StdEncoding.EncodeToString([]byte("\x00alert\x00smtp-pass")); s.auth != want {
+		t.Errorf("AUTH = %q, want %q", s.auth, want)
+	}
+	if s.from != "FROM:<kubeops@example.com>" {
+		t.Errorf("MAIL = %q", s.from)
+	}
+	if want := []string{"TO:<ops@example.com>", "TO:<oncall@example.com>"}; strings.Join(s.rcpt, ",") != strings.Join(want, ",") {
+		t.Errorf("RCPT = %v, want %v", s.rcpt, want)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(s.data))
+	if err != nil {
+		t.Fatalf("parse mail: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject() {
+		t.Errorf("Subject = %q, %v; want %q", subject, err, msg.Subject())
+	}
+	if got := m.Header.Get("X-KubeOps-Event"); got != EventTest {
+		t.Errorf("X-KubeOps-Event = %q, want %q", got, EventTest)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(m.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got := strings.TrimSuffix(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n"); got != msg.Text {
+		t.Errorf("body = %q, want %q", got, msg.Text)
+	}
+}
+
+func TestEmailRecipientRejected(t *testing.T) {
+	tests := []struct {
+		reply         string
+		wantPermanent bool
+	}{
+		{reply: "550 5.1.1 mailbox unavailable", wantPermanent: true},
+		{reply: "451 4.7.1 greylisted, try again later", wantPermanent: false},
+	}
+	for _, tt := range tests {
+		host, port, done := stubSMTP(t, tt.reply)
+		err := newTestSender(t, ChannelEmail, emailConfig(host, port), "smtp-pass").Send(context.Background(), testMessage())
+		waitSession(t, done)
+		if err == nil {
+			t.Errorf("Send() with RCPT reply %q succeeded", tt.reply)
+			continue
+		}
+		if IsPermanent(err) != tt.wantPermanent {
+			t.Errorf("RCPT reply %q: IsPermanent(%v) = %v, want %v", tt.reply, err, IsPermanent(err), tt.wantPermanent)
+		}
+	}
+}
+
+func TestEmailStartTLSRequired(t *testing.T) {
+	host, port, done := stubSMTP(t, "")
+	cfg := emailConfig(host, port)
+	cfg.SMTPTLS = SMTPTLSStartTLS
+	err := newTestSender(t, ChannelEmail, cfg, "smtp-pass").Send(context.Background(), testMessage())
+	waitSession(t, done)
+	if !IsPermanent(err) || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("Send() error = %v, want a permanent STARTTLS error", err)
+	}
+}
@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Masked API 响应中替代敏感值的占位符，更新渠道时原样传回表示保留原值
+const Masked = "******"
+
+// maskMinSegment 地址路径中不短于该长度的段视为 Token（如 Slack、飞书 Webhook 地址的最后一段）
+const maskMinSegment = 16
+
+// Masked 返回用于 API 响应的配置
+// Webhook 地址中的 Token 通常在查询参数（钉钉 access_token）或路径（Slack、飞书）中，请求头通常包含 Authorization，
+// 因此遮盖所有查询参数值、较长的路径段、URL 中的密码和所有请求头的值
+func (c ChannelConfig) Masked() ChannelConfig {
+	if c.URL != "" {
+		c.URL = maskURL(c.URL)
+	}
+	if len(c.Headers) > 0 {
+		headers := make(map[string]string, len(c.Headers))
+		for k := range c.Headers {
+			headers[k] = Masked
+		}
+		c.Headers = headers
+	}
+	return c
+}
+
+// Restore 更新时把仍为遮盖值的地址和请求头换回 existing 中的原值
+func (c *ChannelConfig) Restore(existing ChannelConfig) {
+	if existing.URL != "" && c.URL == maskURL(existing.URL) {
+		c.URL = existing.URL
+	}
+	for k, v := range c.Headers {
+		if old, ok := existing.Headers[k]; ok && v == Masked {
+			c.Headers[k] = old
+		}
+	}
+}
+
+// checkUnmasked 拒绝仍包含遮盖值的配置，避免把占位符当作真实地址或请求头保存
+func (c ChannelConfig) checkUnmasked() error {
+	if strings.Contains(c.URL, Masked) {
+		return fmt.Errorf("url 包含遮盖值 %s，需要填写完整地址", Masked)
+	}
+	for k, v := range c.Headers {
+		if v == Masked {
+			return fmt.Errorf("请求头 %s 的值为遮盖值 %s，需要重新填写", k, Masked)
+		}
+	}
+	return nil
+}
+
+func maskURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return Masked
+	}
+	var b strings.Builder
+	b.WriteString(u.Scheme + "://")
+	if u.User != nil {
+		b.WriteString(Masked + "@")
+	}
+	b.WriteString(u.Host)
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i, s := range segments {
+		if len(s) >= maskMinSegment {
+			segments[i] = Masked
+		}
+	}
+	b.WriteString(strings.Join(segments, "/"))
+
+	if u.RawQuery != "" {
+		pairs := strings.Split(u.RawQuery, "&")
+		for i, pair := range pairs {
+			key, _, _ := strings.Cut(pair, "=")
+			pairs[i] = key + "=" + Masked
+		}
+		b.WriteString("?" + strings.Join(pairs, "&"))
+	}
+	return b.String()
+}
@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// 事件类型
+const (
+	EventInspectionFinding = "inspection.finding" // 巡检发现的问题
+	EventRolloutFailed     = "rollout.failed"     // Deployment 发布超过 progressDeadlineSeconds 仍未完成
+	EventNodeNotReady      = "node.notready"      // 节点 Ready 条件不为 True
+	EventTest              = "test"               // 测试渠道时发送的事件，不经过路由规则
+)
+
+// EventTypes 可在路由规则中使用的事件类型
+var EventTypes = []string{EventInspectionFinding, EventRolloutFailed, EventNodeNotReady}
+
+// 严重程度，与巡检问题的严重程度一致
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Severities 可在路由规则中使用的严重程度
+var Severities = []string{SeverityCritical, SeverityWarning, SeverityInfo}
+
+// Event 一条需要通知的事件
+// Reason 区分同一资源上的不同问题（巡检检查项、Deployment 条件的 reason 等），参与去重
+type Event struct {
+	Type      string    `json:"type"`
+	Severity  string    `json:"severity"`
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace,omitempty"`
+	Kind      string    `json:"kind,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+}
+
+// Fingerprint 事件的指纹：同一类型、同一资源、同一原因的事件指纹相同，用于去重
+func (e Event) Fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{e.Type, e.Cluster, e.Namespace, e.Kind, e.Name, e.Reason}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Object 事件涉及的资源，如 Deployment/web，没有资源时为空
+func (e Event) Object() string {
+	if e.Name == "" {
+		return ""
+	}
+	if e.Kind == "" {
+		return e.Name
+	}
+	return e.Kind + "/" + e.Name
+}
+
+// DefaultTemplate 渠道未配置模板时使用的消息模板
+const DefaultTemplate = `[{{ .Severity | upper }}] {{ .Title }}
+集群: {{ .Cluster }}{{ if .Namespace }}
+命名空间: {{ .Namespace }}{{ end }}{{ if .Object }}
+资源: {{ .Object }}{{ end }}
+{{ .Message }}
+时间: {{ .Time.Format "2006-01-02 15:04:05 MST" }}`
+
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// ParseTemplate 解析消息模板，模板为 text/template 语法，数据为 Event；为空时使用 DefaultTemplate
+// 解析后用示例事件渲染一次，提前发现引用了不存在字段的模板
+func ParseTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := template.New("notification").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Render(tmpl, SampleEvent()); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Render 用事件渲染消息模板
+func Render(tmpl *template.Template, e Event) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, e); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// SampleEvent 校验模板和测试渠道时使用的示例事件
+func SampleEvent() Event {
+	return Event{
+		Type:      EventTest,
+		Severity:  SeverityInfo,
+		Cluster:   "default",
+		Namespace: "default",
+		Kind:      "Deployment",
+		Name:      "kubeops-test",
+		Reason:    "Test",
+		Title:     "KubeOps 通知测试",
+		Message:   "这是一条测试消息，收到说明通知渠道配置正确",
+		Time:      time.Now(),
+	}
+}
+
+// Message 渲染后待发送的通知
+type Message struct {
+	Event Event
+	Text  string
+}
+
+// Subject 邮件主题
+func (m Message) Subject() string {
+	return fmt.Sprintf("[%s] %s", strings.ToUpper(m.Event.Severity), m.Event.Title)
+}
+
+// Sender 一个通知渠道的发送端
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// permanentError 重试也不会成功的错误，如地址、签名或收件人错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent 错误是否不应重试
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Send 发送通知，失败时按指数退避重试，最多尝试 attempts 次；永久性错误不重试
+// 返回实际尝试的次数
+// 类比Shell: for i in 1 2 3 4; do curl -fsS -d "$msg" "$WEBHOOK" && break; sleep $((2 ** i)); done
+func Send(ctx context.Context, sender Sender, msg Message, attempts int, backoff time.Duration) (int, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = sender.Send(ctx, msg); err == nil || IsPermanent(err) || attempt >= attempts {
+			return attempt, err
+		}
+		select {
+		case <-ctx.Done():
+			return attempt, errors.Join(err, ctx.Err())
+		case <-time.After(backoff << (attempt - 1)):
+		}
+	}
+}
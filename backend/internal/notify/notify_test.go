@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakySender 前 failures 次发送返回 err，记录每次发送的时间
+type flakySender struct {
+	failures int
+	err      error
+	calls    []time.Time
+}
+
+func (s *flakySender) Send(context.Context, Message) error {
+	s.calls = append(s.calls, time.Now())
+	if len(s.calls) <= s.failures {
+		return s.err
+	}
+	return nil
+}
+
+func TestSendRetriesWithExponentialBackoff(t *testing.T) {
+	const backoff = 20 * time.Millisecond
+	sender := &flakySender{failures: 2, err: errors.New("HTTP 503")}
+
+	attempts, err := Send(context.Background(), sender, testMessage(), 4, backoff)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	// 第 n 次重试前等待 backoff * 2^(n-1)
+	for i, want := range []time.Duration{backoff, 2 * backoff} {
+		if gap := sender.calls[i+1].Sub(sender.calls[i]); gap < want {
+			t.Errorf("wait before attempt %d = %s, want at least %s", i+2, gap, want)
+		}
+	}
+}
+
+func TestSendStopsAfterMaxAttempts(t *testing.T) {
+	sender := &flakySender{failures: 10, err: errors.New("HTTP 503")}
+	attempts, err := Send(context.Background(), sender, testMessage(), 3, time.Millisecond)
+	if err == nil || attempts != 3 {
+		t.Errorf("Send() = %d, %v; want 3 attempts and an error", attempts, err)
+	}
+}
+
+func TestSendDoesNotRetryPermanentErrors(t *testing.T) {
+	sender := &flakySender{failures: 10, err: permanent(errors.New("HTTP 404"))}
+	attempts, err := Send(context.Background(), sender, testMessage(), 5, time.Millisecond)
+	if !IsPermanent(err) || attempts != 1 {
+		t.Errorf("Send() = %d, %v; want 1 attempt and a permanent error", attempts, err)
+	}
+}
+
+func TestSendStopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	sender := &flakySender{failures: 10, err: errors.New("HTTP 503")}
+
+	attempts, err := Send(ctx, sender, testMessage(), 5, time.Hour)
+	if attempts != 1 || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send() = %d, %v; want 1 attempt and deadline exceeded", attempts, err)
+	}
+}
+
+func TestSendRetriesAgainstHTTPStandIn(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	sender := newTestSender(t, ChannelSlack, ChannelConfig{URL: srv.URL}, "")
+	attempts, err := Send(context.Background(), sender, testMessage(), 4, time.Millisecond)
+	if err != nil || attempts != 3 {
+		t.Errorf("Send() = %d, %v; want 3 attempts and no error", attempts, err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	base := Event{Type: EventRolloutFailed, Cluster: "prod", Namespace: "web", Kind: "Deployment", Name: "api", Reason: "ProgressDeadlineExceeded"}
+
+	same := base
+	same.Message = "different message"
+	same.Time = time.Now()
+	same.Severity = SeverityCritical
+	if base.Fingerprint() != same.Fingerprint() {
+		t.Error("message, time and severity must not change the fingerprint")
+	}
+
+	for name, mutate := range map[string]func(*Event){
+		"type":      func(e *Event) { e.Type = EventNodeNotReady },
+		"cluster":   func(e *Event) { e.Cluster = "staging" },
+		"namespace": func(e *Event) { e.Namespace = "api" },
+		"name":      func(e *Event) { e.Name = "worker" },
+		"reason":    func(e *Event) { e.Reason = "ReplicaFailure" },
+	} {
+		other := base
+		mutate(&other)
+		if base.Fingerprint() == other.Fingerprint() {
+			t.Errorf("changing %s must change the fingerprint", name)
+		}
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate("{{ .Severity | upper }} {{ .Object }} {{ .Title }}")
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+	text, err := Render(tmpl, Event{Severity: SeverityWarning, Kind: "Node", Name: "node-1", Title: "NotReady"})
+	if err != nil || text != "WARNING Node/node-1 NotReady" {
+		t.Errorf("Render() = %q, %v", text, err)
+	}
+
+	if _, err := ParseTemplate("{{ .Missing }}"); err == nil {
+		t.Error("ParseTemplate() accepted a template with an unknown field")
+	}
+
+	tmpl, err = ParseTemplate("")
+	if err != nil {
+		t.Fatalf("ParseTemplate(default) error = %v", err)
+	}
+	text, _ = Render(tmpl, testMessage().Event)
+	if !strings.HasPrefix(text, "[INFO] KubeOps 通知测试") {
+		t.Errorf("default template = %q", text)
+	}
+}
@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+const keyPrefix = "kubeops:ratelimit:"
+
+// gcraScript 基于 GCRA（通用信元速率算法）的令牌桶，在 Redis 中原子执行
+// 只保存一个"理论到达时间"（TAT），使用 Redis 服务器时间，多副本之间不受时钟偏差影响
+// KEYS[1] 限流键；ARGV[1] 每个请求的间隔（微秒）；ARGV[2] 突发容量对应的时长（微秒）
+// 返回 {是否允许, 剩余配额, 需要等待的微秒数}
+var gcraScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then
+  tat = now
+end
+
+local newTat = tat + interval
+local allowAt = newTat - burst
+if allowAt > now then
+  return {0, 0, allowAt - now}
+end
+
+-- 显式格式化，避免 Lua 默认的 %.14g 丢失微秒精度
+redis.call('SET', KEYS[1], string.format('%.0f', newTat), 'PX', math.ceil((newTat - now) / 1000))
+return {1, math.floor((burst - (newTat - now)) / interval), 0}
+`)
+
+// Result 一次限流判断的结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Limiter 按路由组和用户的分布式限流器
+// Redis 不可用时放行所有请求（fail open），避免限流组件本身导致服务不可用
+type Limiter struct {
+	logger *zap.Logger
+	redis  *redis.Client
+	dep    *client.Dependency
+	state  atomic.Pointer[state]
+}
+
+// state 可热加载的限流配置，预先解析网段
+type state struct {
+	cfg         config.RateLimitConfig
+	exemptUsers map[string]bool
+	exemptNets  []*net.IPNet
+}
+
+// NewLimiter 创建限流器
+func NewLimiter(logger *zap.Logger, redisClient *redis.Client, dep *client.Dependency, cfg config.RateLimitConfig) *Limiter {
+	l := &Limiter{logger: logger, redis: redisClient, dep: dep}
+	l.SetConfig(cfg)
+	return l
+}
+
+// SetConfig 替换限流配置，配置热加载时调用
+func (l *Limiter) SetConfig(cfg config.RateLimitConfig) {
+	st := &state{cfg: cfg, exemptUsers: make(map[string]bool, len(cfg.ExemptUsers))}
+	for _, user := range cfg.ExemptUsers {
+		st.exemptUsers[user] = true
+	}
+	for _, cidr := range cfg.ExemptCIDRs {
+		if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+			st.exemptNets = append(st.exemptNets, ipNet)
+		}
+	}
+	l.state.Store(st)
+}
+
+// Exempt 判断请求是否免于限流：限流未启用、已验证的用户或客户端 IP 在豁免列表中
+// 未验证的身份可以任意声明用户名，不参与用户豁免
+func (l *Limiter) Exempt(user auth.User, clientIP string) bool {
+	st := l.state.Load()
+	if !st.cfg.Enabled {
+		return true
+	}
+	if id := user.ID(); id != auth.Anonymous && st.exemptUsers[id] {
+		return true
+	}
+	if ip := net.ParseIP(clientIP); ip != nil {
+		for _, ipNet := range st.exemptNets {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Allow 消耗 identity 在 group 上的一个配额；未配置该路由组时不限流
+func (l *Limiter) Allow(ctx context.Context, group, identity string) (Result, error) {
+	rule, ok := l.state.Load().cfg.Groups[group]
+	if !ok || rule.Requests <= 0 || rule.Period <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	burst := rule.Burst
+	if burst <= 0 {
+		burst = rule.Requests
+	}
+	if l.dep.Err() != nil {
+		return Result{Allowed: true, Limit: burst, Remaining: burst}, nil
+	}
+
+	interval := rule.Period.Microseconds() / int64(rule.Requests)
+	values, err := gcraScript.Run(ctx, l.redis, []string{keyPrefix + group + ":" + identity},
+		interval, interval*int64(burst)).Int64Slice()
+	if err != nil {
+		return Result{Allowed: true, Limit: burst, Remaining: burst}, fmt.Errorf("failed to evaluate rate limit: %w", err)
+	}
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      burst,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
+
+// execSegments 归入 exec 路由组的路由模板段
+var execSegments = map[string]bool{
+	"exec":        true,
+	"attach":      true,
+	"log":         true,
+	"logs":        true,
+	"portforward": true,
+	"cp":          true,
+}
+
+// Group 按请求方法和路由模板划分路由组
+// exec、attach、日志、端口转发等长连接或高开销接口单独限流，其余按读写区分
+// route 为 gin 的 FullPath（如 /api/v1/namespaces/:namespace/pods/:name/exec），只匹配固定段，名为 logs 的命名空间不会被归入 exec 组
+func Group(method, route string) string {
+	for _, segment := range strings.Split(route, "/") {
+		if execSegments[segment] {
+			return config.RateLimitGroupExec
+		}
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return config.RateLimitGroupReads
+	default:
+		return config.RateLimitGroupMutations
+	}
+}
+
+// Identity 限流主体：已验证的用户名；匿名或未验证的请求使用客户端 IP
+// 未验证的用户名和 Token 可以任意伪造，按它们计算配额时每次换一个值就能绕过限流，用户名还能耗尽他人的配额
+func Identity(user auth.User, clientIP string) string {
+	if id := user.ID(); id != auth.Anonymous {
+		return "user:" + id
+	}
+	return "ip:" + clientIP
+}
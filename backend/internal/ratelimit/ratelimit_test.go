@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		method string
+		route  string
+		want   string
+	}{
+		{method: http.MethodGet, route: "/api/v1/namespaces/:namespace/pods/:name/exec", want: config.RateLimitGroupExec},
+		{method: http.MethodPost, route: "/api/v1/namespaces/:namespace/pods/:name/cp", want: config.RateLimitGroupExec},
+		{method: http.MethodGet, route: "/api/v1/logs/tail", want: config.RateLimitGroupExec},
+		{method: http.MethodGet, route: "/api/v1/jenkins/servers/:id/builds/:number/log", want: config.RateLimitGroupExec},
+		// 路由参数的取值不影响分组：GET /api/v1/namespaces/logs/pods 的模板不含 logs 段
+		{method: http.MethodGet, route: "/api/v1/namespaces/:namespace/pods", want: config.RateLimitGroupReads},
+		{method: http.MethodDelete, route: "/api/v1/namespaces/:namespace/pods/:name", want: config.RateLimitGroupMutations},
+		// 未匹配的路由
+		{method: http.MethodGet, route: "", want: config.RateLimitGroupReads},
+		{method: http.MethodPost, route: "", want: config.RateLimitGroupMutations},
+	}
+	for _, tt := range tests {
+		if got := Group(tt.method, tt.route); got != tt.want {
+			t.Errorf("Group(%s, %q) = %s, want %s", tt.method, tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	tests := []struct {
+		name string
+		user auth.User
+		want string
+	}{
+		{name: "verified user", user: auth.User{Name: "alice", Verified: true}, want: "user:alice"},
+		// 未验证的用户名和 Token 都可以每次请求换一个，只能按客户端 IP 限流
+		{name: "unverified user falls back to the IP", user: auth.User{Name: "alice"}, want: "ip:10.0.0.1"},
+		{name: "anonymous", user: auth.User{Name: auth.Anonymous, Verified: true}, want: "ip:10.0.0.1"},
+	}
+	for _, tt := range tests {
+		if got := Identity(tt.user, "10.0.0.1"); got != tt.want {
+			t.Errorf("%s: Identity() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExemptRequiresVerifiedUser(t *testing.T) {
+	l := &Limiter{}
+	l.SetConfig(config.RateLimitConfig{Enabled: true, ExemptUsers: []string{"ci-bot"}, ExemptCIDRs: []string{"10.0.0.0/8"}})
+
+	if !l.Exempt(auth.User{Name: "ci-bot", Verified: true}, "192.168.0.1") {
+		t.Error("verified exempt user must be exempt")
+	}
+	if l.Exempt(auth.User{Name: "ci-bot"}, "192.168.0.1") {
+		t.Error("unverified user must not be exempt by name")
+	}
+	if !l.Exempt(auth.User{}, "10.1.2.3") {
+		t.Error("client IP in exempt CIDR must be exempt")
+	}
+}
@@ -0,0 +1,155 @@
+package reload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// Watcher 监听一组文件的内容变化，变化时回调 onChange
+// 监听的是文件所在目录而不是文件本身：编辑器保存、kubelet 更新挂载的 ConfigMap/Secret
+// （原子替换 ..data 符号链接）都会替换 inode，直接监听文件会丢失后续事件。
+// 目录内任何事件都只触发一次防抖后的内容比对，只有被监听文件的内容真正变化才会回调。
+type Watcher struct {
+	logger   *zap.Logger
+	debounce time.Duration
+	paths    func() []string
+	onChange func()
+}
+
+// NewWatcher 创建文件监听器，paths 在每次回调后重新求值，以便跟随配置变化调整监听范围
+func NewWatcher(logger *zap.Logger, debounce time.Duration, paths func() []string, onChange func()) *Watcher {
+	return &Watcher{
+		logger:   logger,
+		debounce: debounce,
+		paths:    paths,
+		onChange: onChange,
+	}
+}
+
+// Run 阻塞运行直到 ctx 取消
+func (w *Watcher) Run(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer func() {
+		_ = fsw.Close()
+	}()
+
+	fingerprints := w.snapshot()
+	dirs := w.syncDirs(fsw, nil, fingerprints)
+
+	var timer *time.Timer
+	var timerC <-chan time.Time
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if timer == nil {
+				timer = time.NewTimer(w.debounce)
+			} else {
+				timer.Reset(w.debounce)
+			}
+			timerC = timer.C
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.Warn("File watcher error", zap.Error(err))
+		case <-timerC:
+			timerC = nil
+			current := w.snapshot()
+			if sameFingerprints(current, fingerprints) {
+				continue
+			}
+			for path, sum := range current {
+				if fingerprints[path] != sum {
+					w.logger.Info("Watched file changed", zap.String("path", path))
+				}
+			}
+			w.onChange()
+			// 回调后监听路径可能变化（如配置文件中新增了集群）
+			fingerprints = w.snapshot()
+			dirs = w.syncDirs(fsw, dirs, fingerprints)
+		}
+	}
+}
+
+func (w *Watcher) snapshot() map[string]string {
+	result := make(map[string]string)
+	for _, path := range w.paths() {
+		if path != "" {
+			result[path] = Fingerprint(path)
+		}
+	}
+	return result
+}
+
+// syncDirs 让监听的目录集合与当前文件集合保持一致
+func (w *Watcher) syncDirs(fsw *fsnotify.Watcher, watched map[string]bool, files map[string]string) map[string]bool {
+	wanted := make(map[string]bool)
+	for path := range files {
+		wanted[filepath.Dir(path)] = true
+	}
+	for dir := range watched {
+		if !wanted[dir] {
+			_ = fsw.Remove(dir)
+		}
+	}
+	result := make(map[string]bool)
+	for dir := range wanted {
+		if watched[dir] {
+			result[dir] = true
+			continue
+		}
+		if err := fsw.Add(dir); err != nil {
+			w.logger.Warn("Failed to watch directory", zap.String("dir", dir), zap.Error(err))
+			continue
+		}
+		result[dir] = true
+	}
+	return result
+}
+
+func sameFingerprints(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Fingerprint 计算文件内容摘要（跟随符号链接），路径为空或读取失败时返回空串
+func Fingerprint(path string) string {
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// ArgoCDServer 一个 ArgoCD 实例
+// APIToken 为 ArgoCD 账号的 API Token，不通过 API 返回
+type ArgoCDServer struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	URL            string    `json:"url"` // 如 https://argocd.example.com
+	APIToken       string    `json:"-"`
+	HasAPIToken    bool      `json:"hasApiToken"`
+	Cluster        string    `json:"cluster"` // ArgoCD 所在的集群，部署到 in-cluster 的 Application 关联到该集群
+	TLSSkipVerify  bool      `json:"tlsSkipVerify"`
+	TimeoutSeconds int       `json:"timeoutSeconds"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+const argocdColumns = `id, name, url, api_token, cluster, tls_skip_verify, timeout_seconds, created_at, updated_at`
+
+// ArgoCDRepository ArgoCD 实例数据访问层
+// 类比Shell: psql -c "SELECT * FROM argocd_servers"
+type ArgoCDRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewArgoCDRepository 创建 ArgoCD 实例 Repository
+func NewArgoCDRepository(pool *pgxpool.Pool) *ArgoCDRepository {
+	return &ArgoCDRepository{pool: pool}
+}
+
+// List 按名称顺序返回所有 ArgoCD 实例
+func (r *ArgoCDRepository) List(ctx context.Context) ([]ArgoCDServer, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+argocdColumns+` FROM argocd_servers ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list argocd servers: %w", err)
+	}
+	defer rows.Close()
+
+	result := []ArgoCDServer{}
+	for rows.Next() {
+		s, err := scanArgoCDServer(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// GetByID 获取指定的 ArgoCD 实例
+func (r *ArgoCDRepository) GetByID(ctx context.Context, id int64) (ArgoCDServer, error) {
+	s, err := scanArgoCDServer(r.pool.QueryRow(ctx, `SELECT `+argocdColumns+` FROM argocd_servers WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ArgoCDServer{}, response.ErrNotFound(fmt.Sprintf("ArgoCD 实例 %d 不存在", id), nil)
+	}
+	if err != nil {
+		return ArgoCDServer{}, fmt.Errorf("failed to get argocd server: %w", err)
+	}
+	return s, nil
+}
+
+// Create 创建 ArgoCD 实例
+func (r *ArgoCDRepository) Create(ctx context.Context, s ArgoCDServer) (ArgoCDServer, error) {
+	created, err := scanArgoCDServer(r.pool.QueryRow(ctx, `INSERT INTO argocd_servers
+		(name, url, api_token, cluster, tls_skip_verify, timeout_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+argocdColumns,
+		s.Name, s.URL, s.APIToken, s.Cluster, s.TLSSkipVerify, s.TimeoutSeconds))
+	if err != nil {
+		return ArgoCDServer{}, wrapArgoCDWriteError(err, s.Name)
+	}
+	return created, nil
+}
+
+// Update 更新 ArgoCD 实例
+func (r *ArgoCDRepository) Update(ctx context.Context, s ArgoCDServer) (ArgoCDServer, error) {
+	updated, err := scanArgoCDServer(r.pool.QueryRow(ctx, `UPDATE argocd_servers SET
+		name = $2, url = $3, api_token = $4, cluster = $5, tls_skip_verify = $6, timeout_seconds = $7, updated_at = now()
+		WHERE id = $1
+		RETURNING `+argocdColumns,
+		s.ID, s.Name, s.URL, s.APIToken, s.Cluster, s.TLSSkipVerify, s.TimeoutSeconds))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ArgoCDServer{}, response.ErrNotFound(fmt.Sprintf("ArgoCD 实例 %d 不存在", s.ID), nil)
+	}
+	if err != nil {
+		return ArgoCDServer{}, wrapArgoCDWriteError(err, s.Name)
+	}
+	return updated, nil
+}
+
+// Delete 删除 ArgoCD 实例
+func (r *ArgoCDRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM argocd_servers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete argocd server: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return response.ErrNotFound(fmt.Sprintf("ArgoCD 实例 %d 不存在", id), nil)
+	}
+	return nil
+}
+
+func scanArgoCDServer(row pgx.Row) (ArgoCDServer, error) {
+	var s ArgoCDServer
+	err := row.Scan(&s.ID, &s.Name, &s.URL, &s.APIToken, &s.Cluster, &s.TLSSkipVerify, &s.TimeoutSeconds,
+		&s.CreatedAt, &s.UpdatedAt)
+	s.HasAPIToken = s.APIToken != ""
+	return s, err
+}
+
+// wrapArgoCDWriteError 名称冲突返回 409，其余包装为内部错误
+func wrapArgoCDWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return response.NewError(http.StatusConflict, response.CodeAlreadyExists, fmt.Sprintf("ArgoCD 实例 %s 已存在", name), nil)
+	}
+	return fmt.Errorf("failed to save argocd server: %w", err)
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AuditLog 一条审计日志
+type AuditLog struct {
+	ID        int64           `json:"id"`
+	Username  string          `json:"username"`
+	Action    string          `json:"action"` // 如 assistant.tool_call
+	Cluster   string          `json:"cluster"`
+	Namespace string          `json:"namespace"`
+	Resource  string          `json:"resource"`
+	Detail    json.RawMessage `json:"detail"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// AuditFilter 审计日志查询条件，字段为空时不过滤
+type AuditFilter struct {
+	Username string
+	Action   string
+	Cluster  string
+	Before   int64 // 只返回 ID 小于该值的记录，用于翻页
+	Limit    int
+}
+
+// AuditRepository 审计日志数据访问层
+// 类比Shell: echo "$(date) $USER $ACTION" >> /var/log/kubeops/audit.log
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAuditRepository 创建审计日志 Repository
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+// Create 写入一条审计日志，detail 序列化为 JSON
+func (r *AuditRepository) Create(ctx context.Context, log AuditLog, detail interface{}) (AuditLog, error) {
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return AuditLog{}, fmt.Errorf("failed to encode audit detail: %w", err)
+	}
+	err = r.pool.QueryRow(ctx, `INSERT INTO audit_logs (username, action, cluster, namespace, resource, detail)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		log.Username, log.Action, log.Cluster, log.Namespace, log.Resource, data).Scan(&log.ID, &log.CreatedAt)
+	if err != nil {
+		return AuditLog{}, fmt.Errorf("failed to write audit log: %w", err)
+	}
+	log.Detail = data
+	return log, nil
+}
+
+// List 按时间倒序返回审计日志
+func (r *AuditRepository) List(ctx context.Context, f AuditFilter) ([]AuditLog, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, username, action, cluster, namespace, resource, detail, created_at
+		FROM audit_logs
+		WHERE ($1 = '' OR username = $1) AND ($2 = '' OR action = $2) AND ($3 = '' OR cluster = $3)
+		  AND ($4 = 0 OR id < $4)
+		ORDER BY id DESC LIMIT $5`, f.Username, f.Action, f.Cluster, f.Before, f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	result := []AuditLog{}
+	for rows.Next() {
+		var log AuditLog
+		if err := rows.Scan(&log.ID, &log.Username, &log.Action, &log.Cluster, &log.Namespace, &log.Resource,
+			&log.Detail, &log.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		result = append(result, log)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+)
+
+// AuthorizationRepository 通过 SubjectAccessReview 查询用户在集群中的权限
+// 类比Shell: kubectl auth can-i list pods -n $NAMESPACE --as $USER --as-group $GROUP
+type AuthorizationRepository struct {
+	clusters *client.ClusterManager
+}
+
+// NewAuthorizationRepository 创建权限查询 Repository
+func NewAuthorizationRepository(clusters *client.ClusterManager) *AuthorizationRepository {
+	return &AuthorizationRepository{
+		clusters: clusters,
+	}
+}
+
+// Review 查询用户是否可以对资源执行操作，不允许时返回 API Server 给出的原因
+func (r *AuthorizationRepository) Review(ctx context.Context, cluster, user string, groups []string, attrs authorizationv1.ResourceAttributes) (bool, string, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return false, "", err
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:               user,
+			Groups:             groups,
+			ResourceAttributes: &attrs,
+		},
+	}
+	result, err := c.Clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to review access of %s: %w", user, err)
+	}
+	return result.Status.Allowed && !result.Status.Denied, result.Status.Reason, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// Datasource 一个 Prometheus 兼容的监控数据源
+// Secret 为 basic 认证的密码或 bearer Token，不通过 API 返回
+type Datasource struct {
+	ID             int64  `json:"id"`
+	Cluster        string `json:"cluster"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`     // prometheus 或 victoriametrics
+	URL            string `json:"url"`      // 如 http://prometheus.monitoring:9090
+	AuthType       string `json:"authType"` // none、basic 或 bearer
+	Username       string `json:"username,omitempty"`
+	Secret         string `json:"-"`
+	HasSecret      bool   `json:"hasSecret"`
+	TLSSkipVerify  bool   `json:"tlsSkipVerify"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+	NamespaceLabel string `json:"namespaceLabel"` // 命名空间范围查询时注入的标签名
+	// AllowClusterQueries 是否允许不指定命名空间的集群范围查询
+	AllowClusterQueries bool      `json:"allowClusterQueries"`
+	IsDefault           bool      `json:"isDefault"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+const datasourceColumns = `id, cluster, name, type, url, auth_type, username, secret, tls_skip_verify,
+	timeout_seconds, namespace_label, allow_cluster_queries, is_default, created_at, updated_at`
+
+// DatasourceRepository 监控数据源数据访问层
+// 类比Shell: psql -c "SELECT * FROM datasources WHERE cluster = '$CLUSTER'"
+type DatasourceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDatasourceRepository 创建数据源 Repository
+func NewDatasourceRepository(pool *pgxpool.Pool) *DatasourceRepository {
+	return &DatasourceRepository{pool: pool}
+}
+
+// ListByCluster 按名称顺序返回集群的所有数据源
+func (r *DatasourceRepository) ListByCluster(ctx context.Context, cluster string) ([]Datasource, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+datasourceColumns+` FROM datasources WHERE cluster = $1 ORDER BY name`, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datasources: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Datasource
+	for rows.Next() {
+		ds, err := scanDatasource(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ds)
+	}
+	return result, rows.Err()
+}
+
+// GetByID 获取集群中的指定数据源
+func (r *DatasourceRepository) GetByID(ctx context.Context, cluster string, id int64) (Datasource, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+datasourceColumns+` FROM datasources WHERE cluster = $1 AND id = $2`, cluster, id)
+	return scanOne(row, fmt.Sprintf("数据源 %d 不存在", id))
+}
+
+// GetDefault 获取集群的默认数据源；没有标记默认时返回最早创建的数据源
+func (r *DatasourceRepository) GetDefault(ctx context.Context, cluster string) (Datasource, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+datasourceColumns+` FROM datasources WHERE cluster = $1
+		ORDER BY is_default DESC, id LIMIT 1`, cluster)
+	return scanOne(row, fmt.Sprintf("集群 %s 未配置监控数据源", cluster))
+}
+
+// Create 创建数据源，标记为默认时取消集群原有的默认数据源
+func (r *DatasourceRepository) Create(ctx context.Context, ds Datasource) (Datasource, error) {
+	var created Datasource
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := clearDefault(ctx, tx, ds); err != nil {
+			return err
+		}
+		row := tx.QueryRow(ctx, `INSERT INTO datasources
+			(cluster, name, type, url, auth_type, username, secret, tls_skip_verify, timeout_seconds, namespace_label,
+			allow_cluster_queries, is_default)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING `+datasourceColumns,
+			ds.Cluster, ds.Name, ds.Type, ds.URL, ds.AuthType, ds.Username, ds.Secret,
+			ds.TLSSkipVerify, ds.TimeoutSeconds, ds.NamespaceLabel, ds.AllowClusterQueries, ds.IsDefault)
+		var err error
+		created, err = scanDatasource(row)
+		return err
+	})
+	if err != nil {
+		return Datasource{}, wrapWriteError(err, ds.Name)
+	}
+	return created, nil
+}
+
+// Update 更新数据源
+func (r *DatasourceRepository) Update(ctx context.Context, ds Datasource) (Datasource, error) {
+	var updated Datasource
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := clearDefault(ctx, tx, ds); err != nil {
+			return err
+		}
+		row := tx.QueryRow(ctx, `UPDATE datasources SET
+			name = $3, type = $4, url = $5, auth_type = $6, username = $7, secret = $8, tls_skip_verify = $9,
+			timeout_seconds = $10, namespace_label = $11, allow_cluster_queries = $12, is_default = $13, updated_at = now()
+			WHERE cluster = $1 AND id = $2
+			RETURNING `+datasourceColumns,
+			ds.Cluster, ds.ID, ds.Name, ds.Type, ds.URL, ds.AuthType, ds.Username, ds.Secret,
+			ds.TLSSkipVerify, ds.TimeoutSeconds, ds.NamespaceLabel, ds.AllowClusterQueries, ds.IsDefault)
+		var err error
+		updated, err = scanDatasource(row)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Datasource{}, response.ErrNotFound(fmt.Sprintf("数据源 %d 不存在", ds.ID), nil)
+	}
+	if err != nil {
+		return Datasource{}, wrapWriteError(err, ds.Name)
+	}
+	return updated, nil
+}
+
+// Delete 删除数据源
+func (r *DatasourceRepository) Delete(ctx context.Context, cluster string, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM datasources WHERE cluster = $1 AND id = $2`, cluster, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete datasource: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return response.ErrNotFound(fmt.Sprintf("数据源 %d 不存在", id), nil)
+	}
+	return nil
+}
+
+func scanOne(row pgx.Row, notFound string) (Datasource, error) {
+	ds, err := scanDatasource(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Datasource{}, response.ErrNotFound(notFound, nil)
+	}
+	return ds, err
+}
+
+func clearDefault(ctx context.Context, tx pgx.Tx, ds Datasource) error {
+	if !ds.IsDefault {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `UPDATE datasources SET is_default = false WHERE cluster = $1 AND id <> $2 AND is_default`, ds.Cluster, ds.ID)
+	return err
+}
+
+func scanDatasource(row pgx.Row) (Datasource, error) {
+	var ds Datasource
+	err := row.Scan(&ds.ID, &ds.Cluster, &ds.Name, &ds.Type, &ds.URL, &ds.AuthType, &ds.Username, &ds.Secret,
+		&ds.TLSSkipVerify, &ds.TimeoutSeconds, &ds.NamespaceLabel, &ds.AllowClusterQueries, &ds.IsDefault, &ds.CreatedAt, &ds.UpdatedAt)
+	ds.HasSecret = ds.Secret != ""
+	return ds, err
+}
+
+// wrapWriteError 唯一约束冲突返回 409，其余包装为内部错误
+func wrapWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return response.NewError(http.StatusConflict, response.CodeAlreadyExists, fmt.Sprintf("数据源 %s 已存在", name), nil)
+	}
+	return fmt.Errorf("failed to save datasource: %w", err)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+)
+
+// DeploymentRepository Deployment数据访问层
+// 类比Shell函数：get_deployments() { kubectl get deployments -n $NAMESPACE -o json; }
+type DeploymentRepository struct {
+	clusters *client.ClusterManager
+}
+
+// NewDeploymentRepository 创建Deployment Repository
+func NewDeploymentRepository(clusters *client.ClusterManager) *DeploymentRepository {
+	return &DeploymentRepository{
+		clusters: clusters,
+	}
+}
+
+// ListByNamespace 获取指定命名空间的所有Deployment，namespace 为空时获取所有命名空间
+// 对应Shell: kubectl get deployments -n $NAMESPACE -o json
+func (r *DeploymentRepository) ListByNamespace(ctx context.Context, cluster, namespace string) ([]appsv1.Deployment, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	return list.Items, nil
+}
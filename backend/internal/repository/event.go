@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+)
+
+// EventRepository 事件数据访问层
+// 类比Shell函数：get_events() { kubectl get events -n $NAMESPACE --field-selector involvedObject.name=$NAME; }
+type EventRepository struct {
+	clusters *client.ClusterManager
+}
+
+// NewEventRepository 创建事件 Repository
+func NewEventRepository(clusters *client.ClusterManager) *EventRepository {
+	return &EventRepository{
+		clusters: clusters,
+	}
+}
+
+// ListForObject 获取与指定资源相关的事件
+// 对应Shell: kubectl get events -n $NAMESPACE --field-selector involvedObject.kind=$KIND,involvedObject.name=$NAME
+func (r *EventRepository) ListForObject(ctx context.Context, cluster, namespace, kind, name string) ([]corev1.Event, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	selector := fields.Set{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+	}.AsSelector().String()
+	list, err := c.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events for %s %s in namespace %s: %w", kind, name, namespace, err)
+	}
+	return list.Items, nil
+}
+
+// List 按字段选择器获取事件，namespace 为空表示所有命名空间
+// 对应Shell: kubectl get events -n $NAMESPACE --field-selector $FIELD_SELECTOR
+func (r *EventRepository) List(ctx context.Context, cluster, namespace, fieldSelector string) ([]corev1.Event, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events in namespace %s: %w", namespace, err)
+	}
+	return list.Items, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Exec 在容器中执行命令并连接标准输入输出，命令以非 0 退出码结束时返回 k8s.io/client-go/util/exec.CodeExitError
+// 对应Shell: kubectl exec -i $NAME -n $NAMESPACE -c $CONTAINER -- $COMMAND
+func (r *PodRepository) Exec(ctx context.Context, cluster, namespace, name, container string, command []string,
+	stdin io.Reader, stdout, stderr io.Writer) error {
+	return r.stream(ctx, cluster, namespace, name, "exec", &corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     stdin != nil,
+		Stdout:    stdout != nil,
+		Stderr:    stderr != nil,
+	}, remotecommand.StreamOptions{Stdin: stdin, Stdout: stdout, Stderr: stderr})
+}
+
+// ExecTerminal 在容器中执行命令并分配 TTY，终端大小从 streams.TerminalSizeQueue 读取
+// 对应Shell: kubectl exec -it $NAME -n $NAMESPACE -c $CONTAINER -- $COMMAND
+func (r *PodRepository) ExecTerminal(ctx context.Context, cluster, namespace, name, container string, command []string,
+	streams remotecommand.StreamOptions) error {
+	streams.Tty = true
+	return r.stream(ctx, cluster, namespace, name, "exec", &corev1.PodExecOptions{
+		Container: container,
+		Command:   command,
+		Stdin:     streams.Stdin != nil,
+		Stdout:    true,
+		TTY:       true,
+	}, streams)
+}
+
+// AttachTerminal 连接到容器主进程的 TTY，容器需要以 stdin 和 tty 启动（如临时调试容器）
+// 对应Shell: kubectl attach -it $NAME -n $NAMESPACE -c $CONTAINER
+func (r *PodRepository) AttachTerminal(ctx context.Context, cluster, namespace, name, container string,
+	streams remotecommand.StreamOptions) error {
+	streams.Tty = true
+	return r.stream(ctx, cluster, namespace, name, "attach", &corev1.PodAttachOptions{
+		Container: container,
+		Stdin:     streams.Stdin != nil,
+		Stdout:    true,
+		TTY:       true,
+	}, streams)
+}
+
+// stream 连接 exec 或 attach 子资源；与 kubectl 一致，优先使用 WebSocket，API Server 不支持时回退到 SPDY
+func (r *PodRepository) stream(ctx context.Context, cluster, namespace, name, subresource string, opts runtime.Object,
+	streams remotecommand.StreamOptions) error {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return err
+	}
+	u := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(namespace).Name(name).SubResource(subresource).
+		VersionedParams(opts, scheme.ParameterCodec).URL()
+
+	spdyExec, err := remotecommand.NewSPDYExecutor(c.Config, http.MethodPost, u)
+	if err != nil {
+		return fmt.Errorf("failed to create spdy executor: %w", err)
+	}
+	wsExec, err := remotecommand.NewWebSocketExecutor(c.Config, http.MethodGet, u.String())
+	if err != nil {
+		return fmt.Errorf("failed to create websocket executor: %w", err)
+	}
+	executor, err := remotecommand.NewFallbackExecutor(wsExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	if err := executor.StreamWithContext(ctx, streams); err != nil {
+		return fmt.Errorf("failed to %s pod %s in namespace %s: %w", subresource, name, namespace, err)
+	}
+	return nil
+}
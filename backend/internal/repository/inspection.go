@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yansongwel/kubeops/backend/internal/inspection"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// 巡检触发方式
+const (
+	InspectionTriggerManual   = "manual"
+	InspectionTriggerSchedule = "schedule"
+)
+
+// InspectionRun 一次巡检的记录，Summary 不含已确认或屏蔽的问题
+type InspectionRun struct {
+	ID          int64                   `json:"id"`
+	Cluster     string                  `json:"cluster"`
+	Trigger     string                  `json:"trigger"`
+	ScheduleID  *int64                  `json:"scheduleId,omitempty"`
+	ScopeKey    string                  `json:"-"`
+	Scope       inspection.Scope        `json:"scope"`
+	PreviousID  *int64                  `json:"previousId,omitempty"` // 范围相同的上一次巡检
+	StartedAt   time.Time               `json:"startedAt"`
+	DurationMs  int64                   `json:"durationMs"`
+	Summary     inspection.Summary      `json:"summary"`
+	Objects     inspection.ObjectCounts `json:"objects"`
+	Errors      []inspection.CheckError `json:"errors,omitempty"`
+	New         int                     `json:"new"`      // 相比上一次新出现的问题数
+	Resolved    int                     `json:"resolved"` // 相比上一次已消失的问题数
+	RequestedBy string                  `json:"requestedBy"`
+	CreatedAt   time.Time               `json:"createdAt"`
+}
+
+// InspectionFinding 巡检记录中的一个问题
+// New 表示该问题未被确认或屏蔽，且在上一次巡检中没有出现或已被确认、屏蔽；SuppressedBy 为生效的确认或屏蔽规则 ID
+type InspectionFinding struct {
+	inspection.Finding
+	Fingerprint  string `json:"fingerprint"`
+	New          bool   `json:"new"`
+	SuppressedBy *int64 `json:"suppressedBy,omitempty"`
+}
+
+const inspectionRunColumns = `id, cluster, trigger, schedule_id, scope_key, request, previous_id, started_at, duration_ms,
+	summary, objects, errors, new_count, resolved, requested_by, created_at`
+
+// InspectionRepository 巡检记录数据访问层
+// 类比Shell: psql -c "SELECT * FROM inspection_runs WHERE cluster = '$CLUSTER' ORDER BY id DESC"
+type InspectionRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewInspectionRepository 创建巡检 Repository
+func NewInspectionRepository(pool *pgxpool.Pool) *InspectionRepository {
+	return &InspectionRepository{pool: pool}
+}
+
+// LatestRun 获取集群中范围相同的最近一次巡检，没有时返回 nil
+func (r *InspectionRepository) LatestRun(ctx context.Context, cluster, scopeKey string) (*InspectionRun, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+inspectionRunColumns+` FROM inspection_runs
+		WHERE cluster = $1 AND scope_key = $2 ORDER BY id DESC LIMIT 1`, cluster, scopeKey)
+	run, err := scanInspectionRun(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest inspection run: %w", err)
+	}
+	return &run, nil
+}
+
+// CreateRun 在一个事务中保存巡检记录及其问题
+func (r *InspectionRepository) CreateRun(ctx context.Context, run InspectionRun, findings []InspectionFinding) (InspectionRun, error) {
+	scope, err := json.Marshal(run.Scope)
+	if err != nil {
+		return InspectionRun{}, fmt.Errorf("failed to encode inspection scope: %w", err)
+	}
+	if run.Errors == nil {
+		run.Errors = []inspection.CheckError{}
+	}
+
+	var created InspectionRun
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, `INSERT INTO inspection_runs
+			(cluster, trigger, schedule_id, scope_key, request, previous_id, started_at, duration_ms,
+			 summary, objects, errors, new_count, resolved, requested_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING `+inspectionRunColumns,
+			run.Cluster, run.Trigger, run.ScheduleID, run.ScopeKey, scope, run.PreviousID, run.StartedAt, run.DurationMs,
+			run.Summary, run.Objects, run.Errors, run.New, run.Resolved, run.RequestedBy)
+		var err error
+		if created, err = scanInspectionRun(row); err != nil {
+			return err
+		}
+
+		rows := make([][]interface{}, 0, len(findings))
+		for _, f := range findings {
+			rows = append(rows, []interface{}{
+				created.ID, f.Fingerprint, f.Check, string(f.Severity), f.Object.Kind, f.Object.Namespace, f.Object.Name,
+				f.Object.Container, f.Message, f.Remediation, f.New, f.SuppressedBy,
+			})
+		}
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"inspection_findings"},
+			[]string{"run_id", "fingerprint", "check_id", "severity", "kind", "namespace", "name",
+				"container", "message", "remediation", "is_new", "suppressed_by"},
+			pgx.CopyFromRows(rows))
+		return err
+	})
+	if err != nil {
+		return InspectionRun{}, fmt.Errorf("failed to save inspection run: %w", err)
+	}
+	return created, nil
+}
+
+// ListRuns 按时间倒序返回集群的巡检记录，scheduleID 非 0 时只返回该定时任务的记录
+func (r *InspectionRepository) ListRuns(ctx context.Context, cluster string, scheduleID int64, limit int) ([]InspectionRun, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+inspectionRunColumns+` FROM inspection_runs
+		WHERE cluster = $1 AND ($2 = 0 OR schedule_id = $2) ORDER BY id DESC LIMIT $3`, cluster, scheduleID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inspection runs: %w", err)
+	}
+	defer rows.Close()
+
+	result := []InspectionRun{}
+	for rows.Next() {
+		run, err := scanInspectionRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inspection run: %w", err)
+		}
+		result = append(result, run)
+	}
+	return result, rows.Err()
+}
+
+// GetRun 获取集群中的指定巡检记录
+func (r *InspectionRepository) GetRun(ctx context.Context, cluster string, id int64) (InspectionRun, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+inspectionRunColumns+` FROM inspection_runs WHERE cluster = $1 AND id = $2`, cluster, id)
+	run, err := scanInspectionRun(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return InspectionRun{}, response.ErrNotFound(fmt.Sprintf("巡检记录 %d 不存在", id), nil)
+	}
+	if err != nil {
+		return InspectionRun{}, fmt.Errorf("failed to get inspection run: %w", err)
+	}
+	return run, nil
+}
+
+// ListFindings 返回巡检记录中的所有问题，按严重程度排序
+func (r *InspectionRepository) ListFindings(ctx context.Context, runID int64) ([]InspectionFinding, error) {
+	rows, err := r.pool.Query(ctx, `SELECT fingerprint, check_id, severity, kind, namespace, name, container,
+		message, remediation, is_new, suppressed_by
+		FROM inspection_findings WHERE run_id = $1
+		ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 ELSE 2 END, check_id, namespace, name, container`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inspection findings: %w", err)
+	}
+	defer rows.Close()
+
+	result := []InspectionFinding{}
+	for rows.Next() {
+		var f InspectionFinding
+		if err := rows.Scan(&f.Fingerprint, &f.Check, &f.Severity, &f.Object.Kind, &f.Object.Namespace, &f.Object.Name,
+			&f.Object.Container, &f.Message, &f.Remediation, &f.New, &f.SuppressedBy); err != nil {
+			return nil, fmt.Errorf("failed to scan inspection finding: %w", err)
+		}
+		result = append(result, f)
+	}
+	return result, rows.Err()
+}
+
+// DeleteRunsBefore 删除早于 before 的巡检记录（问题级联删除），返回删除的记录数
+func (r *InspectionRepository) DeleteRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM inspection_runs WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete inspection runs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanInspectionRun(row pgx.Row) (InspectionRun, error) {
+	var run InspectionRun
+	var scope []byte
+	err := row.Scan(&run.ID, &run.Cluster, &run.Trigger, &run.ScheduleID, &run.ScopeKey, &scope, &run.PreviousID,
+		&run.StartedAt, &run.DurationMs, &run.Summary, &run.Objects, &run.Errors, &run.New, &run.Resolved,
+		&run.RequestedBy, &run.CreatedAt)
+	if err != nil {
+		return run, err
+	}
+	if err := json.Unmarshal(scope, &run.Scope); err != nil {
+		return run, fmt.Errorf("failed to decode inspection scope: %w", err)
+	}
+	return run, nil
+}
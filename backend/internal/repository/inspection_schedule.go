@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/yansongwel/kubeops/backend/internal/inspection"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// InspectionSchedule 定时巡检
+type InspectionSchedule struct {
+	ID        int64            `json:"id"`
+	Cluster   string           `json:"cluster"`
+	Name      string           `json:"name"`
+	Cron      string           `json:"cron"` // 标准 5 段 cron 表达式或 @hourly、@daily 等描述符
+	Scope     inspection.Scope `json:"scope"`
+	Enabled   bool             `json:"enabled"`
+	LastRunAt *time.Time       `json:"lastRunAt,omitempty"`
+	LastRunID *int64           `json:"lastRunId,omitempty"`
+	CreatedBy string           `json:"createdBy"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+}
+
+const inspectionScheduleColumns = `id, cluster, name, cron, request, enabled, last_run_at, last_run_id, created_by, created_at, updated_at`
+
+// ListSchedules 返回集群的定时巡检，cluster 为空时返回所有集群的
+func (r *InspectionRepository) ListSchedules(ctx context.Context, cluster string) ([]InspectionSchedule, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+inspectionScheduleColumns+` FROM inspection_schedules
+		WHERE $1 = '' OR cluster = $1 ORDER BY cluster, name`, cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inspection schedules: %w", err)
+	}
+	defer rows.Close()
+
+	result := []InspectionSchedule{}
+	for rows.Next() {
+		s, err := scanInspectionSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inspection schedule: %w", err)
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// GetSchedule 获取集群中的指定定时巡检
+func (r *InspectionRepository) GetSchedule(ctx context.Context, cluster string, id int64) (InspectionSchedule, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+inspectionScheduleColumns+` FROM inspection_schedules WHERE cluster = $1 AND id = $2`, cluster, id)
+	return scanOneSchedule(row, id)
+}
+
+// CreateSchedule 创建定时巡检
+func (r *InspectionRepository) CreateSchedule(ctx context.Context, s InspectionSchedule) (InspectionSchedule, error) {
+	scope, err := json.Marshal(s.Scope)
+	if err != nil {
+		return InspectionSchedule{}, fmt.Errorf("failed to encode inspection scope: %w", err)
+	}
+	row := r.pool.QueryRow(ctx, `INSERT INTO inspection_schedules (cluster, name, cron, request, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING `+inspectionScheduleColumns,
+		s.Cluster, s.Name, s.Cron, scope, s.Enabled, s.CreatedBy)
+	created, err := scanInspectionSchedule(row)
+	if err != nil {
+		return InspectionSchedule{}, wrapScheduleWriteError(err, s.Name)
+	}
+	return created, nil
+}
+
+// UpdateSchedule 更新定时巡检的名称、cron、范围和启用状态
+func (r *InspectionRepository) UpdateSchedule(ctx context.Context, s InspectionSchedule) (InspectionSchedule, error) {
+	scope, err := json.Marshal(s.Scope)
+	if err != nil {
+		return InspectionSchedule{}, fmt.Errorf("failed to encode inspection scope: %w", err)
+	}
+	row := r.pool.QueryRow(ctx, `UPDATE inspection_schedules SET
+		name = $3, cron = $4, request = $5, enabled = $6, updated_at = now()
+		WHERE cluster = $1 AND id = $2 RETURNING `+inspectionScheduleColumns,
+		s.Cluster, s.ID, s.Name, s.Cron, scope, s.Enabled)
+	updated, err := scanInspectionSchedule(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return InspectionSchedule{}, response.ErrNotFound(fmt.Sprintf("定时巡检 %d 不存在", s.ID), nil)
+	}
+	if err != nil {
+		return InspectionSchedule{}, wrapScheduleWriteError(err, s.Name)
+	}
+	return updated, nil
+}
+
+// DeleteSchedule 删除定时巡检，已有的巡检记录保留
+func (r *InspectionRepository) DeleteSchedule(ctx context.Context, cluster string, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM inspection_schedules WHERE cluster = $1 AND id = $2`, cluster, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete inspection schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return response.ErrNotFound(fmt.Sprintf("定时巡检 %d 不存在", id), nil)
+	}
+	return nil
+}
+
+// MarkScheduleRun 记录定时巡检的最近一次执行
+func (r *InspectionRepository) MarkScheduleRun(ctx context.Context, id int64, at time.Time, runID *int64) error {
+	_, err := r.pool.Exec(ctx, `UPDATE inspection_schedules SET last_run_at = $2, last_run_id = COALESCE($3, last_run_id)
+		WHERE id = $1`, id, at, runID)
+	if err != nil {
+		return fmt.Errorf("failed to update inspection schedule: %w", err)
+	}
+	return nil
+}
+
+func scanOneSchedule(row pgx.Row, id int64) (InspectionSchedule, error) {
+	s, err := scanInspectionSchedule(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return InspectionSchedule{}, response.ErrNotFound(fmt.Sprintf("定时巡检 %d 不存在", id), nil)
+	}
+	if err != nil {
+		return InspectionSchedule{}, fmt.Errorf("failed to get inspection schedule: %w", err)
+	}
+	return s, nil
+}
+
+func scanInspectionSchedule(row pgx.Row) (InspectionSchedule, error) {
+	var s InspectionSchedule
+	var scope []byte
+	err := row.Scan(&s.ID, &s.Cluster, &s.Name, &s.Cron, &scope, &s.Enabled, &s.LastRunAt, &s.LastRunID,
+		&s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(scope, &s.Scope); err != nil {
+		return s, fmt.Errorf("failed to decode inspection scope: %w", err)
+	}
+	return s, nil
+}
+
+// wrapScheduleWriteError 同一集群中名称重复返回 409
+func wrapScheduleWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return response.NewError(http.StatusConflict, response.CodeAlreadyExists, fmt.Sprintf("定时巡检 %s 已存在", name), nil)
+	}
+	return fmt.Errorf("failed to save inspection schedule: %w", err)
+}
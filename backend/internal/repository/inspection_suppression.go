@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// 确认和屏蔽
+const (
+	SuppressionTypeAck      = "ack"      // 确认某个具体问题（按 fingerprint）
+	SuppressionTypeSuppress = "suppress" // 屏蔽一类问题（按检查项、命名空间、资源名）
+)
+
+// InspectionSuppression 巡检问题的确认或屏蔽规则，ExpiresAt 为空表示永久有效
+// suppress 类型中 Namespace、Name 为空表示匹配任意值
+type InspectionSuppression struct {
+	ID          int64      `json:"id"`
+	Cluster     string     `json:"cluster"`
+	Type        string     `json:"type"`
+	Fingerprint string     `json:"fingerprint,omitempty"`
+	Check       string     `json:"check,omitempty"`
+	Namespace   string     `json:"namespace,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Reason      string     `json:"reason"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	CreatedBy   string     `json:"createdBy"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+const inspectionSuppressionColumns = `id, cluster, type, fingerprint, check_id, namespace, name, reason, expires_at, created_by, created_at`
+
+// ListSuppressions 返回集群的确认和屏蔽规则，activeAt 非零时只返回在该时间仍有效的
+func (r *InspectionRepository) ListSuppressions(ctx context.Context, cluster string, activeAt time.Time) ([]InspectionSuppression, error) {
+	var expiresAfter *time.Time
+	if !activeAt.IsZero() {
+		expiresAfter = &activeAt
+	}
+	rows, err := r.pool.Query(ctx, `SELECT `+inspectionSuppressionColumns+` FROM inspection_suppressions
+		WHERE cluster = $1 AND ($2::timestamptz IS NULL OR expires_at IS NULL OR expires_at > $2)
+		ORDER BY id DESC`, cluster, expiresAfter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inspection suppressions: %w", err)
+	}
+	defer rows.Close()
+
+	result := []InspectionSuppression{}
+	for rows.Next() {
+		var s InspectionSuppression
+		if err := rows.Scan(&s.ID, &s.Cluster, &s.Type, &s.Fingerprint, &s.Check, &s.Namespace, &s.Name,
+			&s.Reason, &s.ExpiresAt, &s.CreatedBy, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan inspection suppression: %w", err)
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// CreateSuppression 创建确认或屏蔽规则
+func (r *InspectionRepository) CreateSuppression(ctx context.Context, s InspectionSuppression) (InspectionSuppression, error) {
+	row := r.pool.QueryRow(ctx, `INSERT INTO inspection_suppressions
+		(cluster, type, fingerprint, check_id, namespace, name, reason, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING `+inspectionSuppressionColumns,
+		s.Cluster, s.Type, s.Fingerprint, s.Check, s.Namespace, s.Name, s.Reason, s.ExpiresAt, s.CreatedBy)
+	var created InspectionSuppression
+	if err := row.Scan(&created.ID, &created.Cluster, &created.Type, &created.Fingerprint, &created.Check,
+		&created.Namespace, &created.Name, &created.Reason, &created.ExpiresAt, &created.CreatedBy, &created.CreatedAt); err != nil {
+		return InspectionSuppression{}, fmt.Errorf("failed to save inspection suppression: %w", err)
+	}
+	return created, nil
+}
+
+// DeleteSuppression 删除确认或屏蔽规则
+func (r *InspectionRepository) DeleteSuppression(ctx context.Context, cluster string, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM inspection_suppressions WHERE cluster = $1 AND id = $2`, cluster, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete inspection suppression: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return response.ErrNotFound(fmt.Sprintf("确认或屏蔽规则 %d 不存在", id), nil)
+	}
+	return nil
+}
+
+// DeleteExpiredSuppressions 删除在 before 之前已过期的确认和屏蔽规则
+func (r *InspectionRepository) DeleteExpiredSuppressions(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM inspection_suppressions WHERE expires_at IS NOT NULL AND expires_at <= $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired inspection suppressions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
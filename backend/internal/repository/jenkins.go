@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// JenkinsServer 一个 Jenkins 服务器
+// APIToken 为用户的 API Token（也可以是密码），不通过 API 返回
+type JenkinsServer struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	URL            string    `json:"url"` // 如 https://jenkins.example.com
+	Username       string    `json:"username"`
+	APIToken       string    `json:"-"`
+	HasAPIToken    bool      `json:"hasApiToken"`
+	TLSSkipVerify  bool      `json:"tlsSkipVerify"`
+	TimeoutSeconds int       `json:"timeoutSeconds"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+const jenkinsColumns = `id, name, url, username, api_token, tls_skip_verify, timeout_seconds, created_at, updated_at`
+
+// JenkinsRepository Jenkins 服务器数据访问层
+// 类比Shell: psql -c "SELECT * FROM jenkins_servers"
+type JenkinsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewJenkinsRepository 创建 Jenkins 服务器 Repository
+func NewJenkinsRepository(pool *pgxpool.Pool) *JenkinsRepository {
+	return &JenkinsRepository{pool: pool}
+}
+
+// List 按名称顺序返回所有 Jenkins 服务器
+func (r *JenkinsRepository) List(ctx context.Context) ([]JenkinsServer, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+jenkinsColumns+` FROM jenkins_servers ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jenkins servers: %w", err)
+	}
+	defer rows.Close()
+
+	result := []JenkinsServer{}
+	for rows.Next() {
+		s, err := scanJenkinsServer(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// GetByID 获取指定的 Jenkins 服务器
+func (r *JenkinsRepository) GetByID(ctx context.Context, id int64) (JenkinsServer, error) {
+	s, err := scanJenkinsServer(r.pool.QueryRow(ctx, `SELECT `+jenkinsColumns+` FROM jenkins_servers WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return JenkinsServer{}, response.ErrNotFound(fmt.Sprintf("Jenkins 服务器 %d 不存在", id), nil)
+	}
+	if err != nil {
+		return JenkinsServer{}, fmt.Errorf("failed to get jenkins server: %w", err)
+	}
+	return s, nil
+}
+
+// Create 创建 Jenkins 服务器
+func (r *JenkinsRepository) Create(ctx context.Context, s JenkinsServer) (JenkinsServer, error) {
+	created, err := scanJenkinsServer(r.pool.QueryRow(ctx, `INSERT INTO jenkins_servers
+		(name, url, username, api_token, tls_skip_verify, timeout_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+jenkinsColumns,
+		s.Name, s.URL, s.Username, s.APIToken, s.TLSSkipVerify, s.TimeoutSeconds))
+	if err != nil {
+		return JenkinsServer{}, wrapJenkinsWriteError(err, s.Name)
+	}
+	return created, nil
+}
+
+// Update 更新 Jenkins 服务器
+func (r *JenkinsRepository) Update(ctx context.Context, s JenkinsServer) (JenkinsServer, error) {
+	updated, err := scanJenkinsServer(r.pool.QueryRow(ctx, `UPDATE jenkins_servers SET
+		name = $2, url = $3, username = $4, api_token = $5, tls_skip_verify = $6, timeout_seconds = $7, updated_at = now()
+		WHERE id = $1
+		RETURNING `+jenkinsColumns,
+		s.ID, s.Name, s.URL, s.Username, s.APIToken, s.TLSSkipVerify, s.TimeoutSeconds))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return JenkinsServer{}, response.ErrNotFound(fmt.Sprintf("Jenkins 服务器 %d 不存在", s.ID), nil)
+	}
+	if err != nil {
+		return JenkinsServer{}, wrapJenkinsWriteError(err, s.Name)
+	}
+	return updated, nil
+}
+
+// Delete 删除 Jenkins 服务器
+func (r *JenkinsRepository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM jenkins_servers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete jenkins server: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return response.ErrNotFound(fmt.Sprintf("Jenkins 服务器 %d 不存在", id), nil)
+	}
+	return nil
+}
+
+func scanJenkinsServer(row pgx.Row) (JenkinsServer, error) {
+	var s JenkinsServer
+	err := row.Scan(&s.ID, &s.Name, &s.URL, &s.Username, &s.APIToken, &s.TLSSkipVerify, &s.TimeoutSeconds,
+		&s.CreatedAt, &s.UpdatedAt)
+	s.HasAPIToken = s.APIToken != ""
+	return s, err
+}
+
+// wrapJenkinsWriteError 名称冲突返回 409，其余包装为内部错误
+func wrapJenkinsWriteError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return response.NewError(http.StatusConflict, response.CodeAlreadyExists, fmt.Sprintf("Jenkins 服务器 %s 已存在", name), nil)
+	}
+	return fmt.Errorf("failed to save jenkins server: %w", err)
+}
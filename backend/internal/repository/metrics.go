@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+)
+
+// MetricsRepository metrics.k8s.io 资源用量数据访问层（由 metrics-server 提供）
+// 类比Shell函数：get_pod_usage() { kubectl top pods -n $NAMESPACE --containers; }
+type MetricsRepository struct {
+	clusters *client.ClusterManager
+}
+
+// NewMetricsRepository 创建 Metrics Repository
+func NewMetricsRepository(clusters *client.ClusterManager) *MetricsRepository {
+	return &MetricsRepository{
+		clusters: clusters,
+	}
+}
+
+// ListPodMetrics 获取命名空间中所有 Pod 的用量，namespace 为空时获取所有命名空间
+// 对应Shell: kubectl top pods -n $NAMESPACE --containers
+func (r *MetricsRepository) ListPodMetrics(ctx context.Context, cluster, namespace string) ([]metricsv1beta1.PodMetrics, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Metrics.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod metrics: %w", err)
+	}
+	return list.Items, nil
+}
+
+// GetPodMetrics 获取单个 Pod 的用量
+// 对应Shell: kubectl top pod $NAME -n $NAMESPACE --containers
+func (r *MetricsRepository) GetPodMetrics(ctx context.Context, cluster, namespace, name string) (*metricsv1beta1.PodMetrics, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.Metrics.MetricsV1beta1().PodMetricses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics of pod %s in namespace %s: %w", name, namespace, err)
+	}
+	return m, nil
+}
+
+// ListNodeMetrics 获取所有节点的用量
+// 对应Shell: kubectl top nodes
+func (r *MetricsRepository) ListNodeMetrics(ctx context.Context, cluster string) ([]metricsv1beta1.NodeMetrics, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Metrics.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node metrics: %w", err)
+	}
+	return list.Items, nil
+}
+
+// GetNodeMetrics 获取单个节点的用量
+// 对应Shell: kubectl top node $NAME
+func (r *MetricsRepository) GetNodeMetrics(ctx context.Context, cluster, name string) (*metricsv1beta1.NodeMetrics, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	m, err := c.Metrics.MetricsV1beta1().NodeMetricses().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics of node %s: %w", name, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+)
+
+// NodeRepository 节点数据访问层
+// 类比Shell函数：get_nodes() { kubectl get nodes -o json; }
+type NodeRepository struct {
+	clusters *client.ClusterManager
+}
+
+// NewNodeRepository 创建节点 Repository
+func NewNodeRepository(clusters *client.ClusterManager) *NodeRepository {
+	return &NodeRepository{
+		clusters: clusters,
+	}
+}
+
+// List 获取所有节点
+// 对应Shell: kubectl get nodes -o json
+func (r *NodeRepository) List(ctx context.Context, cluster string) ([]corev1.Node, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	return list.Items, nil
+}
+
+// GetByName 获取单个节点
+// 对应Shell: kubectl get node $NAME -o json
+func (r *NodeRepository) GetByName(ctx context.Context, cluster, name string) (*corev1.Node, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	node, err := c.Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %w", name, err)
+	}
+	return node, nil
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/yansongwel/kubeops/backend/internal/notify"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// NotificationChannel 一个通知渠道
+// Secret 为钉钉、飞书的加签密钥，Webhook 的签名密钥或 SMTP 密码，不通过 API 返回
+type NotificationChannel struct {
+	ID        int64                `json:"id"`
+	Name      string               `json:"name"`
+	Type      string               `json:"type"` // webhook、slack、dingtalk、feishu 或 email
+	Config    notify.ChannelConfig `json:"config"`
+	Secret    string               `json:"-"`
+	HasSecret bool                 `json:"hasSecret"`
+	Template  string               `json:"template"` // 为空时使用内置模板
+	Enabled   bool                 `json:"enabled"`
+	CreatedAt time.Time            `json:"createdAt"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+// NotificationRule 路由规则：事件满足所有条件时发送到 ChannelID，条件为空表示不限制
+type NotificationRule struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	ChannelID  int64     `json:"channelId"`
+	Clusters   []string  `json:"clusters"`   // 支持 * 通配符
+	Namespaces []string  `json:"namespaces"` // 支持 * 通配符；非空时不匹配节点等集群级别的事件
+	Severities []string  `json:"severities"`
+	EventTypes []string  `json:"eventTypes"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+const (
+	notificationChannelColumns = `id, name, type, config, secret, template, enabled, created_at, updated_at`
+	notificationRuleColumns    = `id, name, channel_id, clusters, namespaces, severities, event_types, enabled, created_at, updated_at`
+)
+
+// NotificationRepository 通知渠道和路由规则数据访问层
+// 类比Shell: psql -c "SELECT * FROM notification_rules JOIN notification_channels ON ..."
+type NotificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewNotificationRepository 创建通知 Repository
+func NewNotificationRepository(pool *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{pool: pool}
+}
+
+// ListChannels 按名称顺序返回所有通知渠道
+func (r *NotificationRepository) ListChannels(ctx context.Context) ([]NotificationChannel, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+notificationChannelColumns+` FROM notification_channels ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	result := []NotificationChannel{}
+	for rows.Next() {
+		ch, err := scanNotificationChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ch)
+	}
+	return result, rows.Err()
+}
+
+// GetChannel 获取指定的通知渠道
+func (r *NotificationRepository) GetChannel(ctx context.Context, id int64) (NotificationChannel, error) {
+	ch, err := scanNotificationChannel(r.pool.QueryRow(ctx, `SELECT `+notificationChannelColumns+` FROM notification_channels WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotificationChannel{}, response.ErrNotFound(fmt.Sprintf("通知渠道 %d 不存在", id), nil)
+	}
+	if err != nil {
+		return NotificationChannel{}, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+	return ch, nil
+}
+
+// CreateChannel 创建通知渠道
+func (r *NotificationRepository) CreateChannel(ctx context.Context, ch NotificationChannel) (NotificationChannel, error) {
+	cfg, err := json.Marshal(ch.Config)
+	if err != nil {
+		return NotificationChannel{}, fmt.Errorf("failed to encode notification channel config: %w", err)
+	}
+	created, err := scanNotificationChannel(r.pool.QueryRow(ctx, `INSERT INTO notification_channels
+		(name, type, config, secret, template, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+notificationChannelColumns,
+		ch.Name, ch.Type, cfg, ch.Secret, ch.Template, ch.Enabled))
+	if err != nil {
+		return NotificationChannel{}, wrapNotificationWriteError(err, "通知渠道", ch.Name)
+	}
+	return created, nil
+}
+
+// UpdateChannel 更新通知渠道
+func (r *NotificationRepository) UpdateChannel(ctx context.Context, ch NotificationChannel) (NotificationChannel, error) {
+	cfg, err := json.Marshal(ch.Config)
+	if err != nil {
+		return NotificationChannel{}, fmt.Errorf("failed to encode notification channel config: %w", err)
+	}
+	updated, err := scanNotificationChannel(r.pool.QueryRow(ctx, `UPDATE notification_channels SET
+		name = $2, type = $3, config = $4, secret = $5, template = $6, enabled = $7, updated_at = now()
+		WHERE id = $1
+		RETURNING `+notificationChannelColumns,
+		ch.ID, ch.Name, ch.Type, cfg, ch.Secret, ch.Template, ch.Enabled))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotificationChannel{}, response.ErrNotFound(fmt.Sprintf("通知渠道 %d 不存在", ch.ID), nil)
+	}
+	if err != nil {
+		return NotificationChannel{}, wrapNotificationWriteError(err, "通知渠道", ch.Name)
+	}
+	return updated, nil
+}
+
+// DeleteChannel 删除通知渠道，渠道的路由规则一并删除
+func (r *NotificationRepository) DeleteChannel(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM notification_channels WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return response.ErrNotFound(fmt.Sprintf("通知渠道 %d 不存在", id), nil)
+	}
+	return nil
+}
+
+// ListRules 按名称顺序返回路由规则，channelID 不为 0 时只返回该渠道的规则
+func (r *NotificationRepository) ListRules(ctx context.Context, channelID int64) ([]NotificationRule, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+notificationRuleColumns+` FROM notification_rules
+		WHERE $1 = 0 OR channel_id = $1 ORDER BY name`, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification rules: %w", err)
+	}
+	defer rows.Close()
+
+	result := []NotificationRule{}
+	for rows.Next() {
+		rule, err := scanNotificationRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rule)
+	}
+	return result, rows.Err()
+}
+
+// GetRule 获取指定的路由规则
+func (r *NotificationRepository) GetRule(ctx context.Context, id int64) (NotificationRule, error) {
+	rule, err := scanNotificationRule(r.pool.QueryRow(ctx, `SELECT `+notificationRuleColumns+` FROM notification_rules WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotificationRule{}, response.ErrNotFound(fmt.Sprintf("通知路由规则 %d 不存在", id), nil)
+	}
+	if err != nil {
+		return NotificationRule{}, fmt.Errorf("failed to get notification rule: %w", err)
+	}
+	return rule, nil
+}
+
+// CreateRule 创建路由规则
+func (r *NotificationRepository) CreateRule(ctx context.Context, rule NotificationRule) (NotificationRule, error) {
+	created, err := scanNotificationRule(r.pool.QueryRow(ctx, `INSERT INTO notification_rules
+		(name, channel_id, clusters, namespaces, severities, event_types, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+notificationRuleColumns,
+		rule.Name, rule.ChannelID, rule.Clusters, rule.Namespaces, rule.Severities, rule.EventTypes, rule.Enabled))
+	if err != nil {
+		return NotificationRule{}, wrapNotificationWriteError(err, "通知路由规则", rule.Name)
+	}
+	return created, nil
+}
+
+// UpdateRule 更新路由规则
+func (r *NotificationRepository) UpdateRule(ctx context.Context, rule NotificationRule) (NotificationRule, error) {
+	updated, err := scanNotificationRule(r.pool.QueryRow(ctx, `UPDATE notification_rules SET
+		name = $2, channel_id = $3, clusters = $4, namespaces = $5, severities = $6, event_types = $7, enabled = $8,
+		updated_at = now()
+		WHERE id = $1
+		RETURNING `+notificationRuleColumns,
+		rule.ID, rule.Name, rule.ChannelID, rule.Clusters, rule.Namespaces, rule.Severities, rule.EventTypes, rule.Enabled))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotificationRule{}, response.ErrNotFound(fmt.Sprintf("通知路由规则 %d 不存在", rule.ID), nil)
+	}
+	if err != nil {
+		return NotificationRule{}, wrapNotificationWriteError(err, "通知路由规则", rule.Name)
+	}
+	return updated, nil
+}
+
+// DeleteRule 删除路由规则
+func (r *NotificationRepository) DeleteRule(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM notification_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return response.ErrNotFound(fmt.Sprintf("通知路由规则 %d 不存在", id), nil)
+	}
+	return nil
+}
+
+func scanNotificationChannel(row pgx.Row) (NotificationChannel, error) {
+	var ch NotificationChannel
+	var cfg []byte
+	if err := row.Scan(&ch.ID, &ch.Name, &ch.Type, &cfg, &ch.Secret, &ch.Template, &ch.Enabled,
+		&ch.CreatedAt, &ch.UpdatedAt); err != nil {
+		return NotificationChannel{}, err
+	}
+	if err := json.Unmarshal(cfg, &ch.Config); err != nil {
+		return NotificationChannel{}, fmt.Errorf("failed to decode notification channel config: %w", err)
+	}
+	ch.HasSecret = ch.Secret != ""
+	return ch, nil
+}
+
+func scanNotificationRule(row pgx.Row) (NotificationRule, error) {
+	var rule NotificationRule
+	err := row.Scan(&rule.ID, &rule.Name, &rule.ChannelID, &rule.Clusters, &rule.Namespaces, &rule.Severities,
+		&rule.EventTypes, &rule.Enabled, &rule.CreatedAt, &rule.UpdatedAt)
+	return rule, err
+}
+
+// wrapNotificationWriteError 名称冲突返回 409，渠道不存在返回 400，其余包装为内部错误
+func wrapNotificationWriteError(err error, resource, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return response.NewError(http.StatusConflict, response.CodeAlreadyExists, fmt.Sprintf("%s %s 已存在", resource, name), nil)
+		case "23503":
+			return response.ErrBadRequest("通知渠道不存在", nil)
+		}
+	}
+	return fmt.Errorf("failed to save notification: %w", err)
+}
@@ -6,26 +6,31 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
 )
 
 // PodRepository Pod数据访问层
 // 类比Shell函数：get_pods_in_namespace() { kubectl get pods -n $NAMESPACE ... }
 type PodRepository struct {
-	client *kubernetes.Clientset
+	clusters *client.ClusterManager
 }
 
 // NewPodRepository 创建Pod Repository
-func NewPodRepository(client *kubernetes.Clientset) *PodRepository {
+func NewPodRepository(clusters *client.ClusterManager) *PodRepository {
 	return &PodRepository{
-		client: client,
+		clusters: clusters,
 	}
 }
 
 // ListByNamespace 获取指定命名空间的所有Pod
 // 对应Shell: kubectl get pods -n $NAMESPACE -o json
-func (r *PodRepository) ListByNamespace(namespace string) ([]corev1.Pod, error) {
-	list, err := r.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+func (r *PodRepository) ListByNamespace(ctx context.Context, cluster, namespace string) ([]corev1.Pod, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
 	}
@@ -34,8 +39,12 @@ func (r *PodRepository) ListByNamespace(namespace string) ([]corev1.Pod, error)
 
 // GetByName 获取指定命名空间中的某个Pod
 // 对应Shell: kubectl get pod $NAME -n $NAMESPACE
-func (r *PodRepository) GetByName(namespace, name string) (*corev1.Pod, error) {
-	pod, err := r.client.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+func (r *PodRepository) GetByName(ctx context.Context, cluster, namespace, name string) (*corev1.Pod, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := c.Clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
 	}
@@ -44,10 +53,82 @@ func (r *PodRepository) GetByName(namespace, name string) (*corev1.Pod, error) {
 
 // ListAll 获取所有命名空间的所有Pod
 // 对应Shell: kubectl get pods --all-namespaces -o json
-func (r *PodRepository) ListAll() ([]corev1.Pod, error) {
-	list, err := r.client.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+func (r *PodRepository) ListAll(ctx context.Context, cluster string) ([]corev1.Pod, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list all pods: %w", err)
 	}
 	return list.Items, nil
 }
+
+// GetLogs 获取容器最近的日志，previous 为 true 时读取上一次重启前的容器日志
+// 对应Shell: kubectl logs $NAME -n $NAMESPACE -c $CONTAINER --tail=$TAIL_LINES [--previous]
+func (r *PodRepository) GetLogs(ctx context.Context, cluster, namespace, name, container string, previous bool, tailLines, limitBytes int64) (string, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return "", err
+	}
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &tailLines,
+	}
+	if limitBytes > 0 {
+		opts.LimitBytes = &limitBytes
+	}
+	data, err := c.Clientset.CoreV1().Pods(namespace).GetLogs(name, opts).DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs of container %s in pod %s: %w", container, name, err)
+	}
+	return string(data), nil
+}
+
+// ListWithSelector 按标签和字段选择器获取Pod，namespace 为空表示所有命名空间
+// 对应Shell: kubectl get pods -n $NAMESPACE -l $LABEL_SELECTOR --field-selector $FIELD_SELECTOR
+func (r *PodRepository) ListWithSelector(ctx context.Context, cluster, namespace, labelSelector, fieldSelector string) ([]corev1.Pod, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+	return list.Items, nil
+}
+
+// Create 创建Pod
+// 对应Shell: kubectl create -f pod.yaml
+func (r *PodRepository) Create(ctx context.Context, cluster string, pod *corev1.Pod) (*corev1.Pod, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.Clientset.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pod %s in namespace %s: %w", pod.Name, pod.Namespace, err)
+	}
+	return created, nil
+}
+
+// UpdateEphemeralContainers 通过 ephemeralcontainers 子资源更新Pod的临时容器，只能追加，不能修改或删除已有的临时容器
+// pod 需要带上读取时的 resourceVersion，并发修改时返回冲突错误
+// 对应Shell: kubectl debug $NAME -n $NAMESPACE --image=$IMAGE --target=$CONTAINER
+func (r *PodRepository) UpdateEphemeralContainers(ctx context.Context, cluster string, pod *corev1.Pod) (*corev1.Pod, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := c.Clientset.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(ctx, pod.Name, pod, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update ephemeral containers of pod %s in namespace %s: %w", pod.Name, pod.Namespace, err)
+	}
+	return updated, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// DialPortForward 建立到 Pod portforward 子资源的多路复用连接，调用方在连接上为每个端口创建 error 和 data 流
+// 与 kubectl 一致，优先使用 WebSocket 隧道，API Server 不支持时回退到 SPDY
+// 对应Shell: kubectl port-forward pod/$NAME -n $NAMESPACE $PORT
+func (r *PodRepository) DialPortForward(cluster, namespace, name string) (httpstream.Connection, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	u := c.Clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(namespace).Name(name).SubResource("portforward").URL()
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spdy round tripper: %w", err)
+	}
+	var dialer httpstream.Dialer = spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, u)
+	tunneling, err := portforward.NewSPDYOverWebsocketDialer(u, c.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create websocket dialer: %w", err)
+	}
+	dialer = portforward.NewFallbackDialer(tunneling, dialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+
+	conn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial port-forward for pod %s in namespace %s: %w", name, namespace, err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+)
+
+// ServiceRepository Service 数据访问层
+type ServiceRepository struct {
+	clusters *client.ClusterManager
+}
+
+// NewServiceRepository 创建 Service Repository
+func NewServiceRepository(clusters *client.ClusterManager) *ServiceRepository {
+	return &ServiceRepository{
+		clusters: clusters,
+	}
+}
+
+// GetByName 获取指定命名空间中的某个 Service
+// 对应Shell: kubectl get service $NAME -n $NAMESPACE
+func (r *ServiceRepository) GetByName(ctx context.Context, cluster, namespace, name string) (*corev1.Service, error) {
+	c, err := r.clusters.Get(cluster)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := c.Clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s in namespace %s: %w", name, namespace, err)
+	}
+	return svc, nil
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/argocd"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// ArgoCD 写操作的审计 action
+const (
+	AuditActionArgoCDSync     = "argocd.sync"
+	AuditActionArgoCDRollback = "argocd.rollback"
+)
+
+const (
+	argocdDefaultTimeout = 30 * time.Second
+	argocdMaxTimeout     = 120 * time.Second
+	// argocdInClusterServer ArgoCD 所在集群的目标地址
+	argocdInClusterServer = "https://kubernetes.default.svc"
+)
+
+// RollbackRequest 回滚参数，ID 为部署历史中的 ID
+type RollbackRequest struct {
+	ID     int64 `json:"id"`
+	Prune  bool  `json:"prune"`
+	DryRun bool  `json:"dryRun"`
+}
+
+// ApplicationWorkloads Application 管理的工作负载和 Pod，Pod 链接到 KubeOps 的 Pod 接口
+type ApplicationWorkloads struct {
+	Application string            `json:"application"`
+	Cluster     string            `json:"cluster,omitempty"` // 为空表示目标集群未接入 KubeOps，Pod 没有链接
+	Workloads   []argocd.Workload `json:"workloads"`
+}
+
+// ArgoCDService ArgoCD 实例管理和 Application 操作
+// 类比Shell: argocd app list && argocd app diff $APP && argocd app sync $APP --prune && argocd app rollback $APP $ID
+type ArgoCDService struct {
+	argocdRepo *repository.ArgoCDRepository
+	auditRepo  *repository.AuditRepository
+	clusters   *client.ClusterManager
+
+	// 按实例 ID 缓存客户端，复用连接
+	clients *clientCache[*argocd.Client]
+}
+
+// NewArgoCDService 创建 ArgoCD Service
+func NewArgoCDService(argocdRepo *repository.ArgoCDRepository, auditRepo *repository.AuditRepository, clusters *client.ClusterManager) *ArgoCDService {
+	return &ArgoCDService{
+		argocdRepo: argocdRepo,
+		auditRepo:  auditRepo,
+		clusters:   clusters,
+		clients:    newClientCache[*argocd.Client](),
+	}
+}
+
+// ListServers 获取所有 ArgoCD 实例
+func (s *ArgoCDService) ListServers(ctx context.Context) ([]repository.ArgoCDServer, error) {
+	return s.argocdRepo.List(ctx)
+}
+
+// GetServer 获取单个 ArgoCD 实例
+func (s *ArgoCDService) GetServer(ctx context.Context, id int64) (repository.ArgoCDServer, error) {
+	return s.argocdRepo.GetByID(ctx, id)
+}
+
+// CreateServer 添加 ArgoCD 实例
+func (s *ArgoCDService) CreateServer(ctx context.Context, server repository.ArgoCDServer) (repository.ArgoCDServer, error) {
+	if err := s.normalizeServer(&server); err != nil {
+		return repository.ArgoCDServer{}, err
+	}
+	return s.argocdRepo.Create(ctx, server)
+}
+
+// UpdateServer 更新 ArgoCD 实例，APIToken 为空时保留原值
+func (s *ArgoCDService) UpdateServer(ctx context.Context, server repository.ArgoCDServer) (repository.ArgoCDServer, error) {
+	existing, err := s.argocdRepo.GetByID(ctx, server.ID)
+	if err != nil {
+		return repository.ArgoCDServer{}, err
+	}
+	if server.APIToken == "" {
+		server.APIToken = existing.APIToken
+	}
+	if err := s.normalizeServer(&server); err != nil {
+		return repository.ArgoCDServer{}, err
+	}
+	return s.argocdRepo.Update(ctx, server)
+}
+
+// DeleteServer 删除 ArgoCD 实例
+func (s *ArgoCDService) DeleteServer(ctx context.Context, id int64) error {
+	if err := s.argocdRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.clients.remove(id)
+	return nil
+}
+
+// TestServer 用保存的 Token 访问 ArgoCD，返回 Token 对应的账号
+func (s *ArgoCDService) TestServer(ctx context.Context, id int64) (string, error) {
+	c, _, err := s.client(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return c.UserInfo(ctx)
+}
+
+// ListApplications 列出 Application 及其同步和健康状态
+func (s *ArgoCDService) ListApplications(ctx context.Context, id int64, opts argocd.ListOptions) ([]argocd.Application, error) {
+	c, server, err := s.client(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := c.ListApplications(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range apps {
+		apps[i].Cluster = s.destinationCluster(server, apps[i].Destination)
+	}
+	return apps, nil
+}
+
+// GetApplication 获取 Application 详情、资源状态和部署历史
+func (s *ArgoCDService) GetApplication(ctx context.Context, id int64, name, appNamespace string) (argocd.ApplicationDetail, error) {
+	c, server, err := s.client(ctx, id)
+	if err != nil {
+		return argocd.ApplicationDetail{}, err
+	}
+	app, err := c.GetApplication(ctx, name, appNamespace)
+	if err != nil {
+		return argocd.ApplicationDetail{}, err
+	}
+	app.Cluster = s.destinationCluster(server, app.Destination)
+	return app, nil
+}
+
+// Diff 返回未同步资源的差异
+func (s *ArgoCDService) Diff(ctx context.Context, id int64, name, appNamespace string) ([]argocd.ResourceDiff, error) {
+	c, _, err := s.client(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := c.ManagedResources(ctx, name, appNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return argocd.Diff(resources)
+}
+
+// Sync 触发同步，dryRun 时只校验不修改集群
+func (s *ArgoCDService) Sync(ctx context.Context, id int64, name, appNamespace string, opts argocd.SyncOptions) (argocd.Application, error) {
+	for _, r := range opts.Resources {
+		if r.Kind == "" || r.Name == "" {
+			return argocd.Application{}, response.ErrBadRequest("resources 中的 kind 和 name 必填", nil)
+		}
+	}
+	c, server, err := s.client(ctx, id)
+	if err != nil {
+		return argocd.Application{}, err
+	}
+	app, err := c.Sync(ctx, name, appNamespace, opts)
+	if err != nil {
+		return argocd.Application{}, err
+	}
+	app.Cluster = s.destinationCluster(server, app.Destination)
+	if !opts.DryRun {
+		recordAudit(ctx, s.auditRepo, repository.AuditLog{
+			Action:    AuditActionArgoCDSync,
+			Cluster:   app.Cluster,
+			Namespace: app.Destination.Namespace,
+			Resource:  name,
+		}, map[string]interface{}{
+			"server":       id,
+			"appNamespace": appNamespace,
+			"revision":     opts.Revision,
+			"prune":        opts.Prune,
+			"resources":    opts.Resources,
+		})
+	}
+	return app, nil
+}
+
+// Rollback 回滚到部署历史中的版本；开启自动同步的 Application 会立即被同步回 Git 中的版本，ArgoCD 拒绝回滚
+func (s *ArgoCDService) Rollback(ctx context.Context, id int64, name, appNamespace string, req RollbackRequest) (argocd.Application, error) {
+	if req.ID < 0 {
+		return argocd.Application{}, response.ErrBadRequest("id 参数无效", nil)
+	}
+	c, server, err := s.client(ctx, id)
+	if err != nil {
+		return argocd.Application{}, err
+	}
+	detail, err := c.GetApplication(ctx, name, appNamespace)
+	if err != nil {
+		return argocd.Application{}, err
+	}
+	if detail.AutoSync {
+		return argocd.Application{}, response.ErrBadRequest(fmt.Sprintf("Application %s 开启了自动同步，请先关闭自动同步再回滚", name), nil)
+	}
+	var target *argocd.HistoryEntry
+	for i := range detail.History {
+		if detail.History[i].ID == req.ID {
+			target = &detail.History[i]
+		}
+	}
+	if target == nil {
+		return argocd.Application{}, response.ErrNotFound(fmt.Sprintf("Application %s 的部署历史中没有 %d", name, req.ID), nil)
+	}
+
+	app, err := c.Rollback(ctx, name, appNamespace, req.ID, req.Prune, req.DryRun)
+	if err != nil {
+		return argocd.Application{}, err
+	}
+	app.Cluster = s.destinationCluster(server, app.Destination)
+	if !req.DryRun {
+		recordAudit(ctx, s.auditRepo, repository.AuditLog{
+			Action:    AuditActionArgoCDRollback,
+			Cluster:   app.Cluster,
+			Namespace: app.Destination.Namespace,
+			Resource:  name,
+		}, map[string]interface{}{
+			"server":       id,
+			"appNamespace": appNamespace,
+			"historyId":    req.ID,
+			"revision":     target.Revision,
+			"prune":        req.Prune,
+		})
+	}
+	return app, nil
+}
+
+// Workloads 返回 Application 管理的工作负载和 Pod；目标集群已接入 KubeOps 时 Pod 链接到 Pod 详情接口
+func (s *ArgoCDService) Workloads(ctx context.Context, id int64, name, appNamespace string) (ApplicationWorkloads, error) {
+	c, server, err := s.client(ctx, id)
+	if err != nil {
+		return ApplicationWorkloads{}, err
+	}
+	app, err := c.GetApplication(ctx, name, appNamespace)
+	if err != nil {
+		return ApplicationWorkloads{}, err
+	}
+	nodes, err := c.ResourceTree(ctx, name, appNamespace)
+	if err != nil {
+		return ApplicationWorkloads{}, err
+	}
+
+	result := ApplicationWorkloads{
+		Application: name,
+		Cluster:     s.destinationCluster(server, app.Destination),
+		Workloads:   argocd.Workloads(nodes),
+	}
+	if result.Cluster != "" {
+		for i := range result.Workloads {
+			for j := range result.Workloads[i].Pods {
+				pod := &result.Workloads[i].Pods[j]
+				pod.Link = podLink(result.Cluster, pod.Namespace, pod.Name)
+			}
+		}
+	}
+	return result, nil
+}
+
+// podLink KubeOps Pod 详情接口的地址
+func podLink(cluster, namespace, name string) string {
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods/%s?cluster=%s",
+		url.PathEscape(namespace), url.PathEscape(name), url.QueryEscape(cluster))
+}
+
+// destinationCluster 把 Application 的目标集群对应到 KubeOps 集群：
+// in-cluster 对应实例所在的集群，其余按集群名或 API Server 地址匹配，无法对应时返回空
+func (s *ArgoCDService) destinationCluster(server repository.ArgoCDServer, dest argocd.Destination) string {
+	if dest.Server == argocdInClusterServer || dest.Name == "in-cluster" {
+		return server.Cluster
+	}
+	names := s.clusters.Names()
+	if dest.Name != "" && slices.Contains(names, dest.Name) {
+		return dest.Name
+	}
+	if dest.Server == "" {
+		return ""
+	}
+	for _, name := range names {
+		c, err := s.clusters.Get(name)
+		if err == nil && strings.TrimRight(c.Config.Host, "/") == strings.TrimRight(dest.Server, "/") {
+			return name
+		}
+	}
+	return ""
+}
+
+// client 按实例 ID 获取客户端，实例配置更新后重新创建
+func (s *ArgoCDService) client(ctx context.Context, id int64) (*argocd.Client, repository.ArgoCDServer, error) {
+	server, err := s.argocdRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, repository.ArgoCDServer{}, err
+	}
+	c, _ := s.clients.get(server.ID, server.UpdatedAt, func() (*argocd.Client, error) {
+		return argocd.NewClient(argocd.ClientConfig{
+			URL:           server.URL,
+			APIToken:      server.APIToken,
+			TLSSkipVerify: server.TLSSkipVerify,
+			Timeout:       time.Duration(server.TimeoutSeconds) * time.Second,
+		}), nil
+	})
+	return c, server, nil
+}
+
+// normalizeServer 校验实例配置并填充默认值
+func (s *ArgoCDService) normalizeServer(server *repository.ArgoCDServer) error {
+	if server.Name == "" {
+		return response.ErrBadRequest("ArgoCD 实例名称不能为空", nil)
+	}
+	u, err := url.Parse(server.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return response.ErrBadRequest(fmt.Sprintf("ArgoCD 地址无效: %s", server.URL), err)
+	}
+	server.URL = strings.TrimRight(server.URL, "/")
+	if server.APIToken == "" {
+		return response.ErrBadRequest("API Token 不能为空", nil)
+	}
+	if server.Cluster != "" && !slices.Contains(s.clusters.Names(), server.Cluster) {
+		return response.ErrBadRequest(fmt.Sprintf("集群 %s 不存在", server.Cluster), nil)
+	}
+	if server.TimeoutSeconds <= 0 {
+		server.TimeoutSeconds = int(argocdDefaultTimeout.Seconds())
+	}
+	if server.TimeoutSeconds > int(argocdMaxTimeout.Seconds()) {
+		return response.ErrBadRequest(fmt.Sprintf("请求超时不能超过 %d 秒", int(argocdMaxTimeout.Seconds())), nil)
+	}
+	return nil
+}
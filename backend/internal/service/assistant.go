@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/assistant"
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/llm"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// 审计日志中助手相关的 action
+const (
+	AuditActionAssistantChat     = "assistant.chat"
+	AuditActionAssistantToolCall = "assistant.tool_call"
+)
+
+// 助手以 SSE 推送的事件类型
+const (
+	AssistantEventStart      = "start"
+	AssistantEventToolCall   = "tool_call"
+	AssistantEventToolResult = "tool_result"
+	AssistantEventMessage    = "message"
+	AssistantEventDone       = "done"
+	AssistantEventError      = "error"
+)
+
+const (
+	assistantMaxQuestionLength = 4000
+	assistantMaxHistory        = 20
+)
+
+const assistantSystemPrompt = `你是 KubeOps 的 Kubernetes 集群问答助手，当前集群为 %s，当前时间为 %s（UTC）。
+- 只能通过提供的只读工具获取集群数据，不能修改集群，也不要建议用户绕过权限。
+- 回答必须基于工具返回的数据，不要编造资源名称或数值；数据不足时说明还需要什么信息。
+- 工具返回 error 时（如没有权限、参数无效），根据错误调整参数重试或向用户说明原因。
+- 工具结果中 truncated 为 true 表示只返回了部分条目，count 为总数。
+- "今天"、"最近" 等时间按当前时间换算后传给工具的 since/restartedSince 参数。
+- 使用与用户提问相同的语言简洁作答，列出资源时使用 命名空间/名称 的形式。`
+
+// AssistantRequest 一次提问
+type AssistantRequest struct {
+	Cluster  string        `json:"cluster"`
+	Question string        `json:"question"`
+	History  []llm.Message `json:"history"` // 之前几轮的问答，只接受 user 和 assistant 消息
+}
+
+// AssistantToolCall 推送给前端的工具调用
+type AssistantToolCall struct {
+	ID        string             `json:"id"`
+	Step      int                `json:"step"`
+	Tool      string             `json:"tool"`
+	Arguments string             `json:"arguments"`
+	Access    []assistant.Access `json:"access,omitempty"`
+}
+
+// AssistantToolResult 推送给前端的工具结果，Content 与发送给模型的内容一致
+type AssistantToolResult struct {
+	ID         string `json:"id"`
+	Tool       string `json:"tool"`
+	Allowed    bool   `json:"allowed"`
+	Error      string `json:"error,omitempty"`
+	Content    string `json:"content"`
+	DurationMs int64  `json:"durationMs"`
+	AuditID    int64  `json:"auditId"`
+}
+
+// AssistantDone 对话结束时的汇总
+type AssistantDone struct {
+	ConversationID string    `json:"conversationId"`
+	Model          string    `json:"model"`
+	Steps          int       `json:"steps"`
+	ToolCalls      int       `json:"toolCalls"`
+	Usage          llm.Usage `json:"usage"`
+	Incomplete     bool      `json:"incomplete,omitempty"` // 达到 maxSteps 或输出达到 maxOutputTokens
+}
+
+// AssistantEmitter 推送一个事件，返回错误（如客户端断开）时终止对话
+type AssistantEmitter func(event string, data interface{}) error
+
+// AssistantService 集群问答助手：模型通过白名单中的只读工具查询集群，每次工具调用都校验当前用户的权限并写入审计日志
+// 类比Shell函数：ask() { while tool=$(llm "$QUESTION" "$RESULTS"); do can_i "$tool" && RESULTS+=$(run "$tool"); audit "$tool"; done; }
+type AssistantService struct {
+	clusters          *client.ClusterManager
+	namespaceRepo     *repository.NamespaceRepository
+	podRepo           *repository.PodRepository
+	deploymentRepo    *repository.DeploymentRepository
+	eventRepo         *repository.EventRepository
+	metricsRepo       *repository.MetricsRepository
+	monitoringService *MonitoringService
+	authzRepo         *repository.AuthorizationRepository
+	auditRepo         *repository.AuditRepository
+	llm               llm.Client
+	budget            *tokenBudget
+	tools             *assistant.Registry
+	cfg               config.LLMConfig
+}
+
+// NewAssistantService 创建问答助手 Service，llmClient 为 nil 表示未配置大模型
+func NewAssistantService(
+	clusters *client.ClusterManager,
+	namespaceRepo *repository.NamespaceRepository,
+	podRepo *repository.PodRepository,
+	deploymentRepo *repository.DeploymentRepository,
+	eventRepo *repository.EventRepository,
+	metricsRepo *repository.MetricsRepository,
+	monitoringService *MonitoringService,
+	authzRepo *repository.AuthorizationRepository,
+	auditRepo *repository.AuditRepository,
+	llmClient llm.Client,
+	redisClient *redis.Client,
+	redisDep *client.Dependency,
+	cfg config.LLMConfig,
+) *AssistantService {
+	s := &AssistantService{
+		clusters:          clusters,
+		namespaceRepo:     namespaceRepo,
+		podRepo:           podRepo,
+		deploymentRepo:    deploymentRepo,
+		eventRepo:         eventRepo,
+		metricsRepo:       metricsRepo,
+		monitoringService: monitoringService,
+		authzRepo:         authzRepo,
+		auditRepo:         auditRepo,
+		llm:               llmClient,
+		budget:            newTokenBudget(redisClient, redisDep, cfg.DailyTokenBudget),
+		cfg:               cfg,
+	}
+	s.tools = s.assistantTools()
+	return s
+}
+
+// Tools 返回可调用的工具定义
+func (s *AssistantService) Tools() []llm.Tool {
+	return s.tools.Definitions()
+}
+
+// Chat 回答一个问题，过程中的工具调用、工具结果和最终回答通过 emit 推送
+// 在推送第一个事件之前返回的错误表示请求无效，之后的错误由调用方以 error 事件推送
+func (s *AssistantService) Chat(ctx context.Context, req AssistantRequest, emit AssistantEmitter) error {
+	if s.llm == nil {
+		return response.NewError(http.StatusServiceUnavailable, response.CodeServiceUnavailable,
+			"未配置大模型（llm.provider）", nil)
+	}
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		return response.ErrBadRequest("question 不能为空", nil)
+	}
+	if len([]rune(question)) > assistantMaxQuestionLength {
+		return response.ErrBadRequest(fmt.Sprintf("question 不能超过 %d 个字符", assistantMaxQuestionLength), nil)
+	}
+	history, err := assistantHistory(req.History)
+	if err != nil {
+		return err
+	}
+	c, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return err
+	}
+	if err := s.budget.Check(ctx); err != nil {
+		return err
+	}
+
+	user := auth.UserFromContext(ctx)
+	conv := &conversation{
+		id:      newConversationID(),
+		cluster: c.Name,
+		user:    user,
+		emit:    emit,
+	}
+	// 提问本身先写入审计日志，写入失败时不开始对话
+	if _, err := s.audit(ctx, conv, AuditActionAssistantChat, "", "", map[string]interface{}{
+		"conversationId": conv.id,
+		"question":       question,
+		"model":          s.llm.Model(),
+	}); err != nil {
+		return err
+	}
+	if err := emit(AssistantEventStart, map[string]interface{}{"conversationId": conv.id, "cluster": c.Name, "model": s.llm.Model()}); err != nil {
+		return err
+	}
+
+	messages := make([]llm.Message, 0, len(history)+2)
+	messages = append(messages, llm.Message{
+		Role:    llm.RoleSystem,
+		Content: fmt.Sprintf(assistantSystemPrompt, c.Name, time.Now().UTC().Format(time.RFC3339)),
+	})
+	messages = append(messages, history...)
+	messages = append(messages, llm.Message{Role: llm.RoleUser, Content: question})
+	messages = s.fitHistory(messages)
+
+	done := AssistantDone{ConversationID: conv.id, Model: s.llm.Model()}
+	for {
+		tools := s.tools.Definitions()
+		if done.Steps >= s.cfg.Assistant.MaxSteps {
+			// 达到轮数上限后不再提供工具，要求模型根据已有结果作答
+			tools = nil
+			done.Incomplete = true
+			messages = append(messages, llm.Message{
+				Role:    llm.RoleUser,
+				Content: "已达到工具调用次数上限，请根据已有的工具结果回答，并说明哪些信息未能查询。",
+			})
+		}
+		if done.Steps > 0 {
+			if err := s.budget.Check(ctx); err != nil {
+				return err
+			}
+		}
+		reply, err := s.llm.Chat(ctx, llm.Request{
+			Messages:    messages,
+			Tools:       tools,
+			MaxTokens:   s.cfg.MaxOutputTokens,
+			Temperature: s.cfg.Temperature,
+		})
+		if err != nil {
+			return err
+		}
+		s.budget.Record(ctx, reply.Usage)
+		done.Usage.PromptTokens += reply.Usage.PromptTokens
+		done.Usage.CompletionTokens += reply.Usage.CompletionTokens
+		done.Usage.TotalTokens += reply.Usage.TotalTokens
+		if reply.Model != "" {
+			done.Model = reply.Model
+		}
+
+		if len(reply.ToolCalls) == 0 || tools == nil {
+			done.Incomplete = done.Incomplete || reply.FinishReason == "length"
+			if err := emit(AssistantEventMessage, map[string]interface{}{"content": reply.Content}); err != nil {
+				return err
+			}
+			return emit(AssistantEventDone, done)
+		}
+
+		done.Steps++
+		messages = append(messages, llm.Message{Role: llm.RoleAssistant, Content: reply.Content, ToolCalls: reply.ToolCalls})
+		for _, call := range reply.ToolCalls {
+			done.ToolCalls++
+			content, err := s.callTool(ctx, conv, done.Steps, call)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, llm.Message{Role: llm.RoleTool, Content: content, ToolCallID: call.ID})
+		}
+	}
+}
+
+// conversation 一次提问的上下文
+type conversation struct {
+	id      string
+	cluster string
+	user    auth.User
+	emit    AssistantEmitter
+}
+
+// callTool 执行一次工具调用：校验参数和权限、执行、审计，然后推送结果
+// 工具自身的错误（参数无效、没有权限、查询失败）作为结果返回给模型；审计写入失败或推送失败时终止对话
+func (s *AssistantService) callTool(ctx context.Context, conv *conversation, step int, call llm.ToolCall) (string, error) {
+	start := time.Now()
+	event := AssistantToolCall{ID: call.ID, Step: step, Tool: call.Name, Arguments: call.Arguments}
+
+	var (
+		result  assistant.Result
+		allowed bool
+		toolErr error
+	)
+	tool, ok := s.tools.Lookup(call.Name)
+	if !ok {
+		toolErr = fmt.Errorf("unknown tool %q", call.Name)
+	}
+	var inv assistant.Invocation
+	if toolErr == nil {
+		inv, toolErr = tool.Prepare(call.Arguments)
+		event.Access = inv.Access
+	}
+	if err := conv.emit(AssistantEventToolCall, event); err != nil {
+		return "", err
+	}
+	if toolErr == nil {
+		allowed, toolErr = s.authorize(ctx, conv, inv.Access)
+	}
+	if toolErr == nil {
+		result, toolErr = inv.Run(ctx, conv.cluster)
+	}
+
+	content, truncated := "", false
+	if toolErr != nil {
+		if appErr := response.FromError(toolErr); appErr.Status < http.StatusInternalServerError {
+			toolErr = errors.New(appErr.Message)
+		}
+		content = assistant.ErrorJSON(toolErr)
+	} else {
+		content, truncated = assistant.Encode(result, s.cfg.Assistant.MaxToolResultTokens)
+	}
+	out := AssistantToolResult{
+		ID:         call.ID,
+		Tool:       call.Name,
+		Allowed:    allowed,
+		Content:    content,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	detail := map[string]interface{}{
+		"conversationId": conv.id,
+		"step":           step,
+		"toolCallId":     call.ID,
+		"tool":           call.Name,
+		"arguments":      call.Arguments,
+		"access":         event.Access,
+		"allowed":        allowed,
+		"durationMs":     out.DurationMs,
+	}
+	if toolErr != nil {
+		out.Error = toolErr.Error()
+		detail["error"] = out.Error
+	} else {
+		detail["count"] = result.Count
+		detail["truncated"] = truncated
+	}
+
+	namespace := ""
+	if len(event.Access) > 0 {
+		namespace = event.Access[0].Namespace
+	}
+	// 结果必须先写入审计日志，才能交给模型和用户
+	log, err := s.audit(ctx, conv, AuditActionAssistantToolCall, namespace, call.Name, detail)
+	if err != nil {
+		return "", err
+	}
+	out.AuditID = log.ID
+	if err := conv.emit(AssistantEventToolResult, out); err != nil {
+		return "", err
+	}
+	return content, nil
+}
+
+// authorize 用 SubjectAccessReview 校验当前用户是否拥有工具所需的所有权限
+// 未开启 llm.assistant.enforceRBAC 时只按 kubeops 自身的 ServiceAccount 权限执行
+// 用户名和用户组来自 Token，只有经过校验的身份才能用于 SubjectAccessReview，否则伪造 system:masters 即可通过
+func (s *AssistantService) authorize(ctx context.Context, conv *conversation, access []assistant.Access) (bool, error) {
+	if !s.cfg.Assistant.EnforceRBAC {
+		return true, nil
+	}
+	if conv.user.ID() == auth.Anonymous {
+		return false, errors.New("permission denied: anonymous or unverified users cannot call tools while llm.assistant.enforceRBAC is enabled")
+	}
+	for _, a := range access {
+		allowed, reason, err := s.authzRepo.Review(ctx, conv.cluster, conv.user.Name, conv.user.Groups, authorizationv1.ResourceAttributes{
+			Namespace: a.Namespace,
+			Verb:      a.Verb,
+			Group:     a.Group,
+			Resource:  a.Resource,
+		})
+		if err != nil {
+			return false, err
+		}
+		if !allowed {
+			msg := fmt.Sprintf("permission denied: user %s cannot %s", conv.user.Name, a)
+			if reason != "" {
+				msg += ": " + reason
+			}
+			return false, errors.New(msg)
+		}
+	}
+	return true, nil
+}
+
+func (s *AssistantService) audit(ctx context.Context, conv *conversation, action, namespace, resource string, detail interface{}) (repository.AuditLog, error) {
+	log, err := s.auditRepo.Create(ctx, repository.AuditLog{
+		Username:  conv.user.DisplayName(),
+		Action:    action,
+		Cluster:   conv.cluster,
+		Namespace: namespace,
+		Resource:  resource,
+	}, detail)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to audit assistant", zap.String("action", action), zap.Error(err))
+		return repository.AuditLog{}, response.NewError(http.StatusServiceUnavailable, response.CodeServiceUnavailable,
+			"审计日志写入失败，已终止对话", err)
+	}
+	return log, nil
+}
+
+// fitHistory 消息超过 maxInputTokens 时从最早的历史开始丢弃，保留系统提示和当前问题
+func (s *AssistantService) fitHistory(messages []llm.Message) []llm.Message {
+	for len(messages) > 2 && llm.EstimateMessages(messages) > s.cfg.MaxInputTokens {
+		messages = append(messages[:1], messages[2:]...)
+	}
+	return messages
+}
+
+// assistantHistory 校验前端传入的历史消息，历史中不能包含工具调用
+func assistantHistory(history []llm.Message) ([]llm.Message, error) {
+	if len(history) > assistantMaxHistory {
+		history = history[len(history)-assistantMaxHistory:]
+	}
+	out := make([]llm.Message, 0, len(history))
+	for _, m := range history {
+		if m.Role != llm.RoleUser && m.Role != llm.RoleAssistant {
+			return nil, response.ErrBadRequest("history 中的消息 role 只能是 user 或 assistant", nil)
+		}
+		out = append(out, llm.Message{Role: m.Role, Content: m.Content})
+	}
+	return out, nil
+}
+
+func newConversationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,529 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/yansongwel/kubeops/backend/internal/assistant"
+	"github.com/yansongwel/kubeops/backend/internal/diagnosis"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	// assistantDefaultLimit 列表类工具默认返回的条目数
+	assistantDefaultLimit = 50
+	assistantMaxLimit     = 500
+)
+
+var (
+	propNamespace     = assistant.Prop("string", "命名空间，为空表示所有命名空间")
+	propLabelSelector = assistant.Prop("string", "标签选择器，如 app=api,tier!=cache")
+	propLimit         = assistant.Prop("integer", "最多返回的条目数，默认 50")
+)
+
+// assistantTools 助手可调用的只读工具白名单
+func (s *AssistantService) assistantTools() *assistant.Registry {
+	return assistant.NewRegistry(
+		s.listNamespacesTool(),
+		s.listPodsTool(),
+		s.listDeploymentsTool(),
+		s.getEventsTool(),
+		s.queryMetricsTool(),
+		s.topPodsTool(),
+	)
+}
+
+type listNamespacesArgs struct {
+	LabelSelector string `json:"labelSelector"`
+}
+
+type namespaceSummary struct {
+	Name      string            `json:"name"`
+	Status    string            `json:"status"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// listNamespacesTool 对应Shell: kubectl get namespaces -l $LABEL_SELECTOR
+func (s *AssistantService) listNamespacesTool() assistant.Tool {
+	return assistant.NewTool("list_namespaces", "列出集群中的命名空间",
+		assistant.Schema(map[string]interface{}{"labelSelector": propLabelSelector}),
+		func(args listNamespacesArgs) []assistant.Access {
+			return []assistant.Access{{Verb: "list", Resource: "namespaces"}}
+		},
+		func(ctx context.Context, cluster string, args listNamespacesArgs) (assistant.Result, error) {
+			selector, err := parseLabelSelector(args.LabelSelector)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			namespaces, err := s.namespaceRepo.ListAll(ctx, cluster)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			items := make([]namespaceSummary, 0, len(namespaces))
+			for _, ns := range namespaces {
+				if !selector.Matches(labels.Set(ns.Labels)) {
+					continue
+				}
+				items = append(items, namespaceSummary{
+					Name:      ns.Name,
+					Status:    string(ns.Status.Phase),
+					Labels:    ns.Labels,
+					CreatedAt: ns.CreationTimestamp.Time,
+				})
+			}
+			return assistant.Result{Count: len(items), Items: items}, nil
+		},
+	)
+}
+
+type listPodsArgs struct {
+	Namespace      string `json:"namespace"`
+	LabelSelector  string `json:"labelSelector"`
+	FieldSelector  string `json:"fieldSelector"`
+	MinRestarts    int32  `json:"minRestarts"`
+	RestartedSince string `json:"restartedSince"`
+	Limit          int    `json:"limit"`
+}
+
+type podSummary struct {
+	Namespace     string     `json:"namespace"`
+	Name          string     `json:"name"`
+	Owner         string     `json:"owner,omitempty"` // 顶层控制器，如 Deployment/api
+	Phase         string     `json:"phase"`
+	Reason        string     `json:"reason,omitempty"` // 未就绪容器的等待或终止原因，如 CrashLoopBackOff
+	Ready         string     `json:"ready"`
+	Restarts      int32      `json:"restarts"`
+	LastRestartAt *time.Time `json:"lastRestartAt,omitempty"`
+	Node          string     `json:"node,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// listPodsTool 对应Shell: kubectl get pods -n $NAMESPACE -l $LABEL_SELECTOR --sort-by=restarts
+func (s *AssistantService) listPodsTool() assistant.Tool {
+	return assistant.NewTool("list_pods",
+		"列出 Pod 及其状态、重启次数、最近一次重启时间和所属控制器，按重启次数从多到少排序",
+		assistant.Schema(map[string]interface{}{
+			"namespace":      propNamespace,
+			"labelSelector":  propLabelSelector,
+			"fieldSelector":  assistant.Prop("string", "字段选择器，如 status.phase!=Running 或 spec.nodeName=node-1"),
+			"minRestarts":    assistant.Prop("integer", "只返回容器重启次数之和不少于该值的 Pod"),
+			"restartedSince": assistant.Prop("string", "只返回在该时间之后重启过的 Pod，RFC3339 时间或时长（如 24h）"),
+			"limit":          propLimit,
+		}),
+		func(args listPodsArgs) []assistant.Access {
+			return []assistant.Access{{Verb: "list", Resource: "pods", Namespace: args.Namespace}}
+		},
+		func(ctx context.Context, cluster string, args listPodsArgs) (assistant.Result, error) {
+			limit, err := assistantLimit(args.Limit)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			since, err := parseSince(args.RestartedSince)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			if _, err := fields.ParseSelector(args.FieldSelector); err != nil {
+				return assistant.Result{}, response.ErrBadRequest(fmt.Sprintf("fieldSelector 无效: %v", err), err)
+			}
+			if _, err := parseLabelSelector(args.LabelSelector); err != nil {
+				return assistant.Result{}, err
+			}
+			pods, err := s.podRepo.ListWithSelector(ctx, cluster, args.Namespace, args.LabelSelector, args.FieldSelector)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			items := make([]podSummary, 0, len(pods))
+			for i := range pods {
+				p := summarizePod(&pods[i])
+				if p.Restarts < args.MinRestarts {
+					continue
+				}
+				if !since.IsZero() && (p.LastRestartAt == nil || p.LastRestartAt.Before(since)) {
+					continue
+				}
+				items = append(items, p)
+			}
+			sort.SliceStable(items, func(i, j int) bool {
+				if items[i].Restarts != items[j].Restarts {
+					return items[i].Restarts > items[j].Restarts
+				}
+				return items[i].Namespace+"/"+items[i].Name < items[j].Namespace+"/"+items[j].Name
+			})
+			return limitResult(items, limit), nil
+		},
+	)
+}
+
+// summarizePod Pod 摘要；重启时间取各容器上一次终止的时间中最晚的一个
+func summarizePod(pod *corev1.Pod) podSummary {
+	p := podSummary{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		Owner:     podOwnerName(pod),
+		Phase:     string(pod.Status.Phase),
+		Reason:    pod.Status.Reason,
+		Node:      pod.Spec.NodeName,
+		CreatedAt: pod.CreationTimestamp.Time,
+	}
+	ready := 0
+	for _, st := range pod.Status.ContainerStatuses {
+		p.Restarts += st.RestartCount
+		if st.Ready {
+			ready++
+		} else if p.Reason == "" {
+			switch {
+			case st.State.Waiting != nil:
+				p.Reason = st.State.Waiting.Reason
+			case st.State.Terminated != nil:
+				p.Reason = st.State.Terminated.Reason
+			}
+		}
+		if t := st.LastTerminationState.Terminated; t != nil && !t.FinishedAt.IsZero() {
+			if p.LastRestartAt == nil || t.FinishedAt.After(*p.LastRestartAt) {
+				finished := t.FinishedAt.Time
+				p.LastRestartAt = &finished
+			}
+		}
+	}
+	p.Ready = fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+	return p
+}
+
+// podOwnerName Pod 的顶层控制器；ReplicaSet 按 pod-template-hash 还原为 Deployment
+func podOwnerName(pod *corev1.Pod) string {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(ref.Name, "-"+hash) {
+				return "Deployment/" + strings.TrimSuffix(ref.Name, "-"+hash)
+			}
+		}
+		return ref.Kind + "/" + ref.Name
+	}
+	return ""
+}
+
+type listDeploymentsArgs struct {
+	Namespace     string `json:"namespace"`
+	LabelSelector string `json:"labelSelector"`
+	Limit         int    `json:"limit"`
+}
+
+type deploymentSummary struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Replicas   int32     `json:"replicas"`
+	Ready      int32     `json:"ready"`
+	Updated    int32     `json:"updated"`
+	Available  int32     `json:"available"`
+	Images     []string  `json:"images"`
+	Conditions []string  `json:"conditions,omitempty"` // 不满足的条件，如 Available: MinimumReplicasUnavailable
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// listDeploymentsTool 对应Shell: kubectl get deployments -n $NAMESPACE -l $LABEL_SELECTOR -o wide
+func (s *AssistantService) listDeploymentsTool() assistant.Tool {
+	return assistant.NewTool("list_deployments", "列出 Deployment 及其副本数、镜像和不满足的状态条件",
+		assistant.Schema(map[string]interface{}{
+			"namespace":     propNamespace,
+			"labelSelector": propLabelSelector,
+			"limit":         propLimit,
+		}),
+		func(args listDeploymentsArgs) []assistant.Access {
+			return []assistant.Access{{Verb: "list", Group: "apps", Resource: "deployments", Namespace: args.Namespace}}
+		},
+		func(ctx context.Context, cluster string, args listDeploymentsArgs) (assistant.Result, error) {
+			limit, err := assistantLimit(args.Limit)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			selector, err := parseLabelSelector(args.LabelSelector)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			deployments, err := s.deploymentRepo.ListByNamespace(ctx, cluster, args.Namespace)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			items := make([]deploymentSummary, 0, len(deployments))
+			for _, d := range deployments {
+				if !selector.Matches(labels.Set(d.Labels)) {
+					continue
+				}
+				item := deploymentSummary{
+					Namespace: d.Namespace,
+					Name:      d.Name,
+					Replicas:  d.Status.Replicas,
+					Ready:     d.Status.ReadyReplicas,
+					Updated:   d.Status.UpdatedReplicas,
+					Available: d.Status.AvailableReplicas,
+					CreatedAt: d.CreationTimestamp.Time,
+				}
+				if d.Spec.Replicas != nil {
+					item.Replicas = *d.Spec.Replicas
+				}
+				for _, c := range d.Spec.Template.Spec.Containers {
+					item.Images = append(item.Images, c.Image)
+				}
+				for _, cond := range d.Status.Conditions {
+					if cond.Status != corev1.ConditionTrue {
+						item.Conditions = append(item.Conditions, fmt.Sprintf("%s: %s", cond.Type, cond.Reason))
+					}
+				}
+				items = append(items, item)
+			}
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].Namespace+"/"+items[i].Name < items[j].Namespace+"/"+items[j].Name
+			})
+			return limitResult(items, limit), nil
+		},
+	)
+}
+
+type getEventsArgs struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Since     string `json:"since"`
+	Limit     int    `json:"limit"`
+}
+
+type eventSummary struct {
+	Namespace string    `json:"namespace"`
+	Object    string    `json:"object"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// getEventsTool 对应Shell: kubectl get events -n $NAMESPACE --field-selector involvedObject.name=$NAME --sort-by=.lastTimestamp
+func (s *AssistantService) getEventsTool() assistant.Tool {
+	return assistant.NewTool("get_events", "查询事件，按最后发生时间从新到旧排序",
+		assistant.Schema(map[string]interface{}{
+			"namespace": propNamespace,
+			"kind":      assistant.Prop("string", "关联对象的类型，如 Pod、Deployment、Node"),
+			"name":      assistant.Prop("string", "关联对象的名称"),
+			"type":      map[string]interface{}{"type": "string", "enum": []string{"Normal", "Warning"}, "description": "事件类型"},
+			"reason":    assistant.Prop("string", "事件原因，如 BackOff、OOMKilling、FailedScheduling"),
+			"since":     assistant.Prop("string", "只返回该时间之后发生的事件，RFC3339 时间或时长（如 1h）"),
+			"limit":     propLimit,
+		}),
+		func(args getEventsArgs) []assistant.Access {
+			return []assistant.Access{{Verb: "list", Resource: "events", Namespace: args.Namespace}}
+		},
+		func(ctx context.Context, cluster string, args getEventsArgs) (assistant.Result, error) {
+			limit, err := assistantLimit(args.Limit)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			since, err := parseSince(args.Since)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			selector := fields.Set{}
+			for field, value := range map[string]string{
+				"involvedObject.kind": args.Kind,
+				"involvedObject.name": args.Name,
+				"type":                args.Type,
+				"reason":              args.Reason,
+			} {
+				if value != "" {
+					selector[field] = value
+				}
+			}
+			events, err := s.eventRepo.List(ctx, cluster, args.Namespace, selector.AsSelector().String())
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			items := make([]eventSummary, 0, len(events))
+			for _, e := range events {
+				lastSeen := diagnosis.EventTimestamp(e)
+				if !since.IsZero() && lastSeen.Before(since) {
+					continue
+				}
+				count := e.Count
+				if e.Series != nil {
+					count = e.Series.Count
+				}
+				items = append(items, eventSummary{
+					Namespace: e.Namespace,
+					Object:    e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name,
+					Type:      e.Type,
+					Reason:    e.Reason,
+					Message:   diagnosis.RedactText(e.Message),
+					Count:     count,
+					LastSeen:  lastSeen,
+				})
+			}
+			sort.SliceStable(items, func(i, j int) bool {
+				return items[i].LastSeen.After(items[j].LastSeen)
+			})
+			return limitResult(items, limit), nil
+		},
+	)
+}
+
+type queryMetricsArgs struct {
+	Query     string `json:"query"`
+	Namespace string `json:"namespace"`
+	Time      string `json:"time"`
+}
+
+// queryMetricsTool 通过集群默认的 Prometheus 数据源执行 PromQL 即时查询
+// 指定 namespace 时查询自动限定在该命名空间，与 /monitoring/query 一致
+func (s *AssistantService) queryMetricsTool() assistant.Tool {
+	return assistant.NewTool("query_metrics", "执行 PromQL 即时查询，返回 Prometheus 的 result 数组",
+		assistant.Schema(map[string]interface{}{
+			"query":     assistant.Prop("string", "PromQL 表达式"),
+			"namespace": assistant.Prop("string", "把查询限定在该命名空间；为空时查询整个集群，需要所有命名空间的 Pod 读取权限"),
+			"time":      assistant.Prop("string", "查询时间，RFC3339 或 Unix 时间戳，默认当前时间"),
+		}, "query"),
+		func(args queryMetricsArgs) []assistant.Access {
+			return []assistant.Access{{Verb: "list", Resource: "pods", Namespace: args.Namespace}}
+		},
+		func(ctx context.Context, cluster string, args queryMetricsArgs) (assistant.Result, error) {
+			if strings.TrimSpace(args.Query) == "" {
+				return assistant.Result{}, response.ErrBadRequest("query 不能为空", nil)
+			}
+			result, err := s.monitoringService.Query(ctx, QueryParams{
+				Cluster:   cluster,
+				Namespace: args.Namespace,
+				Query:     args.Query,
+				Time:      args.Time,
+			})
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			var data struct {
+				ResultType string            `json:"resultType"`
+				Result     []json.RawMessage `json:"result"`
+			}
+			if err := json.Unmarshal(result.Data, &data); err != nil {
+				return assistant.Result{}, fmt.Errorf("failed to decode prometheus result: %w", err)
+			}
+			if data.Result == nil {
+				// scalar 和 string 类型的 result 不是数组，原样返回
+				return assistant.Result{Count: 1, Items: []json.RawMessage{result.Data}}, nil
+			}
+			return assistant.Result{Count: len(data.Result), Items: data.Result}, nil
+		},
+	)
+}
+
+type topPodsArgs struct {
+	Namespace string `json:"namespace"`
+	SortBy    string `json:"sortBy"`
+	Limit     int    `json:"limit"`
+}
+
+type podUsageSummary struct {
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	CPUMillicores int64  `json:"cpuMillicores"`
+	MemoryBytes   int64  `json:"memoryBytes"`
+}
+
+// topPodsTool 对应Shell: kubectl top pods -n $NAMESPACE --sort-by=cpu
+func (s *AssistantService) topPodsTool() assistant.Tool {
+	return assistant.NewTool("top_pods", "查询 Pod 当前的 CPU 和内存用量（metrics-server），按用量从高到低排序",
+		assistant.Schema(map[string]interface{}{
+			"namespace": propNamespace,
+			"sortBy":    map[string]interface{}{"type": "string", "enum": []string{"cpu", "memory"}, "description": "排序字段，默认 cpu"},
+			"limit":     assistant.Prop("integer", "最多返回的条目数，默认 10"),
+		}),
+		func(args topPodsArgs) []assistant.Access {
+			return []assistant.Access{{Verb: "list", Group: "metrics.k8s.io", Resource: "pods", Namespace: args.Namespace}}
+		},
+		func(ctx context.Context, cluster string, args topPodsArgs) (assistant.Result, error) {
+			if args.Limit == 0 {
+				args.Limit = 10
+			}
+			limit, err := assistantLimit(args.Limit)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			if args.SortBy != "" && args.SortBy != "cpu" && args.SortBy != "memory" {
+				return assistant.Result{}, response.ErrBadRequest("sortBy 只能是 cpu 或 memory", nil)
+			}
+			metrics, err := s.metricsRepo.ListPodMetrics(ctx, cluster, args.Namespace)
+			if err != nil {
+				return assistant.Result{}, err
+			}
+			items := make([]podUsageSummary, 0, len(metrics))
+			for _, m := range metrics {
+				item := podUsageSummary{Namespace: m.Namespace, Name: m.Name}
+				for _, c := range m.Containers {
+					q := quantities(c.Usage)
+					item.CPUMillicores += q.CPUMillicores
+					item.MemoryBytes += q.MemoryBytes
+				}
+				items = append(items, item)
+			}
+			sort.SliceStable(items, func(i, j int) bool {
+				if args.SortBy == "memory" {
+					return items[i].MemoryBytes > items[j].MemoryBytes
+				}
+				return items[i].CPUMillicores > items[j].CPUMillicores
+			})
+			return limitResult(items, limit), nil
+		},
+	)
+}
+
+func parseLabelSelector(s string) (labels.Selector, error) {
+	selector, err := labels.Parse(s)
+	if err != nil {
+		return nil, response.ErrBadRequest(fmt.Sprintf("labelSelector 无效: %v", err), err)
+	}
+	return selector, nil
+}
+
+// parseSince 解析 RFC3339 时间或相对当前的时长，为空时返回零值
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return time.Time{}, response.ErrBadRequest(fmt.Sprintf("时间 %q 无效，应为 RFC3339 时间或时长（如 24h）", s), err)
+	}
+	return time.Now().Add(-d), nil
+}
+
+func assistantLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return assistantDefaultLimit, nil
+	case limit < 0 || limit > assistantMaxLimit:
+		return 0, response.ErrBadRequest(fmt.Sprintf("limit 必须在 1 到 %d 之间", assistantMaxLimit), nil)
+	}
+	return limit, nil
+}
+
+// limitResult 只保留前 limit 条，Count 为过滤后的总数
+func limitResult[T any](items []T, limit int) assistant.Result {
+	result := assistant.Result{Count: len(items), Items: items}
+	if len(items) > limit {
+		result.Items = items[:limit]
+		result.Truncated = true
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	auditDefaultLimit = 100
+	auditMaxLimit     = 1000
+)
+
+// AuditService 审计日志查询
+// 类比Shell函数：audit_logs() { grep "$USER" /var/log/kubeops/audit.log | tail -n $LIMIT | tac; }
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+}
+
+// NewAuditService 创建审计日志 Service
+func NewAuditService(auditRepo *repository.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// List 按时间倒序查询审计日志，用上一页最后一条的 ID 作为 before 翻页
+func (s *AuditService) List(ctx context.Context, f repository.AuditFilter) ([]repository.AuditLog, error) {
+	switch {
+	case f.Limit == 0:
+		f.Limit = auditDefaultLimit
+	case f.Limit < 0 || f.Limit > auditMaxLimit:
+		return nil, response.ErrBadRequest(fmt.Sprintf("limit 必须在 1 到 %d 之间", auditMaxLimit), nil)
+	}
+	if f.Before < 0 {
+		return nil, response.ErrBadRequest("before 参数无效", nil)
+	}
+	return s.auditRepo.List(ctx, f)
+}
+
+// recordAudit 以当前用户记录一次写操作，写入失败只记录日志（操作已经完成，不能回滚）
+func recordAudit(ctx context.Context, repo *repository.AuditRepository, log repository.AuditLog, detail interface{}) {
+	log.Username = auth.UserFromContext(ctx).DisplayName()
+	if _, err := repo.Create(ctx, log, detail); err != nil {
+		logging.FromContext(ctx).Warn("Failed to write audit log",
+			zap.String("action", log.Action), zap.String("resource", log.Resource), zap.Error(err))
+	}
+}
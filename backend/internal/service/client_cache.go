@@ -0,0 +1,62 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// idleCloser 持有 HTTP 连接池的客户端，被替换或移除时关闭空闲连接
+type idleCloser interface {
+	CloseIdleConnections()
+}
+
+// clientCache 按配置 ID 缓存外部系统的客户端，复用连接
+// 配置的 UpdatedAt 变化时替换旧客户端，配置删除时移除，缓存大小不超过配置条数
+type clientCache[T any] struct {
+	mu      sync.Mutex
+	entries map[int64]cachedClient[T]
+}
+
+type cachedClient[T any] struct {
+	updatedAt time.Time
+	client    T
+}
+
+func newClientCache[T any]() *clientCache[T] {
+	return &clientCache[T]{entries: make(map[int64]cachedClient[T])}
+}
+
+// get 返回配置 id 的客户端；没有缓存或缓存的 updatedAt 与当前配置不同时调用 create 重新创建
+func (c *clientCache[T]) get(id int64, updatedAt time.Time, create func() (T, error)) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old, ok := c.entries[id]
+	if ok && old.updatedAt.Equal(updatedAt) {
+		return old.client, nil
+	}
+	client, err := create()
+	if err != nil {
+		return client, err
+	}
+	if ok {
+		closeIdle(old.client)
+	}
+	c.entries[id] = cachedClient[T]{updatedAt: updatedAt, client: client}
+	return client, nil
+}
+
+// remove 配置删除后移除缓存的客户端
+func (c *clientCache[T]) remove(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[id]; ok {
+		closeIdle(old.client)
+		delete(c.entries, id)
+	}
+}
+
+func closeIdle(client any) {
+	if ic, ok := client.(idleCloser); ok {
+		ic.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClient struct {
+	version int
+	closed  bool
+}
+
+func (c *fakeClient) CloseIdleConnections() { c.closed = true }
+
+func TestClientCache(t *testing.T) {
+	cache := newClientCache[*fakeClient]()
+	created := 0
+	create := func() (*fakeClient, error) {
+		created++
+		return &fakeClient{version: created}, nil
+	}
+	t0 := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first, _ := cache.get(1, t0, create)
+	if again, _ := cache.get(1, t0.In(time.Local), create); again != first {
+		t.Error("same updatedAt must reuse the cached client")
+	}
+
+	// 配置更新后替换，旧客户端的空闲连接被关闭，缓存中每个 ID 只保留一个客户端
+	second, _ := cache.get(1, t0.Add(time.Second), create)
+	if second == first || !first.closed {
+		t.Errorf("updated config: got version %d, old closed = %v", second.version, first.closed)
+	}
+	if len(cache.entries) != 1 {
+		t.Errorf("entries = %d, want 1", len(cache.entries))
+	}
+
+	// 创建失败时保留原客户端
+	failed := errors.New("invalid config")
+	if _, err := cache.get(1, t0.Add(2*time.Second), func() (*fakeClient, error) { return nil, failed }); !errors.Is(err, failed) {
+		t.Errorf("get() error = %v, want %v", err, failed)
+	}
+	if second.closed || cache.entries[1].client != second {
+		t.Error("a failed create must keep the previous client")
+	}
+
+	cache.remove(1)
+	if !second.closed || len(cache.entries) != 0 {
+		t.Errorf("remove(): closed = %v, entries = %d", second.closed, len(cache.entries))
+	}
+	cache.remove(1)
+}
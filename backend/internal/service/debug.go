@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// 调试的审计 action
+const (
+	AuditActionDebugEphemeral = "debug.ephemeral"
+	AuditActionDebugCopy      = "debug.copy"
+)
+
+// DebugCopyAnnotation 调试副本上记录原 Pod 名称的注解
+const DebugCopyAnnotation = "kubeops.io/debug-copy-of"
+
+// debugPollInterval 等待调试容器运行时查询 Pod 状态的间隔
+const debugPollInterval = time.Second
+
+// debugFailureReasons 容器处于这些 Waiting 原因时不会自行恢复，不再等待
+var debugFailureReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"ErrImageNeverPull":          true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+	"CrashLoopBackOff":           true,
+}
+
+// EphemeralDebugRequest 向运行中的 Pod 添加临时调试容器
+type EphemeralDebugRequest struct {
+	Cluster   string   `json:"-"`
+	Namespace string   `json:"-"`
+	Pod       string   `json:"-"`
+	Name      string   `json:"name"`    // 临时容器名，为空时生成 debugger-xxxxx
+	Image     string   `json:"image"`   // 为空时使用 debug.image
+	Target    string   `json:"target"`  // 共享进程命名空间的目标容器，为空时使用默认容器
+	Command   []string `json:"command"` // 为空时使用镜像的默认命令
+}
+
+// CopyDebugRequest 复制 Pod 用于调试，原 Pod 不受影响
+// Container 为已有容器时修改该容器的命令或镜像；否则向副本添加一个调试容器
+type CopyDebugRequest struct {
+	Cluster        string   `json:"-"`
+	Namespace      string   `json:"-"`
+	Pod            string   `json:"-"`
+	Name           string   `json:"name"`           // 副本名称，为空时为 <pod>-debug
+	Container      string   `json:"container"`      // 要修改的容器，或新增调试容器的名称（为空时生成 debugger-xxxxx）
+	Image          string   `json:"image"`          // 修改已有容器时为空表示不换镜像；新增容器时为空使用 debug.image
+	Command        []string `json:"command"`        // 替换容器的启动命令，同时清空 args
+	ShareProcesses *bool    `json:"shareProcesses"` // 副本中的容器共享进程命名空间，默认 true
+	SameNode       bool     `json:"sameNode"`       // 调度到原 Pod 所在的节点
+	KeepLabels     bool     `json:"keepLabels"`     // 保留标签；默认不保留，避免副本被 Service 和控制器选中
+}
+
+// DebugContainer 已运行的调试容器，通过 Terminal 给出的 attach 或 exec 终端接口连接
+type DebugContainer struct {
+	Cluster   string   `json:"cluster"`
+	Namespace string   `json:"namespace"`
+	Pod       string   `json:"pod"`
+	Container string   `json:"container"`
+	Image     string   `json:"image"`
+	Target    string   `json:"target,omitempty"`
+	Command   []string `json:"command,omitempty"`
+	Terminal  string   `json:"terminal"`
+}
+
+// DebugService 为没有 shell 的镜像提供调试手段：临时容器或修改了命令的 Pod 副本
+// 类比Shell: kubectl debug -it $NAME --image=busybox --target=$CONTAINER / kubectl debug $NAME --copy-to=$NAME-debug
+type DebugService struct {
+	clusters  *client.ClusterManager
+	podRepo   *repository.PodRepository
+	auditRepo *repository.AuditRepository
+	cfg       config.DebugConfig
+}
+
+// NewDebugService 创建调试 Service
+func NewDebugService(clusters *client.ClusterManager, podRepo *repository.PodRepository, auditRepo *repository.AuditRepository,
+	cfg config.DebugConfig) *DebugService {
+	return &DebugService{
+		clusters:  clusters,
+		podRepo:   podRepo,
+		auditRepo: auditRepo,
+		cfg:       cfg,
+	}
+}
+
+// CreateEphemeral 通过 ephemeralcontainers 子资源向 Pod 添加临时容器并等待其运行
+// 临时容器以 stdin 和 tty 启动，指定 target 时与目标容器共享进程命名空间，可以看到并调试目标容器中的进程
+// 临时容器无法删除，进程退出后保留在 Pod 中直到 Pod 被删除
+func (s *DebugService) CreateEphemeral(ctx context.Context, req EphemeralDebugRequest) (DebugContainer, error) {
+	cluster, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return DebugContainer{}, err
+	}
+	image, err := s.image(req.Image)
+	if err != nil {
+		return DebugContainer{}, err
+	}
+	pod, err := s.podRepo.GetByName(ctx, cluster.Name, req.Namespace, req.Pod)
+	if err != nil {
+		return DebugContainer{}, err
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return DebugContainer{}, response.ErrBadRequest(fmt.Sprintf("Pod %s 未在运行（%s），无法添加临时容器", pod.Name, pod.Status.Phase), nil)
+	}
+	target := defaultContainer(pod, req.Target)
+	if !hasContainer(pod.Spec.Containers, target) {
+		return DebugContainer{}, response.ErrBadRequest(fmt.Sprintf("Pod %s 中没有容器 %s", pod.Name, target), nil)
+	}
+	name, err := containerName(pod, req.Name)
+	if err != nil {
+		return DebugContainer{}, err
+	}
+
+	ec := corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			Command:                  req.Command,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: target,
+	}
+	updated := pod.DeepCopy()
+	updated.Spec.EphemeralContainers = append(updated.Spec.EphemeralContainers, ec)
+	if _, err := s.podRepo.UpdateEphemeralContainers(ctx, cluster.Name, updated); err != nil {
+		if apierrors.IsNotFound(err) {
+			return DebugContainer{}, response.ErrBadRequest("集群不支持临时容器（需要 Kubernetes 1.25 及以上版本）", err)
+		}
+		return DebugContainer{}, err
+	}
+
+	result := DebugContainer{
+		Cluster:   cluster.Name,
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Container: name,
+		Image:     image,
+		Target:    target,
+		Command:   req.Command,
+	}
+	result.Terminal = attachPath(result)
+	recordAudit(ctx, s.auditRepo, repository.AuditLog{
+		Action:    AuditActionDebugEphemeral,
+		Cluster:   cluster.Name,
+		Namespace: pod.Namespace,
+		Resource:  "pods/" + pod.Name,
+	}, result)
+
+	if err := s.waitRunning(ctx, cluster.Name, pod.Namespace, pod.Name, name); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// CreateCopy 复制 Pod 并修改容器的命令或镜像，等待目标容器运行
+// 副本不保留 ownerReferences、nodeName（除非 sameNode）、临时容器和探针，默认不保留标签，避免被控制器接管、接收 Service 流量或因探针失败被重启
+func (s *DebugService) CreateCopy(ctx context.Context, req CopyDebugRequest) (DebugContainer, error) {
+	cluster, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return DebugContainer{}, err
+	}
+	pod, err := s.podRepo.GetByName(ctx, cluster.Name, req.Namespace, req.Pod)
+	if err != nil {
+		return DebugContainer{}, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = pod.Name + "-debug"
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return DebugContainer{}, response.ErrBadRequest(fmt.Sprintf("副本名称 %s 无效：%s", name, errs[0]), nil)
+	}
+
+	copied := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   pod.Namespace,
+			Annotations: make(map[string]string, len(pod.Annotations)+1),
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	for k, v := range pod.Annotations {
+		copied.Annotations[k] = v
+	}
+	copied.Annotations[DebugCopyAnnotation] = pod.Name
+	if req.KeepLabels {
+		copied.Labels = pod.Labels
+	}
+	if !req.SameNode {
+		copied.Spec.NodeName = ""
+	}
+	copied.Spec.EphemeralContainers = nil
+	shareProcesses := req.ShareProcesses == nil || *req.ShareProcesses
+	copied.Spec.ShareProcessNamespace = &shareProcesses
+	for i := range copied.Spec.Containers {
+		c := &copied.Spec.Containers[i]
+		c.LivenessProbe, c.ReadinessProbe, c.StartupProbe = nil, nil, nil
+	}
+
+	result := DebugContainer{Cluster: cluster.Name, Namespace: pod.Namespace, Pod: name, Command: req.Command}
+	if i := containerIndex(copied.Spec.Containers, req.Container); i >= 0 {
+		// 修改已有容器：常用于把启动即崩溃的命令换成 sleep，再进入容器排查
+		c := &copied.Spec.Containers[i]
+		if req.Image != "" {
+			if c.Image, err = s.image(req.Image); err != nil {
+				return DebugContainer{}, err
+			}
+		}
+		if len(req.Command) > 0 {
+			c.Command, c.Args = req.Command, nil
+		}
+		c.Stdin, c.TTY = true, true
+		result.Container, result.Image = c.Name, c.Image
+	} else {
+		image, err := s.image(req.Image)
+		if err != nil {
+			return DebugContainer{}, err
+		}
+		containerName, err := containerName(pod, req.Container)
+		if err != nil {
+			return DebugContainer{}, err
+		}
+		copied.Spec.Containers = append(copied.Spec.Containers, corev1.Container{
+			Name:                     containerName,
+			Image:                    image,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			Command:                  req.Command,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		})
+		result.Container, result.Image = containerName, image
+	}
+	result.Terminal = attachPath(result)
+
+	if _, err := s.podRepo.Create(ctx, cluster.Name, copied); err != nil {
+		return DebugContainer{}, err
+	}
+	recordAudit(ctx, s.auditRepo, repository.AuditLog{
+		Action:    AuditActionDebugCopy,
+		Cluster:   cluster.Name,
+		Namespace: pod.Namespace,
+		Resource:  "pods/" + pod.Name,
+	}, result)
+
+	if err := s.waitRunning(ctx, cluster.Name, pod.Namespace, name, result.Container); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// waitRunning 轮询 Pod 状态直到容器运行；镜像拉取失败等不会自行恢复的状态立即返回错误
+func (s *DebugService) waitRunning(ctx context.Context, cluster, namespace, pod, container string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.StartTimeout)
+	defer cancel()
+	ticker := time.NewTicker(debugPollInterval)
+	defer ticker.Stop()
+
+	var last string
+	for {
+		p, err := s.podRepo.GetByName(ctx, cluster, namespace, pod)
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if p != nil {
+			state, done, err := containerState(p, container)
+			if done {
+				return err
+			}
+			last = state
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return response.NewError(http.StatusGatewayTimeout, response.CodeTimeout,
+					fmt.Sprintf("调试容器 %s 在 %s 内未运行（%s）", container, s.cfg.StartTimeout, last), nil)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// containerState 返回容器当前状态的描述，以及是否不必再等待（运行中或已失败）
+func containerState(pod *corev1.Pod, container string) (string, bool, error) {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return string(pod.Status.Phase), true, response.ErrBadRequest(fmt.Sprintf("Pod %s 已结束（%s）", pod.Name, pod.Status.Phase), nil)
+	}
+	statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.ContainerStatuses...), pod.Status.EphemeralContainerStatuses...)
+	for _, st := range statuses {
+		if st.Name != container {
+			continue
+		}
+		switch {
+		case st.State.Running != nil:
+			return "Running", true, nil
+		case st.State.Terminated != nil:
+			t := st.State.Terminated
+			return "Terminated", true, response.ErrBadRequest(
+				fmt.Sprintf("调试容器 %s 已退出（%s，退出码 %d）：%s", container, t.Reason, t.ExitCode, t.Message), nil)
+		case st.State.Waiting != nil:
+			w := st.State.Waiting
+			if debugFailureReasons[w.Reason] {
+				return w.Reason, true, response.ErrBadRequest(fmt.Sprintf("调试容器 %s 无法启动（%s）：%s", container, w.Reason, w.Message), nil)
+			}
+			return w.Reason, false, nil
+		}
+	}
+	return string(pod.Status.Phase), false, nil
+}
+
+// image 返回要使用的调试镜像，配置了 allowedImages 时必须匹配其中之一
+func (s *DebugService) image(image string) (string, error) {
+	if image == "" {
+		return s.cfg.Image, nil
+	}
+	if len(s.cfg.AllowedImages) == 0 {
+		return image, nil
+	}
+	for _, pattern := range s.cfg.AllowedImages {
+		if ok, _ := path.Match(pattern, image); ok {
+			return image, nil
+		}
+	}
+	return "", response.NewError(http.StatusForbidden, response.CodeForbidden, fmt.Sprintf("不允许使用调试镜像 %s", image), nil)
+}
+
+// containerName 校验或生成新容器的名称，不能与 Pod 中已有的容器重名
+func containerName(pod *corev1.Pod, name string) (string, error) {
+	if name == "" {
+		// 与 kubectl debug 一致，随机后缀避免与之前添加的临时容器冲突
+		for {
+			name = "debugger-" + utilrand.String(5)
+			if !podHasContainer(pod, name) {
+				return name, nil
+			}
+		}
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return "", response.ErrBadRequest(fmt.Sprintf("容器名称 %s 无效：%s", name, errs[0]), nil)
+	}
+	if podHasContainer(pod, name) {
+		return "", response.NewError(http.StatusConflict, response.CodeAlreadyExists, fmt.Sprintf("Pod %s 中已有容器 %s", pod.Name, name), nil)
+	}
+	return name, nil
+}
+
+func podHasContainer(pod *corev1.Pod, name string) bool {
+	if hasContainer(pod.Spec.Containers, name) || hasContainer(pod.Spec.InitContainers, name) {
+		return true
+	}
+	for _, ec := range pod.Spec.EphemeralContainers {
+		if ec.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasContainer(containers []corev1.Container, name string) bool {
+	return containerIndex(containers, name) >= 0
+}
+
+func containerIndex(containers []corev1.Container, name string) int {
+	for i, c := range containers {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// attachPath 连接调试容器主进程的终端接口
+func attachPath(c DebugContainer) string {
+	q := url.Values{"container": {c.Container}, "cluster": {c.Cluster}}
+	return fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/attach?%s", c.Namespace, c.Pod, q.Encode())
+}
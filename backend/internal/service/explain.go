@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/diagnosis"
+	"github.com/yansongwel/kubeops/backend/internal/llm"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	explainKeyPrefix = "kubeops:explain:"
+	// explainLogBytes 每个容器读取日志的字节上限，避免单行超长的日志占满内存
+	explainLogBytes = 256 << 10
+	// explainMaxLineLength 单行日志的字符上限
+	explainMaxLineLength = 2000
+)
+
+// ExplainOptions Pod 根因分析参数
+type ExplainOptions struct {
+	Container string // 为空时分析所有容器
+	TailLines int    // 为 0 时使用 llm.explain.logLines
+	Refresh   bool   // 忽略缓存重新分析
+}
+
+// Explanation Pod 根因分析结果
+type Explanation struct {
+	diagnosis.Diagnosis
+	Cluster     string               `json:"cluster"`
+	Namespace   string               `json:"namespace"`
+	Pod         string               `json:"pod"`
+	Model       string               `json:"model"`
+	Cached      bool                 `json:"cached"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+	Usage       llm.Usage            `json:"usage"`
+	Context     diagnosis.Truncation `json:"context"`
+	Incomplete  bool                 `json:"incomplete,omitempty"` // 模型输出达到 maxOutputTokens 被截断
+}
+
+// ExplainService 基于大模型的 Pod 根因分析
+// 类比Shell函数：explain() { { kubectl get pod -o yaml; kubectl get events; kubectl logs --previous; } | redact | llm "为什么这个 Pod 异常?"; }
+type ExplainService struct {
+	clusters  *client.ClusterManager
+	podRepo   *repository.PodRepository
+	eventRepo *repository.EventRepository
+	llm       llm.Client
+	redis     *redis.Client
+	redisDep  *client.Dependency
+	budget    *tokenBudget
+	cfg       config.LLMConfig
+}
+
+// NewExplainService 创建根因分析 Service，llmClient 为 nil 表示未配置大模型
+func NewExplainService(
+	clusters *client.ClusterManager,
+	podRepo *repository.PodRepository,
+	eventRepo *repository.EventRepository,
+	llmClient llm.Client,
+	redisClient *redis.Client,
+	redisDep *client.Dependency,
+	cfg config.LLMConfig,
+) *ExplainService {
+	return &ExplainService{
+		clusters:  clusters,
+		podRepo:   podRepo,
+		eventRepo: eventRepo,
+		llm:       llmClient,
+		redis:     redisClient,
+		redisDep:  redisDep,
+		budget:    newTokenBudget(redisClient, redisDep, cfg.DailyTokenBudget),
+		cfg:       cfg,
+	}
+}
+
+// ExplainPod 收集 Pod 定义、状态、事件和最近日志，脱敏后交给大模型分析根因
+// 结果按 Pod 的 resourceVersion 和最新事件缓存，Pod 状态变化或出现新事件后重新分析
+func (s *ExplainService) ExplainPod(ctx context.Context, cluster, namespace, name string, opts ExplainOptions) (Explanation, error) {
+	if s.llm == nil {
+		return Explanation{}, response.NewError(http.StatusServiceUnavailable, response.CodeServiceUnavailable,
+			"未配置大模型（llm.provider）", nil)
+	}
+	if opts.TailLines <= 0 {
+		opts.TailLines = s.cfg.Explain.LogLines
+	}
+	if opts.TailLines > 1000 {
+		return Explanation{}, response.ErrBadRequest("tailLines 不能超过 1000", nil)
+	}
+
+	c, err := s.clusters.Get(cluster)
+	if err != nil {
+		return Explanation{}, err
+	}
+	pod, err := s.podRepo.GetByName(ctx, c.Name, namespace, name)
+	if err != nil {
+		return Explanation{}, err
+	}
+	containers, err := explainContainers(pod, opts.Container)
+	if err != nil {
+		return Explanation{}, err
+	}
+	events, err := s.eventRepo.ListForObject(ctx, c.Name, namespace, "Pod", name)
+	if err != nil {
+		return Explanation{}, err
+	}
+	events = diagnosis.LatestEvents(events, s.cfg.Explain.Events)
+
+	key := s.cacheKey(c.Name, pod, events, opts)
+	if !opts.Refresh {
+		if cached, ok := s.cached(ctx, key); ok {
+			return cached, nil
+		}
+	}
+	if err := s.budget.Check(ctx); err != nil {
+		return Explanation{}, err
+	}
+
+	pc := diagnosis.PodContext{
+		Cluster: c.Name,
+		Pod:     diagnosis.RedactPod(pod),
+		Events:  redactEvents(events),
+		Logs:    s.collectLogs(ctx, c.Name, pod, containers, int64(opts.TailLines)),
+	}
+	messages, truncation := diagnosis.BuildMessages(pc, s.cfg.MaxInputTokens)
+
+	reply, err := s.llm.Chat(ctx, llm.Request{
+		Messages:    messages,
+		MaxTokens:   s.cfg.MaxOutputTokens,
+		Temperature: s.cfg.Temperature,
+		JSON:        true,
+	})
+	if err != nil {
+		return Explanation{}, err
+	}
+	s.budget.Record(ctx, reply.Usage)
+
+	d, err := diagnosis.Parse(reply.Content)
+	if err != nil {
+		return Explanation{}, err
+	}
+	result := Explanation{
+		Diagnosis:   d,
+		Cluster:     c.Name,
+		Namespace:   namespace,
+		Pod:         name,
+		Model:       reply.Model,
+		GeneratedAt: time.Now(),
+		Usage:       reply.Usage,
+		Context:     truncation,
+		Incomplete:  reply.FinishReason == "length",
+	}
+	s.store(ctx, key, result)
+	return result, nil
+}
+
+// explainContainers 要读取日志的容器：指定的容器，或所有业务容器及未成功结束的 init 容器
+func explainContainers(pod *corev1.Pod, container string) ([]string, error) {
+	var names []string
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, st := range pod.Status.InitContainerStatuses {
+		statuses[st.Name] = st
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if container == c.Name {
+			return []string{c.Name}, nil
+		}
+		st, ok := statuses[c.Name]
+		if ok && st.State.Terminated != nil && st.State.Terminated.ExitCode == 0 {
+			continue
+		}
+		if ok && (st.RestartCount > 0 || st.State.Running != nil || st.State.Terminated != nil) {
+			names = append(names, c.Name)
+		}
+	}
+	for _, c := range pod.Spec.Containers {
+		if container == c.Name {
+			return []string{c.Name}, nil
+		}
+		names = append(names, c.Name)
+	}
+	if container != "" {
+		return nil, response.ErrBadRequest(fmt.Sprintf("Pod %s 中没有容器 %s", pod.Name, container), nil)
+	}
+	return names, nil
+}
+
+// collectLogs 并发读取容器当前和上一次重启前的日志，读取失败时把原因作为日志内容交给模型
+func (s *ExplainService) collectLogs(ctx context.Context, cluster string, pod *corev1.Pod, containers []string, tailLines int64) []diagnosis.ContainerLogs {
+	restarted := make(map[string]bool)
+	for _, st := range append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		restarted[st.Name] = st.RestartCount > 0 || st.LastTerminationState.Terminated != nil
+	}
+
+	var blocks []diagnosis.ContainerLogs
+	for _, name := range containers {
+		if restarted[name] {
+			blocks = append(blocks, diagnosis.ContainerLogs{Container: name, Previous: true})
+		}
+		blocks = append(blocks, diagnosis.ContainerLogs{Container: name})
+	}
+
+	var g errgroup.Group
+	for i := range blocks {
+		b := &blocks[i]
+		g.Go(func() error {
+			text, err := s.podRepo.GetLogs(ctx, cluster, pod.Namespace, pod.Name, b.Container, b.Previous, tailLines, explainLogBytes)
+			if err != nil {
+				logging.FromContext(ctx).Debug("Failed to read container logs for explain",
+					zap.String("container", b.Container), zap.Bool("previous", b.Previous), zap.Error(err))
+				b.Lines = []string{"(无法读取日志: " + diagnosis.RedactText(err.Error()) + ")"}
+				return nil
+			}
+			b.Lines = splitLogLines(text)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return blocks
+}
+
+// splitLogLines 按行拆分并脱敏，超长的行截断
+func splitLogLines(text string) []string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if r := []rune(line); len(r) > explainMaxLineLength {
+			line = string(r[:explainMaxLineLength]) + "..."
+		}
+		lines[i] = diagnosis.RedactText(line)
+	}
+	return lines
+}
+
+func redactEvents(events []corev1.Event) []corev1.Event {
+	out := make([]corev1.Event, len(events))
+	for i, e := range events {
+		e.Message = diagnosis.RedactText(e.Message)
+		out[i] = e
+	}
+	return out
+}
+
+// cacheKey Pod 的 resourceVersion 在状态变化（重启、就绪）时改变，最新事件的 resourceVersion 在出现新事件时改变
+func (s *ExplainService) cacheKey(cluster string, pod *corev1.Pod, events []corev1.Event, opts ExplainOptions) string {
+	latestEvent := ""
+	if n := len(events); n > 0 {
+		latestEvent = events[n-1].ResourceVersion
+	}
+	raw := strings.Join([]string{
+		s.llm.Model(), string(pod.UID), pod.ResourceVersion, latestEvent, opts.Container, strconv.Itoa(opts.TailLines),
+	}, "\x00")
+	sum := sha256.Sum256([]byte(raw))
+	return fmt.Sprintf("%s%s:%s:%s:%s", explainKeyPrefix, cluster, pod.Namespace, pod.Name, hex.EncodeToString(sum[:8]))
+}
+
+// cached 读取缓存的分析结果，Redis 不可用或读取失败时视为未命中
+func (s *ExplainService) cached(ctx context.Context, key string) (Explanation, bool) {
+	if s.cfg.Explain.CacheTTL <= 0 || s.redisDep.Err() != nil {
+		return Explanation{}, false
+	}
+	data, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			logging.FromContext(ctx).Warn("Explain cache read failed", zap.String("key", key), zap.Error(err))
+		}
+		return Explanation{}, false
+	}
+	var result Explanation
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Explanation{}, false
+	}
+	result.Cached = true
+	return result, true
+}
+
+func (s *ExplainService) store(ctx context.Context, key string, result Explanation) {
+	if s.cfg.Explain.CacheTTL <= 0 || s.redisDep.Err() != nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Set(ctx, key, data, s.cfg.Explain.CacheTTL).Err(); err != nil {
+		logging.FromContext(ctx).Warn("Explain cache write failed", zap.String("key", key), zap.Error(err))
+	}
+}
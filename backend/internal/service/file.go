@@ -0,0 +1,479 @@
+package service
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/exec"
+
+	"github.com/yansongwel/kubeops/backend/internal/archive"
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// 文件传输的审计 action
+const (
+	AuditActionFileUpload   = "files.upload"
+	AuditActionFileDownload = "files.download"
+)
+
+// 传输方向和状态
+const (
+	FileTransferUpload   = "upload"
+	FileTransferDownload = "download"
+
+	FileTransferRunning   = "running"
+	FileTransferSucceeded = "succeeded"
+	FileTransferFailed    = "failed"
+)
+
+// fileTransferRetention 传输结束后保留进度的时长
+const fileTransferRetention = 10 * time.Minute
+
+// maxStderrBytes 保留容器中 tar 输出的错误信息的长度
+const maxStderrBytes = 4 * 1024
+
+// errTransferStopped 一端结束后关闭管道，使另一端停止读写
+var errTransferStopped = errors.New("transfer stopped")
+
+// defaultContainerAnnotation kubectl 选择默认容器时使用的注解
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// FileRequest 容器中的文件或目录，与 Pod 详情接口使用相同的 cluster、namespace、name 定位 Pod
+type FileRequest struct {
+	Cluster    string
+	Namespace  string
+	Pod        string
+	Container  string // 为空时使用默认容器
+	Path       string // 容器中的绝对路径
+	TransferID string // 调用方指定的传输 ID（UUID），用于在上传过程中查询进度；为空时自动生成
+}
+
+// FileTransfer 一次上传或下载的进度
+type FileTransfer struct {
+	ID         string     `json:"id"`
+	Direction  string     `json:"direction"`
+	Cluster    string     `json:"cluster"`
+	Namespace  string     `json:"namespace"`
+	Pod        string     `json:"pod"`
+	Container  string     `json:"container"`
+	Path       string     `json:"path"`
+	Status     string     `json:"status"`
+	Entries    int        `json:"entries"`         // 已复制的文件、目录和链接数
+	Bytes      int64      `json:"bytes"`           // 已复制的文件内容字节数
+	Total      int64      `json:"total,omitempty"` // 上传单个文件时为文件大小，其余情况未知
+	Current    string     `json:"current,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	user string
+}
+
+// FileService 通过 exec 在容器中运行 tar 上传下载文件，数据流式转发，后端不落盘
+// 类比Shell: kubectl cp $NAMESPACE/$NAME:$PATH ./local -c $CONTAINER
+type FileService struct {
+	clusters  *client.ClusterManager
+	podRepo   *repository.PodRepository
+	auditRepo *repository.AuditRepository
+	cfg       config.FileTransferConfig
+
+	mu        sync.Mutex
+	transfers map[string]*FileTransfer // 本副本上的传输进度
+}
+
+// NewFileService 创建容器文件 Service
+func NewFileService(clusters *client.ClusterManager, podRepo *repository.PodRepository, auditRepo *repository.AuditRepository,
+	cfg config.FileTransferConfig) *FileService {
+	return &FileService{
+		clusters:  clusters,
+		podRepo:   podRepo,
+		auditRepo: auditRepo,
+		cfg:       cfg,
+		transfers: make(map[string]*FileTransfer),
+	}
+}
+
+// Download 把容器中的文件或目录打包为 tar 写入 open 返回的 Writer，包中只有一个以 path 最后一级命名的顶层条目
+// open 在写入第一个字节前调用，之前发生的错误（路径不存在、超过大小限制等）以普通错误返回，调用方仍可返回 JSON；
+// 之后发生错误时不写入 tar 的结束标记，客户端解包时会报错
+// 对应Shell: kubectl exec $NAME -c $CONTAINER -- tar cf - -C $(dirname $PATH) -- $(basename $PATH)
+func (s *FileService) Download(ctx context.Context, req FileRequest, open func(t FileTransfer) io.Writer) (FileTransfer, error) {
+	if err := validateFilePath(req.Path); err != nil {
+		return FileTransfer{}, err
+	}
+	if req.Path == "/" {
+		return FileTransfer{}, response.ErrBadRequest("不能下载根目录", nil)
+	}
+	t, err := s.begin(ctx, FileTransferDownload, req)
+	if err != nil {
+		return FileTransfer{}, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	// 停止读取后远端的 tar 会阻塞在写 stdout 上，需要取消 exec 才能结束
+	execCtx, stopExec := context.WithCancel(ctx)
+	defer stopExec()
+
+	base := path.Base(t.Path)
+	pr, pw := io.Pipe()
+	stderr := &limitedBuffer{limit: maxStderrBytes}
+	execDone := make(chan error, 1)
+	go func() {
+		err := s.podRepo.Exec(execCtx, t.Cluster, t.Namespace, t.Pod, t.Container,
+			tarCreateCommand(t.Path), nil, pw, stderr)
+		_ = pw.CloseWithError(err)
+		execDone <- err
+	}()
+
+	w := &lazyWriter{open: func() io.Writer { return open(s.snapshot(t.ID)) }}
+	tw := tar.NewWriter(w)
+	_, copyErr := archive.Copy(tw, tar.NewReader(pr), archive.Options{
+		MaxBytes:   s.cfg.MaxDownloadMB << 20,
+		Root:       base,
+		OnProgress: s.progress(t.ID),
+	})
+	if copyErr == nil {
+		// 读到结束标记后 tar 还会输出补齐记录的空块，读完才能让 tar 正常退出
+		_, _ = io.Copy(io.Discard, pr)
+	} else {
+		stopExec()
+	}
+	_ = pr.CloseWithError(errTransferStopped)
+	execErr := <-execDone
+
+	switch {
+	case copyErr != nil && (errors.Is(copyErr, archive.ErrTooLarge) || isEntryError(copyErr)):
+		err = transferError(copyErr, s.cfg.MaxDownloadMB)
+	case execErr != nil && (copyErr == nil || !errors.Is(execErr, context.Canceled)):
+		err = execError(execErr, stderr.String())
+	case copyErr != nil:
+		err = fmt.Errorf("failed to copy archive: %w", copyErr)
+	default:
+		err = tw.Close()
+	}
+	return s.finish(ctx, t.ID, AuditActionFileDownload, err)
+}
+
+// Upload 把客户端上传的内容写入容器
+// archived 为 true 时 body 为 tar 包，解包到目录 path 中；否则 body 为单个文件的内容，写入文件 path，size 为文件大小
+// 包中的条目先经过校验再写入容器，某个条目校验失败时之前的条目已经写入，不会回滚
+// 对应Shell: tar cf - $LOCAL | kubectl exec -i $NAME -c $CONTAINER -- tar xmf - -C $PATH --
+func (s *FileService) Upload(ctx context.Context, req FileRequest, body io.Reader, archived bool, size int64) (FileTransfer, error) {
+	if err := validateFilePath(req.Path); err != nil {
+		return FileTransfer{}, err
+	}
+	limit := s.cfg.MaxUploadMB << 20
+	dir, name := path.Clean(req.Path), ""
+	if !archived {
+		if req.Path == "/" || strings.HasSuffix(req.Path, "/") {
+			return FileTransfer{}, response.ErrBadRequest("上传单个文件时 path 必须是文件路径", nil)
+		}
+		if size < 0 {
+			return FileTransfer{}, response.ErrBadRequest("上传单个文件时需要 Content-Length，上传目录请使用 tar 包", nil)
+		}
+		if size > limit {
+			return FileTransfer{}, transferError(archive.ErrTooLarge, s.cfg.MaxUploadMB)
+		}
+		dir, name = path.Split(path.Clean(req.Path))
+	}
+	t, err := s.begin(ctx, FileTransferUpload, req)
+	if err != nil {
+		return FileTransfer{}, err
+	}
+	if !archived {
+		s.update(t.ID, func(t *FileTransfer) { t.Total = size })
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	// 请求体校验失败或读取出错时取消 exec，不再等待容器中的 tar 发现输入不完整
+	execCtx, stopExec := context.WithCancel(ctx)
+	defer stopExec()
+
+	pr, pw := io.Pipe()
+	writeDone := make(chan error, 1)
+	go func() {
+		tw := tar.NewWriter(pw)
+		var err error
+		if archived {
+			_, err = archive.Copy(tw, tar.NewReader(body), archive.Options{MaxBytes: limit, OnProgress: s.progress(t.ID)})
+		} else {
+			_, err = archive.WriteFile(tw, name, size, body, s.progress(t.ID))
+		}
+		// 结束标记没有送达时容器中的 tar 会报错，这里只关心内容是否写完
+		if err == nil {
+			_ = tw.Close()
+		} else if !errors.Is(err, errTransferStopped) {
+			stopExec()
+		}
+		_ = pw.CloseWithError(err)
+		writeDone <- err
+	}()
+
+	stderr := &limitedBuffer{limit: maxStderrBytes}
+	execErr := s.podRepo.Exec(execCtx, t.Cluster, t.Namespace, t.Pod, t.Container,
+		tarExtractCommand(dir), pr, nil, stderr)
+	// tar 提前退出时停止读取请求体
+	_ = pr.CloseWithError(errTransferStopped)
+	writeErr := <-writeDone
+
+	switch {
+	case writeErr != nil && (errors.Is(writeErr, archive.ErrTooLarge) || isEntryError(writeErr)):
+		err = transferError(writeErr, s.cfg.MaxUploadMB)
+	case writeErr != nil && !errors.Is(writeErr, errTransferStopped):
+		err = response.ErrBadRequest("读取上传内容失败", writeErr)
+	case execErr != nil:
+		err = execError(execErr, stderr.String())
+	case writeErr != nil:
+		err = response.NewError(http.StatusBadGateway, response.CodeBadGateway, "容器中的 tar 在上传完成前退出", nil)
+	}
+	return s.finish(ctx, t.ID, AuditActionFileUpload, err)
+}
+
+// GetTransfer 查询本副本上的传输进度，只能查看自己发起的传输
+func (s *FileService) GetTransfer(ctx context.Context, id string) (FileTransfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.transfers[id]
+	if !ok || t.user != auth.UserFromContext(ctx).ID() {
+		return FileTransfer{}, response.ErrNotFound("传输不存在或已过期", nil)
+	}
+	return *t, nil
+}
+
+// begin 校验 Pod 和容器后登记一次传输
+func (s *FileService) begin(ctx context.Context, direction string, req FileRequest) (FileTransfer, error) {
+	cluster, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return FileTransfer{}, err
+	}
+	pod, err := s.podRepo.GetByName(ctx, cluster.Name, req.Namespace, req.Pod)
+	if err != nil {
+		return FileTransfer{}, err
+	}
+	container, err := runningContainer(pod, req.Container)
+	if err != nil {
+		return FileTransfer{}, err
+	}
+
+	id := req.TransferID
+	if id == "" {
+		id = uuid.NewString()
+	} else if _, err := uuid.Parse(id); err != nil {
+		return FileTransfer{}, response.ErrBadRequest("transferId 必须是 UUID", err)
+	}
+
+	now := time.Now()
+	t := &FileTransfer{
+		ID:        id,
+		Direction: direction,
+		Cluster:   cluster.Name,
+		Namespace: req.Namespace,
+		Pod:       req.Pod,
+		Container: container,
+		Path:      path.Clean(req.Path),
+		Status:    FileTransferRunning,
+		StartedAt: now,
+		user:      auth.UserFromContext(ctx).ID(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, old := range s.transfers {
+		if old.FinishedAt != nil && now.Sub(*old.FinishedAt) > fileTransferRetention {
+			delete(s.transfers, key)
+		}
+	}
+	if _, ok := s.transfers[id]; ok {
+		return FileTransfer{}, response.NewError(http.StatusConflict, response.CodeAlreadyExists, "transferId 已被使用", nil)
+	}
+	s.transfers[id] = t
+	return *t, nil
+}
+
+// finish 记录传输结果并写入审计日志
+func (s *FileService) finish(ctx context.Context, id, action string, err error) (FileTransfer, error) {
+	now := time.Now()
+	s.update(id, func(t *FileTransfer) {
+		t.FinishedAt = &now
+		t.Current = ""
+		t.Status = FileTransferSucceeded
+		if err != nil {
+			t.Status = FileTransferFailed
+			t.Error = response.FromError(err).Message
+		}
+	})
+	t := s.snapshot(id)
+	recordAudit(context.WithoutCancel(ctx), s.auditRepo, repository.AuditLog{
+		Action:    action,
+		Cluster:   t.Cluster,
+		Namespace: t.Namespace,
+		Resource:  "pods/" + t.Pod,
+	}, t)
+	return t, err
+}
+
+// progress 返回更新传输进度的回调
+func (s *FileService) progress(id string) func(archive.Progress) {
+	return func(p archive.Progress) {
+		s.update(id, func(t *FileTransfer) {
+			t.Entries = p.Entries
+			t.Bytes = p.Bytes
+			t.Current = p.Current
+		})
+	}
+}
+
+func (s *FileService) update(id string, fn func(t *FileTransfer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.transfers[id]; ok {
+		fn(t)
+	}
+}
+
+func (s *FileService) snapshot(id string) FileTransfer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.transfers[id]; ok {
+		return *t
+	}
+	return FileTransfer{ID: id}
+}
+
+// validateFilePath 容器中的路径必须是绝对路径，不能包含 ..
+func validateFilePath(p string) error {
+	if !path.IsAbs(p) {
+		return response.ErrBadRequest("path 必须是容器中的绝对路径", nil)
+	}
+	for _, elem := range strings.Split(p, "/") {
+		if elem == ".." {
+			return response.ErrBadRequest("path 不能包含 ..", nil)
+		}
+	}
+	return nil
+}
+
+// tarCreateCommand 在容器中把 p 打包输出到 stdout 的命令，包中的顶层条目以 p 的最后一级命名
+// 文件名可以以 - 开头（如 --checkpoint-action=exec=...），放在 -- 之后才不会被 tar 当作选项
+func tarCreateCommand(p string) []string {
+	dir, base := path.Split(p)
+	return []string{"tar", "cf", "-", "-C", dir, "--", base}
+}
+
+// tarExtractCommand 在容器中把 stdin 的 tar 包解到目录 dir 的命令
+func tarExtractCommand(dir string) []string {
+	return []string{"tar", "xmf", "-", "-C", dir, "--"}
+}
+
+// runningContainer 返回要操作的容器名，未指定时与 kubectl 一致优先使用 default-container 注解，其次是第一个容器
+// 也可以指定正在运行的 init 容器和临时容器
+func runningContainer(pod *corev1.Pod, name string) (string, error) {
+	name = defaultContainer(pod, name)
+	statuses := append(append(append([]corev1.ContainerStatus(nil), pod.Status.ContainerStatuses...),
+		pod.Status.InitContainerStatuses...), pod.Status.EphemeralContainerStatuses...)
+	for _, st := range statuses {
+		if st.Name != name {
+			continue
+		}
+		if st.State.Running == nil {
+			return "", response.ErrBadRequest(fmt.Sprintf("容器 %s 未在运行", name), nil)
+		}
+		return name, nil
+	}
+	return "", response.ErrBadRequest(fmt.Sprintf("Pod %s 中没有容器 %s", pod.Name, name), nil)
+}
+
+// defaultContainer name 为空时返回 default-container 注解指定的容器，其次是第一个容器
+func defaultContainer(pod *corev1.Pod, name string) string {
+	if name == "" {
+		name = pod.Annotations[defaultContainerAnnotation]
+	}
+	if name == "" && len(pod.Spec.Containers) > 0 {
+		name = pod.Spec.Containers[0].Name
+	}
+	return name
+}
+
+// transferError 转换大小超限和不安全条目的错误
+func transferError(err error, limitMB int64) error {
+	if errors.Is(err, archive.ErrTooLarge) {
+		return response.NewError(http.StatusRequestEntityTooLarge, response.CodePayloadTooLarge,
+			fmt.Sprintf("文件总大小超过 %d MiB 的限制", limitMB), nil)
+	}
+	return response.ErrBadRequest("tar 包中包含不安全的路径", err)
+}
+
+func isEntryError(err error) bool {
+	var entryErr *archive.EntryError
+	return errors.As(err, &entryErr)
+}
+
+// execError 根据容器中 tar 的错误输出转换错误
+func execError(err error, stderr string) error {
+	stderr = strings.TrimSpace(stderr)
+	var exitErr exec.ExitError
+	switch {
+	case strings.Contains(err.Error(), "executable file not found") || strings.Contains(stderr, "executable file not found"):
+		return response.ErrBadRequest("容器中没有 tar 命令，无法复制文件", err)
+	case errors.As(err, &exitErr):
+		// GNU tar 和 busybox tar 找不到文件或目录时都输出 No such file or directory
+		if strings.Contains(stderr, "No such file or directory") {
+			return response.ErrNotFound("容器中的路径不存在", errors.New(stderr))
+		}
+		return response.ErrBadRequest(fmt.Sprintf("容器中的 tar 以退出码 %d 结束", exitErr.ExitStatus()), errors.New(stderr))
+	case errors.Is(err, context.DeadlineExceeded):
+		return response.NewError(http.StatusGatewayTimeout, response.CodeTimeout, "文件传输超时", err)
+	default:
+		return response.NewError(http.StatusBadGateway, response.CodeBadGateway, "在容器中执行 tar 失败", err)
+	}
+}
+
+// lazyWriter 第一次写入时才调用 open，之前发生的错误仍可以返回 JSON
+type lazyWriter struct {
+	open func() io.Writer
+	w    io.Writer
+}
+
+func (l *lazyWriter) Write(p []byte) (int, error) {
+	if l.w == nil {
+		l.w = l.open()
+	}
+	return l.w.Write(p)
+}
+
+// limitedBuffer 只保留前 limit 字节的输出
+type limitedBuffer struct {
+	mu    sync.Mutex
+	limit int
+	buf   []byte
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n := b.limit - len(b.buf); n > 0 {
+		b.buf = append(b.buf, p[:min(n, len(p))]...)
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateFilePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{path: "/var/log/app.log"},
+		{path: "/"},
+		{path: "/data/-rf"},
+		{path: "/data/..backup"},
+		{path: "var/log", wantErr: true},
+		{path: "", wantErr: true},
+		{path: "/var/../etc/shadow", wantErr: true},
+		{path: "/var/log/..", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := validateFilePath(tt.path); (err != nil) != tt.wantErr {
+			t.Errorf("validateFilePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTarCommands(t *testing.T) {
+	// 以 - 开头的文件名放在 -- 之后，不会被 tar 解析为选项
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "/var/log/app.log", want: []string{"tar", "cf", "-", "-C", "/var/log/", "--", "app.log"}},
+		{path: "/tmp/--checkpoint-action=exec=sh", want: []string{"tar", "cf", "-", "-C", "/tmp/", "--", "--checkpoint-action=exec=sh"}},
+		{path: "/-v", want: []string{"tar", "cf", "-", "-C", "/", "--", "-v"}},
+	}
+	for _, tt := range tests {
+		if got := tarCreateCommand(tt.path); !slices.Equal(got, tt.want) {
+			t.Errorf("tarCreateCommand(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+	if got, want := tarExtractCommand("/data/-x"), []string{"tar", "xmf", "-", "-C", "/data/-x", "--"}; !slices.Equal(got, want) {
+		t.Errorf("tarExtractCommand() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/inspection"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// InspectionRequest 巡检参数
+type InspectionRequest struct {
+	Cluster string `json:"cluster"`
+	inspection.Scope
+}
+
+// InspectionRunDetail 巡检记录及其问题
+type InspectionRunDetail struct {
+	repository.InspectionRun
+	Findings []repository.InspectionFinding `json:"findings"`
+}
+
+// InspectionDiff 一次巡检与范围相同的上一次巡检的差异，不含已确认或屏蔽的问题
+type InspectionDiff struct {
+	Run      repository.InspectionRun       `json:"run"`
+	Previous *repository.InspectionRun      `json:"previous,omitempty"` // 为空表示这是该范围的第一次巡检
+	New      []repository.InspectionFinding `json:"new"`
+	Resolved []repository.InspectionFinding `json:"resolved"`
+	Open     []repository.InspectionFinding `json:"open"` // 上一次已存在、本次仍未解决
+}
+
+const (
+	defaultInspectionRunLimit = 20
+	maxInspectionRunLimit     = 200
+)
+
+// InspectionService 集群巡检业务逻辑层
+// 类比Shell函数：inspect() { snapshot=$(kubectl get pods,deploy,nodes -A -o json); run_checks "$snapshot" | tee -a history; }
+type InspectionService struct {
+	clusters       *client.ClusterManager
+	podRepo        *repository.PodRepository
+	deploymentRepo *repository.DeploymentRepository
+	nodeRepo       *repository.NodeRepository
+	inspectionRepo *repository.InspectionRepository
+	engine         *inspection.Engine
+	notifier       *NotificationService
+	cfg            config.InspectionConfig
+}
+
+// NewInspectionService 创建巡检 Service
+func NewInspectionService(
+	clusters *client.ClusterManager,
+	podRepo *repository.PodRepository,
+	deploymentRepo *repository.DeploymentRepository,
+	nodeRepo *repository.NodeRepository,
+	inspectionRepo *repository.InspectionRepository,
+	engine *inspection.Engine,
+	notifier *NotificationService,
+	cfg config.InspectionConfig,
+) *InspectionService {
+	return &InspectionService{
+		clusters:       clusters,
+		podRepo:        podRepo,
+		deploymentRepo: deploymentRepo,
+		nodeRepo:       nodeRepo,
+		inspectionRepo: inspectionRepo,
+		engine:         engine,
+		notifier:       notifier,
+		cfg:            cfg,
+	}
+}
+
+// Checks 返回所有检查项
+func (s *InspectionService) Checks() []inspection.Check {
+	return s.engine.Checks()
+}
+
+// Inspect 执行一次手动巡检并保存记录，返回的问题不含已确认或屏蔽的
+func (s *InspectionService) Inspect(ctx context.Context, req InspectionRequest) (InspectionRunDetail, error) {
+	if err := s.validateScope(req.Scope); err != nil {
+		return InspectionRunDetail{}, err
+	}
+	run := repository.InspectionRun{
+		Cluster:     req.Cluster,
+		Trigger:     repository.InspectionTriggerManual,
+		Scope:       req.Scope,
+		RequestedBy: auth.UserFromContext(ctx).DisplayName(),
+	}
+	detail, err := s.run(ctx, run)
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	detail.Findings = unsuppressed(detail.Findings)
+	return detail, nil
+}
+
+// ListRuns 按时间倒序返回巡检记录，scheduleID 非 0 时只返回该定时巡检的记录
+func (s *InspectionService) ListRuns(ctx context.Context, cluster string, scheduleID int64, limit int) ([]repository.InspectionRun, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultInspectionRunLimit
+	}
+	if limit > maxInspectionRunLimit {
+		limit = maxInspectionRunLimit
+	}
+	return s.inspectionRepo.ListRuns(ctx, name, scheduleID, limit)
+}
+
+// GetRun 获取巡检记录及其问题
+// 记录保存后新增的确认和屏蔽规则也会生效；all 为 true 时返回包括已确认或屏蔽在内的所有问题
+func (s *InspectionService) GetRun(ctx context.Context, cluster string, id int64, all bool) (InspectionRunDetail, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	run, err := s.inspectionRepo.GetRun(ctx, name, id)
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	findings, err := s.findings(ctx, run)
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	if !all {
+		findings = unsuppressed(findings)
+	}
+	return InspectionRunDetail{InspectionRun: run, Findings: findings}, nil
+}
+
+// Diff 对比巡检记录与范围相同的上一次巡检：新出现、已解决、仍未解决
+func (s *InspectionService) Diff(ctx context.Context, cluster string, id int64) (InspectionDiff, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return InspectionDiff{}, err
+	}
+	run, err := s.inspectionRepo.GetRun(ctx, name, id)
+	if err != nil {
+		return InspectionDiff{}, err
+	}
+	current, err := s.findings(ctx, run)
+	if err != nil {
+		return InspectionDiff{}, err
+	}
+	diff := InspectionDiff{
+		Run:      run,
+		New:      []repository.InspectionFinding{},
+		Resolved: []repository.InspectionFinding{},
+		Open:     []repository.InspectionFinding{},
+	}
+
+	var previous []repository.InspectionFinding
+	if run.PreviousID != nil {
+		prev, err := s.inspectionRepo.GetRun(ctx, name, *run.PreviousID)
+		if err == nil {
+			diff.Previous = &prev
+			if previous, err = s.findings(ctx, prev); err != nil {
+				return InspectionDiff{}, err
+			}
+		} else if !isNotFound(err) {
+			return InspectionDiff{}, err
+		}
+	}
+
+	// 上一次巡检已被清理时无法对比，所有问题都视为仍未解决
+	added, resolved, open := compareFindings(current, previous, diff.Previous != nil)
+	diff.New = append(diff.New, added...)
+	diff.Resolved = append(diff.Resolved, resolved...)
+	diff.Open = append(diff.Open, open...)
+	return diff, nil
+}
+
+// compareFindings 对比本次和上一次巡检中未被确认或屏蔽的问题：
+// added 为上一次不可见（未出现，或被确认、屏蔽而规则已过期或删除）、本次可见的问题；
+// resolved 为上一次可见、本次不再出现的问题，本次被确认或屏蔽的不算已解决；open 为两次都可见的问题
+// hasPrevious 为 false 时没有可对比的巡检，本次可见的问题都属于 open
+func compareFindings(current, previous []repository.InspectionFinding, hasPrevious bool) (added, resolved, open []repository.InspectionFinding) {
+	visible := fingerprints(unsuppressed(previous))
+	for _, f := range unsuppressed(current) {
+		if hasPrevious && !visible[f.Fingerprint] {
+			added = append(added, f)
+		} else {
+			open = append(open, f)
+		}
+	}
+	now := fingerprints(current)
+	for _, f := range unsuppressed(previous) {
+		if !now[f.Fingerprint] {
+			resolved = append(resolved, f)
+		}
+	}
+	return added, resolved, open
+}
+
+// run 执行巡检，对比范围相同的上一次巡检并按当前生效的确认和屏蔽规则标记问题后保存
+func (s *InspectionService) run(ctx context.Context, run repository.InspectionRun) (InspectionRunDetail, error) {
+	req := InspectionRequest{Cluster: run.Cluster, Scope: run.Scope}
+	snapshot, err := s.snapshot(ctx, req)
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	run.Cluster = snapshot.Cluster
+	report := s.engine.Run(snapshot, req.Checks, req.MinSeverity)
+
+	run.ScopeKey = run.Scope.Key()
+	previous, err := s.inspectionRepo.LatestRun(ctx, run.Cluster, run.ScopeKey)
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	var prevFindings []repository.InspectionFinding
+	if previous != nil {
+		run.PreviousID = &previous.ID
+		if prevFindings, err = s.inspectionRepo.ListFindings(ctx, previous.ID); err != nil {
+			return InspectionRunDetail{}, err
+		}
+	}
+
+	suppressions, err := s.inspectionRepo.ListSuppressions(ctx, run.Cluster, time.Now())
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	findings := classifyFindings(&run, report.Findings, prevFindings, previous != nil, suppressions)
+
+	run.StartedAt = report.StartedAt
+	run.DurationMs = time.Since(report.StartedAt).Milliseconds()
+	run.Objects = report.Objects
+	run.Errors = report.Errors
+	created, err := s.inspectionRepo.CreateRun(ctx, run, findings)
+	if err != nil {
+		return InspectionRunDetail{}, err
+	}
+	// 未确认或屏蔽的问题按路由规则通知，持续存在的问题由去重窗口控制重复通知的频率
+	s.notifier.Notify(ctx, findingEvents(run.Cluster, findings, time.Now())...)
+	return InspectionRunDetail{InspectionRun: created, Findings: findings}, nil
+}
+
+// classifyFindings 按确认和屏蔽规则标记本次巡检的问题，与上一次巡检对比后填写 run 的统计、新出现和已解决的数量
+func classifyFindings(
+	run *repository.InspectionRun,
+	reported []inspection.Finding,
+	previous []repository.InspectionFinding,
+	hasPrevious bool,
+	suppressions []repository.InspectionSuppression,
+) []repository.InspectionFinding {
+	findings := make([]repository.InspectionFinding, 0, len(reported))
+	for _, f := range reported {
+		finding := repository.InspectionFinding{Finding: f, Fingerprint: f.Fingerprint()}
+		finding.SuppressedBy = matchSuppression(suppressions, finding)
+		if finding.SuppressedBy != nil {
+			run.Summary.Suppressed++
+		} else {
+			run.Summary.Add(f.Severity)
+		}
+		findings = append(findings, finding)
+	}
+
+	added, resolved, _ := compareFindings(findings, previous, hasPrevious)
+	isNew := fingerprints(added)
+	for i := range findings {
+		findings[i].New = isNew[findings[i].Fingerprint]
+	}
+	run.New = len(added)
+	run.Resolved = len(resolved)
+	return findings
+}
+
+// findings 读取巡检记录的问题，并按当前生效的确认和屏蔽规则补充标记
+func (s *InspectionService) findings(ctx context.Context, run repository.InspectionRun) ([]repository.InspectionFinding, error) {
+	findings, err := s.inspectionRepo.ListFindings(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	suppressions, err := s.inspectionRepo.ListSuppressions(ctx, run.Cluster, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range findings {
+		if findings[i].SuppressedBy == nil {
+			findings[i].SuppressedBy = matchSuppression(suppressions, findings[i])
+		}
+	}
+	return findings, nil
+}
+
+// validateScope 校验巡检范围中的检查项和严重程度
+func (s *InspectionService) validateScope(scope inspection.Scope) error {
+	for _, id := range scope.Checks {
+		if _, ok := s.engine.Lookup(id); !ok {
+			return response.ErrBadRequest(fmt.Sprintf("未知的检查项 %s", id), nil)
+		}
+	}
+	if scope.MinSeverity != "" && !scope.MinSeverity.Valid() {
+		return response.ErrBadRequest("minSeverity 只支持 critical、warning、info", nil)
+	}
+	return nil
+}
+
+// clusterName 解析集群名，为空时使用默认集群；只校验集群已配置，不要求当前可连接
+func (s *InspectionService) clusterName(name string) (string, error) {
+	names := s.clusters.Names()
+	if name == "" && len(names) > 0 {
+		return names[0], nil
+	}
+	for _, n := range names {
+		if n == name {
+			return name, nil
+		}
+	}
+	return "", response.ErrNotFound(fmt.Sprintf("集群 %s 不存在", name), nil)
+}
+
+// matchSuppression 返回匹配问题的第一条确认或屏蔽规则 ID
+func matchSuppression(suppressions []repository.InspectionSuppression, f repository.InspectionFinding) *int64 {
+	for i := range suppressions {
+		sup := &suppressions[i]
+		switch sup.Type {
+		case repository.SuppressionTypeAck:
+			if sup.Fingerprint != f.Fingerprint {
+				continue
+			}
+		case repository.SuppressionTypeSuppress:
+			if sup.Check != f.Check ||
+				(sup.Namespace != "" && sup.Namespace != f.Object.Namespace) ||
+				(sup.Name != "" && sup.Name != f.Object.Name) {
+				continue
+			}
+		default:
+			continue
+		}
+		return &sup.ID
+	}
+	return nil
+}
+
+func unsuppressed(findings []repository.InspectionFinding) []repository.InspectionFinding {
+	result := make([]repository.InspectionFinding, 0, len(findings))
+	for _, f := range findings {
+		if f.SuppressedBy == nil {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func fingerprints(findings []repository.InspectionFinding) map[string]bool {
+	set := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		set[f.Fingerprint] = true
+	}
+	return set
+}
+
+func isNotFound(err error) bool {
+	var appErr *response.AppError
+	return errors.As(err, &appErr) && appErr.Status == http.StatusNotFound
+}
+
+// snapshot 并发读取 Pod、Deployment 和节点
+func (s *InspectionService) snapshot(ctx context.Context, req InspectionRequest) (*inspection.Snapshot, error) {
+	c, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &inspection.Snapshot{Cluster: c.Name}
+
+	namespaces := req.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+	pods := make([][]corev1.Pod, len(namespaces))
+	deployments := make([][]appsv1.Deployment, len(namespaces))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, ns := range namespaces {
+		i, ns := i, ns
+		g.Go(func() error {
+			var err error
+			pods[i], err = s.podRepo.ListByNamespace(gctx, c.Name, ns)
+			return err
+		})
+		g.Go(func() error {
+			var err error
+			deployments[i], err = s.deploymentRepo.ListByNamespace(gctx, c.Name, ns)
+			return err
+		})
+	}
+	g.Go(func() error {
+		var err error
+		snapshot.Nodes, err = s.nodeRepo.List(gctx, c.Name)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// 未指定命名空间时按命名空间列表的业务规则过滤系统命名空间
+	skip := func(ns string) bool {
+		return len(req.Namespaces) == 0 && !req.IncludeSystemNamespaces && isSystemNamespace(ns)
+	}
+	for i := range namespaces {
+		for _, pod := range pods[i] {
+			if !skip(pod.Namespace) {
+				snapshot.Pods = append(snapshot.Pods, pod)
+			}
+		}
+		for _, d := range deployments[i] {
+			if !skip(d.Namespace) {
+				snapshot.Deployments = append(snapshot.Deployments, d)
+			}
+		}
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/inspection"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// ScheduleRequest 创建或更新定时巡检的参数，Enabled 为空时默认启用
+type ScheduleRequest struct {
+	inspection.Scope
+	Name    string `json:"name"`
+	Cron    string `json:"cron"`
+	Enabled *bool  `json:"enabled"`
+}
+
+// SuppressionRequest 创建确认或屏蔽规则的参数
+// ack 需要 fingerprint；suppress 需要 check，namespace、name 可选；TTL 与 ExpiresAt 二选一，都为空表示永久有效
+type SuppressionRequest struct {
+	Type        string     `json:"type"`
+	Fingerprint string     `json:"fingerprint"`
+	Check       string     `json:"check"`
+	Namespace   string     `json:"namespace"`
+	Name        string     `json:"name"`
+	Reason      string     `json:"reason"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	TTL         string     `json:"ttl"` // 如 72h
+}
+
+// 清理过期巡检记录和确认规则的间隔
+const inspectionCleanupInterval = time.Hour
+
+// ListSchedules 返回集群的定时巡检
+func (s *InspectionService) ListSchedules(ctx context.Context, cluster string) ([]repository.InspectionSchedule, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return s.inspectionRepo.ListSchedules(ctx, name)
+}
+
+// GetSchedule 获取定时巡检
+func (s *InspectionService) GetSchedule(ctx context.Context, cluster string, id int64) (repository.InspectionSchedule, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return repository.InspectionSchedule{}, err
+	}
+	return s.inspectionRepo.GetSchedule(ctx, name, id)
+}
+
+// CreateSchedule 创建定时巡检
+func (s *InspectionService) CreateSchedule(ctx context.Context, cluster string, req ScheduleRequest) (repository.InspectionSchedule, error) {
+	schedule, err := s.scheduleFromRequest(cluster, req)
+	if err != nil {
+		return repository.InspectionSchedule{}, err
+	}
+	schedule.CreatedBy = auth.UserFromContext(ctx).DisplayName()
+	return s.inspectionRepo.CreateSchedule(ctx, schedule)
+}
+
+// UpdateSchedule 更新定时巡检
+func (s *InspectionService) UpdateSchedule(ctx context.Context, cluster string, id int64, req ScheduleRequest) (repository.InspectionSchedule, error) {
+	schedule, err := s.scheduleFromRequest(cluster, req)
+	if err != nil {
+		return repository.InspectionSchedule{}, err
+	}
+	schedule.ID = id
+	return s.inspectionRepo.UpdateSchedule(ctx, schedule)
+}
+
+// DeleteSchedule 删除定时巡检
+func (s *InspectionService) DeleteSchedule(ctx context.Context, cluster string, id int64) error {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return err
+	}
+	return s.inspectionRepo.DeleteSchedule(ctx, name, id)
+}
+
+func (s *InspectionService) scheduleFromRequest(cluster string, req ScheduleRequest) (repository.InspectionSchedule, error) {
+	cluster, err := s.clusterName(cluster)
+	if err != nil {
+		return repository.InspectionSchedule{}, err
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return repository.InspectionSchedule{}, response.ErrBadRequest("定时巡检名称不能为空", nil)
+	}
+	req.Cron = strings.TrimSpace(req.Cron)
+	if _, err := cron.ParseStandard(req.Cron); err != nil {
+		return repository.InspectionSchedule{}, response.ErrBadRequest(fmt.Sprintf("cron 表达式无效: %s（示例: 0 */6 * * *、@daily）", req.Cron), err)
+	}
+	if err := s.validateScope(req.Scope); err != nil {
+		return repository.InspectionSchedule{}, err
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return repository.InspectionSchedule{
+		Cluster: cluster,
+		Name:    req.Name,
+		Cron:    req.Cron,
+		Scope:   req.Scope,
+		Enabled: enabled,
+	}, nil
+}
+
+// ListSuppressions 返回集群的确认和屏蔽规则，all 为 false 时不含已过期的
+func (s *InspectionService) ListSuppressions(ctx context.Context, cluster string, all bool) ([]repository.InspectionSuppression, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return nil, err
+	}
+	var activeAt time.Time
+	if !all {
+		activeAt = time.Now()
+	}
+	return s.inspectionRepo.ListSuppressions(ctx, name, activeAt)
+}
+
+// CreateSuppression 确认一个问题或屏蔽一类问题，之后的巡检中匹配的问题不再计入统计
+func (s *InspectionService) CreateSuppression(ctx context.Context, cluster string, req SuppressionRequest) (repository.InspectionSuppression, error) {
+	cluster, err := s.clusterName(cluster)
+	if err != nil {
+		return repository.InspectionSuppression{}, err
+	}
+	sup := repository.InspectionSuppression{
+		Cluster:   cluster,
+		Type:      req.Type,
+		Reason:    strings.TrimSpace(req.Reason),
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: auth.UserFromContext(ctx).DisplayName(),
+	}
+	switch req.Type {
+	case repository.SuppressionTypeAck:
+		if req.Fingerprint == "" {
+			return repository.InspectionSuppression{}, response.ErrBadRequest("确认问题时 fingerprint 不能为空", nil)
+		}
+		sup.Fingerprint = req.Fingerprint
+	case repository.SuppressionTypeSuppress:
+		if _, ok := s.engine.Lookup(req.Check); !ok {
+			return repository.InspectionSuppression{}, response.ErrBadRequest(fmt.Sprintf("未知的检查项 %s", req.Check), nil)
+		}
+		sup.Check, sup.Namespace, sup.Name = req.Check, req.Namespace, req.Name
+	default:
+		return repository.InspectionSuppression{}, response.ErrBadRequest("type 只支持 ack、suppress", nil)
+	}
+	if sup.Reason == "" {
+		return repository.InspectionSuppression{}, response.ErrBadRequest("请填写确认或屏蔽的原因", nil)
+	}
+
+	if req.TTL != "" {
+		if req.ExpiresAt != nil {
+			return repository.InspectionSuppression{}, response.ErrBadRequest("ttl 与 expiresAt 不能同时指定", nil)
+		}
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			return repository.InspectionSuppression{}, response.ErrBadRequest("ttl 参数无效，示例: 72h", err)
+		}
+		expiresAt := time.Now().Add(ttl)
+		sup.ExpiresAt = &expiresAt
+	}
+	if sup.ExpiresAt != nil && !sup.ExpiresAt.After(time.Now()) {
+		return repository.InspectionSuppression{}, response.ErrBadRequest("expiresAt 必须晚于当前时间", nil)
+	}
+	return s.inspectionRepo.CreateSuppression(ctx, sup)
+}
+
+// DeleteSuppression 删除确认或屏蔽规则，匹配的问题在之后的巡检中重新出现
+func (s *InspectionService) DeleteSuppression(ctx context.Context, cluster string, id int64) error {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return err
+	}
+	return s.inspectionRepo.DeleteSuppression(ctx, name, id)
+}
+
+// RunSchedules 执行到期的定时巡检并定期清理过期记录，阻塞直到 ctx 取消
+// 只在主节点上运行；到期时间由 last_run_at 推算，不依赖内存状态，切换主节点后不会重复或遗漏
+// 类比Shell: while sleep 30; do for s in $(due_schedules); do inspect "$s"; done; done
+func (s *InspectionService) RunSchedules(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.ScheduleInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		now := time.Now()
+		s.runDueSchedules(ctx, now)
+		if now.Sub(lastCleanup) >= inspectionCleanupInterval {
+			s.cleanup(ctx, now)
+			lastCleanup = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *InspectionService) runDueSchedules(ctx context.Context, now time.Time) {
+	logger := logging.FromContext(ctx)
+	schedules, err := s.inspectionRepo.ListSchedules(ctx, "")
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("Failed to list inspection schedules", zap.Error(err))
+		}
+		return
+	}
+
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		if !schedule.Enabled {
+			continue
+		}
+		log := logger.With(zap.Int64("schedule", schedule.ID), zap.String("cluster", schedule.Cluster), zap.String("name", schedule.Name))
+		due, err := scheduleDue(schedule, now)
+		if err != nil {
+			log.Warn("Invalid inspection schedule cron", zap.String("cron", schedule.Cron), zap.Error(err))
+			continue
+		}
+		if !due {
+			continue
+		}
+
+		// 失败也记录执行时间，等下一个周期再试，避免集群不可用时每个间隔都重试
+		var runID *int64
+		detail, err := s.run(ctx, repository.InspectionRun{
+			Cluster:     schedule.Cluster,
+			Trigger:     repository.InspectionTriggerSchedule,
+			ScheduleID:  &schedule.ID,
+			Scope:       schedule.Scope,
+			RequestedBy: "schedule:" + schedule.Name,
+		})
+		if err != nil {
+			log.Warn("Scheduled inspection failed", zap.Error(err))
+		} else {
+			runID = &detail.ID
+			log.Info("Scheduled inspection finished",
+				zap.Int64("run", detail.ID),
+				zap.Int("findings", detail.Summary.Total),
+				zap.Int("new", detail.New),
+				zap.Int("resolved", detail.Resolved),
+			)
+		}
+		if err := s.inspectionRepo.MarkScheduleRun(ctx, schedule.ID, now, runID); err != nil {
+			log.Warn("Failed to record inspection schedule run", zap.Error(err))
+		}
+	}
+}
+
+// scheduleDue 定时巡检在 now 时是否到期：上次执行（从未执行时为创建时间）之后的下一个触发时间不晚于 now
+// 错过的多个触发时间只执行一次
+func scheduleDue(schedule repository.InspectionSchedule, now time.Time) (bool, error) {
+	sched, err := cron.ParseStandard(schedule.Cron)
+	if err != nil {
+		return false, err
+	}
+	last := schedule.CreatedAt
+	if schedule.LastRunAt != nil {
+		last = *schedule.LastRunAt
+	}
+	return !sched.Next(last).After(now), nil
+}
+
+// cleanup 删除超过保留时长的巡检记录，以及过期时间早于保留时长的确认和屏蔽规则
+func (s *InspectionService) cleanup(ctx context.Context, now time.Time) {
+	logger := logging.FromContext(ctx)
+	before := now.Add(-s.cfg.Retention)
+	runs, err := s.inspectionRepo.DeleteRunsBefore(ctx, before)
+	if err != nil {
+		logger.Warn("Failed to delete expired inspection runs", zap.Error(err))
+	}
+	suppressions, err := s.inspectionRepo.DeleteExpiredSuppressions(ctx, before)
+	if err != nil {
+		logger.Warn("Failed to delete expired inspection suppressions", zap.Error(err))
+	}
+	if runs > 0 || suppressions > 0 {
+		logger.Info("Cleaned up inspection history", zap.Int64("runs", runs), zap.Int64("suppressions", suppressions))
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/inspection"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+)
+
+// 测试用的巡检问题：crashloop 和 restarts 属于 Pod shop/web-1，limits 属于 Deployment shop/web
+var (
+	crashloop = inspection.Finding{Check: "pod-crashloop", Severity: inspection.SeverityCritical,
+		Object: inspection.Object{Kind: "Pod", Namespace: "shop", Name: "web-1", Container: "app"}}
+	restarts = inspection.Finding{Check: "pod-high-restarts", Severity: inspection.SeverityWarning,
+		Object: inspection.Object{Kind: "Pod", Namespace: "shop", Name: "web-1", Container: "app"}}
+	limits = inspection.Finding{Check: "container-missing-limits", Severity: inspection.SeverityWarning,
+		Object: inspection.Object{Kind: "Deployment", Namespace: "shop", Name: "web", Container: "app"}}
+)
+
+// stored 上一次巡检保存的问题，suppressedBy 非零表示当时已被确认或屏蔽
+func stored(f inspection.Finding, suppressedBy int64) repository.InspectionFinding {
+	finding := repository.InspectionFinding{Finding: f, Fingerprint: f.Fingerprint()}
+	if suppressedBy != 0 {
+		finding.SuppressedBy = &suppressedBy
+	}
+	return finding
+}
+
+func ack(id int64, f inspection.Finding) repository.InspectionSuppression {
+	return repository.InspectionSuppression{ID: id, Type: repository.SuppressionTypeAck, Fingerprint: f.Fingerprint()}
+}
+
+func checksOf(findings []repository.InspectionFinding) []string {
+	var result []string
+	for _, f := range findings {
+		result = append(result, f.Check)
+	}
+	return result
+}
+
+func TestClassifyFindings(t *testing.T) {
+	tests := []struct {
+		name         string
+		reported     []inspection.Finding
+		previous     []repository.InspectionFinding
+		hasPrevious  bool
+		suppressions []repository.InspectionSuppression
+		wantNew      []string // 标记为 New 的检查项
+		wantResolved int
+		wantSummary  inspection.Summary
+	}{
+		{
+			name:        "first run has nothing to compare",
+			reported:    []inspection.Finding{crashloop, limits},
+			wantSummary: inspection.Summary{Total: 2, Critical: 1, Warning: 1},
+		},
+		{
+			name:        "new finding",
+			reported:    []inspection.Finding{crashloop, limits},
+			previous:    []repository.InspectionFinding{stored(limits, 0)},
+			hasPrevious: true,
+			wantNew:     []string{"pod-crashloop"},
+			wantSummary: inspection.Summary{Total: 2, Critical: 1, Warning: 1},
+		},
+		{
+			name:         "resolved finding",
+			reported:     []inspection.Finding{limits},
+			previous:     []repository.InspectionFinding{stored(crashloop, 0), stored(limits, 0)},
+			hasPrevious:  true,
+			wantResolved: 1,
+			wantSummary:  inspection.Summary{Total: 1, Warning: 1},
+		},
+		{
+			name:        "unchanged findings",
+			reported:    []inspection.Finding{crashloop, limits},
+			previous:    []repository.InspectionFinding{stored(crashloop, 0), stored(limits, 0)},
+			hasPrevious: true,
+			wantSummary: inspection.Summary{Total: 2, Critical: 1, Warning: 1},
+		},
+		{
+			// 确认后既不是新问题，也不算已解决，只计入 suppressed
+			name:         "acknowledged finding",
+			reported:     []inspection.Finding{crashloop, limits},
+			previous:     []repository.InspectionFinding{stored(crashloop, 0), stored(limits, 0)},
+			hasPrevious:  true,
+			suppressions: []repository.InspectionSuppression{ack(7, crashloop)},
+			wantSummary:  inspection.Summary{Total: 1, Warning: 1, Suppressed: 1},
+		},
+		{
+			name:         "acknowledged finding disappears",
+			reported:     []inspection.Finding{limits},
+			previous:     []repository.InspectionFinding{stored(crashloop, 7), stored(limits, 0)},
+			hasPrevious:  true,
+			suppressions: []repository.InspectionSuppression{ack(7, crashloop)},
+			wantSummary:  inspection.Summary{Total: 1, Warning: 1},
+		},
+		{
+			// 确认过期或删除后问题重新出现，视为新问题
+			name:        "acknowledged finding comes back",
+			reported:    []inspection.Finding{crashloop, limits},
+			previous:    []repository.InspectionFinding{stored(crashloop, 7), stored(limits, 0)},
+			hasPrevious: true,
+			wantNew:     []string{"pod-crashloop"},
+			wantSummary: inspection.Summary{Total: 2, Critical: 1, Warning: 1},
+		},
+		{
+			// 确认按 fingerprint 匹配，同一 Pod 上的其他问题不受影响
+			name:         "ack matches one finding only",
+			reported:     []inspection.Finding{crashloop, restarts},
+			hasPrevious:  true,
+			suppressions: []repository.InspectionSuppression{ack(7, crashloop)},
+			wantNew:      []string{"pod-high-restarts"},
+			wantSummary:  inspection.Summary{Total: 1, Warning: 1, Suppressed: 1},
+		},
+		{
+			name:     "suppress rule matches check and namespace",
+			reported: []inspection.Finding{crashloop, restarts, limits},
+			suppressions: []repository.InspectionSuppression{
+				{ID: 8, Type: repository.SuppressionTypeSuppress, Check: "pod-high-restarts", Namespace: "shop"},
+				{ID: 9, Type: repository.SuppressionTypeSuppress, Check: "container-missing-limits", Namespace: "other"},
+			},
+			wantSummary: inspection.Summary{Total: 2, Critical: 1, Warning: 1, Suppressed: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var run repository.InspectionRun
+			findings := classifyFindings(&run, tt.reported, tt.previous, tt.hasPrevious, tt.suppressions)
+
+			var gotNew []string
+			for _, f := range findings {
+				if f.New {
+					gotNew = append(gotNew, f.Check)
+				}
+			}
+			if len(gotNew) != len(tt.wantNew) || (len(gotNew) > 0 && gotNew[0] != tt.wantNew[0]) {
+				t.Errorf("new findings = %v, want %v", gotNew, tt.wantNew)
+			}
+			if run.New != len(tt.wantNew) || run.Resolved != tt.wantResolved {
+				t.Errorf("run.New = %d, run.Resolved = %d; want %d, %d", run.New, run.Resolved, len(tt.wantNew), tt.wantResolved)
+			}
+			if run.Summary != tt.wantSummary {
+				t.Errorf("summary = %+v, want %+v", run.Summary, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestCompareFindings(t *testing.T) {
+	previous := []repository.InspectionFinding{stored(crashloop, 0), stored(restarts, 7), stored(limits, 0)}
+	current := []repository.InspectionFinding{stored(restarts, 0), stored(limits, 9)}
+
+	// crashloop 已解决；restarts 的确认已过期，重新可见；limits 本次被屏蔽，不算已解决
+	added, resolved, open := compareFindings(current, previous, true)
+	if got := checksOf(added); len(got) != 1 || got[0] != "pod-high-restarts" {
+		t.Errorf("added = %v", got)
+	}
+	if got := checksOf(resolved); len(got) != 1 || got[0] != "pod-crashloop" {
+		t.Errorf("resolved = %v", got)
+	}
+	if len(open) != 0 {
+		t.Errorf("open = %v", checksOf(open))
+	}
+
+	// 上一次巡检已被清理：可见的问题都视为仍未解决
+	added, resolved, open = compareFindings(current, nil, false)
+	if len(added) != 0 || len(resolved) != 0 || len(open) != 1 {
+		t.Errorf("without previous: added = %v, resolved = %v, open = %v", checksOf(added), checksOf(resolved), checksOf(open))
+	}
+}
+
+func TestScheduleDue(t *testing.T) {
+	created := time.Date(2026, 5, 1, 1, 30, 0, 0, time.UTC)
+	lastRun := time.Date(2026, 5, 2, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		cron    string
+		lastRun *time.Time
+		now     time.Time
+		want    bool
+		wantErr bool
+	}{
+		{name: "never run, before the first trigger", cron: "0 2 * * *", now: created.Add(20 * time.Minute)},
+		{name: "never run, first trigger reached", cron: "0 2 * * *", now: created.Add(30 * time.Minute), want: true},
+		{name: "ran today, next trigger tomorrow", cron: "0 2 * * *", lastRun: &lastRun, now: lastRun.Add(23 * time.Hour)},
+		{name: "missed triggers run once", cron: "0 2 * * *", lastRun: &lastRun, now: lastRun.Add(72 * time.Hour), want: true},
+		{name: "descriptor", cron: "@hourly", lastRun: &lastRun, now: lastRun.Add(time.Hour), want: true},
+		{name: "invalid cron", cron: "every day", now: created, wantErr: true},
+	}
+	for _, tt := range tests {
+		schedule := repository.InspectionSchedule{Cron: tt.cron, CreatedAt: created, LastRunAt: tt.lastRun}
+		got, err := scheduleDue(schedule, tt.now)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: scheduleDue() = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/jenkins"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// AuditActionJenkinsBuild 触发 Jenkins 构建的审计 action
+const AuditActionJenkinsBuild = "jenkins.build"
+
+const (
+	jenkinsDefaultTimeout = 30 * time.Second
+	jenkinsMaxTimeout     = 120 * time.Second
+	jenkinsDefaultBuilds  = 20
+	jenkinsMaxBuilds      = 100
+)
+
+// Jenkins 构建日志 SSE 事件
+const (
+	JenkinsEventLog   = "log"
+	JenkinsEventEnd   = "end"
+	JenkinsEventError = "error"
+)
+
+// TriggeredBuild 触发构建的结果，构建在队列中等待，通过队列条目查询构建号
+type TriggeredBuild struct {
+	QueueID    int64             `json:"queueId"`
+	Job        string            `json:"job"`
+	Parameters map[string]string `json:"parameters,omitempty"` // 密码参数的值不返回
+}
+
+// JenkinsLogEnd 控制台输出结束时推送的构建结果
+type JenkinsLogEnd struct {
+	NextStart int64  `json:"nextStart"`
+	Result    string `json:"result"`
+}
+
+// JenkinsService Jenkins 服务器管理、Job 浏览、触发构建和控制台输出
+// 类比Shell: curl -u "$USER:$TOKEN" "$JENKINS_URL/job/$JOB/buildWithParameters" && curl "$JENKINS_URL/job/$JOB/lastBuild/consoleText"
+type JenkinsService struct {
+	jenkinsRepo *repository.JenkinsRepository
+	auditRepo   *repository.AuditRepository
+	cfg         config.JenkinsConfig
+
+	// 按服务器 ID 缓存客户端，复用连接、会话和 crumb
+	clients *clientCache[*jenkins.Client]
+}
+
+// NewJenkinsService 创建 Jenkins Service
+func NewJenkinsService(jenkinsRepo *repository.JenkinsRepository, auditRepo *repository.AuditRepository, cfg config.JenkinsConfig) *JenkinsService {
+	return &JenkinsService{
+		jenkinsRepo: jenkinsRepo,
+		auditRepo:   auditRepo,
+		cfg:         cfg,
+		clients:     newClientCache[*jenkins.Client](),
+	}
+}
+
+// ListServers 获取所有 Jenkins 服务器
+func (s *JenkinsService) ListServers(ctx context.Context) ([]repository.JenkinsServer, error) {
+	return s.jenkinsRepo.List(ctx)
+}
+
+// GetServer 获取单个 Jenkins 服务器
+func (s *JenkinsService) GetServer(ctx context.Context, id int64) (repository.JenkinsServer, error) {
+	return s.jenkinsRepo.GetByID(ctx, id)
+}
+
+// CreateServer 添加 Jenkins 服务器
+func (s *JenkinsService) CreateServer(ctx context.Context, server repository.JenkinsServer) (repository.JenkinsServer, error) {
+	if err := normalizeJenkinsServer(&server); err != nil {
+		return repository.JenkinsServer{}, err
+	}
+	return s.jenkinsRepo.Create(ctx, server)
+}
+
+// UpdateServer 更新 Jenkins 服务器，APIToken 为空时保留原值
+func (s *JenkinsService) UpdateServer(ctx context.Context, server repository.JenkinsServer) (repository.JenkinsServer, error) {
+	existing, err := s.jenkinsRepo.GetByID(ctx, server.ID)
+	if err != nil {
+		return repository.JenkinsServer{}, err
+	}
+	if server.APIToken == "" && server.Username == existing.Username {
+		server.APIToken = existing.APIToken
+	}
+	if err := normalizeJenkinsServer(&server); err != nil {
+		return repository.JenkinsServer{}, err
+	}
+	return s.jenkinsRepo.Update(ctx, server)
+}
+
+// DeleteServer 删除 Jenkins 服务器
+func (s *JenkinsService) DeleteServer(ctx context.Context, id int64) error {
+	if err := s.jenkinsRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.clients.remove(id)
+	return nil
+}
+
+// TestServer 用保存的地址和凭据访问 Jenkins，校验连通性
+func (s *JenkinsService) TestServer(ctx context.Context, id int64) error {
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.Ping(ctx)
+}
+
+// ListJobs 列出文件夹中的 Job 和子文件夹，folder 为空时列出根目录
+func (s *JenkinsService) ListJobs(ctx context.Context, id int64, folder string) ([]jenkins.Job, error) {
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListJobs(ctx, folder)
+}
+
+// GetJob 获取 Job 详情、构建参数和最近的构建历史，builds 为 0 时返回最近 20 次
+func (s *JenkinsService) GetJob(ctx context.Context, id int64, job string, builds int) (jenkins.JobDetail, error) {
+	if builds == 0 {
+		builds = jenkinsDefaultBuilds
+	}
+	if builds < 0 || builds > jenkinsMaxBuilds {
+		return jenkins.JobDetail{}, response.ErrBadRequest(fmt.Sprintf("builds 必须在 1 到 %d 之间", jenkinsMaxBuilds), nil)
+	}
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return jenkins.JobDetail{}, err
+	}
+	return c.GetJob(ctx, job, builds)
+}
+
+// GetBuild 获取构建详情
+func (s *JenkinsService) GetBuild(ctx context.Context, id int64, job string, number int) (jenkins.Build, error) {
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return jenkins.Build{}, err
+	}
+	return c.GetBuild(ctx, job, number)
+}
+
+// TriggerBuild 触发构建：按 Job 定义的参数校验请求，未提供的参数使用 Jenkins 中的默认值
+func (s *JenkinsService) TriggerBuild(ctx context.Context, id int64, job string, params map[string]string) (TriggeredBuild, error) {
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return TriggeredBuild{}, err
+	}
+	detail, err := c.GetJob(ctx, job, 0)
+	if err != nil {
+		return TriggeredBuild{}, err
+	}
+	if detail.Folder {
+		return TriggeredBuild{}, response.ErrBadRequest(fmt.Sprintf("%s 是文件夹，不能构建", job), nil)
+	}
+	if !detail.Buildable {
+		return TriggeredBuild{}, response.ErrBadRequest(fmt.Sprintf("Job %s 已禁用或不可构建", job), nil)
+	}
+	if err := validateJenkinsParameters(detail.Parameters, params); err != nil {
+		return TriggeredBuild{}, err
+	}
+
+	queueID, err := c.Build(ctx, job, len(detail.Parameters) > 0, params)
+	if err != nil {
+		return TriggeredBuild{}, err
+	}
+	result := TriggeredBuild{QueueID: queueID, Job: detail.FullName, Parameters: maskedParameters(detail.Parameters, params)}
+	if result.Job == "" {
+		result.Job = job
+	}
+	recordAudit(ctx, s.auditRepo, repository.AuditLog{Action: AuditActionJenkinsBuild, Resource: result.Job}, map[string]interface{}{
+		"server":     id,
+		"queueId":    result.QueueID,
+		"parameters": result.Parameters,
+	})
+	return result, nil
+}
+
+// GetQueueItem 查询触发后的队列条目，构建开始后返回构建号
+func (s *JenkinsService) GetQueueItem(ctx context.Context, id, queueID int64) (jenkins.QueueItem, error) {
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return jenkins.QueueItem{}, err
+	}
+	return c.GetQueueItem(ctx, queueID)
+}
+
+// GetLog 从字节偏移 start 开始读取一段控制台输出
+func (s *JenkinsService) GetLog(ctx context.Context, id int64, job string, number int, start int64) (jenkins.LogChunk, error) {
+	if start < 0 {
+		return jenkins.LogChunk{}, response.ErrBadRequest("start 参数无效", nil)
+	}
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return jenkins.LogChunk{}, err
+	}
+	return c.ProgressiveLog(ctx, job, number, start)
+}
+
+// StreamLog 按 jenkins.logPollInterval 轮询控制台输出并推送新内容，构建结束后推送结果
+// 类比Shell: while :; do curl "$BUILD_URL/logText/progressiveText?start=$START"; sleep 2; done
+func (s *JenkinsService) StreamLog(ctx context.Context, id int64, job string, number int, start int64,
+	emit func(event string, data interface{}) error) error {
+	if start < 0 {
+		return response.ErrBadRequest("start 参数无效", nil)
+	}
+	c, err := s.client(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.cfg.LogPollInterval)
+	defer ticker.Stop()
+	for {
+		chunk, err := c.ProgressiveLog(ctx, job, number, start)
+		if err != nil {
+			return err
+		}
+		start = chunk.NextStart
+		if chunk.Text != "" {
+			if err := emit(JenkinsEventLog, chunk); err != nil {
+				return err
+			}
+		}
+		if !chunk.More {
+			end := JenkinsLogEnd{NextStart: start}
+			if build, err := c.GetBuild(ctx, job, number); err == nil {
+				end.Result = build.Result
+			}
+			return emit(JenkinsEventEnd, end)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// client 按服务器 ID 获取客户端，服务器配置更新后重新创建
+func (s *JenkinsService) client(ctx context.Context, id int64) (*jenkins.Client, error) {
+	server, err := s.jenkinsRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.clients.get(server.ID, server.UpdatedAt, func() (*jenkins.Client, error) {
+		return jenkins.NewClient(jenkins.ClientConfig{
+			URL:           server.URL,
+			Username:      server.Username,
+			APIToken:      server.APIToken,
+			TLSSkipVerify: server.TLSSkipVerify,
+			Timeout:       time.Duration(server.TimeoutSeconds) * time.Second,
+		}), nil
+	})
+}
+
+// normalizeJenkinsServer 校验服务器配置并填充默认值
+func normalizeJenkinsServer(server *repository.JenkinsServer) error {
+	if server.Name == "" {
+		return response.ErrBadRequest("Jenkins 服务器名称不能为空", nil)
+	}
+	u, err := url.Parse(server.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return response.ErrBadRequest(fmt.Sprintf("Jenkins 地址无效: %s", server.URL), err)
+	}
+	server.URL = strings.TrimRight(server.URL, "/")
+	if server.APIToken != "" && server.Username == "" {
+		return response.ErrBadRequest("提供 API Token 时必须提供用户名", nil)
+	}
+	if server.TimeoutSeconds <= 0 {
+		server.TimeoutSeconds = int(jenkinsDefaultTimeout.Seconds())
+	}
+	if server.TimeoutSeconds > int(jenkinsMaxTimeout.Seconds()) {
+		return response.ErrBadRequest(fmt.Sprintf("请求超时不能超过 %d 秒", int(jenkinsMaxTimeout.Seconds())), nil)
+	}
+	return nil
+}
+
+// validateJenkinsParameters 参数名必须在 Job 中定义，choice 参数的值必须是可选值之一，boolean 参数只能是 true/false
+func validateJenkinsParameters(defs []jenkins.ParameterDefinition, params map[string]string) error {
+	if len(defs) == 0 && len(params) > 0 {
+		return response.ErrBadRequest("该 Job 没有定义构建参数", nil)
+	}
+	byName := make(map[string]jenkins.ParameterDefinition, len(defs))
+	for _, d := range defs {
+		byName[d.Name] = d
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := params[name]
+		def, ok := byName[name]
+		if !ok {
+			return response.ErrBadRequest(fmt.Sprintf("Job 没有定义参数 %s", name), nil)
+		}
+		switch def.Type {
+		case "choice":
+			if !slices.Contains(def.Choices, value) {
+				return response.ErrBadRequest(fmt.Sprintf("参数 %s 的值必须是 %s 之一", name, strings.Join(def.Choices, "、")), nil)
+			}
+		case "boolean":
+			if value != "true" && value != "false" {
+				return response.ErrBadRequest(fmt.Sprintf("参数 %s 只能是 true 或 false", name), nil)
+			}
+		}
+	}
+	return nil
+}
+
+// maskedParameters 返回和审计时隐藏密码参数的值
+func maskedParameters(defs []jenkins.ParameterDefinition, params map[string]string) map[string]string {
+	if len(params) == 0 {
+		return nil
+	}
+	secret := make(map[string]bool)
+	for _, d := range defs {
+		if d.Type == "password" {
+			secret[d.Name] = true
+		}
+	}
+	masked := make(map[string]string, len(params))
+	for k, v := range params {
+		if secret[k] {
+			v = "[REDACTED]"
+		}
+		masked[k] = v
+	}
+	return masked
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/llm"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const llmUsageKeyPrefix = "kubeops:llm:usage:"
+
+// tokenBudget 每个用户每天的大模型 token 额度，用量记录在 Redis 中由所有副本共享
+// Redis 不可用时不限制（fail open），与限流一致
+type tokenBudget struct {
+	redis *redis.Client
+	dep   *client.Dependency
+	daily int
+}
+
+func newTokenBudget(redisClient *redis.Client, dep *client.Dependency, daily int) *tokenBudget {
+	return &tokenBudget{redis: redisClient, dep: dep, daily: daily}
+}
+
+// key 按 UTC 日期分桶
+func (b *tokenBudget) key(ctx context.Context) string {
+	return llmUsageKeyPrefix + auth.UserFromContext(ctx).ID() + ":" + time.Now().UTC().Format("20060102")
+}
+
+// Check 当前用户今天的额度已用完时返回 429
+func (b *tokenBudget) Check(ctx context.Context) error {
+	if b.daily <= 0 || b.dep.Err() != nil {
+		return nil
+	}
+	used, err := b.redis.Get(ctx, b.key(ctx)).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logging.FromContext(ctx).Warn("Failed to read LLM token usage", zap.Error(err))
+		return nil
+	}
+	if used >= b.daily {
+		return response.NewError(http.StatusTooManyRequests, response.CodeTooManyRequests,
+			fmt.Sprintf("今日大模型 token 额度已用完（%d），请明天再试", b.daily), nil)
+	}
+	return nil
+}
+
+// Record 记录一次调用的用量
+func (b *tokenBudget) Record(ctx context.Context, usage llm.Usage) {
+	if b.daily <= 0 || b.dep.Err() != nil || usage.TotalTokens <= 0 {
+		return
+	}
+	key := b.key(ctx)
+	pipe := b.redis.TxPipeline()
+	pipe.IncrBy(ctx, key, int64(usage.TotalTokens))
+	pipe.Expire(ctx, key, 48*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logging.FromContext(ctx).Warn("Failed to record LLM token usage", zap.Error(err))
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/logsearch"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	defaultLogLimit = 100
+	// defaultLogRange 未指定 start 时查询最近 1 小时
+	defaultLogRange = time.Hour
+)
+
+// LogQueryParams 日志查询参数，时间支持 Unix 秒或 RFC3339
+type LogQueryParams struct {
+	Namespace string
+	Pod       string
+	Container string
+	Contains  string
+	Query     string
+	Start     string
+	End       string
+	Limit     string
+	Direction string
+}
+
+// LogService 日志检索业务逻辑层，后端由配置选择 Loki 或 Elasticsearch
+// 类比Shell函数：search_logs() { logcli query "$QUERY" --since=1h --limit=100; }
+type LogService struct {
+	backend      logsearch.Backend
+	maxLimit     int
+	tailInterval time.Duration
+}
+
+// NewLogService 创建日志 Service，backend 为 nil 表示未配置日志后端
+func NewLogService(backend logsearch.Backend, cfg config.LogsConfig) *LogService {
+	return &LogService{
+		backend:      backend,
+		maxLimit:     cfg.MaxLimit,
+		tailInterval: cfg.TailInterval,
+	}
+}
+
+// Search 查询日志
+func (s *LogService) Search(ctx context.Context, params LogQueryParams) ([]logsearch.Entry, error) {
+	q, err := s.query(params, false)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.backend.Search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []logsearch.Entry{}
+	}
+	return entries, nil
+}
+
+// TailQuery 校验实时跟踪的参数，在开始推送前调用，以便参数错误时仍能返回普通的错误响应
+// 未指定 start 时只推送新产生的日志
+func (s *LogService) TailQuery(params LogQueryParams) (logsearch.Query, error) {
+	return s.query(params, true)
+}
+
+// Tail 持续推送新日志，直到 ctx 取消或 fn 返回错误
+// 对应Shell: logcli query --tail '{namespace="default"}'
+func (s *LogService) Tail(ctx context.Context, q logsearch.Query, fn func(logsearch.Entry) error) error {
+	return logsearch.Tail(ctx, s.backend, q, s.tailInterval, fn)
+}
+
+// query 校验参数并填充默认值
+func (s *LogService) query(params LogQueryParams, tail bool) (logsearch.Query, error) {
+	if s.backend == nil {
+		return logsearch.Query{}, response.NewError(http.StatusServiceUnavailable, response.CodeServiceUnavailable,
+			"未配置日志后端（logs.backend）", nil)
+	}
+
+	q := logsearch.Query{
+		Namespace: params.Namespace,
+		Pod:       params.Pod,
+		Container: params.Container,
+		Contains:  params.Contains,
+		Query:     params.Query,
+		Limit:     defaultLogLimit,
+		Direction: logsearch.Backward,
+	}
+
+	if params.Limit != "" {
+		limit, err := strconv.Atoi(params.Limit)
+		if err != nil || limit <= 0 {
+			return q, response.ErrBadRequest("limit 必须是正整数", err)
+		}
+		if limit > s.maxLimit {
+			return q, response.ErrBadRequest(fmt.Sprintf("limit 不能超过 %d", s.maxLimit), nil)
+		}
+		q.Limit = limit
+	}
+
+	switch logsearch.Direction(params.Direction) {
+	case "":
+	case logsearch.Backward, logsearch.Forward:
+		q.Direction = logsearch.Direction(params.Direction)
+	default:
+		return q, response.ErrBadRequest("direction 只支持 backward、forward", nil)
+	}
+
+	var err error
+	if params.Start != "" {
+		if q.Start, err = parseTime(params.Start); err != nil {
+			return q, response.ErrBadRequest("start 参数无效", err)
+		}
+	}
+	if tail {
+		if params.End != "" {
+			return q, response.ErrBadRequest("实时跟踪不支持 end 参数", nil)
+		}
+		return q, nil
+	}
+
+	q.End = time.Now()
+	if params.End != "" {
+		if q.End, err = parseTime(params.End); err != nil {
+			return q, response.ErrBadRequest("end 参数无效", err)
+		}
+	}
+	if q.Start.IsZero() {
+		q.Start = q.End.Add(-defaultLogRange)
+	}
+	if !q.Start.Before(q.End) {
+		return q, response.ErrBadRequest("start 必须早于 end", nil)
+	}
+	return q, nil
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/monitoring"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	defaultQueryTimeout = 30 * time.Second
+	maxQueryTimeout     = 5 * time.Minute
+	// defaultPodMetricsRange 预置 Pod 指标默认查询最近 1 小时，约 120 个点
+	defaultPodMetricsRange = time.Hour
+	minPodMetricsStep      = 15 * time.Second
+)
+
+// QueryParams 监控查询参数
+// Namespace 非空时查询被限制在该命名空间内；为空时只有开启 AllowClusterQueries 的数据源允许查询
+type QueryParams struct {
+	Cluster    string
+	Datasource int64 // 为 0 时使用集群默认数据源
+	Namespace  string
+	Query      string
+	Time       string
+	Start      string
+	End        string
+	Step       string
+	Match      []string
+	Label      string
+	Timeout    time.Duration
+}
+
+// PodMetricsResult 一个预置指标的区间查询结果
+type PodMetricsResult struct {
+	monitoring.PodMetric
+	monitoring.Result
+}
+
+// MonitoringService 监控数据源管理和 Prometheus 查询代理
+// 类比Shell: curl -s "$PROM/api/v1/query_range" --data-urlencode "query=..." | jq .data
+type MonitoringService struct {
+	datasourceRepo *repository.DatasourceRepository
+	clusters       *client.ClusterManager
+
+	// 按数据源 ID 缓存客户端，复用连接
+	clients *clientCache[*monitoring.Client]
+}
+
+// NewMonitoringService 创建监控 Service
+func NewMonitoringService(repo *repository.DatasourceRepository, clusters *client.ClusterManager) *MonitoringService {
+	return &MonitoringService{
+		datasourceRepo: repo,
+		clusters:       clusters,
+		clients:        newClientCache[*monitoring.Client](),
+	}
+}
+
+// ListDatasources 获取集群的所有数据源
+func (s *MonitoringService) ListDatasources(ctx context.Context, cluster string) ([]repository.Datasource, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return s.datasourceRepo.ListByCluster(ctx, name)
+}
+
+// GetDatasource 获取单个数据源
+func (s *MonitoringService) GetDatasource(ctx context.Context, cluster string, id int64) (repository.Datasource, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return repository.Datasource{}, err
+	}
+	return s.datasourceRepo.GetByID(ctx, name, id)
+}
+
+// CreateDatasource 创建数据源
+func (s *MonitoringService) CreateDatasource(ctx context.Context, cluster string, ds repository.Datasource) (repository.Datasource, error) {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return repository.Datasource{}, err
+	}
+	ds.Cluster = name
+	if err := normalizeDatasource(&ds); err != nil {
+		return repository.Datasource{}, err
+	}
+	return s.datasourceRepo.Create(ctx, ds)
+}
+
+// UpdateDatasource 更新数据源，Secret 为空时保留原值
+func (s *MonitoringService) UpdateDatasource(ctx context.Context, cluster string, ds repository.Datasource) (repository.Datasource, error) {
+	existing, err := s.GetDatasource(ctx, cluster, ds.ID)
+	if err != nil {
+		return repository.Datasource{}, err
+	}
+	ds.Cluster = existing.Cluster
+	if ds.Secret == "" && ds.AuthType == existing.AuthType {
+		ds.Secret = existing.Secret
+	}
+	if err := normalizeDatasource(&ds); err != nil {
+		return repository.Datasource{}, err
+	}
+	return s.datasourceRepo.Update(ctx, ds)
+}
+
+// DeleteDatasource 删除数据源
+func (s *MonitoringService) DeleteDatasource(ctx context.Context, cluster string, id int64) error {
+	name, err := s.clusterName(cluster)
+	if err != nil {
+		return err
+	}
+	if err := s.datasourceRepo.Delete(ctx, name, id); err != nil {
+		return err
+	}
+	s.clients.remove(id)
+	return nil
+}
+
+// Query 即时查询
+func (s *MonitoringService) Query(ctx context.Context, params QueryParams) (monitoring.Result, error) {
+	c, ds, err := s.client(ctx, params)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	query, err := scopeQuery(params.Query, ds, params.Namespace)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	return c.Query(ctx, query, params.Time, params.Timeout)
+}
+
+// QueryRange 区间查询
+func (s *MonitoringService) QueryRange(ctx context.Context, params QueryParams) (monitoring.Result, error) {
+	if params.Start == "" || params.End == "" || params.Step == "" {
+		return monitoring.Result{}, response.ErrBadRequest("start、end、step 参数必填", nil)
+	}
+	c, ds, err := s.client(ctx, params)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	query, err := scopeQuery(params.Query, ds, params.Namespace)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	return c.QueryRange(ctx, query, params.Start, params.End, params.Step, params.Timeout)
+}
+
+// Series 查询时间序列
+func (s *MonitoringService) Series(ctx context.Context, params QueryParams) (monitoring.Result, error) {
+	if len(params.Match) == 0 {
+		return monitoring.Result{}, response.ErrBadRequest("至少需要一个 match[] 参数", nil)
+	}
+	c, ds, err := s.client(ctx, params)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	matches, err := scopeMatches(params.Match, ds, params.Namespace)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	return c.Series(ctx, matches, params.Start, params.End, params.Timeout)
+}
+
+// Labels 查询标签名，指定 Label 时查询标签取值
+func (s *MonitoringService) Labels(ctx context.Context, params QueryParams) (monitoring.Result, error) {
+	c, ds, err := s.client(ctx, params)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	matches, err := scopeMatches(params.Match, ds, params.Namespace)
+	if err != nil {
+		return monitoring.Result{}, err
+	}
+	return c.Labels(ctx, params.Label, matches, params.Start, params.End, params.Timeout)
+}
+
+// PodMetrics 并发执行 Pod 的预置 CPU、内存、网络区间查询
+// start、end 为空时查询最近 1 小时，step 为空时按约 120 个点计算
+func (s *MonitoringService) PodMetrics(ctx context.Context, params QueryParams, pod string) ([]PodMetricsResult, error) {
+	c, ds, err := s.client(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := podMetricsRange(&params); err != nil {
+		return nil, err
+	}
+
+	queries := monitoring.PodQueries(ds.NamespaceLabel, params.Namespace, pod)
+	results := make([]PodMetricsResult, len(queries))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, q := range queries {
+		i, q := i, q
+		g.Go(func() error {
+			result, err := c.QueryRange(gctx, q.Query, params.Start, params.End, params.Step, params.Timeout)
+			if err != nil {
+				return fmt.Errorf("%s: %w", q.Name, err)
+			}
+			results[i] = PodMetricsResult{PodMetric: q, Result: result}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// client 获取查询使用的数据源和客户端
+func (s *MonitoringService) client(ctx context.Context, params QueryParams) (*monitoring.Client, repository.Datasource, error) {
+	name, err := s.clusterName(params.Cluster)
+	if err != nil {
+		return nil, repository.Datasource{}, err
+	}
+
+	var ds repository.Datasource
+	if params.Datasource > 0 {
+		ds, err = s.datasourceRepo.GetByID(ctx, name, params.Datasource)
+	} else {
+		ds, err = s.datasourceRepo.GetDefault(ctx, name)
+	}
+	if err != nil {
+		return nil, repository.Datasource{}, err
+	}
+
+	c, _ := s.clients.get(ds.ID, ds.UpdatedAt, func() (*monitoring.Client, error) {
+		return monitoring.NewClient(monitoring.ClientConfig{
+			URL:           ds.URL,
+			AuthType:      ds.AuthType,
+			Username:      ds.Username,
+			Secret:        ds.Secret,
+			TLSSkipVerify: ds.TLSSkipVerify,
+			Timeout:       time.Duration(ds.TimeoutSeconds) * time.Second,
+		}), nil
+	})
+	return c, ds, nil
+}
+
+// clusterName 将缺省集群解析为实际集群名，数据源按实际集群名存储
+func (s *MonitoringService) clusterName(cluster string) (string, error) {
+	c, err := s.clusters.Get(cluster)
+	if err != nil {
+		return "", err
+	}
+	return c.Name, nil
+}
+
+// normalizeDatasource 校验数据源并填充默认值
+func normalizeDatasource(ds *repository.Datasource) error {
+	if ds.Name == "" {
+		return response.ErrBadRequest("数据源名称不能为空", nil)
+	}
+	if ds.Type == "" {
+		ds.Type = "prometheus"
+	}
+	if ds.Type != "prometheus" && ds.Type != "victoriametrics" {
+		return response.ErrBadRequest(fmt.Sprintf("不支持的数据源类型 %s（可选 prometheus、victoriametrics）", ds.Type), nil)
+	}
+	u, err := url.Parse(ds.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return response.ErrBadRequest(fmt.Sprintf("数据源地址无效: %s", ds.URL), err)
+	}
+	if ds.AuthType == "" {
+		ds.AuthType = "none"
+	}
+	switch ds.AuthType {
+	case "none":
+		ds.Username, ds.Secret = "", ""
+	case "basic", "bearer":
+		if ds.Secret == "" {
+			return response.ErrBadRequest("认证方式为 basic/bearer 时必须提供密码或 Token", nil)
+		}
+	default:
+		return response.ErrBadRequest(fmt.Sprintf("不支持的认证方式 %s（可选 none、basic、bearer）", ds.AuthType), nil)
+	}
+	if ds.TimeoutSeconds <= 0 {
+		ds.TimeoutSeconds = int(defaultQueryTimeout.Seconds())
+	}
+	if ds.TimeoutSeconds > int(maxQueryTimeout.Seconds()) {
+		return response.ErrBadRequest(fmt.Sprintf("查询超时不能超过 %d 秒", int(maxQueryTimeout.Seconds())), nil)
+	}
+	if ds.NamespaceLabel == "" {
+		ds.NamespaceLabel = "namespace"
+	}
+	return nil
+}
+
+// scopeQuery 命名空间范围查询时为每个选择器注入命名空间标签
+func scopeQuery(query string, ds repository.Datasource, namespace string) (string, error) {
+	if query == "" {
+		return "", response.ErrBadRequest("query 参数必填", nil)
+	}
+	if namespace == "" {
+		return query, clusterQueryAllowed(ds)
+	}
+	scoped, err := monitoring.ScopeQuery(query, ds.NamespaceLabel, namespace)
+	if err != nil {
+		return "", response.ErrBadRequest("无法限定查询的命名空间", err)
+	}
+	return scoped, nil
+}
+
+// scopeMatches 限定 series/labels 的 match[]；没有 match[] 时只匹配该命名空间的序列
+func scopeMatches(matches []string, ds repository.Datasource, namespace string) ([]string, error) {
+	if namespace == "" {
+		return matches, clusterQueryAllowed(ds)
+	}
+	if len(matches) == 0 {
+		return []string{fmt.Sprintf("{%s=%s}", ds.NamespaceLabel, strconv.Quote(namespace))}, nil
+	}
+	scoped := make([]string, 0, len(matches))
+	for _, m := range matches {
+		s, err := scopeQuery(m, ds, namespace)
+		if err != nil {
+			return nil, err
+		}
+		scoped = append(scoped, s)
+	}
+	return scoped, nil
+}
+
+// clusterQueryAllowed 未指定命名空间的查询能读取整个集群的指标，只有数据源显式开启时允许
+func clusterQueryAllowed(ds repository.Datasource) error {
+	if ds.AllowClusterQueries {
+		return nil
+	}
+	return response.NewError(http.StatusForbidden, response.CodeForbidden,
+		fmt.Sprintf("数据源 %s 未开启 allowClusterQueries，查询必须指定 namespace 参数", ds.Name), nil)
+}
+
+// podMetricsRange 填充预置查询的默认时间范围和步长
+func podMetricsRange(params *QueryParams) error {
+	end := time.Now()
+	var start time.Time
+	var err error
+	if params.End != "" {
+		if end, err = parseTime(params.End); err != nil {
+			return response.ErrBadRequest("end 参数无效", err)
+		}
+	}
+	if params.Start != "" {
+		if start, err = parseTime(params.Start); err != nil {
+			return response.ErrBadRequest("start 参数无效", err)
+		}
+	} else {
+		start = end.Add(-defaultPodMetricsRange)
+	}
+	if !start.Before(end) {
+		return response.ErrBadRequest("start 必须早于 end", nil)
+	}
+	if params.Step == "" {
+		step := max(end.Sub(start)/120, minPodMetricsStep).Round(time.Second)
+		params.Step = strconv.Itoa(int(step.Seconds()))
+	}
+	params.Start = strconv.FormatInt(start.Unix(), 10)
+	params.End = strconv.FormatInt(end.Unix(), 10)
+	return nil
+}
+
+// parseTime 解析 Prometheus 风格的时间：Unix 秒（可带小数）或 RFC3339
+func parseTime(value string) (time.Time, error) {
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		sec := int64(f)
+		return time.Unix(sec, int64((f-float64(sec))*1e9)), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+func TestScopeQuery(t *testing.T) {
+	ds := repository.Datasource{Name: "prom", NamespaceLabel: "kubernetes_namespace"}
+	clusterDS := ds
+	clusterDS.AllowClusterQueries = true
+
+	tests := []struct {
+		name      string
+		ds        repository.Datasource
+		query     string
+		namespace string
+		want      string
+		wantCode  int
+	}{
+		{name: "namespace scoped", ds: ds, query: `sum(rate(x[5m]))`, namespace: "web", want: `sum(rate(x{kubernetes_namespace="web"}[5m]))`},
+		{name: "unscoped query is rejected", ds: ds, query: `up`, wantCode: response.CodeForbidden},
+		{name: "unscoped query on a cluster datasource", ds: clusterDS, query: `up`, want: `up`},
+		{name: "cluster datasource still scopes when namespace is set", ds: clusterDS, query: `up`, namespace: "web", want: `up{kubernetes_namespace="web"}`},
+		{name: "escaping the namespace", ds: ds, query: `up{kubernetes_namespace=~".*"}`, namespace: "web", wantCode: response.CodeBadRequest},
+		{name: "empty query", ds: ds, namespace: "web", wantCode: response.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scopeQuery(tt.query, tt.ds, tt.namespace)
+			if tt.wantCode != 0 {
+				var appErr *response.AppError
+				if !errors.As(err, &appErr) || appErr.Code != tt.wantCode {
+					t.Fatalf("scopeQuery() = %q, %v; want error code %d", got, err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("scopeQuery() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeMatches(t *testing.T) {
+	ds := repository.Datasource{Name: "prom", NamespaceLabel: "namespace"}
+
+	got, err := scopeMatches(nil, ds, "web")
+	if err != nil || !slices.Equal(got, []string{`{namespace="web"}`}) {
+		t.Errorf("scopeMatches(nil) = %v, %v", got, err)
+	}
+	got, err = scopeMatches([]string{`up`, `{job="a"}`}, ds, "web")
+	if err != nil || !slices.Equal(got, []string{`up{namespace="web"}`, `{job="a",namespace="web"}`}) {
+		t.Errorf("scopeMatches() = %v, %v", got, err)
+	}
+
+	// 不带 namespace 的 series/labels 会列出整个集群的序列
+	var appErr *response.AppError
+	if _, err := scopeMatches(nil, ds, ""); !errors.As(err, &appErr) || appErr.Code != response.CodeForbidden {
+		t.Errorf("scopeMatches() without namespace error = %v, want forbidden", err)
+	}
+	ds.AllowClusterQueries = true
+	if got, err := scopeMatches([]string{`up`}, ds, ""); err != nil || !slices.Equal(got, []string{`up`}) {
+		t.Errorf("scopeMatches() on a cluster datasource = %v, %v", got, err)
+	}
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+
+	"github.com/yansongwel/kubeops/backend/internal/cache"
 	"github.com/yansongwel/kubeops/backend/internal/repository"
 )
 
@@ -8,21 +11,30 @@ import (
 // 类比Shell函数：list_namespaces() { all=$(get_all_namespaces); filter; echo; }
 type NamespaceService struct {
 	namespaceRepo *repository.NamespaceRepository
+	cache         *cache.Cache
 }
 
-// NewNamespaceService 创建命名空间Service
-func NewNamespaceService(repo *repository.NamespaceRepository) *NamespaceService {
+// NewNamespaceService 创建命名空间Service，listCache 为 nil 时不使用缓存
+func NewNamespaceService(repo *repository.NamespaceRepository, listCache *cache.Cache) *NamespaceService {
 	return &NamespaceService{
 		namespaceRepo: repo,
+		cache:         listCache,
 	}
 }
 
 // ListNamespaces 获取命名空间列表（带业务规则过滤）
 // 业务规则：过滤系统命名空间（kube-system, kube-public, kube-node-lease）
 // 对应Shell: get_all_namespaces | grep -v "kube-system" | grep -v "kube-public"
-func (s *NamespaceService) ListNamespaces() ([]string, error) {
+func (s *NamespaceService) ListNamespaces(ctx context.Context, cluster string) ([]string, error) {
+	key := cache.Key{Cluster: cluster, Resource: "namespaces"}
+	return cache.Fetch(ctx, s.cache, key, func(ctx context.Context) ([]string, error) {
+		return s.listNamespaces(ctx, cluster)
+	})
+}
+
+func (s *NamespaceService) listNamespaces(ctx context.Context, cluster string) ([]string, error) {
 	// 调用Repository层获取数据
-	allNamespaces, err := s.namespaceRepo.ListAll()
+	allNamespaces, err := s.namespaceRepo.ListAll(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -39,8 +51,8 @@ func (s *NamespaceService) ListNamespaces() ([]string, error) {
 }
 
 // GetNamespace 获取单个命名空间信息
-func (s *NamespaceService) GetNamespace(name string) (string, error) {
-	_, err := s.namespaceRepo.GetByName(name)
+func (s *NamespaceService) GetNamespace(ctx context.Context, cluster, name string) (string, error) {
+	_, err := s.namespaceRepo.GetByName(ctx, cluster, name)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+)
+
+// Node 节点列表和详情的返回结构
+// Usage 来自 metrics-server，未安装或暂不可用时省略
+type Node struct {
+	Name          string             `json:"name"`
+	Status        string             `json:"status"` // Ready、NotReady 或 Unknown
+	Roles         []string           `json:"roles"`
+	Unschedulable bool               `json:"unschedulable"`
+	Version       string             `json:"version"`
+	InternalIP    string             `json:"internalIP"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	Labels        map[string]string  `json:"labels"`
+	Capacity      ResourceQuantities `json:"capacity"`
+	Allocatable   ResourceQuantities `json:"allocatable"`
+	Usage         *NodeUsage         `json:"usage,omitempty"`
+}
+
+// NodeService 节点业务逻辑层
+// 类比Shell函数：list_nodes() { kubectl get nodes; kubectl top nodes; }
+type NodeService struct {
+	nodeRepo    *repository.NodeRepository
+	metricsRepo *repository.MetricsRepository
+}
+
+// NewNodeService 创建节点 Service
+func NewNodeService(repo *repository.NodeRepository, metricsRepo *repository.MetricsRepository) *NodeService {
+	return &NodeService{
+		nodeRepo:    repo,
+		metricsRepo: metricsRepo,
+	}
+}
+
+// ListNodes 获取节点列表，合并实时用量并按 sortBy 排序
+// 对应Shell: kubectl top nodes --sort-by=cpu
+func (s *NodeService) ListNodes(ctx context.Context, cluster, sortBy string) ([]Node, error) {
+	if err := validateSortBy(sortBy); err != nil {
+		return nil, err
+	}
+	nodes, err := s.nodeRepo.List(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Node, 0, len(nodes))
+	for i := range nodes {
+		result = append(result, toNode(&nodes[i]))
+	}
+
+	mctx, cancel := context.WithTimeout(ctx, metricsTimeout)
+	defer cancel()
+	items, err := s.metricsRepo.ListNodeMetrics(mctx, cluster)
+	if err != nil {
+		logMetricsError(ctx, cluster, err)
+	} else {
+		index := make(map[string]*metricsv1beta1.NodeMetrics, len(items))
+		for i := range items {
+			index[items[i].Name] = &items[i]
+		}
+		for i := range result {
+			if m, ok := index[result[i].Name]; ok {
+				applyNodeUsage(&result[i], m)
+			}
+		}
+	}
+
+	sortByUsage(result, sortBy, func(n Node) (ResourceQuantities, bool) {
+		if n.Usage == nil {
+			return ResourceQuantities{}, false
+		}
+		return ResourceQuantities{CPUMillicores: n.Usage.CPUMillicores, MemoryBytes: n.Usage.MemoryBytes}, true
+	}, func(n Node) string {
+		return n.Name
+	})
+	return result, nil
+}
+
+// GetNode 获取单个节点及其实时用量
+func (s *NodeService) GetNode(ctx context.Context, cluster, name string) (Node, error) {
+	node, err := s.nodeRepo.GetByName(ctx, cluster, name)
+	if err != nil {
+		return Node{}, err
+	}
+	result := toNode(node)
+
+	mctx, cancel := context.WithTimeout(ctx, metricsTimeout)
+	defer cancel()
+	m, err := s.metricsRepo.GetNodeMetrics(mctx, cluster, name)
+	if err != nil {
+		logMetricsError(ctx, cluster, err)
+		return result, nil
+	}
+	applyNodeUsage(&result, m)
+	return result, nil
+}
+
+// applyNodeUsage 合并节点用量，百分比相对 allocatable
+func applyNodeUsage(node *Node, m *metricsv1beta1.NodeMetrics) {
+	u := quantities(m.Usage)
+	node.Usage = &NodeUsage{
+		CPUMillicores: u.CPUMillicores,
+		MemoryBytes:   u.MemoryBytes,
+		CPUPercent:    percent(u.CPUMillicores, node.Allocatable.CPUMillicores),
+		MemoryPercent: percent(u.MemoryBytes, node.Allocatable.MemoryBytes),
+		Timestamp:     m.Timestamp.Time,
+		Window:        m.Window.Duration.String(),
+	}
+}
+
+func toNode(node *corev1.Node) Node {
+	status := "Unknown"
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			switch cond.Status {
+			case corev1.ConditionTrue:
+				status = "Ready"
+			case corev1.ConditionFalse:
+				status = "NotReady"
+			}
+		}
+	}
+	var internalIP string
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP {
+			internalIP = addr.Address
+			break
+		}
+	}
+	return Node{
+		Name:          node.Name,
+		Status:        status,
+		Roles:         nodeRoles(node.Labels),
+		Unschedulable: node.Spec.Unschedulable,
+		Version:       node.Status.NodeInfo.KubeletVersion,
+		InternalIP:    internalIP,
+		CreatedAt:     node.CreationTimestamp.Time,
+		Labels:        node.Labels,
+		Capacity:      quantities(node.Status.Capacity),
+		Allocatable:   quantities(node.Status.Allocatable),
+	}
+}
+
+// nodeRoles 从 node-role.kubernetes.io/<role> 和 kubernetes.io/role 标签提取角色，与 kubectl get nodes 一致
+func nodeRoles(labels map[string]string) []string {
+	roles := []string{}
+	for k, v := range labels {
+		switch {
+		case strings.HasPrefix(k, "node-role.kubernetes.io/"):
+			if role := strings.TrimPrefix(k, "node-role.kubernetes.io/"); role != "" {
+				roles = append(roles, role)
+			}
+		case k == "kubernetes.io/role" && v != "":
+			roles = append(roles, v)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,461 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/metrics"
+	"github.com/yansongwel/kubeops/backend/internal/notify"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+const (
+	notifyDedupKeyPrefix = "kubeops:notify:dedup:"
+	notifyRateKeyPrefix  = "kubeops:notify:rate:"
+
+	// notificationQueueSize 待发送通知的队列长度，队列满时丢弃并记录日志
+	notificationQueueSize = 1000
+	// notificationWorkers 并发发送通知的 worker 数
+	notificationWorkers = 4
+)
+
+// 通知发送结果，对应 kubeops_notifications_total 的 result 标签
+const (
+	notifyResultSent         = "sent"
+	notifyResultFailed       = "failed"
+	notifyResultDeduplicated = "deduplicated"
+	notifyResultRateLimited  = "rate_limited"
+	notifyResultDropped      = "dropped"
+)
+
+// notificationDelivery 已通过去重和限流、等待发送的一条通知
+type notificationDelivery struct {
+	channel  repository.NotificationChannel
+	event    notify.Event
+	dedupKey string // 发送失败时删除，下次检测到时重新发送
+}
+
+// ChannelTestResult 测试渠道的结果
+type ChannelTestResult struct {
+	Text string `json:"text"` // 发送的消息内容
+}
+
+// NotificationService 通知渠道、路由规则管理和通知发送
+// 事件按路由规则匹配渠道，经 Redis 去重和限流后进入队列，由 worker 渲染模板并带重试发送
+// 类比Shell: echo "$event" | match_rules | while read channel; do dedup "$channel" && retry curl -d "$msg" "$webhook"; done
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	redis            *redis.Client
+	redisDep         *client.Dependency
+	clusters         *client.ClusterManager
+	nodeRepo         *repository.NodeRepository
+	deploymentRepo   *repository.DeploymentRepository
+	cfg              config.NotificationConfig
+
+	queue chan notificationDelivery
+
+	// 按渠道 ID 缓存发送端和模板，渠道更新后重新创建
+	senders *clientCache[channelSender]
+}
+
+type channelSender struct {
+	sender   notify.Sender
+	template *template.Template
+}
+
+// CloseIdleConnections 发送端被替换时关闭其空闲连接
+func (cs channelSender) CloseIdleConnections() {
+	closeIdle(cs.sender)
+}
+
+// NewNotificationService 创建通知 Service
+func NewNotificationService(
+	notificationRepo *repository.NotificationRepository,
+	redisClient *redis.Client,
+	redisDep *client.Dependency,
+	clusters *client.ClusterManager,
+	nodeRepo *repository.NodeRepository,
+	deploymentRepo *repository.DeploymentRepository,
+	cfg config.NotificationConfig,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		redis:            redisClient,
+		redisDep:         redisDep,
+		clusters:         clusters,
+		nodeRepo:         nodeRepo,
+		deploymentRepo:   deploymentRepo,
+		cfg:              cfg,
+		queue:            make(chan notificationDelivery, notificationQueueSize),
+		senders:          newClientCache[channelSender](),
+	}
+}
+
+// ListChannels 获取所有通知渠道，地址中的 Token 和请求头的值被遮盖
+func (s *NotificationService) ListChannels(ctx context.Context) ([]repository.NotificationChannel, error) {
+	channels, err := s.notificationRepo.ListChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range channels {
+		channels[i].Config = channels[i].Config.Masked()
+	}
+	return channels, nil
+}
+
+// GetChannel 获取单个通知渠道，地址中的 Token 和请求头的值被遮盖
+func (s *NotificationService) GetChannel(ctx context.Context, id int64) (repository.NotificationChannel, error) {
+	return maskChannel(s.notificationRepo.GetChannel(ctx, id))
+}
+
+// CreateChannel 添加通知渠道
+func (s *NotificationService) CreateChannel(ctx context.Context, ch repository.NotificationChannel) (repository.NotificationChannel, error) {
+	if err := normalizeChannel(&ch); err != nil {
+		return repository.NotificationChannel{}, err
+	}
+	return maskChannel(s.notificationRepo.CreateChannel(ctx, ch))
+}
+
+// UpdateChannel 更新通知渠道，Secret 为空时保留原值，地址和请求头为遮盖值时保留原值
+func (s *NotificationService) UpdateChannel(ctx context.Context, ch repository.NotificationChannel) (repository.NotificationChannel, error) {
+	existing, err := s.notificationRepo.GetChannel(ctx, ch.ID)
+	if err != nil {
+		return repository.NotificationChannel{}, err
+	}
+	if ch.Secret == "" {
+		ch.Secret = existing.Secret
+	}
+	ch.Config.Restore(existing.Config)
+	if err := normalizeChannel(&ch); err != nil {
+		return repository.NotificationChannel{}, err
+	}
+	return maskChannel(s.notificationRepo.UpdateChannel(ctx, ch))
+}
+
+// DeleteChannel 删除通知渠道及其路由规则
+func (s *NotificationService) DeleteChannel(ctx context.Context, id int64) error {
+	if err := s.notificationRepo.DeleteChannel(ctx, id); err != nil {
+		return err
+	}
+	s.senders.remove(id)
+	return nil
+}
+
+// TestChannel 向渠道发送一条测试消息，不经过路由规则、去重和限流，也不重试
+func (s *NotificationService) TestChannel(ctx context.Context, id int64) (ChannelTestResult, error) {
+	ch, err := s.notificationRepo.GetChannel(ctx, id)
+	if err != nil {
+		return ChannelTestResult{}, err
+	}
+	cs, err := s.sender(ch)
+	if err != nil {
+		return ChannelTestResult{}, response.ErrBadRequest(fmt.Sprintf("通知渠道 %s 配置无效: %v", ch.Name, err), err)
+	}
+	msg, err := render(cs, notify.SampleEvent())
+	if err != nil {
+		return ChannelTestResult{}, response.ErrBadRequest(fmt.Sprintf("渲染模板失败: %v", err), err)
+	}
+	if err := cs.sender.Send(ctx, msg); err != nil {
+		return ChannelTestResult{}, response.NewError(http.StatusBadGateway, response.CodeBadGateway,
+			fmt.Sprintf("发送测试通知失败: %v", err), err)
+	}
+	return ChannelTestResult{Text: msg.Text}, nil
+}
+
+// ListRules 获取路由规则，channelID 不为 0 时只返回该渠道的规则
+func (s *NotificationService) ListRules(ctx context.Context, channelID int64) ([]repository.NotificationRule, error) {
+	return s.notificationRepo.ListRules(ctx, channelID)
+}
+
+// GetRule 获取单个路由规则
+func (s *NotificationService) GetRule(ctx context.Context, id int64) (repository.NotificationRule, error) {
+	return s.notificationRepo.GetRule(ctx, id)
+}
+
+// CreateRule 添加路由规则
+func (s *NotificationService) CreateRule(ctx context.Context, rule repository.NotificationRule) (repository.NotificationRule, error) {
+	if err := normalizeRule(&rule); err != nil {
+		return repository.NotificationRule{}, err
+	}
+	return s.notificationRepo.CreateRule(ctx, rule)
+}
+
+// UpdateRule 更新路由规则
+func (s *NotificationService) UpdateRule(ctx context.Context, rule repository.NotificationRule) (repository.NotificationRule, error) {
+	if err := normalizeRule(&rule); err != nil {
+		return repository.NotificationRule{}, err
+	}
+	return s.notificationRepo.UpdateRule(ctx, rule)
+}
+
+// DeleteRule 删除路由规则
+func (s *NotificationService) DeleteRule(ctx context.Context, id int64) error {
+	return s.notificationRepo.DeleteRule(ctx, id)
+}
+
+// Notify 按路由规则把事件分发到渠道，经去重和限流后放入发送队列，不等待发送完成
+// 同一事件匹配到同一渠道的多条规则时只发送一次
+func (s *NotificationService) Notify(ctx context.Context, events ...notify.Event) {
+	if len(events) == 0 {
+		return
+	}
+	logger := logging.FromContext(ctx)
+	rules, err := s.notificationRepo.ListRules(ctx, 0)
+	if err != nil {
+		logger.Warn("Failed to list notification rules", zap.Error(err))
+		return
+	}
+	channels, err := s.notificationRepo.ListChannels(ctx)
+	if err != nil {
+		logger.Warn("Failed to list notification channels", zap.Error(err))
+		return
+	}
+	byID := make(map[int64]repository.NotificationChannel, len(channels))
+	for _, ch := range channels {
+		if ch.Enabled {
+			byID[ch.ID] = ch
+		}
+	}
+
+	for _, event := range events {
+		if event.Time.IsZero() {
+			event.Time = time.Now()
+		}
+		sent := make(map[int64]bool)
+		for _, rule := range rules {
+			ch, ok := byID[rule.ChannelID]
+			if !ok || sent[ch.ID] || !rule.Enabled || !matchRule(rule, event) {
+				continue
+			}
+			sent[ch.ID] = true
+			s.enqueue(ctx, ch, event)
+		}
+	}
+}
+
+// enqueue 去重和限流通过后放入发送队列
+// Redis 不可用时不去重、不限流（fail open），保证通知能发出去
+func (s *NotificationService) enqueue(ctx context.Context, ch repository.NotificationChannel, event notify.Event) {
+	logger := logging.FromContext(ctx).With(zap.String("channel", ch.Name), zap.String("event", event.Type),
+		zap.String("cluster", event.Cluster), zap.String("object", event.Object()))
+	var dedupKey string
+	if s.redisDep.Err() == nil {
+		if s.cfg.DedupWindow > 0 {
+			key := notifyDedupKeyPrefix + strconv.FormatInt(ch.ID, 10) + ":" + event.Fingerprint()
+			ok, err := s.redis.SetNX(ctx, key, event.Time.Unix(), s.cfg.DedupWindow).Result()
+			switch {
+			case err != nil:
+				logger.Warn("Failed to deduplicate notification", zap.Error(err))
+			case !ok:
+				metrics.Notifications.WithLabelValues(ch.Type, notifyResultDeduplicated).Inc()
+				return
+			default:
+				dedupKey = key
+			}
+		}
+		if s.cfg.RateLimit > 0 {
+			key := notifyRateKeyPrefix + strconv.FormatInt(ch.ID, 10) + ":" + strconv.FormatInt(time.Now().Unix()/60, 10)
+			pipe := s.redis.TxPipeline()
+			incr := pipe.Incr(ctx, key)
+			pipe.Expire(ctx, key, 2*time.Minute)
+			if _, err := pipe.Exec(ctx); err != nil {
+				logger.Warn("Failed to rate limit notification", zap.Error(err))
+			} else if incr.Val() > int64(s.cfg.RateLimit) {
+				// 被限流的事件不占用去重窗口，下次检测到时再发送
+				s.forget(ctx, dedupKey)
+				metrics.Notifications.WithLabelValues(ch.Type, notifyResultRateLimited).Inc()
+				logger.Warn("Notification rate limited", zap.Int("limit", s.cfg.RateLimit))
+				return
+			}
+		}
+	}
+
+	select {
+	case s.queue <- notificationDelivery{channel: ch, event: event, dedupKey: dedupKey}:
+	default:
+		s.forget(ctx, dedupKey)
+		metrics.Notifications.WithLabelValues(ch.Type, notifyResultDropped).Inc()
+		logger.Warn("Notification queue is full, dropping notification")
+	}
+}
+
+// Run 启动发送 worker，阻塞直到 ctx 取消；所有副本都运行，手动巡检等在任意副本上产生的事件就地发送
+func (s *NotificationService) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < notificationWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-s.queue:
+					s.deliver(ctx, d)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver 渲染模板并发送，失败时按配置指数退避重试
+func (s *NotificationService) deliver(ctx context.Context, d notificationDelivery) {
+	logger := logging.FromContext(ctx).With(zap.String("channel", d.channel.Name), zap.String("event", d.event.Type),
+		zap.String("cluster", d.event.Cluster), zap.String("object", d.event.Object()))
+	cs, err := s.sender(d.channel)
+	if err == nil {
+		var msg notify.Message
+		if msg, err = render(cs, d.event); err == nil {
+			var attempts int
+			attempts, err = notify.Send(ctx, cs.sender, msg, s.cfg.MaxAttempts, s.cfg.RetryBackoff)
+			if err == nil {
+				metrics.Notifications.WithLabelValues(d.channel.Type, notifyResultSent).Inc()
+				logger.Debug("Notification sent", zap.Int("attempts", attempts))
+				return
+			}
+			logger = logger.With(zap.Int("attempts", attempts))
+		}
+	}
+	s.forget(context.WithoutCancel(ctx), d.dedupKey)
+	metrics.Notifications.WithLabelValues(d.channel.Type, notifyResultFailed).Inc()
+	logger.Warn("Failed to send notification", zap.Error(err))
+}
+
+// forget 删除去重记录
+func (s *NotificationService) forget(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := s.redis.Del(ctx, key).Err(); err != nil {
+		logging.FromContext(ctx).Warn("Failed to delete notification dedup key", zap.Error(err))
+	}
+}
+
+// sender 按渠道 ID 获取发送端，渠道更新后重新创建
+func (s *NotificationService) sender(ch repository.NotificationChannel) (channelSender, error) {
+	return s.senders.get(ch.ID, ch.UpdatedAt, func() (channelSender, error) {
+		sender, err := notify.NewSender(ch.Type, ch.Config, ch.Secret, s.cfg.Timeout)
+		if err != nil {
+			return channelSender{}, err
+		}
+		tmpl, err := notify.ParseTemplate(ch.Template)
+		if err != nil {
+			return channelSender{}, fmt.Errorf("invalid template: %w", err)
+		}
+		return channelSender{sender: sender, template: tmpl}, nil
+	})
+}
+
+func render(cs channelSender, event notify.Event) (notify.Message, error) {
+	text, err := notify.Render(cs.template, event)
+	if err != nil {
+		return notify.Message{}, err
+	}
+	return notify.Message{Event: event, Text: text}, nil
+}
+
+// matchRule 事件是否满足规则的所有条件，条件为空表示不限制
+func matchRule(rule repository.NotificationRule, event notify.Event) bool {
+	if len(rule.EventTypes) > 0 && !slices.Contains(rule.EventTypes, event.Type) {
+		return false
+	}
+	if len(rule.Severities) > 0 && !slices.Contains(rule.Severities, event.Severity) {
+		return false
+	}
+	if len(rule.Clusters) > 0 && !matchAny(rule.Clusters, event.Cluster) {
+		return false
+	}
+	// 节点等集群级别的事件没有命名空间，只匹配不限制命名空间的规则
+	if len(rule.Namespaces) > 0 && (event.Namespace == "" || !matchAny(rule.Namespaces, event.Namespace)) {
+		return false
+	}
+	return true
+}
+
+// matchAny 值是否匹配任意一个模式，模式支持 * 通配符
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// maskChannel 遮盖返回给 API 的渠道配置
+func maskChannel(ch repository.NotificationChannel, err error) (repository.NotificationChannel, error) {
+	if err != nil {
+		return ch, err
+	}
+	ch.Config = ch.Config.Masked()
+	return ch, nil
+}
+
+// normalizeChannel 校验渠道配置和模板
+func normalizeChannel(ch *repository.NotificationChannel) error {
+	ch.Name = strings.TrimSpace(ch.Name)
+	if ch.Name == "" {
+		return response.ErrBadRequest("通知渠道名称不能为空", nil)
+	}
+	if !slices.Contains(notify.ChannelTypes, ch.Type) {
+		return response.ErrBadRequest(fmt.Sprintf("不支持的渠道类型 %q，可选: %s", ch.Type, strings.Join(notify.ChannelTypes, ", ")), nil)
+	}
+	if err := ch.Config.Validate(ch.Type); err != nil {
+		return response.ErrBadRequest(fmt.Sprintf("通知渠道配置无效: %v", err), err)
+	}
+	if _, err := notify.ParseTemplate(ch.Template); err != nil {
+		return response.ErrBadRequest(fmt.Sprintf("通知模板无效: %v", err), err)
+	}
+	return nil
+}
+
+// normalizeRule 校验路由规则，空条件保存为空数组
+func normalizeRule(rule *repository.NotificationRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return response.ErrBadRequest("路由规则名称不能为空", nil)
+	}
+	if rule.ChannelID <= 0 {
+		return response.ErrBadRequest("channelId 不能为空", nil)
+	}
+	for _, v := range rule.Severities {
+		if !slices.Contains(notify.Severities, v) {
+			return response.ErrBadRequest(fmt.Sprintf("不支持的严重程度 %q，可选: %s", v, strings.Join(notify.Severities, ", ")), nil)
+		}
+	}
+	for _, v := range rule.EventTypes {
+		if !slices.Contains(notify.EventTypes, v) {
+			return response.ErrBadRequest(fmt.Sprintf("不支持的事件类型 %q，可选: %s", v, strings.Join(notify.EventTypes, ", ")), nil)
+		}
+	}
+	for _, patterns := range [][]string{rule.Clusters, rule.Namespaces} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); errors.Is(err, path.ErrBadPattern) || p == "" {
+				return response.ErrBadRequest(fmt.Sprintf("匹配模式无效: %q", p), err)
+			}
+		}
+	}
+	for _, list := range []*[]string{&rule.Clusters, &rule.Namespaces, &rule.Severities, &rule.EventTypes} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/notify"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// redisStub 只实现通知去重和限流用到的命令（SET NX、INCR、EXPIRE、DEL、MULTI/EXEC）的 RESP2 服务端
+type redisStub struct {
+	mu   sync.Mutex
+	data map[string]string
+	dels []string
+}
+
+func newRedisStub(t *testing.T) (*redisStub, *redis.Client) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	stub := &redisStub{data: map[string]string{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go stub.serve(conn)
+		}
+	}()
+	rdb := redis.NewClient(&redis.Options{Addr: ln.Addr().String(), Protocol: 2, DisableIdentity: true})
+	t.Cleanup(func() {
+		_ = rdb.Close()
+		_ = ln.Close()
+	})
+	return stub, rdb
+}
+
+func (s *redisStub) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	var queued [][]string
+	inMulti := false
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		var reply string
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "MULTI":
+			inMulti, queued = true, nil
+			reply = "+OK\r\n"
+		case cmd == "EXEC":
+			reply = fmt.Sprintf("*%d\r\n", len(queued))
+			for _, q := range queued {
+				reply += s.exec(q)
+			}
+			inMulti = false
+		case inMulti:
+			queued = append(queued, args)
+			reply = "+QUEUED\r\n"
+		default:
+			reply = s.exec(args)
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *redisStub) exec(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		nx := false
+		for _, a := range args[3:] {
+			nx = nx || strings.EqualFold(a, "NX")
+		}
+		if _, ok := s.data[args[1]]; ok && nx {
+			return "$-1\r\n"
+		}
+		s.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "INCR":
+		n, _ := strconv.Atoi(s.data[args[1]])
+		n++
+		s.data[args[1]] = strconv.Itoa(n)
+		return fmt.Sprintf(":%d\r\n", n)
+	case "EXPIRE":
+		return ":1\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[key]; ok {
+				deleted++
+				delete(s.data, key)
+			}
+			s.dels = append(s.dels, key)
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	default:
+		// HELLO 等握手命令返回错误，客户端回退到 RESP2
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+func (s *redisStub) deleted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.dels...)
+}
+
+// readCommand 读取一条 RESP 数组形式的命令
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil { // $<len>
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+// newTestNotificationService 创建连接 redisStub 的通知 Service，等待 Redis 依赖变为可用
+func newTestNotificationService(t *testing.T, cfg config.NotificationConfig) (*NotificationService, *redisStub) {
+	t.Helper()
+	stub, rdb := newRedisStub(t)
+	dep := client.NewDependency(zap.NewNop(), "redis", response.CodeRedisUnavailable, func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go dep.Run(ctx)
+	for deadline := time.Now().Add(5 * time.Second); dep.Err() != nil; {
+		if time.Now().After(deadline) {
+			t.Fatalf("redis stub not ready: %v", dep.Err())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return NewNotificationService(nil, rdb, dep, nil, nil, nil, cfg), stub
+}
+
+func testEvent(name string) notify.Event {
+	return notify.Event{Type: notify.EventRolloutFailed, Severity: notify.SeverityCritical, Cluster: "prod",
+		Namespace: "web", Kind: "Deployment", Name: name, Reason: "ProgressDeadlineExceeded", Time: time.Now()}
+}
+
+func TestNotificationDedup(t *testing.T) {
+	s, _ := newTestNotificationService(t, config.NotificationConfig{DedupWindow: time.Hour})
+	ch := repository.NotificationChannel{ID: 1, Name: "ops", Type: notify.ChannelWebhook}
+	ctx := context.Background()
+
+	event := testEvent("api")
+	s.enqueue(ctx, ch, event)
+	// 同一事件的后续检测只是时间和消息不同，在去重窗口内不再发送
+	again := event
+	again.Time = event.Time.Add(time.Minute)
+	again.Message = "still failing"
+	s.enqueue(ctx, ch, again)
+	// 其他渠道和其他对象不受影响
+	s.enqueue(ctx, repository.NotificationChannel{ID: 2, Name: "dev", Type: notify.ChannelWebhook}, event)
+	s.enqueue(ctx, ch, testEvent("worker"))
+
+	if got := len(s.queue); got != 3 {
+		t.Errorf("queued = %d, want 3", got)
+	}
+}
+
+func TestNotificationRateLimit(t *testing.T) {
+	s, stub := newTestNotificationService(t, config.NotificationConfig{DedupWindow: time.Hour, RateLimit: 2})
+	ch := repository.NotificationChannel{ID: 1, Name: "ops", Type: notify.ChannelWebhook}
+	ctx := context.Background()
+
+	for _, name := range []string{"a", "b", "c"} {
+		s.enqueue(ctx, ch, testEvent(name))
+	}
+	if got := len(s.queue); got != 2 {
+		t.Errorf("queued = %d, want 2", got)
+	}
+	// 被限流的事件删除去重记录，下次检测到时可以再发送
+	if dels := stub.deleted(); len(dels) != 1 || !strings.HasSuffix(dels[0], testEvent("c").Fingerprint()) {
+		t.Errorf("deleted keys = %v, want the dedup key of the rate limited event", dels)
+	}
+}
+
+func TestNotificationFailedDeliveryForgetsDedupKey(t *testing.T) {
+	var requests int
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	s, _ := newTestNotificationService(t, config.NotificationConfig{
+		DedupWindow: time.Hour, MaxAttempts: 3, RetryBackoff: time.Millisecond, Timeout: 5 * time.Second,
+	})
+	ch := repository.NotificationChannel{ID: 1, Name: "ops", Type: notify.ChannelWebhook,
+		Config: notify.ChannelConfig{URL: srv.URL}, UpdatedAt: time.Now()}
+	ctx := context.Background()
+
+	event := testEvent("api")
+	s.enqueue(ctx, ch, event)
+	s.deliver(ctx, <-s.queue)
+	mu.Lock()
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3 attempts", requests)
+	}
+	mu.Unlock()
+
+	// 发送失败后同一事件不再被去重
+	s.enqueue(ctx, ch, event)
+	if got := len(s.queue); got != 1 {
+		t.Errorf("queued after failed delivery = %d, want 1", got)
+	}
+}
+
+func TestNotificationRedisUnavailableFailsOpen(t *testing.T) {
+	dep := client.NewDependency(zap.NewNop(), "redis", response.CodeRedisUnavailable, func(context.Context) error { return nil })
+	s := NewNotificationService(nil, nil, dep, nil, nil, nil, config.NotificationConfig{DedupWindow: time.Hour, RateLimit: 1})
+	ch := repository.NotificationChannel{ID: 1, Name: "ops", Type: notify.ChannelWebhook}
+
+	event := testEvent("api")
+	s.enqueue(context.Background(), ch, event)
+	s.enqueue(context.Background(), ch, event)
+	if got := len(s.queue); got != 2 {
+		t.Errorf("queued = %d, want 2 without dedup and rate limit", got)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/notify"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+)
+
+// Watch 定期检查所有集群的节点和 Deployment，发现节点 NotReady 或发布失败时发送通知，阻塞直到 ctx 取消
+// 只在主节点上运行；不记录上一次的状态，问题持续存在时由去重窗口控制重复通知的频率
+// 类比Shell: while sleep 60; do kubectl get nodes | grep NotReady; kubectl get deploy -A -o json | jq '...ProgressDeadlineExceeded'; done
+func (s *NotificationService) Watch(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, cluster := range s.clusters.Names() {
+			if ctx.Err() != nil {
+				return
+			}
+			s.Notify(ctx, s.clusterEvents(ctx, cluster)...)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// clusterEvents 读取集群的节点和 Deployment，返回需要通知的事件；集群不可用时跳过
+func (s *NotificationService) clusterEvents(ctx context.Context, cluster string) []notify.Event {
+	logger := logging.FromContext(ctx).With(zap.String("cluster", cluster))
+	now := time.Now()
+	var events []notify.Event
+
+	nodes, err := s.nodeRepo.List(ctx, cluster)
+	if err != nil {
+		logger.Debug("Failed to list nodes for notifications", zap.Error(err))
+	}
+	for _, node := range nodes {
+		if event, ok := nodeNotReadyEvent(cluster, node, now); ok {
+			events = append(events, event)
+		}
+	}
+
+	deployments, err := s.deploymentRepo.ListByNamespace(ctx, cluster, "")
+	if err != nil {
+		logger.Debug("Failed to list deployments for notifications", zap.Error(err))
+	}
+	for _, deploy := range deployments {
+		if event, ok := rolloutFailedEvent(cluster, deploy, now); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// nodeNotReadyEvent 节点 Ready 条件不为 True 时返回事件，Unknown 通常表示 kubelet 失联
+func nodeNotReadyEvent(cluster string, node corev1.Node, now time.Time) (notify.Event, bool) {
+	var ready *corev1.NodeCondition
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			ready = &node.Status.Conditions[i]
+		}
+	}
+	if ready == nil || ready.Status == corev1.ConditionTrue {
+		return notify.Event{}, false
+	}
+	message := fmt.Sprintf("节点 %s 的 Ready 状态为 %s，已持续 %s", node.Name, ready.Status,
+		now.Sub(ready.LastTransitionTime.Time).Round(time.Second))
+	if ready.Message != "" {
+		message += "：" + ready.Message
+	}
+	return notify.Event{
+		Type:     notify.EventNodeNotReady,
+		Severity: notify.SeverityCritical,
+		Cluster:  cluster,
+		Kind:     "Node",
+		Name:     node.Name,
+		Reason:   "NotReady",
+		Title:    fmt.Sprintf("节点 %s NotReady", node.Name),
+		Message:  message,
+		Time:     now,
+	}, true
+}
+
+// rolloutFailedEvent Deployment 发布超过 progressDeadlineSeconds 仍未完成，或无法创建 Pod（如超出配额）时返回事件
+// 对应Shell: kubectl rollout status deployment/$NAME -n $NAMESPACE 返回 "exceeded its progress deadline"
+func rolloutFailedEvent(cluster string, deploy appsv1.Deployment, now time.Time) (notify.Event, bool) {
+	for _, cond := range deploy.Status.Conditions {
+		failed := (cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse &&
+			cond.Reason == "ProgressDeadlineExceeded") ||
+			(cond.Type == appsv1.DeploymentReplicaFailure && cond.Status == corev1.ConditionTrue)
+		if !failed {
+			continue
+		}
+		return notify.Event{
+			Type:      notify.EventRolloutFailed,
+			Severity:  notify.SeverityCritical,
+			Cluster:   cluster,
+			Namespace: deploy.Namespace,
+			Kind:      "Deployment",
+			Name:      deploy.Name,
+			Reason:    cond.Reason,
+			Title:     fmt.Sprintf("Deployment %s/%s 发布失败", deploy.Namespace, deploy.Name),
+			Message: fmt.Sprintf("%s：%s（就绪 %d/%d，已更新 %d）", cond.Reason, cond.Message,
+				deploy.Status.ReadyReplicas, deploy.Status.Replicas, deploy.Status.UpdatedReplicas),
+			Time: now,
+		}, true
+	}
+	return notify.Event{}, false
+}
+
+// findingEvents 巡检问题转换为通知事件，不含已确认或屏蔽的问题
+func findingEvents(cluster string, findings []repository.InspectionFinding, now time.Time) []notify.Event {
+	events := make([]notify.Event, 0, len(findings))
+	for _, f := range unsuppressed(findings) {
+		reason := f.Check
+		if f.Object.Container != "" {
+			reason += "/" + f.Object.Container
+		}
+		message := f.Message
+		if f.Remediation != "" {
+			message += "\n建议：" + f.Remediation
+		}
+		events = append(events, notify.Event{
+			Type:      notify.EventInspectionFinding,
+			Severity:  string(f.Severity),
+			Cluster:   cluster,
+			Namespace: f.Object.Namespace,
+			Kind:      f.Object.Kind,
+			Name:      f.Object.Name,
+			Reason:    reason,
+			Title:     fmt.Sprintf("巡检发现问题：%s %s", f.Check, f.Object),
+			Message:   message,
+			Time:      now,
+		})
+	}
+	return events
+}
@@ -1,6 +1,13 @@
 package service
 
 import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/yansongwel/kubeops/backend/internal/cache"
 	"github.com/yansongwel/kubeops/backend/internal/repository"
 )
 
@@ -10,62 +17,241 @@ type PodRepositoryInterface interface {
 	ListByNamespace(namespace string) ([]interface{}, error)
 }
 
+// Container Pod 中的容器
+type Container struct {
+	Name         string             `json:"name"`
+	Image        string             `json:"image"`
+	Ready        bool               `json:"ready"`
+	RestartCount int32              `json:"restartCount"`
+	Resources    ContainerResources `json:"resources"`
+	Usage        *ResourceUsage     `json:"usage,omitempty"`
+}
+
+// Pod Pod 列表和详情的返回结构
+// Usage 来自 metrics-server，未安装或暂不可用时省略
+type Pod struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Status     string            `json:"status"`
+	NodeName   string            `json:"nodeName"`
+	IP         string            `json:"ip"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	Labels     map[string]string `json:"labels"`
+	Containers []Container       `json:"containers"`
+	Usage      *ResourceUsage    `json:"usage,omitempty"`
+}
+
 type PodService struct {
-	podRepo *repository.PodRepository
+	podRepo     *repository.PodRepository
+	metricsRepo *repository.MetricsRepository
+	cache       *cache.Cache
 }
 
-// NewPodService 创建Pod Service
-func NewPodService(repo *repository.PodRepository) *PodService {
+// NewPodService 创建Pod Service，listCache 为 nil 时不使用缓存
+func NewPodService(repo *repository.PodRepository, metricsRepo *repository.MetricsRepository, listCache *cache.Cache) *PodService {
 	return &PodService{
-		podRepo: repo,
+		podRepo:     repo,
+		metricsRepo: metricsRepo,
+		cache:       listCache,
+	}
+}
+
+// ListPodsInNamespace 获取指定命名空间中的Pod列表，合并实时用量并按 sortBy 排序
+// 列表本身走缓存，用量每次实时查询
+// 对应Shell: kubectl get pods -n $NAMESPACE; kubectl top pods -n $NAMESPACE --sort-by=cpu
+func (s *PodService) ListPodsInNamespace(ctx context.Context, cluster, namespace, sortBy string) ([]Pod, error) {
+	if err := validateSortBy(sortBy); err != nil {
+		return nil, err
+	}
+	key := cache.Key{Cluster: cluster, Resource: "pods", Namespace: namespace}
+	pods, err := cache.Fetch(ctx, s.cache, key, func(ctx context.Context) ([]Pod, error) {
+		return s.listPodsInNamespace(ctx, cluster, namespace)
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.mergeUsage(ctx, cluster, namespace, pods)
+	sortPods(pods, sortBy)
+	return pods, nil
 }
 
-// ListPodsInNamespace 获取指定命名空间中的Pod列表（带业务规则）
-// 业务规则：可以添加过滤、排序等逻辑
-// 对应Shell: get_pods_in_namespace | awk '{print $1}'
-func (s *PodService) ListPodsInNamespace(namespace string) ([]string, error) {
+func (s *PodService) listPodsInNamespace(ctx context.Context, cluster, namespace string) ([]Pod, error) {
 	// 调用Repository层获取数据
-	pods, err := s.podRepo.ListByNamespace(namespace)
+	pods, err := s.podRepo.ListByNamespace(ctx, cluster, namespace)
 	if err != nil {
 		return nil, err
 	}
+	return toPods(pods), nil
+}
 
-	// 业务逻辑：提取Pod名称
-	var result []string
-	for _, pod := range pods {
-		result = append(result, pod.Name)
+// GetPod 获取单个Pod信息及其实时用量
+func (s *PodService) GetPod(ctx context.Context, cluster, namespace, name string) (Pod, error) {
+	pod, err := s.podRepo.GetByName(ctx, cluster, namespace, name)
+	if err != nil {
+		return Pod{}, err
 	}
+	result := toPod(pod)
 
+	mctx, cancel := context.WithTimeout(ctx, metricsTimeout)
+	defer cancel()
+	m, err := s.metricsRepo.GetPodMetrics(mctx, cluster, namespace, name)
+	if err != nil {
+		logMetricsError(ctx, cluster, err)
+		return result, nil
+	}
+	applyPodUsage(&result, m)
 	return result, nil
 }
 
-// GetPod 获取单个Pod信息
-func (s *PodService) GetPod(namespace, name string) (string, error) {
-	_, err := s.podRepo.GetByName(namespace, name)
+// ListAllPods 获取所有命名空间的Pod，合并实时用量并按 sortBy 排序
+func (s *PodService) ListAllPods(ctx context.Context, cluster, sortBy string) ([]Pod, error) {
+	if err := validateSortBy(sortBy); err != nil {
+		return nil, err
+	}
+	key := cache.Key{Cluster: cluster, Resource: "pods"}
+	pods, err := cache.Fetch(ctx, s.cache, key, func(ctx context.Context) ([]Pod, error) {
+		return s.listAllPods(ctx, cluster)
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return name, nil
+	s.mergeUsage(ctx, cluster, "", pods)
+	sortPods(pods, sortBy)
+	return pods, nil
 }
 
-// ListAllPods 获取所有命名空间的Pod（带命名空间前缀）
-func (s *PodService) ListAllPods() ([]map[string]string, error) {
+func (s *PodService) listAllPods(ctx context.Context, cluster string) ([]Pod, error) {
 	// 调用Repository层获取数据
-	pods, err := s.podRepo.ListAll()
+	pods, err := s.podRepo.ListAll(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
+	return toPods(pods), nil
+}
 
-	// 业务逻辑：组合命名空间和Pod名称
-	var result []map[string]string
-	for _, pod := range pods {
-		result = append(result, map[string]string{
-			"namespace": pod.Namespace,
-			"name":      pod.Name,
-			"status":    string(pod.Status.Phase),
-		})
+// mergeUsage 查询命名空间内所有 Pod 的用量并合并到列表中，失败时保持列表不带用量
+func (s *PodService) mergeUsage(ctx context.Context, cluster, namespace string, pods []Pod) {
+	if len(pods) == 0 {
+		return
+	}
+	mctx, cancel := context.WithTimeout(ctx, metricsTimeout)
+	defer cancel()
+	items, err := s.metricsRepo.ListPodMetrics(mctx, cluster, namespace)
+	if err != nil {
+		logMetricsError(ctx, cluster, err)
+		return
 	}
+	index := podUsageIndex(items)
+	for i := range pods {
+		if m, ok := index[pods[i].Namespace+"/"+pods[i].Name]; ok {
+			applyPodUsage(&pods[i], m)
+		}
+	}
+}
 
-	return result, nil
+// applyPodUsage 把 metrics-server 的容器用量合并到 Pod 和容器上
+// Pod 的 requests/limits 百分比只在所有容器都设置了对应值时计算，与 kubectl describe 的口径一致
+func applyPodUsage(pod *Pod, m *metricsv1beta1.PodMetrics) {
+	usage := make(map[string]ResourceQuantities, len(m.Containers))
+	for _, c := range m.Containers {
+		usage[c.Name] = quantities(c.Usage)
+	}
+
+	var total, requests, limits ResourceQuantities
+	allCPURequests, allCPULimits, allMemoryRequests, allMemoryLimits := true, true, true, true
+	for i := range pod.Containers {
+		c := &pod.Containers[i]
+		u, ok := usage[c.Name]
+		if ok {
+			c.Usage = newResourceUsage(u, c.Resources, m)
+		}
+		total.CPUMillicores += u.CPUMillicores
+		total.MemoryBytes += u.MemoryBytes
+		requests.CPUMillicores += c.Resources.Requests.CPUMillicores
+		requests.MemoryBytes += c.Resources.Requests.MemoryBytes
+		limits.CPUMillicores += c.Resources.Limits.CPUMillicores
+		limits.MemoryBytes += c.Resources.Limits.MemoryBytes
+		allCPURequests = allCPURequests && c.Resources.Requests.CPUMillicores > 0
+		allCPULimits = allCPULimits && c.Resources.Limits.CPUMillicores > 0
+		allMemoryRequests = allMemoryRequests && c.Resources.Requests.MemoryBytes > 0
+		allMemoryLimits = allMemoryLimits && c.Resources.Limits.MemoryBytes > 0
+	}
+	if !allCPURequests {
+		requests.CPUMillicores = 0
+	}
+	if !allCPULimits {
+		limits.CPUMillicores = 0
+	}
+	if !allMemoryRequests {
+		requests.MemoryBytes = 0
+	}
+	if !allMemoryLimits {
+		limits.MemoryBytes = 0
+	}
+	pod.Usage = newResourceUsage(total, ContainerResources{Requests: requests, Limits: limits}, m)
+}
+
+func newResourceUsage(u ResourceQuantities, res ContainerResources, m *metricsv1beta1.PodMetrics) *ResourceUsage {
+	return &ResourceUsage{
+		CPUMillicores:        u.CPUMillicores,
+		MemoryBytes:          u.MemoryBytes,
+		CPURequestPercent:    percent(u.CPUMillicores, res.Requests.CPUMillicores),
+		CPULimitPercent:      percent(u.CPUMillicores, res.Limits.CPUMillicores),
+		MemoryRequestPercent: percent(u.MemoryBytes, res.Requests.MemoryBytes),
+		MemoryLimitPercent:   percent(u.MemoryBytes, res.Limits.MemoryBytes),
+		Timestamp:            m.Timestamp.Time,
+		Window:               m.Window.Duration.String(),
+	}
+}
+
+// sortPods 按名称或用量排序，所有命名空间的列表按 命名空间/名称 排序
+func sortPods(pods []Pod, sortBy string) {
+	sortByUsage(pods, sortBy, func(p Pod) (ResourceQuantities, bool) {
+		if p.Usage == nil {
+			return ResourceQuantities{}, false
+		}
+		return ResourceQuantities{CPUMillicores: p.Usage.CPUMillicores, MemoryBytes: p.Usage.MemoryBytes}, true
+	}, func(p Pod) string {
+		return p.Namespace + "/" + p.Name
+	})
+}
+
+func toPods(pods []corev1.Pod) []Pod {
+	result := make([]Pod, 0, len(pods))
+	for i := range pods {
+		result = append(result, toPod(&pods[i]))
+	}
+	return result
+}
+
+// toPod 转换为返回结构，只包含普通容器（不含 init 容器和临时容器）
+func toPod(pod *corev1.Pod) Pod {
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, st := range pod.Status.ContainerStatuses {
+		statuses[st.Name] = st
+	}
+	containers := make([]Container, 0, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		st := statuses[c.Name]
+		containers = append(containers, Container{
+			Name:         c.Name,
+			Image:        c.Image,
+			Ready:        st.Ready,
+			RestartCount: st.RestartCount,
+			Resources: ContainerResources{
+				Requests: quantities(c.Resources.Requests),
+				Limits:   quantities(c.Resources.Limits),
+			},
+		})
+	}
+	return Pod{
+		Name:       pod.Name,
+		Namespace:  pod.Namespace,
+		Status:     string(pod.Status.Phase),
+		NodeName:   pod.Spec.NodeName,
+		IP:         pod.Status.PodIP,
+		CreatedAt:  pod.CreationTimestamp.Time,
+		Labels:     pod.Labels,
+		Containers: containers,
+	}
 }
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/yansongwel/kubeops/backend/internal/auth"
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/config"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// 端口转发的审计 action
+const (
+	AuditActionPortForwardOpen  = "portforward.open"
+	AuditActionPortForwardClose = "portforward.close"
+)
+
+// 端口转发结束的原因
+const (
+	PortForwardClosedByClient = "client"   // 客户端断开
+	PortForwardClosedByRemote = "remote"   // Pod 中的进程关闭了连接
+	PortForwardClosedIdle     = "idle"     // 超过空闲超时
+	PortForwardClosedError    = "error"    // 连接 Pod 端口失败等错误
+	PortForwardClosedShutdown = "shutdown" // 服务停止或请求被取消
+)
+
+// PortForwardRequest 端口转发目标：Pod 或 Service 的端口，二者只能指定一个
+// Service 的端口可以是端口号或端口名，转发到该端口 targetPort 对应的一个就绪 Pod
+type PortForwardRequest struct {
+	Cluster   string
+	Namespace string
+	Pod       string
+	Service   string
+	Port      string
+}
+
+// PortForwardTarget 解析后的转发目标
+type PortForwardTarget struct {
+	Cluster     string `json:"cluster"`
+	Namespace   string `json:"namespace"`
+	Pod         string `json:"pod"`
+	Port        int32  `json:"port"`
+	Service     string `json:"service,omitempty"`
+	ServicePort int32  `json:"servicePort,omitempty"`
+}
+
+// PortForwardSession 一个已连接到 Pod 端口的转发会话，Serve 结束后自动释放
+type PortForwardSession struct {
+	Target PortForwardTarget
+
+	svc     *PortForwardService
+	ctx     context.Context
+	conn    httpstream.Connection
+	data    httpstream.Stream
+	errCh   chan error
+	started time.Time
+	release func()
+	once    sync.Once
+}
+
+// PortForwardResult 会话结束时的统计
+type PortForwardResult struct {
+	Reason   string `json:"reason"`
+	Error    string `json:"error,omitempty"`
+	BytesIn  int64  `json:"bytesIn"`  // 客户端发往 Pod
+	BytesOut int64  `json:"bytesOut"` // Pod 发往客户端
+	Duration string `json:"duration"`
+}
+
+// PortForwardService 通过后端把客户端连接转发到 Pod 端口，客户端不需要 kubeconfig
+// 类比Shell: kubectl port-forward svc/$NAME -n $NAMESPACE $PORT
+type PortForwardService struct {
+	clusters    *client.ClusterManager
+	podRepo     *repository.PodRepository
+	serviceRepo *repository.ServiceRepository
+	auditRepo   *repository.AuditRepository
+	cfg         config.PortForwardConfig
+
+	mu        sync.Mutex
+	active    map[string]int // 按用户统计本副本上打开的会话数
+	requestID atomic.Int64
+}
+
+// NewPortForwardService 创建端口转发 Service
+func NewPortForwardService(
+	clusters *client.ClusterManager,
+	podRepo *repository.PodRepository,
+	serviceRepo *repository.ServiceRepository,
+	auditRepo *repository.AuditRepository,
+	cfg config.PortForwardConfig,
+) *PortForwardService {
+	return &PortForwardService{
+		clusters:    clusters,
+		podRepo:     podRepo,
+		serviceRepo: serviceRepo,
+		auditRepo:   auditRepo,
+		cfg:         cfg,
+		active:      make(map[string]int),
+	}
+}
+
+// Open 解析转发目标、检查并发数并连接到 Pod 端口
+// 连接在升级为 WebSocket 之前建立，目标不存在或不可达时调用方仍能返回普通的错误响应
+func (s *PortForwardService) Open(ctx context.Context, req PortForwardRequest) (*PortForwardSession, error) {
+	target, err := s.resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	release, err := s.acquire(auth.UserFromContext(ctx).ID())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.podRepo.DialPortForward(target.Cluster, target.Namespace, target.Pod)
+	if err != nil {
+		release()
+		return nil, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "连接 Pod 端口转发失败", err)
+	}
+	session := &PortForwardSession{
+		Target:  target,
+		svc:     s,
+		ctx:     ctx,
+		conn:    conn,
+		errCh:   make(chan error, 1),
+		started: time.Now(),
+		release: release,
+	}
+	if err := session.createStreams(strconv.FormatInt(s.requestID.Add(1), 10)); err != nil {
+		session.Close()
+		return nil, response.NewError(http.StatusBadGateway, response.CodeBadGateway, "创建端口转发流失败", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, repository.AuditLog{
+		Action:    AuditActionPortForwardOpen,
+		Cluster:   target.Cluster,
+		Namespace: target.Namespace,
+		Resource:  "pods/" + target.Pod,
+	}, target)
+	return session, nil
+}
+
+// createStreams 按 portforward 协议为端口创建 error 流和 data 流，与 kubectl 的实现一致
+func (p *PortForwardSession) createStreams(requestID string) error {
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, corev1.StreamTypeError)
+	headers.Set(corev1.PortHeader, strconv.Itoa(int(p.Target.Port)))
+	headers.Set(corev1.PortForwardRequestIDHeader, requestID)
+	errorStream, err := p.conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("failed to create error stream: %w", err)
+	}
+	// error 流只读，关闭写方向
+	_ = errorStream.Close()
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			p.errCh <- fmt.Errorf("failed to read error stream: %w", err)
+		case len(message) > 0:
+			p.errCh <- errors.New(string(message))
+		}
+	}()
+
+	headers.Set(corev1.StreamType, corev1.StreamTypeData)
+	p.data, err = p.conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("failed to create data stream: %w", err)
+	}
+	return nil
+}
+
+// Serve 在客户端和 Pod 端口之间双向复制数据，直到任一方关闭、出错、空闲超时或 ctx 取消
+// 返回结束原因和流量统计，并写入审计日志
+func (p *PortForwardSession) Serve(ctx context.Context, rw io.ReadWriter) PortForwardResult {
+	defer p.Close()
+
+	var bytesIn, bytesOut atomic.Int64
+	var lastActive atomic.Int64
+	lastActive.Store(time.Now().UnixNano())
+
+	done := make(chan string, 2)
+	go func() {
+		_, err := io.Copy(p.data, &countingReader{r: rw, n: &bytesIn, last: &lastActive})
+		// 客户端不再发送数据，关闭 data 流的写方向，Pod 中的进程读到 EOF
+		_ = p.data.Close()
+		if err != nil {
+			logging.FromContext(p.ctx).Debug("Port-forward client read failed", zap.Error(err))
+		}
+		done <- PortForwardClosedByClient
+	}()
+	go func() {
+		_, _ = io.Copy(rw, &countingReader{r: p.data, n: &bytesOut, last: &lastActive})
+		done <- PortForwardClosedByRemote
+	}()
+
+	idle := p.svc.cfg.IdleTimeout
+	ticker := time.NewTicker(min(idle/4, 30*time.Second))
+	defer ticker.Stop()
+
+	result := PortForwardResult{}
+	for result.Reason == "" {
+		select {
+		case reason := <-done:
+			result.Reason = reason
+		case err := <-p.errCh:
+			result.Reason = PortForwardClosedError
+			result.Error = err.Error()
+		case <-ctx.Done():
+			result.Reason = PortForwardClosedShutdown
+		case <-ticker.C:
+			if time.Since(time.Unix(0, lastActive.Load())) >= idle {
+				result.Reason = PortForwardClosedIdle
+			}
+		}
+	}
+	// Pod 一侧关闭时 error 流可能稍后才带回错误原因（如端口上没有进程监听）
+	if result.Reason == PortForwardClosedByRemote {
+		select {
+		case err := <-p.errCh:
+			result.Reason = PortForwardClosedError
+			result.Error = err.Error()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	result.BytesIn = bytesIn.Load()
+	result.BytesOut = bytesOut.Load()
+	result.Duration = time.Since(p.started).Round(time.Millisecond).String()
+
+	logging.FromContext(p.ctx).Info("Port-forward closed",
+		zap.String("cluster", p.Target.Cluster), zap.String("namespace", p.Target.Namespace),
+		zap.String("pod", p.Target.Pod), zap.Int32("port", p.Target.Port),
+		zap.String("reason", result.Reason), zap.String("error", result.Error),
+		zap.Int64("bytesIn", result.BytesIn), zap.Int64("bytesOut", result.BytesOut))
+	recordAudit(context.WithoutCancel(p.ctx), p.svc.auditRepo, repository.AuditLog{
+		Action:    AuditActionPortForwardClose,
+		Cluster:   p.Target.Cluster,
+		Namespace: p.Target.Namespace,
+		Resource:  "pods/" + p.Target.Pod,
+	}, struct {
+		PortForwardTarget
+		PortForwardResult
+	}{p.Target, result})
+	return result
+}
+
+// Close 关闭到 Pod 的连接并释放并发名额，可以重复调用
+func (p *PortForwardSession) Close() {
+	p.once.Do(func() {
+		if p.data != nil {
+			_ = p.data.Reset()
+		}
+		_ = p.conn.Close()
+		p.release()
+	})
+}
+
+// countingReader 统计读取的字节数并记录最近一次有数据的时间
+type countingReader struct {
+	r    io.Reader
+	n    *atomic.Int64
+	last *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.n.Add(int64(n))
+		c.last.Store(time.Now().UnixNano())
+	}
+	return n, err
+}
+
+// acquire 占用一个并发名额，超过每用户上限时返回 429
+func (s *PortForwardService) acquire(user string) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cfg.MaxPerUser > 0 && s.active[user] >= s.cfg.MaxPerUser {
+		return nil, response.NewError(http.StatusTooManyRequests, response.CodeTooManyRequests,
+			fmt.Sprintf("同时打开的端口转发不能超过 %d 个，请先关闭不用的连接", s.cfg.MaxPerUser), nil)
+	}
+	s.active[user]++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.active[user]--; s.active[user] <= 0 {
+				delete(s.active, user)
+			}
+		})
+	}, nil
+}
+
+// resolve 校验目标 Pod 正在运行；目标为 Service 时选择一个就绪的后端 Pod 并把 Service 端口换算为容器端口
+func (s *PortForwardService) resolve(ctx context.Context, req PortForwardRequest) (PortForwardTarget, error) {
+	if (req.Pod == "") == (req.Service == "") {
+		return PortForwardTarget{}, response.ErrBadRequest("Pod 和 Service 必须且只能指定一个", nil)
+	}
+	if req.Port == "" {
+		return PortForwardTarget{}, response.ErrBadRequest("port 参数不能为空", nil)
+	}
+	cluster, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return PortForwardTarget{}, err
+	}
+	target := PortForwardTarget{Cluster: cluster.Name, Namespace: req.Namespace, Pod: req.Pod}
+
+	if req.Service == "" {
+		pod, err := s.podRepo.GetByName(ctx, cluster.Name, req.Namespace, req.Pod)
+		if err != nil {
+			return PortForwardTarget{}, err
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return PortForwardTarget{}, response.ErrBadRequest(fmt.Sprintf("Pod %s 未在运行（%s），无法转发端口", pod.Name, pod.Status.Phase), nil)
+		}
+		port, err := containerPort(pod, intstr.Parse(req.Port))
+		if err != nil {
+			return PortForwardTarget{}, err
+		}
+		target.Port = port
+		return target, nil
+	}
+
+	svc, err := s.serviceRepo.GetByName(ctx, cluster.Name, req.Namespace, req.Service)
+	if err != nil {
+		return PortForwardTarget{}, err
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return PortForwardTarget{}, response.ErrBadRequest(fmt.Sprintf("Service %s 没有 selector，无法确定后端 Pod", svc.Name), nil)
+	}
+	var servicePort *corev1.ServicePort
+	for i, p := range svc.Spec.Ports {
+		if p.Name == req.Port || strconv.Itoa(int(p.Port)) == req.Port {
+			servicePort = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		return PortForwardTarget{}, response.ErrBadRequest(fmt.Sprintf("Service %s 没有端口 %s", svc.Name, req.Port), nil)
+	}
+
+	pods, err := s.podRepo.ListWithSelector(ctx, cluster.Name, req.Namespace, labels.SelectorFromSet(svc.Spec.Selector).String(), "")
+	if err != nil {
+		return PortForwardTarget{}, err
+	}
+	pod := readyPod(pods)
+	if pod == nil {
+		return PortForwardTarget{}, response.NewError(http.StatusServiceUnavailable, response.CodeServiceUnavailable,
+			fmt.Sprintf("Service %s 没有就绪的后端 Pod", svc.Name), nil)
+	}
+	// targetPort 为空时与 port 相同
+	targetPort := servicePort.TargetPort
+	if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+		targetPort = intstr.FromInt32(servicePort.Port)
+	}
+	port, err := containerPort(pod, targetPort)
+	if err != nil {
+		return PortForwardTarget{}, err
+	}
+	target.Pod = pod.Name
+	target.Port = port
+	target.Service = svc.Name
+	target.ServicePort = servicePort.Port
+	return target, nil
+}
+
+// readyPod 选择一个就绪且未在删除中的 Pod，按名称排序保证结果稳定
+func readyPod(pods []corev1.Pod) *corev1.Pod {
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				return pod
+			}
+		}
+	}
+	return nil
+}
+
+// containerPort 把端口号或端口名换算为容器端口；端口号不要求在容器中声明，与 kubectl 一致
+func containerPort(pod *corev1.Pod, port intstr.IntOrString) (int32, error) {
+	if port.Type == intstr.Int {
+		if port.IntVal < 1 || port.IntVal > 65535 {
+			return 0, response.ErrBadRequest(fmt.Sprintf("端口 %d 无效", port.IntVal), nil)
+		}
+		return port.IntVal, nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == port.StrVal {
+				return p.ContainerPort, nil
+			}
+		}
+	}
+	return 0, response.ErrBadRequest(fmt.Sprintf("Pod %s 的容器中没有名为 %s 的端口", pod.Name, port.StrVal), nil)
+}
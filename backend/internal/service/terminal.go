@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+
+	"github.com/yansongwel/kubeops/backend/internal/client"
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/internal/repository"
+)
+
+// 终端的审计 action
+const (
+	AuditActionTerminalExec   = "terminal.exec"
+	AuditActionTerminalAttach = "terminal.attach"
+	AuditActionTerminalClose  = "terminal.close"
+)
+
+// TerminalRequest 终端目标，Command 为空时连接到容器主进程（attach），否则在容器中执行命令（exec）
+type TerminalRequest struct {
+	Cluster   string   `json:"cluster"`
+	Namespace string   `json:"namespace"`
+	Pod       string   `json:"pod"`
+	Container string   `json:"container"`
+	Command   []string `json:"command,omitempty"`
+}
+
+// TerminalResult 终端结束时的结果
+type TerminalResult struct {
+	ExitCode int    `json:"exitCode"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// TerminalStreams 终端的输入输出，TTY 模式下 stderr 合并到 stdout
+type TerminalStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Sizes  remotecommand.TerminalSizeQueue // 终端大小变化，可以为 nil
+}
+
+// TerminalService 通过 exec 或 attach 子资源提供交互式终端
+// 类比Shell: kubectl exec -it $NAME -c $CONTAINER -- sh / kubectl attach -it $NAME -c $CONTAINER
+type TerminalService struct {
+	clusters  *client.ClusterManager
+	podRepo   *repository.PodRepository
+	auditRepo *repository.AuditRepository
+}
+
+// NewTerminalService 创建终端 Service
+func NewTerminalService(clusters *client.ClusterManager, podRepo *repository.PodRepository, auditRepo *repository.AuditRepository) *TerminalService {
+	return &TerminalService{
+		clusters:  clusters,
+		podRepo:   podRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+// Prepare 校验 Pod 和容器正在运行并补全集群名和默认容器，在升级为 WebSocket 之前调用，错误仍可以 JSON 返回
+func (s *TerminalService) Prepare(ctx context.Context, req TerminalRequest) (TerminalRequest, error) {
+	cluster, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return req, err
+	}
+	req.Cluster = cluster.Name
+	pod, err := s.podRepo.GetByName(ctx, req.Cluster, req.Namespace, req.Pod)
+	if err != nil {
+		return req, err
+	}
+	req.Container, err = runningContainer(pod, req.Container)
+	return req, err
+}
+
+// Run 连接终端直到进程退出、客户端断开或 ctx 取消，打开和结束时写入审计日志
+// 命令以非 0 退出码结束时 ExitCode 为该退出码；连接失败等错误时 ExitCode 为 -1
+func (s *TerminalService) Run(ctx context.Context, req TerminalRequest, streams TerminalStreams) TerminalResult {
+	action := AuditActionTerminalExec
+	if len(req.Command) == 0 {
+		action = AuditActionTerminalAttach
+	}
+	audit := repository.AuditLog{
+		Action:    action,
+		Cluster:   req.Cluster,
+		Namespace: req.Namespace,
+		Resource:  "pods/" + req.Pod,
+	}
+	recordAudit(ctx, s.auditRepo, audit, req)
+
+	started := time.Now()
+	opts := remotecommand.StreamOptions{Stdin: streams.Stdin, Stdout: streams.Stdout, TerminalSizeQueue: streams.Sizes}
+	var err error
+	if len(req.Command) == 0 {
+		err = s.podRepo.AttachTerminal(ctx, req.Cluster, req.Namespace, req.Pod, req.Container, opts)
+	} else {
+		err = s.podRepo.ExecTerminal(ctx, req.Cluster, req.Namespace, req.Pod, req.Container, req.Command, opts)
+	}
+
+	result := TerminalResult{Duration: time.Since(started).Round(time.Second).String()}
+	var exitErr exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitStatus()
+	case ctx.Err() != nil:
+		// 客户端断开或服务停止，不视为错误
+	default:
+		result.ExitCode = -1
+		result.Error = "连接容器终端失败: " + err.Error()
+		logging.FromContext(ctx).Warn("Terminal stream failed", zap.String("pod", req.Pod),
+			zap.String("container", req.Container), zap.Error(err))
+	}
+
+	audit.Action = AuditActionTerminalClose
+	recordAudit(context.WithoutCancel(ctx), s.auditRepo, audit, struct {
+		TerminalRequest
+		TerminalResult
+	}{req, result})
+	return result
+}
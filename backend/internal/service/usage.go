@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/yansongwel/kubeops/backend/internal/logging"
+	"github.com/yansongwel/kubeops/backend/pkg/response"
+)
+
+// metricsTimeout metrics-server 查询超时，超时后列表照常返回，只是不带用量
+const metricsTimeout = 3 * time.Second
+
+// 列表排序字段，对应 kubectl top --sort-by
+const (
+	SortByName   = "name"
+	SortByCPU    = "cpu"
+	SortByMemory = "memory"
+)
+
+// ResourceQuantities CPU（毫核）和内存（字节），未设置时为 0
+type ResourceQuantities struct {
+	CPUMillicores int64 `json:"cpuMillicores"`
+	MemoryBytes   int64 `json:"memoryBytes"`
+}
+
+// ContainerResources 容器的 requests 和 limits
+type ContainerResources struct {
+	Requests ResourceQuantities `json:"requests"`
+	Limits   ResourceQuantities `json:"limits"`
+}
+
+// ResourceUsage Pod 或容器的实时用量（来自 metrics-server）
+// 百分比为用量占 requests/limits 的比例，未设置 requests/limits 时省略
+type ResourceUsage struct {
+	CPUMillicores        int64     `json:"cpuMillicores"`
+	MemoryBytes          int64     `json:"memoryBytes"`
+	CPURequestPercent    *float64  `json:"cpuRequestPercent,omitempty"`
+	CPULimitPercent      *float64  `json:"cpuLimitPercent,omitempty"`
+	MemoryRequestPercent *float64  `json:"memoryRequestPercent,omitempty"`
+	MemoryLimitPercent   *float64  `json:"memoryLimitPercent,omitempty"`
+	Timestamp            time.Time `json:"timestamp"`
+	Window               string    `json:"window"`
+}
+
+// NodeUsage 节点的实时用量，百分比为用量占 allocatable 的比例（与 kubectl top node 一致）
+type NodeUsage struct {
+	CPUMillicores int64     `json:"cpuMillicores"`
+	MemoryBytes   int64     `json:"memoryBytes"`
+	CPUPercent    *float64  `json:"cpuPercent,omitempty"`
+	MemoryPercent *float64  `json:"memoryPercent,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+	Window        string    `json:"window"`
+}
+
+// quantities 提取 ResourceList 中的 CPU 和内存
+func quantities(list corev1.ResourceList) ResourceQuantities {
+	return ResourceQuantities{
+		CPUMillicores: list.Cpu().MilliValue(),
+		MemoryBytes:   list.Memory().Value(),
+	}
+}
+
+// percent 计算百分比并保留一位小数，total 为 0 时返回 nil
+func percent(used, total int64) *float64 {
+	if total <= 0 {
+		return nil
+	}
+	v := math.Round(float64(used)/float64(total)*1000) / 10
+	return &v
+}
+
+// validateSortBy 校验排序字段，空值表示按名称排序
+func validateSortBy(sortBy string) error {
+	switch sortBy {
+	case "", SortByName, SortByCPU, SortByMemory:
+		return nil
+	default:
+		return response.ErrBadRequest("sortBy 只支持 name、cpu、memory", nil)
+	}
+}
+
+// sortByUsage 按用量降序排列，没有用量的排在最后；用量相同或按名称排序时按 key 升序
+// 类比Shell: kubectl top pods --sort-by=cpu
+func sortByUsage[T any](items []T, sortBy string, usage func(T) (ResourceQuantities, bool), key func(T) string) {
+	value := func(q ResourceQuantities) int64 {
+		if sortBy == SortByMemory {
+			return q.MemoryBytes
+		}
+		return q.CPUMillicores
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if sortBy == SortByCPU || sortBy == SortByMemory {
+			ui, okI := usage(items[i])
+			uj, okJ := usage(items[j])
+			if okI != okJ {
+				return okI
+			}
+			if vi, vj := value(ui), value(uj); vi != vj {
+				return vi > vj
+			}
+		}
+		return key(items[i]) < key(items[j])
+	})
+}
+
+// logMetricsError metrics-server 不可用时降级为不带用量的结果
+// 未安装 metrics-server 是常见情况，只记录 Debug 日志
+func logMetricsError(ctx context.Context, cluster string, err error) {
+	logger := logging.FromContext(ctx).With(zap.String("cluster", cluster), zap.Error(err))
+	if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) || meta.IsNoMatchError(err) {
+		logger.Debug("Metrics API unavailable, returning resources without usage")
+		return
+	}
+	logger.Warn("Failed to fetch resource usage, returning resources without usage")
+}
+
+// podUsageIndex 按 命名空间/名称 索引 Pod 用量
+func podUsageIndex(items []metricsv1beta1.PodMetrics) map[string]*metricsv1beta1.PodMetrics {
+	index := make(map[string]*metricsv1beta1.PodMetrics, len(items))
+	for i := range items {
+		index[items[i].Namespace+"/"+items[i].Name] = &items[i]
+	}
+	return index
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yansongwel/kubeops/backend/internal/kuberequest"
+)
+
+// WrapTransport 为 client-go 的每次 HTTP 往返创建 client span，用于 rest.Config.Wrap
+// span 名称只包含 verb 和资源（如 "k8s list pods"），对象名和命名空间等完整路径放在属性中，避免 span 名称高基数
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(&kubeAttributesTransport{next: rt},
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			info := kuberequest.Parse(r.Method, r.URL)
+			return "k8s " + info.Verb + " " + info.Resource
+		}),
+	)
+}
+
+// kubeAttributesTransport 在 otelhttp 创建的 span 上补充 Kubernetes 请求属性
+type kubeAttributesTransport struct {
+	next http.RoundTripper
+}
+
+func (t *kubeAttributesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	info := kuberequest.Parse(req.Method, req.URL)
+	trace.SpanFromContext(req.Context()).SetAttributes(
+		attribute.String("url.path", req.URL.Path),
+		attribute.String("k8s.request.verb", info.Verb),
+		attribute.String("k8s.request.resource", info.Resource),
+	)
+	return t.next.RoundTrip(req)
+}
+
+// WrappedRoundTripper 供 client-go 的 utilnet 解包，找到底层 Transport
+func (t *kubeAttributesTransport) WrappedRoundTripper() http.RoundTripper {
+	return t.next
+}
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PgxTracer 实现 pgx.QueryTracer，为每条 SQL 创建 client span
+type PgxTracer struct{}
+
+// NewPgxTracer 创建 pgx 查询追踪钩子，设置到 pgx.ConnConfig.Tracer
+func NewPgxTracer() *PgxTracer {
+	return &PgxTracer{}
+}
+
+func (t *PgxTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", data.SQL),
+	}
+	if conn != nil {
+		attrs = append(attrs, attribute.String("db.name", conn.Config().Database))
+	}
+	ctx, _ = tracer().Start(ctx, "postgres query",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+func (t *PgxTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	span.End()
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook 实现 redis.Hook，为每条命令和每个 pipeline 创建 client span
+type RedisHook struct{}
+
+// NewRedisHook 创建 go-redis 追踪钩子，通过 client.AddHook 注册
+func NewRedisHook() *RedisHook {
+	return &RedisHook{}
+}
+
+func (h *RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := tracer().Start(ctx, "redis "+cmd.Name(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "redis"),
+				attribute.String("db.operation", cmd.Name()),
+			),
+		)
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+func (h *RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := tracer().Start(ctx, "redis pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "redis"),
+				attribute.Int("db.redis.num_cmd", len(cmds)),
+			),
+		)
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordRedisError(span, err)
+		return err
+	}
+}
+
+// recordRedisError 记录错误，redis.Nil（键不存在）不算失败
+func recordRedisError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,282 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/yansongwel/kubeops/backend/internal/config"
+)
+
+// collectorStub OTLP/HTTP 接收端的替身，记录收到的 span 名称和 trace ID
+type collectorStub struct {
+	mu    sync.Mutex
+	spans map[string]string // span 名称 -> trace ID
+}
+
+func (s *collectorStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				s.spans[span.Name] = hex.EncodeToString(span.TraceId)
+			}
+		}
+	}
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSetupExportsSpansToCollector(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	collector := &collectorStub{spans: map[string]string{}}
+	collectorSrv := httptest.NewServer(collector)
+	defer collectorSrv.Close()
+
+	// API Server 替身，记录 client-go 往返带上的 traceparent
+	var traceparent string
+	apiserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_, _ = w.Write([]byte(`{"kind":"NamespaceList","items":[]}`))
+	}))
+	defer apiserver.Close()
+
+	shutdown, err := Setup(context.Background(), config.TracingConfig{
+		Enabled:     true,
+		Endpoint:    strings.TrimPrefix(collectorSrv.URL, "http://"),
+		Insecure:    true,
+		SampleRatio: 1,
+		ServiceName: "kubeops-test",
+	})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	var logTraceID string
+	router := gin.New()
+	router.Use(HTTPMiddleware())
+	router.GET("/api/v1/namespaces", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		for _, f := range ZapFields(ctx) {
+			if f.Key == "trace_id" {
+				logTraceID = f.String
+			}
+		}
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, apiserver.URL+"/api/v1/namespaces", nil)
+		resp, err := (&http.Client{Transport: WrapTransport(http.DefaultTransport)}).Do(req)
+		if err != nil {
+			c.Status(http.StatusBadGateway)
+			return
+		}
+		_ = resp.Body.Close()
+		c.Status(http.StatusOK)
+	})
+
+	// 上游网关传入的 trace 需要延续
+	const upstreamTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil)
+	req.Header.Set("traceparent", "00-"+upstreamTraceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for _, name := range []string{"GET /api/v1/namespaces", "k8s list namespaces"} {
+		got, ok := collector.spans[name]
+		if !ok {
+			t.Errorf("collector did not receive span %q, got %v", name, collector.spans)
+			continue
+		}
+		if got != upstreamTraceID {
+			t.Errorf("span %q trace ID = %s, want %s", name, got, upstreamTraceID)
+		}
+	}
+	if logTraceID != upstreamTraceID {
+		t.Errorf("log trace_id = %q, want %s", logTraceID, upstreamTraceID)
+	}
+	if !strings.Contains(traceparent, upstreamTraceID) {
+		t.Errorf("apiserver traceparent = %q, want trace ID %s", traceparent, upstreamTraceID)
+	}
+}
+
+func TestSetupDisabledKeepsPropagator(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+	// 组合 propagator 的字段顺序不固定
+	fields := otel.GetTextMapPropagator().Fields()
+	if !slices.Contains(fields, "traceparent") || !slices.Contains(fields, "baggage") {
+		t.Errorf("propagator fields = %v, want traceparent and baggage", fields)
+	}
+}
+
+// useRecorder 把全局 TracerProvider 换成内存记录器，测试结束时恢复
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestPgxTracer(t *testing.T) {
+	recorder := useRecorder(t)
+	tracer := NewPgxTracer()
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("relation does not exist")})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "postgres query" {
+		t.Errorf("span name = %q, want %q", span.Name(), "postgres query")
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want Error", span.Status().Code)
+	}
+	found := false
+	for _, attr := range span.Attributes() {
+		if attr.Key == "db.statement" && attr.Value.AsString() == "SELECT 1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("span attributes %v missing db.statement", span.Attributes())
+	}
+}
+
+func TestRedisHook(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "ok", err: nil, wantStatus: codes.Unset},
+		{name: "missing key is not an error", err: redis.Nil, wantStatus: codes.Unset},
+		{name: "connection error", err: errors.New("connection refused"), wantStatus: codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := useRecorder(t)
+			hook := NewRedisHook()
+			process := hook.ProcessHook(func(context.Context, redis.Cmder) error { return tt.err })
+
+			ctx := context.Background()
+			if err := process(ctx, redis.NewStringCmd(ctx, "get", "k")); !errors.Is(err, tt.err) {
+				t.Fatalf("process() error = %v, want %v", err, tt.err)
+			}
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			if spans[0].Name() != "redis get" {
+				t.Errorf("span name = %q, want %q", spans[0].Name(), "redis get")
+			}
+			if spans[0].Status().Code != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", spans[0].Status().Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+type closeIdleRecorder struct {
+	http.RoundTripper
+	closed bool
+}
+
+func (r *closeIdleRecorder) CloseIdleConnections() { r.closed = true }
+
+func TestWrapClientTransportClosesIdleConnections(t *testing.T) {
+	base := &closeIdleRecorder{RoundTripper: http.DefaultTransport}
+	(&http.Client{Transport: WrapClientTransport("test", base)}).CloseIdleConnections()
+	if !base.closed {
+		t.Error("CloseIdleConnections was not forwarded to the base transport")
+	}
+}
+
+func TestWrapTransportSpanName(t *testing.T) {
+	recorder := useRecorder(t)
+	apiserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer apiserver.Close()
+
+	client := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+	for _, path := range []string{"/api/v1/namespaces/web/pods/api-1", "/api/v1/namespaces/web/pods?watch=true"} {
+		resp, err := client.Get(apiserver.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	// span 名称不含命名空间和对象名，完整路径记录在 url.path 属性中
+	spans := recorder.Ended()
+	want := []struct{ name, path string }{
+		{"k8s get pods", "/api/v1/namespaces/web/pods/api-1"},
+		{"k8s watch pods", "/api/v1/namespaces/web/pods"},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("got %d spans, want %d", len(spans), len(want))
+	}
+	for i, w := range want {
+		if spans[i].Name() != w.name {
+			t.Errorf("span %d name = %q, want %q", i, spans[i].Name(), w.name)
+		}
+		found := false
+		for _, attr := range spans[i].Attributes() {
+			if attr.Key == "url.path" && attr.Value.AsString() == w.path {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("span %q attributes %v missing url.path %s", w.name, spans[i].Attributes(), w.path)
+		}
+	}
+}
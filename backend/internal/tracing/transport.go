@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// WrapClientTransport 为访问外部系统（Prometheus、Jenkins 等）的 HTTP 请求创建 client span
+// system 作为 span 名称前缀，如 "prometheus GET /api/v1/query"
+func WrapClientTransport(system string, rt http.RoundTripper) http.RoundTripper {
+	return &clientTransport{
+		RoundTripper: otelhttp.NewTransport(rt,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return system + " " + r.Method + " " + r.URL.Path
+			}),
+		),
+		base: rt,
+	}
+}
+
+// clientTransport otelhttp.Transport 不转发 CloseIdleConnections，由这里转发给底层 Transport
+// 使 http.Client.CloseIdleConnections 能释放客户端被替换后遗留的空闲连接
+type clientTransport struct {
+	http.RoundTripper
+	base http.RoundTripper
+}
+
+// CloseIdleConnections 关闭底层 Transport 的空闲连接
+func (t *clientTransport) CloseIdleConnections() {
+	if ci, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+}
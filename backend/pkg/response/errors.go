@@ -0,0 +1,123 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// 业务错误码：与 HTTP 状态码一一对应且保持稳定，前端可以据此分支处理
+const (
+	CodeSuccess             = 0
+	CodeBadRequest          = 40000
+	CodeInvalid             = 40001
+	CodeUnauthorized        = 40100
+	CodeForbidden           = 40300
+	CodeNotFound            = 40400
+	CodeMethodNotAllowed    = 40500
+	CodeConflict            = 40900
+	CodeAlreadyExists       = 40901
+	CodeGone                = 41000
+	CodePayloadTooLarge     = 41300
+	CodeTooManyRequests     = 42900
+	CodeInternal            = 50000
+	CodeBadGateway          = 50200
+	CodeServiceUnavailable  = 50300
+	CodePostgresUnavailable = 50301
+	CodeRedisUnavailable    = 50302
+	CodeClusterUnavailable  = 50303
+	CodeTimeout             = 50400
+)
+
+// AppError 带 HTTP 状态码和业务错误码的类型化错误
+type AppError struct {
+	Status  int    // HTTP 状态码
+	Code    int    // 业务错误码
+	Message string // 面向用户的错误描述
+	Err     error  // 原始错误，4xx 时作为 details 返回，5xx 只写入日志
+}
+
+// NewError 创建类型化错误
+func NewError(status, code int, message string, err error) *AppError {
+	return &AppError{Status: status, Code: code, Message: message, Err: err}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// ErrBadRequest 参数错误
+func ErrBadRequest(message string, err error) *AppError {
+	return NewError(http.StatusBadRequest, CodeBadRequest, message, err)
+}
+
+// ErrNotFound 资源不存在
+func ErrNotFound(message string, err error) *AppError {
+	return NewError(http.StatusNotFound, CodeNotFound, message, err)
+}
+
+// ErrInternal 服务内部错误
+func ErrInternal(message string, err error) *AppError {
+	return NewError(http.StatusInternalServerError, CodeInternal, message, err)
+}
+
+// FromError 将任意错误转换为 AppError
+// Kubernetes apiserver 返回的错误按其语义映射到对应的 HTTP 状态码，而不是一律 404/500
+func FromError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return NewError(http.StatusNotFound, CodeNotFound, "资源不存在", err)
+	case apierrors.IsAlreadyExists(err):
+		return NewError(http.StatusConflict, CodeAlreadyExists, "资源已存在", err)
+	case apierrors.IsConflict(err):
+		return NewError(http.StatusConflict, CodeConflict, "资源版本冲突，请刷新后重试", err)
+	case apierrors.IsInvalid(err):
+		return NewError(http.StatusUnprocessableEntity, CodeInvalid, "资源校验失败", err)
+	case apierrors.IsBadRequest(err):
+		return NewError(http.StatusBadRequest, CodeBadRequest, "请求参数错误", err)
+	case apierrors.IsUnauthorized(err):
+		return NewError(http.StatusUnauthorized, CodeUnauthorized, "未认证或凭证已失效", err)
+	case apierrors.IsForbidden(err):
+		return NewError(http.StatusForbidden, CodeForbidden, "没有权限执行该操作", err)
+	case apierrors.IsMethodNotSupported(err):
+		return NewError(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "不支持的操作", err)
+	case apierrors.IsGone(err), apierrors.IsResourceExpired(err):
+		return NewError(http.StatusGone, CodeGone, "资源版本已过期", err)
+	case apierrors.IsTooManyRequests(err):
+		return NewError(http.StatusTooManyRequests, CodeTooManyRequests, "请求过于频繁", err)
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return NewError(http.StatusGatewayTimeout, CodeTimeout, "请求超时", err)
+	case apierrors.IsServiceUnavailable(err):
+		return NewError(http.StatusServiceUnavailable, CodeServiceUnavailable, "依赖服务不可用", err)
+	case isConnectionError(err):
+		// apiserver 等依赖在启动后断开时，返回 503 而不是 500
+		return NewError(http.StatusServiceUnavailable, CodeServiceUnavailable, "依赖服务不可达", err)
+	default:
+		return NewError(http.StatusInternalServerError, CodeInternal, "服务内部错误", err)
+	}
+}
+
+// isConnectionError 判断是否为连接被拒绝、DNS 解析失败等网络层错误
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
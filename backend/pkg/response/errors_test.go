@@ -0,0 +1,100 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var pods = schema.GroupResource{Resource: "pods"}
+
+func TestFromError(t *testing.T) {
+	appErr := NewError(http.StatusServiceUnavailable, CodeRedisUnavailable, "Redis 暂不可用", errors.New("dial tcp: refused"))
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   int
+	}{
+		{name: "not found", err: apierrors.NewNotFound(pods, "web"), wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "wrapped not found", err: fmt.Errorf("failed to get pod: %w", apierrors.NewNotFound(pods, "web")), wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "forbidden", err: apierrors.NewForbidden(pods, "web", errors.New("rbac")), wantStatus: http.StatusForbidden, wantCode: CodeForbidden},
+		{name: "conflict", err: apierrors.NewConflict(pods, "web", errors.New("modified")), wantStatus: http.StatusConflict, wantCode: CodeConflict},
+		{name: "already exists", err: apierrors.NewAlreadyExists(pods, "web"), wantStatus: http.StatusConflict, wantCode: CodeAlreadyExists},
+		{
+			name:       "invalid",
+			err:        apierrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, "web", field.ErrorList{field.Required(field.NewPath("spec"), "")}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   CodeInvalid,
+		},
+		{name: "apiserver timeout", err: apierrors.NewTimeoutError("watch", 5), wantStatus: http.StatusGatewayTimeout, wantCode: CodeTimeout},
+		{name: "context deadline", err: fmt.Errorf("failed to list pods: %w", context.DeadlineExceeded), wantStatus: http.StatusGatewayTimeout, wantCode: CodeTimeout},
+		{name: "app error", err: appErr, wantStatus: http.StatusServiceUnavailable, wantCode: CodeRedisUnavailable},
+		{name: "wrapped app error", err: fmt.Errorf("failed to check token: %w", appErr), wantStatus: http.StatusServiceUnavailable, wantCode: CodeRedisUnavailable},
+		{name: "plain error", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCode: CodeInternal},
+	}
+	for _, tt := range tests {
+		got := FromError(tt.err)
+		if got.Status != tt.wantStatus || got.Code != tt.wantCode {
+			t.Errorf("%s: FromError() = %d/%d, want %d/%d", tt.name, got.Status, got.Code, tt.wantStatus, tt.wantCode)
+		}
+	}
+}
+
+func TestErrorDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+		wantDetails string
+	}{
+		{
+			name:        "4xx keeps details",
+			err:         ErrBadRequest("参数错误", errors.New("namespace is required")),
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "参数错误",
+			wantDetails: "namespace is required",
+		},
+		{
+			// 5xx 只返回通用描述，原始错误不进入响应体
+			name:        "5xx hides the cause",
+			err:         fmt.Errorf("failed to query: %w", errors.New("dial tcp 10.0.0.5:5432: connection refused")),
+			wantStatus:  http.StatusInternalServerError,
+			wantMessage: "服务内部错误",
+		},
+		{
+			name:        "typed 5xx hides the cause",
+			err:         NewError(http.StatusBadGateway, CodeBadGateway, "监控数据源返回错误", errors.New("http://prometheus:9090: 500")),
+			wantStatus:  http.StatusBadGateway,
+			wantMessage: "监控数据源返回错误",
+		},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		Error(c, tt.err)
+
+		var resp Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode response: %v", tt.name, err)
+		}
+		if w.Code != tt.wantStatus || resp.Message != tt.wantMessage || resp.Details != tt.wantDetails {
+			t.Errorf("%s: response = %d %+v, want %d %q details %q", tt.name, w.Code, resp, tt.wantStatus, tt.wantMessage, tt.wantDetails)
+		}
+		// 原始错误仍然记录在 gin 上下文中，由访问日志输出
+		if len(c.Errors) != 1 || !errors.Is(c.Errors[0].Err, tt.err) {
+			t.Errorf("%s: c.Errors = %v, want the original error", tt.name, c.Errors)
+		}
+	}
+}
@@ -11,41 +11,50 @@ type Response struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Details string      `json:"details,omitempty"`
 }
 
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
-		Code:    0,
+		Code:    CodeSuccess,
 		Message: "success",
 		Data:    data,
 	})
 }
 
-// Error 错误响应
-func Error(c *gin.Context, code int, message string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    code,
-		Message: message,
-	})
+// Error 错误响应，HTTP 状态码和业务错误码由错误类型决定
+func Error(c *gin.Context, err error) {
+	appErr := FromError(err)
+	resp := Response{
+		Code:    appErr.Code,
+		Message: appErr.Message,
+	}
+	// details 只对 4xx 返回；5xx 的原始错误可能包含内部地址、SQL 等信息，只返回通用描述
+	if appErr.Err != nil && appErr.Status < http.StatusInternalServerError {
+		resp.Details = appErr.Err.Error()
+	}
+	// 记录到 gin 上下文，由访问日志中间件输出（5xx 按 error 级别记录完整原因）
+	_ = c.Error(err)
+	c.AbortWithStatusJSON(appErr.Status, resp)
 }
 
 // BadRequest 400 错误
 func BadRequest(c *gin.Context, message string) {
-	Error(c, 400, message)
+	Error(c, ErrBadRequest(message, nil))
 }
 
 // Unauthorized 401 错误
 func Unauthorized(c *gin.Context, message string) {
-	Error(c, 401, message)
+	Error(c, NewError(http.StatusUnauthorized, CodeUnauthorized, message, nil))
 }
 
 // NotFound 404 错误
 func NotFound(c *gin.Context, message string) {
-	Error(c, 404, message)
+	Error(c, ErrNotFound(message, nil))
 }
 
 // InternalServerError 500 错误
 func InternalServerError(c *gin.Context, message string) {
-	Error(c, 500, message)
+	Error(c, ErrInternal(message, nil))
 }
@@ -37,11 +37,33 @@ export interface Namespace {
 // Pod 相关
 // ============================================================================
 
+export interface ResourceQuantities {
+  cpuMillicores: number
+  memoryBytes: number
+}
+
+// 实时用量（metrics-server），百分比未设置 requests/limits 时省略
+export interface ResourceUsage {
+  cpuMillicores: number
+  memoryBytes: number
+  cpuRequestPercent?: number
+  cpuLimitPercent?: number
+  memoryRequestPercent?: number
+  memoryLimitPercent?: number
+  timestamp: string
+  window: string
+}
+
 export interface Container {
   name: string
   image: string
   ready: boolean
   restartCount: number
+  resources: {
+    requests: ResourceQuantities
+    limits: ResourceQuantities
+  }
+  usage?: ResourceUsage
 }
 
 export interface Pod {
@@ -53,6 +75,34 @@ export interface Pod {
   createdAt: string
   labels: Record<string, string>
   containers: Container[]
+  usage?: ResourceUsage
+}
+
+// ============================================================================
+// 节点相关
+// ============================================================================
+
+export interface NodeUsage {
+  cpuMillicores: number
+  memoryBytes: number
+  cpuPercent?: number
+  memoryPercent?: number
+  timestamp: string
+  window: string
+}
+
+export interface Node {
+  name: string
+  status: 'Ready' | 'NotReady' | 'Unknown'
+  roles: string[]
+  unschedulable: boolean
+  version: string
+  internalIP: string
+  createdAt: string
+  labels: Record<string, string>
+  capacity: ResourceQuantities
+  allocatable: ResourceQuantities
+  usage?: NodeUsage
 }
 
 // ============================================================================